@@ -17,15 +17,19 @@ limitations under the License.
 package builder
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -62,6 +66,21 @@ type Builder struct {
 	ctrl             controller.Controller
 	ctrlOptions      controller.Options
 	name             string
+	requiredIndexes  []requiredIndex
+
+	// predicateMetrics is set by WithPredicateMetrics, predicateMetricsName is the
+	// name of the controller used to label them.
+	predicateMetrics     bool
+	predicateMetricsName string
+
+	// recoverPanic is set by WithRecoverPanic.
+	recoverPanic *bool
+}
+
+// requiredIndex is an index declared with RequiresIndex.
+type requiredIndex struct {
+	object client.Object
+	field  string
 }
 
 // ControllerManagedBy returns a new controller builder that will be started by the provided Manager.
@@ -74,6 +93,7 @@ type ForInput struct {
 	object           client.Object
 	predicates       []predicate.Predicate
 	objectProjection objectProjection
+	cacheOptions     *cache.ByObject
 	err              error
 }
 
@@ -106,11 +126,15 @@ type OwnsInput struct {
 // Owns defines types of Objects being *generated* by the ControllerManagedBy, and configures the ControllerManagedBy to respond to
 // create / delete / update events by *reconciling the owner object*.
 //
-// The default behavior reconciles only the first controller-type OwnerReference of the given type.
+// The default behavior reconciles only the first controller-type OwnerReference of the given type,
+// i.e. the one with Controller set to true. OwnerReferences without it, e.g. those set with
+// controllerutil.SetOwnerReference, are ignored.
 // Use Owns(object, builder.MatchEveryOwner) to reconcile all owners.
 //
 // By default, this is the equivalent of calling
-// Watches(object, handler.EnqueueRequestForOwner([...], ownerType, OnlyControllerOwner())).
+// Watches(object, handler.EnqueueRequestForOwner([...], ownerType, OnlyControllerOwner())),
+// and with MatchEveryOwner of calling
+// Watches(object, handler.EnqueueRequestForOwner([...], ownerType)).
 func (blder *Builder) Owns(object client.Object, opts ...OwnsOption) *Builder {
 	input := OwnsInput{object: object}
 	for _, opt := range opts {
@@ -121,6 +145,15 @@ func (blder *Builder) Owns(object client.Object, opts ...OwnsOption) *Builder {
 	return blder
 }
 
+// OwnsMetadata is the same as Owns, but for objects identified by their GVK that are only watched as
+// metav1.PartialObjectMetadata, e.g. owned objects the reconciler only needs the labels or owner
+// references of, or whose Go type is not available. The informer lists and watches them with the
+// metadata client, see WatchesMetadata for how to fetch them in the reconciler.
+func (blder *Builder) OwnsMetadata(gvk schema.GroupVersionKind, opts ...OwnsOption) *Builder {
+	opts = append(opts, OnlyMetadata)
+	return blder.Owns(metadataObject(gvk), opts...)
+}
+
 // WatchesInput represents the information set by Watches method.
 type WatchesInput struct {
 	obj              client.Object
@@ -180,6 +213,19 @@ func (blder *Builder) WatchesMetadata(object client.Object, eventHandler handler
 	return blder.Watches(object, eventHandler, opts...)
 }
 
+// WatchesMetadataGVK is the same as WatchesMetadata, but for objects identified by their GVK, e.g.
+// because their Go type is not available. The eventHandler gets metav1.PartialObjectMetadata objects.
+func (blder *Builder) WatchesMetadataGVK(gvk schema.GroupVersionKind, eventHandler handler.EventHandler, opts ...WatchesOption) *Builder {
+	return blder.WatchesMetadata(metadataObject(gvk), eventHandler, opts...)
+}
+
+// metadataObject returns a metav1.PartialObjectMetadata of the given GVK.
+func metadataObject(gvk schema.GroupVersionKind) *metav1.PartialObjectMetadata {
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+	return obj
+}
+
 // WatchesRawSource exposes the lower-level ControllerManagedBy Watches functions through the builder.
 // Specified predicates are registered only for given source.
 //
@@ -187,12 +233,25 @@ func (blder *Builder) WatchesMetadata(object client.Object, eventHandler handler
 // This method is only exposed for more advanced use cases, most users should use one of the higher level functions.
 //
 // WatchesRawSource does not respect predicates configured through WithEventFilter.
+//
+// Use it with source.SingleObject to watch a single named object, e.g. a ConfigMap
+// holding configuration, without caching all objects of its type.
 func (blder *Builder) WatchesRawSource(src source.Source) *Builder {
 	blder.rawSources = append(blder.rawSources, src)
 
 	return blder
 }
 
+// RequiresIndex declares that the Reconciler relies on an index on the given field of object in the
+// manager's cache, e.g. for List calls with a field selector. When the controller starts, it verifies
+// that the index was added, e.g. with the manager's FieldIndexer, and fails with an error naming the
+// missing index otherwise, rather than failing the List calls later on. The index may be added after
+// calling RequiresIndex, as long as it is added before the manager is started.
+func (blder *Builder) RequiresIndex(object client.Object, field string) *Builder {
+	blder.requiredIndexes = append(blder.requiredIndexes, requiredIndex{object: object, field: field})
+	return blder
+}
+
 // WithEventFilter sets the event filters, to filter which create/update/delete/generic events eventually
 // trigger reconciliations. For example, filtering on whether the resource version has changed.
 // Given predicate is added for all watched objects.
@@ -202,6 +261,15 @@ func (blder *Builder) WithEventFilter(p predicate.Predicate) *Builder {
 	return blder
 }
 
+// WithPredicateMetrics makes the predicates of the watches of the controller count the events
+// they accept and reject in the controller_runtime_predicate_evaluations_total metric, labeled
+// with the controller name and the event type, see predicate.Instrumented. Watches without
+// predicates and WatchesRawSource are not instrumented.
+func (blder *Builder) WithPredicateMetrics() *Builder {
+	blder.predicateMetrics = true
+	return blder
+}
+
 // WithOptions overrides the controller options used in doController. Defaults to empty.
 func (blder *Builder) WithOptions(options controller.Options) *Builder {
 	blder.ctrlOptions = options
@@ -214,11 +282,27 @@ func (blder *Builder) WithLogConstructor(logConstructor func(*reconcile.Request)
 	return blder
 }
 
+// WithRecoverPanic sets whether the controller recovers panics of the Reconciler, and
+// returns them as errors, which requeues the request. Otherwise a panic crashes the process,
+// e.g. to make tests fail loudly. It takes precedence over the RecoverPanic of WithOptions
+// and of the manager's controller options.
+//
+// By default, the controller uses the RecoverPanic of WithOptions, or the one of the manager's
+// controller options if that is unset, and doesn't recover panics if both are unset.
+func (blder *Builder) WithRecoverPanic(recoverPanic bool) *Builder {
+	blder.recoverPanic = &recoverPanic
+	return blder
+}
+
 // Named sets the name of the controller to the given name. The name shows up
 // in metrics, among other things, and thus should be a prometheus compatible name
 // (underscores and alphanumeric characters only).
 //
-// By default, controllers are named using the lowercase version of their kind.
+// The name is used as the "controller" value of the logger, the "controller" label of the
+// controller and predicate metrics, and the name of the workqueue and its metrics.
+//
+// By default, controllers are named using the lowercase version of their kind. Controllers
+// for the same kind thus need to be given distinct names, so that they can be told apart.
 func (blder *Builder) Named(name string) *Builder {
 	blder.name = name
 	return blder
@@ -260,13 +344,11 @@ func (blder *Builder) project(obj client.Object, proj objectProjection) (client.
 	case projectAsNormal:
 		return obj, nil
 	case projectAsMetadata:
-		metaObj := &metav1.PartialObjectMetadata{}
 		gvk, err := getGvk(obj, blder.mgr.GetScheme())
 		if err != nil {
 			return nil, fmt.Errorf("unable to determine GVK of %T for a metadata-only watch: %w", obj, err)
 		}
-		metaObj.SetGroupVersionKind(gvk)
-		return metaObj, nil
+		return metadataObject(gvk), nil
 	default:
 		panic(fmt.Sprintf("unexpected projection type %v on type %T, should not be possible since this is an internal field", proj, obj))
 	}
@@ -280,8 +362,12 @@ func (blder *Builder) doWatch() error {
 			return err
 		}
 		hdler := &handler.EnqueueRequestForObject{}
-		allPredicates := append([]predicate.Predicate(nil), blder.globalPredicates...)
-		allPredicates = append(allPredicates, blder.forInput.predicates...)
+		allPredicates := blder.watchPredicates(blder.forInput.predicates)
+		if blder.forInput.cacheOptions != nil {
+			if err := cache.AddByObject(blder.mgr.GetCache(), obj, *blder.forInput.cacheOptions); err != nil {
+				return fmt.Errorf("failed to apply the cache options of %T: %w", obj, err)
+			}
+		}
 		src := source.Kind(blder.mgr.GetCache(), obj, hdler, allPredicates...)
 		if err := blder.ctrl.Watch(src); err != nil {
 			return err
@@ -306,8 +392,7 @@ func (blder *Builder) doWatch() error {
 			blder.forInput.object,
 			opts...,
 		)
-		allPredicates := append([]predicate.Predicate(nil), blder.globalPredicates...)
-		allPredicates = append(allPredicates, own.predicates...)
+		allPredicates := blder.watchPredicates(own.predicates)
 		src := source.Kind(blder.mgr.GetCache(), obj, hdler, allPredicates...)
 		if err := blder.ctrl.Watch(src); err != nil {
 			return err
@@ -323,8 +408,7 @@ func (blder *Builder) doWatch() error {
 		if err != nil {
 			return fmt.Errorf("failed to project for %T: %w", w.obj, err)
 		}
-		allPredicates := append([]predicate.Predicate(nil), blder.globalPredicates...)
-		allPredicates = append(allPredicates, w.predicates...)
+		allPredicates := blder.watchPredicates(w.predicates)
 		if err := blder.ctrl.Watch(source.Kind(blder.mgr.GetCache(), projected, w.handler, allPredicates...)); err != nil {
 			return err
 		}
@@ -334,6 +418,39 @@ func (blder *Builder) doWatch() error {
 			return err
 		}
 	}
+
+	// Verify the required indexes when the controller starts, before any reconcile.
+	if len(blder.requiredIndexes) > 0 {
+		if err := blder.ctrl.Watch(source.Func(blder.verifyRequiredIndexes)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchPredicates returns the global predicates followed by the given ones, combined into
+// a single instrumented predicate if WithPredicateMetrics was used.
+func (blder *Builder) watchPredicates(predicates []predicate.Predicate) []predicate.Predicate {
+	allPredicates := append([]predicate.Predicate(nil), blder.globalPredicates...)
+	allPredicates = append(allPredicates, predicates...)
+	if !blder.predicateMetrics || len(allPredicates) == 0 {
+		return allPredicates
+	}
+	return []predicate.Predicate{predicate.Instrumented(blder.predicateMetricsName, predicate.And(allPredicates...))}
+}
+
+// verifyRequiredIndexes returns an error if any of the indexes declared with RequiresIndex
+// is missing from the manager's cache.
+func (blder *Builder) verifyRequiredIndexes(ctx context.Context, _ workqueue.RateLimitingInterface) error {
+	for _, index := range blder.requiredIndexes {
+		ok, err := cache.HasIndex(ctx, blder.mgr.GetCache(), index.object, index.field)
+		if err != nil {
+			return fmt.Errorf("failed to verify required index on field %q of %T: %w", index.field, index.object, err)
+		}
+		if !ok {
+			return fmt.Errorf("required index on field %q of %T is not registered, add it with the manager's FieldIndexer before starting the manager", index.field, index.object)
+		}
+	}
 	return nil
 }
 
@@ -379,6 +496,11 @@ func (blder *Builder) doController(r reconcile.Reconciler) error {
 		}
 	}
 
+	// Setup panic recovery.
+	if blder.recoverPanic != nil {
+		ctrlOptions.RecoverPanic = blder.recoverPanic
+	}
+
 	// Setup cache sync timeout.
 	if ctrlOptions.CacheSyncTimeout == 0 && globalOpts.CacheSyncTimeout > 0 {
 		ctrlOptions.CacheSyncTimeout = globalOpts.CacheSyncTimeout
@@ -388,6 +510,7 @@ func (blder *Builder) doController(r reconcile.Reconciler) error {
 	if err != nil {
 		return err
 	}
+	blder.predicateMetricsName = controllerName
 
 	// Setup the logger.
 	if ctrlOptions.LogConstructor == nil {
@@ -415,6 +538,24 @@ func (blder *Builder) doController(r reconcile.Reconciler) error {
 		}
 	}
 
+	// Setup the lookup of the reconciled object for the DeletedObjectPolicy and DefaultRequeueAfter.
+	if (ctrlOptions.DeletedObjectPolicy != "" || ctrlOptions.DefaultRequeueAfter > 0) && ctrlOptions.ObjectExists == nil && hasGVK {
+		obj, err := blder.project(blder.forInput.object, blder.forInput.objectProjection)
+		if err != nil {
+			return err
+		}
+		c := blder.mgr.GetClient()
+		ctrlOptions.ObjectExists = func(ctx context.Context, req reconcile.Request) (bool, error) {
+			if err := c.Get(ctx, req.NamespacedName, obj.DeepCopyObject().(client.Object)); err != nil {
+				if apierrors.IsNotFound(err) {
+					return false, nil
+				}
+				return false, err
+			}
+			return true, nil
+		}
+	}
+
 	// Build the controller and return.
 	blder.ctrl, err = newController(controllerName, blder.mgr, ctrlOptions)
 	return err
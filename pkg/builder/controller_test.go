@@ -20,11 +20,15 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -41,8 +45,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/scheme"
 )
@@ -298,6 +304,44 @@ var _ = Describe("application", func() {
 			Expect(instance).NotTo(BeNil())
 		})
 
+		It("should propagate panics of the Reconciler if RecoverPanic is disabled", func() {
+			By("creating a controller manager recovering panics by default")
+			m, err := manager.New(cfg, manager.Options{
+				Controller: config.Controller{RecoverPanic: ptr.To(true)},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			instance, err := ControllerManagedBy(m).
+				For(&appsv1.ReplicaSet{}).
+				WithRecoverPanic(false).
+				Build(reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+					panic("reconcile panic")
+				}))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(func() {
+				_, _ = instance.Reconcile(context.Background(), reconcile.Request{})
+			}).To(PanicWith("reconcile panic"))
+		})
+
+		It("should convert panics of the Reconciler to errors if RecoverPanic is enabled", func() {
+			By("creating a controller manager not recovering panics by default")
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			instance, err := ControllerManagedBy(m).
+				For(&appsv1.ReplicaSet{}).
+				WithOptions(controller.Options{RecoverPanic: ptr.To(false)}).
+				WithRecoverPanic(true).
+				Build(reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+					panic("reconcile panic")
+				}))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = instance.Reconcile(context.Background(), reconcile.Request{})
+			Expect(err).To(MatchError("panic: reconcile panic [recovered]"))
+		})
+
 		It("should not allow multiple reconcilers during creation of controller", func() {
 			newController = func(name string, mgr manager.Manager, options controller.Options) (controller.Controller, error) {
 				if options.Reconciler != (typedNoop{}) {
@@ -346,6 +390,68 @@ var _ = Describe("application", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(ctrl2).NotTo(BeNil())
 		})
+
+		It("should use distinct names for the logger, workqueue and metrics of controllers for the same kind", func() {
+			var (
+				mu     sync.Mutex
+				logged []string
+			)
+			logger := funcr.New(func(prefix, args string) {
+				mu.Lock()
+				defer mu.Unlock()
+				logged = append(logged, args)
+			}, funcr.Options{})
+
+			By("creating a controller manager")
+			m, err := manager.New(cfg, manager.Options{Logger: logger})
+			Expect(err).NotTo(HaveOccurred())
+
+			queueNames := make(chan string, 2)
+			newQueue := func(controllerName string, rateLimiter ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
+				queueNames <- controllerName
+				return workqueue.NewRateLimitingQueueWithConfig(rateLimiter, workqueue.RateLimitingQueueConfig{
+					Name: controllerName,
+				})
+			}
+
+			By("creating two controllers for Deployments with different names")
+			for i, name := range []string{"named_deployment_a", "named_deployment_b"} {
+				ctrl, err := ControllerManagedBy(m).
+					Named(name).
+					For(&appsv1.Deployment{}).
+					WithOptions(controller.Options{MaxConcurrentReconciles: i + 1, NewQueue: newQueue}).
+					Build(noop)
+				Expect(err).NotTo(HaveOccurred())
+				ctrl.GetLogger().Info("test")
+			}
+
+			mu.Lock()
+			Expect(logged).To(ContainElements(
+				ContainSubstring(`"controller"="named_deployment_a"`),
+				ContainSubstring(`"controller"="named_deployment_b"`),
+			))
+			mu.Unlock()
+
+			By("starting the manager")
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).To(Succeed())
+			}()
+
+			var names []string
+			for i := 0; i < 2; i++ {
+				var name string
+				Eventually(queueNames).Should(Receive(&name))
+				names = append(names, name)
+			}
+			Expect(names).To(ConsistOf("named_deployment_a", "named_deployment_b"))
+			Eventually(func() float64 {
+				return testutil.ToFloat64(ctrlmetrics.WorkerCount.WithLabelValues("named_deployment_b"))
+			}).Should(Equal(2.0))
+			Expect(testutil.ToFloat64(ctrlmetrics.WorkerCount.WithLabelValues("named_deployment_a"))).To(Equal(1.0))
+		})
 	})
 
 	Describe("Start with ControllerManagedBy", func() {
@@ -375,6 +481,73 @@ var _ = Describe("application", func() {
 			doReconcileTest(ctx, "12", m, false, bldr)
 		})
 
+		DescribeTable("should Reconcile the owners of an Owns object with a controller and a non-controller reference",
+			func(nameSuffix string, opts []OwnsOption, expectedOwners []string) {
+				m, err := manager.New(cfg, manager.Options{})
+				Expect(err).NotTo(HaveOccurred())
+
+				ch := make(chan reconcile.Request, 10)
+				bldr := ControllerManagedBy(m).
+					For(&corev1.ConfigMap{}).
+					Owns(&corev1.Secret{}, opts...)
+				Expect(bldr.Complete(reconcile.Func(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+					if strings.HasSuffix(req.Name, nameSuffix) {
+						ch <- req
+					}
+					return reconcile.Result{}, nil
+				}))).To(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(ctx)).To(Succeed())
+				}()
+
+				By("Creating the owners")
+				var ownerRefs []metav1.OwnerReference
+				for _, name := range []string{"controller-" + nameSuffix, "owner-" + nameSuffix} {
+					owner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+					Expect(m.GetClient().Create(ctx, owner)).To(Succeed())
+					Eventually(ch).Should(Receive(Equal(reconcile.Request{
+						NamespacedName: types.NamespacedName{Namespace: "default", Name: name}})))
+					ownerRefs = append(ownerRefs, metav1.OwnerReference{
+						APIVersion: "v1",
+						Kind:       "ConfigMap",
+						Name:       name,
+						UID:        owner.UID,
+						Controller: ptr.To(name == "controller-"+nameSuffix),
+					})
+				}
+
+				By("Creating the owned object")
+				owned := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+					Namespace:       "default",
+					Name:            "owned-" + nameSuffix,
+					OwnerReferences: ownerRefs,
+				}}
+				Expect(m.GetClient().Create(ctx, owned)).To(Succeed())
+
+				var expected []reconcile.Request
+				for _, name := range expectedOwners {
+					expected = append(expected, reconcile.Request{
+						NamespacedName: types.NamespacedName{Namespace: "default", Name: name + "-" + nameSuffix}})
+				}
+				var reconciled []reconcile.Request
+				Eventually(func() []reconcile.Request {
+					select {
+					case req := <-ch:
+						reconciled = append(reconciled, req)
+					default:
+					}
+					return reconciled
+				}).Should(ConsistOf(expected))
+				Consistently(ch).ShouldNot(Receive())
+			},
+			Entry("only the controller owner by default", "owns-controller", nil, []string{"controller"}),
+			Entry("every owner with MatchEveryOwner", "owns-every", []OwnsOption{MatchEveryOwner}, []string{"controller", "owner"}),
+		)
+
 		It("should Reconcile Watches objects", func() {
 			m, err := manager.New(cfg, manager.Options{})
 			Expect(err).NotTo(HaveOccurred())
@@ -410,6 +583,89 @@ var _ = Describe("application", func() {
 		})
 	})
 
+	Describe("WithCacheOptions", func() {
+		It("should watch the For object with the given cache options", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			transform := func(in interface{}) (interface{}, error) {
+				if dep, ok := in.(*appsv1.Deployment); ok {
+					dep.Annotations = map[string]string{"transformed": "true"}
+				}
+				return in, nil
+			}
+			// Only reconcile Deployments that went through the transform of the primary informer.
+			onlyTransformed := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				return obj.GetAnnotations()["transformed"] == "true"
+			})
+
+			bldr := ControllerManagedBy(m).
+				For(&appsv1.Deployment{},
+					WithCacheOptions(cache.ByObject{Transform: transform}),
+					WithPredicates(onlyTransformed),
+				).
+				Owns(&appsv1.ReplicaSet{})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			doReconcileTest(ctx, "14", m, true, bldr)
+
+			By("Applying the options to the cache of the manager")
+			dep := &appsv1.Deployment{}
+			Expect(m.GetClient().Get(ctx, types.NamespacedName{Namespace: "default", Name: "deploy-name-14"}, dep)).To(Succeed())
+			Expect(dep.Annotations).To(HaveKeyWithValue("transformed", "true"))
+		})
+
+		It("should fail if the cache of the manager already has an informer for the For object", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			Expect(m.GetFieldIndexer().IndexField(ctx, &appsv1.Deployment{}, "spec.paused", func(obj client.Object) []string {
+				return []string{fmt.Sprint(obj.(*appsv1.Deployment).Spec.Paused)}
+			})).To(Succeed())
+
+			err = ControllerManagedBy(m).
+				For(&appsv1.Deployment{}, WithCacheOptions(cache.ByObject{Transform: cache.TransformStripManagedFields()})).
+				Complete(noop)
+			Expect(err).To(MatchError(ContainSubstring("cache already has an informer")))
+		})
+	})
+
+	Describe("RequiresIndex", func() {
+		It("should fail to start when a required index is missing", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(ControllerManagedBy(m).
+				For(&appsv1.Deployment{}).
+				RequiresIndex(&appsv1.ReplicaSet{}, "spec.missing").
+				Complete(noop)).To(Succeed())
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			Expect(m.Start(ctx)).To(MatchError(ContainSubstring(`required index on field "spec.missing" of *v1.ReplicaSet is not registered`)))
+		})
+
+		It("should Reconcile when a required index is added after declaring it", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			bldr := ControllerManagedBy(m).
+				For(&appsv1.Deployment{}).
+				Owns(&appsv1.ReplicaSet{}).
+				RequiresIndex(&appsv1.ReplicaSet{}, "spec.template.spec.serviceAccountName")
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			Expect(m.GetFieldIndexer().IndexField(ctx, &appsv1.ReplicaSet{}, "spec.template.spec.serviceAccountName", func(obj client.Object) []string {
+				return []string{obj.(*appsv1.ReplicaSet).Spec.Template.Spec.ServiceAccountName}
+			})).To(Succeed())
+			doReconcileTest(ctx, "13", m, true, bldr)
+		})
+	})
+
 	Describe("Set custom predicates", func() {
 		It("should execute registered predicates only for assigned kind", func() {
 			m, err := manager.New(cfg, manager.Options{})
@@ -468,6 +724,26 @@ var _ = Describe("application", func() {
 			Expect(replicaSetPrctExecuted).To(BeTrue(), "ReplicaSet predicated should be called at least once")
 			Expect(allPrctExecuted).To(BeNumerically(">=", 2), "Global Predicated should be called at least twice")
 		})
+
+		It("should count the events accepted by the predicates with WithPredicateMetrics", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			bldr := ControllerManagedBy(m).
+				Named("predicate_metrics").
+				For(&appsv1.Deployment{}, WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+					return strings.HasSuffix(o.GetName(), "predicate-metrics")
+				}))).
+				Owns(&appsv1.ReplicaSet{}).
+				WithPredicateMetrics()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			doReconcileTest(ctx, "predicate-metrics", m, true, bldr)
+
+			Expect(testutil.ToFloat64(ctrlmetrics.PredicateEvaluationsTotal.WithLabelValues("predicate_metrics", "create", "accepted"))).
+				To(BeNumerically(">=", 1))
+		})
 	})
 
 	Describe("watching with projections", func() {
@@ -555,6 +831,63 @@ var _ = Describe("application", func() {
 				return true
 			}).Should(BeTrue())
 		})
+
+		It("should support watching Owns and Watch as metadata by GVK", func() {
+			statefulSetGVK := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}
+			statefulSetMaps := make(chan *metav1.PartialObjectMetadata)
+
+			bldr := ControllerManagedBy(mgr).
+				For(&appsv1.Deployment{}, OnlyMetadata).
+				OwnsMetadata(appsv1.SchemeGroupVersion.WithKind("ReplicaSet")).
+				WatchesMetadataGVK(statefulSetGVK,
+					handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+						defer GinkgoRecover()
+
+						ometa := o.(*metav1.PartialObjectMetadata)
+						Expect(ometa.GroupVersionKind()).To(Equal(statefulSetGVK))
+						statefulSetMaps <- ometa
+						return nil
+					}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			doReconcileTest(ctx, "metadata-gvk", mgr, true, bldr)
+
+			By("Creating a new stateful set")
+			set := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-metadata-gvk",
+					Labels: map[string]string{
+						"foo": "baz",
+					},
+				},
+				Spec: appsv1.StatefulSetSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"foo": "baz"},
+					},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"foo": "baz"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name:  "nginx",
+									Image: "nginx",
+								},
+							},
+						},
+					},
+				},
+			}
+			err := mgr.GetClient().Create(context.TODO(), set)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking that the mapping function gets the labels of the stateful set")
+			Eventually(statefulSetMaps).Should(Receive(And(
+				HaveField("Name", set.Name),
+				HaveField("Labels", set.Labels),
+			)))
+		})
 	})
 })
 
@@ -17,6 +17,7 @@ limitations under the License.
 package builder
 
 import (
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
@@ -154,3 +155,26 @@ type matchEveryOwner struct{}
 func (o matchEveryOwner) ApplyToOwns(opts *OwnsInput) {
 	opts.matchEveryOwner = true
 }
+
+// WithCacheOptions configures the informer the object passed to For is watched with,
+// e.g. to strip large fields with a Transform or to only watch some objects with a
+// label or field selector. The options are added to the ByObject options of the cache
+// of the manager, so they apply to the objects read through its client as well.
+//
+// Building the controller fails if the cache of the manager already has an informer
+// or ByObject options for the object, e.g. because a field index was added for it.
+func WithCacheOptions(opts cache.ByObject) CacheOptions {
+	return CacheOptions{byObject: opts}
+}
+
+// CacheOptions configures the informer the object passed to For is watched with.
+type CacheOptions struct {
+	byObject cache.ByObject
+}
+
+// ApplyToFor applies this configuration to the given ForInput options.
+func (c CacheOptions) ApplyToFor(opts *ForInput) {
+	opts.cacheOptions = &c.byObject
+}
+
+var _ ForOption = CacheOptions{}
@@ -20,7 +20,6 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
-	"strings"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -30,6 +29,7 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 )
@@ -154,7 +154,7 @@ func (blder *WebhookBuilder) registerDefaultingWebhook() {
 	mwh := blder.getDefaultingWebhook()
 	if mwh != nil {
 		mwh.LogConstructor = blder.logConstructor
-		path := generateMutatePath(blder.gvk)
+		path := webhook.MutatePath(blder.gvk)
 
 		// Checking if the path is already registered.
 		// If so, just skip it.
@@ -163,6 +163,7 @@ func (blder *WebhookBuilder) registerDefaultingWebhook() {
 				"GVK", blder.gvk,
 				"path", path)
 			blder.mgr.GetWebhookServer().Register(path, mwh)
+			blder.addRegistration(webhook.MutatingRegistration, path, mwh)
 		}
 	}
 }
@@ -185,7 +186,7 @@ func (blder *WebhookBuilder) registerValidatingWebhook() {
 	vwh := blder.getValidatingWebhook()
 	if vwh != nil {
 		vwh.LogConstructor = blder.logConstructor
-		path := generateValidatePath(blder.gvk)
+		path := webhook.ValidatePath(blder.gvk)
 
 		// Checking if the path is already registered.
 		// If so, just skip it.
@@ -194,6 +195,7 @@ func (blder *WebhookBuilder) registerValidatingWebhook() {
 				"GVK", blder.gvk,
 				"path", path)
 			blder.mgr.GetWebhookServer().Register(path, vwh)
+			blder.addRegistration(webhook.ValidatingRegistration, path, vwh)
 		}
 	}
 }
@@ -245,12 +247,12 @@ func (blder *WebhookBuilder) isAlreadyHandled(path string) bool {
 	return false
 }
 
-func generateMutatePath(gvk schema.GroupVersionKind) string {
-	return "/mutate-" + strings.ReplaceAll(gvk.Group, ".", "-") + "-" +
-		gvk.Version + "-" + strings.ToLower(gvk.Kind)
-}
-
-func generateValidatePath(gvk schema.GroupVersionKind) string {
-	return "/validate-" + strings.ReplaceAll(gvk.Group, ".", "-") + "-" +
-		gvk.Version + "-" + strings.ToLower(gvk.Kind)
+// addRegistration records a webhook registered at path on the webhook server, if the server
+// records registrations like the webhook.DefaultServer does.
+func (blder *WebhookBuilder) addRegistration(typ webhook.RegistrationType, path string, hook http.Handler) {
+	if s, ok := blder.mgr.GetWebhookServer().(interface {
+		AddRegistration(webhook.Registration)
+	}); ok {
+		s.AddRegistration(webhook.Registration{Type: typ, GVK: blder.gvk, Path: path, Handler: hook})
+	}
 }
@@ -39,6 +39,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
@@ -127,7 +128,7 @@ func runTests(admissionReviewVersion string) {
 		}
 
 		By("sending a request to a mutating webhook path")
-		path := generateMutatePath(testDefaulterGVK)
+		path := webhook.MutatePath(testDefaulterGVK)
 		req := httptest.NewRequest("POST", svcBaseAddr+path, reader)
 		req.Header.Add("Content-Type", "application/json")
 		w := httptest.NewRecorder()
@@ -139,7 +140,7 @@ func runTests(admissionReviewVersion string) {
 		ExpectWithOffset(1, w.Body).To(ContainSubstring(`"code":200`))
 
 		By("sending a request to a validating webhook path that doesn't exist")
-		path = generateValidatePath(testDefaulterGVK)
+		path = webhook.ValidatePath(testDefaulterGVK)
 		_, err = reader.Seek(0, 0)
 		ExpectWithOffset(1, err).NotTo(HaveOccurred())
 		req = httptest.NewRequest("POST", svcBaseAddr+path, reader)
@@ -199,7 +200,7 @@ func runTests(admissionReviewVersion string) {
 		}
 
 		By("sending a request to a mutating webhook path")
-		path := generateMutatePath(testDefaulterGVK)
+		path := webhook.MutatePath(testDefaulterGVK)
 		req := httptest.NewRequest("POST", svcBaseAddr+path, reader)
 		req.Header.Add("Content-Type", "application/json")
 		w := httptest.NewRecorder()
@@ -264,7 +265,7 @@ func runTests(admissionReviewVersion string) {
 		}
 
 		By("sending a request to a mutating webhook path")
-		path := generateMutatePath(testDefaulterGVK)
+		path := webhook.MutatePath(testDefaulterGVK)
 		req := httptest.NewRequest("POST", svcBaseAddr+path, reader)
 		req.Header.Add("Content-Type", "application/json")
 		w := httptest.NewRecorder()
@@ -277,7 +278,7 @@ func runTests(admissionReviewVersion string) {
 		EventuallyWithOffset(1, logBuffer).Should(gbytes.Say(`"msg":"Defaulting object","object":{"name":"foo","namespace":"default"},"namespace":"default","name":"foo","resource":{"group":"foo.test.org","version":"v1","resource":"testdefaulter"},"user":"","requestID":"07e52e8d-4513-11e9-a716-42010a800270"`))
 
 		By("sending a request to a validating webhook path that doesn't exist")
-		path = generateValidatePath(testDefaulterGVK)
+		path = webhook.ValidatePath(testDefaulterGVK)
 		_, err = reader.Seek(0, 0)
 		ExpectWithOffset(1, err).NotTo(HaveOccurred())
 		req = httptest.NewRequest("POST", svcBaseAddr+path, reader)
@@ -337,7 +338,7 @@ func runTests(admissionReviewVersion string) {
 		}
 
 		By("sending a request to a mutating webhook path that doesn't exist")
-		path := generateMutatePath(testValidatorGVK)
+		path := webhook.MutatePath(testValidatorGVK)
 		req := httptest.NewRequest("POST", svcBaseAddr+path, reader)
 		req.Header.Add("Content-Type", "application/json")
 		w := httptest.NewRecorder()
@@ -345,7 +346,7 @@ func runTests(admissionReviewVersion string) {
 		ExpectWithOffset(1, w.Code).To(Equal(http.StatusNotFound))
 
 		By("sending a request to a validating webhook path")
-		path = generateValidatePath(testValidatorGVK)
+		path = webhook.ValidatePath(testValidatorGVK)
 		_, err = reader.Seek(0, 0)
 		ExpectWithOffset(1, err).NotTo(HaveOccurred())
 		req = httptest.NewRequest("POST", svcBaseAddr+path, reader)
@@ -407,7 +408,7 @@ func runTests(admissionReviewVersion string) {
 		}
 
 		By("sending a request to a validating webhook path")
-		path := generateValidatePath(testValidatorGVK)
+		path := webhook.ValidatePath(testValidatorGVK)
 		_, err = reader.Seek(0, 0)
 		ExpectWithOffset(1, err).NotTo(HaveOccurred())
 		req := httptest.NewRequest("POST", svcBaseAddr+path, reader)
@@ -476,7 +477,7 @@ func runTests(admissionReviewVersion string) {
 		}
 
 		By("sending a request to a mutating webhook path that doesn't exist")
-		path := generateMutatePath(testValidatorGVK)
+		path := webhook.MutatePath(testValidatorGVK)
 		req := httptest.NewRequest("POST", svcBaseAddr+path, reader)
 		req.Header.Add("Content-Type", "application/json")
 		w := httptest.NewRecorder()
@@ -484,7 +485,7 @@ func runTests(admissionReviewVersion string) {
 		ExpectWithOffset(1, w.Code).To(Equal(http.StatusNotFound))
 
 		By("sending a request to a validating webhook path")
-		path = generateValidatePath(testValidatorGVK)
+		path = webhook.ValidatePath(testValidatorGVK)
 		_, err = reader.Seek(0, 0)
 		ExpectWithOffset(1, err).NotTo(HaveOccurred())
 		req = httptest.NewRequest("POST", svcBaseAddr+path, reader)
@@ -516,6 +517,20 @@ func runTests(admissionReviewVersion string) {
 		svr := m.GetWebhookServer()
 		ExpectWithOffset(1, svr).NotTo(BeNil())
 
+		By("recording the registrations of the webhooks")
+		var registered []webhook.Registration
+		for _, r := range svr.(*webhook.DefaultServer).RegistrationInfo() {
+			if r.GVK == testDefaultValidatorGVK {
+				Expect(r.Handler).NotTo(BeNil())
+				r.Handler = nil
+				registered = append(registered, r)
+			}
+		}
+		ExpectWithOffset(1, registered).To(ConsistOf(
+			webhook.Registration{Type: webhook.MutatingRegistration, GVK: testDefaultValidatorGVK, Path: "/mutate-foo-test-org-v1-testdefaultvalidator"},
+			webhook.Registration{Type: webhook.ValidatingRegistration, GVK: testDefaultValidatorGVK, Path: "/validate-foo-test-org-v1-testdefaultvalidator"},
+		))
+
 		reader := strings.NewReader(admissionReviewGV + admissionReviewVersion + `",
   "request":{
     "uid":"07e52e8d-4513-11e9-a716-42010a800270",
@@ -546,7 +561,7 @@ func runTests(admissionReviewVersion string) {
 		}
 
 		By("sending a request to a mutating webhook path")
-		path := generateMutatePath(testDefaultValidatorGVK)
+		path := webhook.MutatePath(testDefaultValidatorGVK)
 		req := httptest.NewRequest("POST", svcBaseAddr+path, reader)
 		req.Header.Add("Content-Type", "application/json")
 		w := httptest.NewRecorder()
@@ -558,7 +573,7 @@ func runTests(admissionReviewVersion string) {
 		ExpectWithOffset(1, w.Body).To(ContainSubstring(`"code":200`))
 
 		By("sending a request to a validating webhook path")
-		path = generateValidatePath(testDefaultValidatorGVK)
+		path = webhook.ValidatePath(testDefaultValidatorGVK)
 		_, err = reader.Seek(0, 0)
 		ExpectWithOffset(1, err).NotTo(HaveOccurred())
 		req = httptest.NewRequest("POST", svcBaseAddr+path, reader)
@@ -620,7 +635,7 @@ func runTests(admissionReviewVersion string) {
 		}
 
 		By("sending a request to a validating webhook path to check for failed delete")
-		path := generateValidatePath(testValidatorGVK)
+		path := webhook.ValidatePath(testValidatorGVK)
 		req := httptest.NewRequest("POST", svcBaseAddr+path, reader)
 		req.Header.Add("Content-Type", "application/json")
 		w := httptest.NewRecorder()
@@ -652,7 +667,7 @@ func runTests(admissionReviewVersion string) {
   }
 }`)
 		By("sending a request to a validating webhook path with correct request")
-		path = generateValidatePath(testValidatorGVK)
+		path = webhook.ValidatePath(testValidatorGVK)
 		req = httptest.NewRequest("POST", svcBaseAddr+path, reader)
 		req.Header.Add("Content-Type", "application/json")
 		w = httptest.NewRecorder()
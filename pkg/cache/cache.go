@@ -18,8 +18,10 @@ package cache
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"net/http"
+	"reflect"
 	"sort"
 	"time"
 
@@ -27,10 +29,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	toolscache "k8s.io/client-go/tools/cache"
@@ -75,7 +79,9 @@ type Cache interface {
 // GetInformer from multiple threads.
 type Informers interface {
 	// GetInformer fetches or constructs an informer for the given object that corresponds to a single
-	// API kind and resource.
+	// API kind and resource. If ctx is done before the informer has been created or, unless
+	// BlockUntilSynced(false) is passed, has synced, a timeout error is returned; the informer is
+	// still created and synced in the background.
 	GetInformer(ctx context.Context, obj client.Object, opts ...InformerGetOption) (Informer, error)
 
 	// GetInformerForKind is similar to GetInformer, except that it takes a group-version-kind, instead
@@ -127,6 +133,106 @@ type Informer interface {
 	IsStopped() bool
 }
 
+// IndexFieldTyped adds an index with the given field name on the type T to the
+// given FieldIndexer, e.g. a Cache, like FieldIndexer.IndexField. Unlike
+// IndexField, it passes objects to extractValue as T, so that extractValue
+// doesn't have to type-assert them.
+func IndexFieldTyped[T client.Object](ctx context.Context, indexer client.FieldIndexer, field string, extractValue func(T) []string) error {
+	obj := reflect.New(reflect.TypeOf(*new(T)).Elem()).Interface().(T)
+	return indexer.IndexField(ctx, obj, field, func(obj client.Object) []string {
+		typed, ok := obj.(T)
+		if !ok {
+			return nil
+		}
+		return extractValue(typed)
+	})
+}
+
+// UnsyncedBestEffortInformers returns the GVKs of the informers of c that use the
+// InformerStartBestEffort policy and did not sync yet, e.g. to fail a readiness check
+// until they did. It returns nothing for caches that were not created by New.
+func UnsyncedBestEffortInformers(c Cache) []schema.GroupVersionKind {
+	gvks := map[schema.GroupVersionKind]struct{}{}
+	addUnsyncedBestEffortInformers(c, gvks)
+
+	res := maps.Keys(gvks)
+	sort.Slice(res, func(i, j int) bool { return res[i].String() < res[j].String() })
+	return res
+}
+
+func addUnsyncedBestEffortInformers(c Cache, gvks map[schema.GroupVersionKind]struct{}) {
+	switch c := c.(type) {
+	case *informerCache:
+		for _, gvk := range c.Informers.UnsyncedBestEffort() {
+			gvks[gvk] = struct{}{}
+		}
+	case *multiNamespaceCache:
+		for _, cache := range c.namespaceToCache {
+			addUnsyncedBestEffortInformers(cache, gvks)
+		}
+		if c.clusterCache != nil {
+			addUnsyncedBestEffortInformers(c.clusterCache, gvks)
+		}
+	case *delegatingByGVKCache:
+		for _, cache := range c.allCaches() {
+			addUnsyncedBestEffortInformers(cache, gvks)
+		}
+	}
+}
+
+// InformerStatus is the status of the informers of a GVK, see ActiveInformers.
+type InformerStatus struct {
+	// Synced is true if all informers of the GVK have synced.
+	Synced bool
+	// LastResync is the time the informers of the GVK last delivered a periodic resync,
+	// the earliest one if there are several, e.g. for different namespaces. It is zero
+	// if any of them did not resync yet, or unless Options.RecordLastResync is set.
+	LastResync time.Time
+}
+
+// ActiveInformers returns the status of the informers c has created, by GVK. It is
+// safe to call concurrently with the creation of informers. It returns nothing for
+// caches that were not created by New.
+func ActiveInformers(c Cache) map[schema.GroupVersionKind]InformerStatus {
+	res := map[schema.GroupVersionKind]InformerStatus{}
+	addActiveInformers(c, res)
+	return res
+}
+
+func addActiveInformers(c Cache, res map[schema.GroupVersionKind]InformerStatus) {
+	switch c := c.(type) {
+	case *informerCache:
+		for gvk, statuses := range c.Informers.Statuses() {
+			for _, status := range statuses {
+				res[gvk] = mergeInformerStatus(res, gvk, status)
+			}
+		}
+	case *multiNamespaceCache:
+		for _, cache := range c.namespaceToCache {
+			addActiveInformers(cache, res)
+		}
+		if c.clusterCache != nil {
+			addActiveInformers(c.clusterCache, res)
+		}
+	case *delegatingByGVKCache:
+		for _, cache := range c.allCaches() {
+			addActiveInformers(cache, res)
+		}
+	}
+}
+
+func mergeInformerStatus(res map[schema.GroupVersionKind]InformerStatus, gvk schema.GroupVersionKind, status internal.Status) InformerStatus {
+	existing, ok := res[gvk]
+	if !ok {
+		return InformerStatus{Synced: status.Synced, LastResync: status.LastResync}
+	}
+	existing.Synced = existing.Synced && status.Synced
+	if status.LastResync.IsZero() || status.LastResync.Before(existing.LastResync) {
+		existing.LastResync = status.LastResync
+	}
+	return existing
+}
+
 // AllNamespaces should be used as the map key to deliminate namespace settings
 // that apply to all namespaces that themselves do not have explicit settings.
 const AllNamespaces = metav1.NamespaceAll
@@ -169,6 +275,52 @@ type Options struct {
 	// instead of `reconcile.Result{}`.
 	SyncPeriod *time.Duration
 
+	// WaitForSyncBackoff is the backoff with which WaitForCacheSync polls whether the informers
+	// synced, e.g. to poll less often while the informers of a large cluster sync for minutes.
+	// The polling interval starts at Duration, grows by Factor for at most Steps polls or until
+	// it reaches Cap, and stays the same afterwards. Defaults to polling every 100ms.
+	//
+	// While it waits, WaitForCacheSync periodically logs how many of the informers synced.
+	WaitForSyncBackoff *wait.Backoff
+
+	// ResumeStore, if set, persists the resourceVersion each informer last observed, periodically
+	// and when it stops, so that the cache doesn't go back in time when it is recreated, e.g. after
+	// a restart of the manager: the first list of every informer is for a resourceVersion not older
+	// than the stored one, rather than for any resourceVersion, which an API server whose watch
+	// cache lags behind may serve from an older state than the one observed before the restart.
+	//
+	// ResumeStore does not avoid or reduce relists. As the cache is held in memory, informers still
+	// list all objects when they start, unpaginated, which costs the API server as much as the list
+	// they make without it. Informers fall back to listing from scratch if the API server can't
+	// serve the stored resourceVersion, e.g. with a 410 Gone.
+	//
+	// ResumeStore is experimental and subject to future change.
+	ResumeStore ResumeStore
+
+	// OnStore, if set, is called whenever an informer of the cache stores an object, i.e. when the
+	// object is added or updated, with the GroupVersionKind of the informer and the key and the
+	// stored version of the object, e.g. to track the approximate memory the cache uses. Resyncs,
+	// which don't change the stored objects, are skipped.
+	//
+	// OnStore and OnDelete are called after the informer changed its store, from the goroutine
+	// delivering its events to all event handlers, so they must return quickly and must not
+	// modify the object. They can't remove objects from the informers; to limit the objects an
+	// informer stores, use ByObject with selectors, or a Transform dropping unneeded fields.
+	//
+	// OnStore is experimental and subject to future change.
+	OnStore func(gvk schema.GroupVersionKind, key client.ObjectKey, obj client.Object)
+
+	// OnDelete, if set, is called whenever an informer of the cache removes an object, with the
+	// GroupVersionKind of the informer and the key of the object. See OnStore for the details.
+	//
+	// OnDelete is experimental and subject to future change.
+	OnDelete func(gvk schema.GroupVersionKind, key client.ObjectKey)
+
+	// RecordLastResync makes the informers of the cache record when they last delivered a periodic
+	// resync, as reported by ActiveInformers. It adds an event handler to every informer, which is
+	// called for every update. Defaults to false, in which case the LastResync of all informers is zero.
+	RecordLastResync bool
+
 	// ReaderFailOnMissingInformer configures the cache to return a ErrResourceNotCached error when a user
 	// requests, using Get() and List(), a resource the cache does not already have an informer for.
 	//
@@ -226,10 +378,34 @@ type Options struct {
 	// If unset, this will fall through to the Default* settings.
 	ByObject map[client.Object]ByObject
 
+	// InformerStartPolicy sets the InformerStartPolicy per GVK at the specified object.
+	// Informers for objects not in this map use InformerStartRequired.
+	InformerStartPolicy map[client.Object]InformerStartPolicy
+
+	// bestEffortGVKs are the GVKs of the objects with InformerStartBestEffort in InformerStartPolicy.
+	bestEffortGVKs map[schema.GroupVersionKind]bool
+
 	// newInformer allows overriding of NewSharedIndexInformer for testing.
 	newInformer *func(toolscache.ListerWatcher, runtime.Object, time.Duration, toolscache.Indexers) toolscache.SharedIndexInformer
 }
 
+// InformerStartPolicy determines how an informer that fails to start, i.e. to complete its
+// initial list, for example because the API of its resource is temporarily unavailable,
+// affects the cache.
+type InformerStartPolicy string
+
+const (
+	// InformerStartRequired makes WaitForCacheSync, and with it the start of the manager,
+	// block until the informer synced. This is the default.
+	InformerStartRequired InformerStartPolicy = "Required"
+
+	// InformerStartBestEffort makes WaitForCacheSync and GetInformer not wait for the informer
+	// to sync. The informer keeps retrying its initial list with a backoff and starts delivering
+	// events to its handlers once it succeeds. Reads from the cache still wait for the informer
+	// to sync. Use UnsyncedBestEffortInformers to reflect such informers in readiness.
+	InformerStartBestEffort InformerStartPolicy = "BestEffort"
+)
+
 // ByObject offers more fine-grained control over the cache's ListWatch by object.
 type ByObject struct {
 	// Namespaces maps a namespace name to cache configs. If set, only the
@@ -251,6 +427,11 @@ type ByObject struct {
 	// 2. DefaultNamespaces[namespace]
 	// 3. Default*
 	//
+	// This allows to use different selectors for the same type in different
+	// namespaces: a selector set in the Config of a namespace always takes
+	// precedence over the selector of the type, which in turn takes precedence
+	// over the global selectors.
+	//
 	// This must be unset for cluster-scoped objects.
 	Namespaces map[string]Config
 
@@ -272,6 +453,49 @@ type ByObject struct {
 	// Be very careful with this, when enabled you must DeepCopy any object before mutating it,
 	// otherwise you will mutate the object in the cache.
 	UnsafeDisableDeepCopy *bool
+
+	// SyncPeriod overrides the cache's SyncPeriod for the informers of this object.
+	// A nil value means the cache's SyncPeriod is used, a zero value disables resyncs.
+	SyncPeriod *time.Duration
+
+	// ListerWatcher, if set, is called to create the ListerWatcher the informers of this
+	// object list and watch with, instead of the API server, e.g. to inject faults or to
+	// serve synthetic objects in tests, or to list and watch the API server with options
+	// that can't be expressed by Label and Field. The cache still manages the lifecycle
+	// of the informers. See NewListerWatcherFunc.
+	ListerWatcher NewListerWatcherFunc
+
+	// Indexers maps field names to the functions extracting their values, like the ones
+	// passed to IndexField. The informers of this object are created with these indexes,
+	// so that they don't need to be added after the informers started, and can be used
+	// as soon as the cache synced. IndexField must not be called for these fields.
+	Indexers map[string]client.IndexerFunc
+}
+
+// NewListerWatcherFunc creates the ListerWatcher for an informer. obj is the object the informer
+// is for, i.e. a typed, an unstructured or a metadata-only object, and namespace is the namespace it is restricted to, or metav1.NamespaceAll.
+//
+// The selectors configured for the informer are applied to the ListOptions passed to the
+// ListerWatcher, but the ListerWatcher is responsible for honoring them. Like the API server, it must
+// return lists with a resourceVersion the watch can be started from, and objects of the type of obj
+// with a resourceVersion that changes whenever they do, as the informer relies on those to detect
+// updates and to resume watches.
+type NewListerWatcherFunc func(obj runtime.Object, namespace string) (toolscache.ListerWatcher, error)
+
+// ResumeStore persists the resourceVersion the informers of a cache last observed, e.g. in a
+// ConfigMap or on disk, so that they don't start from an older state after a restart, see
+// Options.ResumeStore. Informers are identified by a key like
+// "Deployment.v1.apps", suffixed with "_" and the namespace for informers restricted to one,
+// which only consists of characters valid in ConfigMap keys.
+//
+// ResumeStore is experimental and subject to future change.
+type ResumeStore interface {
+	// ResourceVersion returns the resourceVersion stored for the informer with the given key,
+	// or an empty string if there is none.
+	ResourceVersion(ctx context.Context, key string) (string, error)
+
+	// StoreResourceVersion stores the resourceVersion the informer with the given key last observed.
+	StoreResourceVersion(ctx context.Context, key, resourceVersion string) error
 }
 
 // Config describes all potential options for a given watch.
@@ -298,6 +522,19 @@ type Config struct {
 	// UnsafeDisableDeepCopy specifies if List and Get requests against the
 	// cache should not DeepCopy. A nil value allows to default this.
 	UnsafeDisableDeepCopy *bool
+
+	// SyncPeriod specifies the resync period of the informers. A nil value
+	// allows to default this, ultimately to the cache's SyncPeriod.
+	SyncPeriod *time.Duration
+
+	// ListerWatcher specifies the function creating the ListerWatcher of the
+	// informers. A nil value allows to default this, ultimately to listing and
+	// watching the API server.
+	ListerWatcher NewListerWatcherFunc
+
+	// Indexers specifies the field indexes the informers are created with.
+	// A nil value allows to default this.
+	Indexers map[string]client.IndexerFunc
 }
 
 // NewCacheFunc - Function for creating a new cache from the options and a rest config.
@@ -305,6 +542,9 @@ type NewCacheFunc func(config *rest.Config, opts Options) (Cache, error)
 
 // New initializes and returns a new Cache.
 func New(cfg *rest.Config, opts Options) (Cache, error) {
+	// The ByObject options added with AddByObject are defaulted from the DefaultNamespaces as they
+	// were passed in, like those in opts.
+	defaultNamespaces := maps.Clone(opts.DefaultNamespaces)
 	opts, err := defaultOpts(cfg, opts)
 	if err != nil {
 		return nil, err
@@ -320,14 +560,24 @@ func New(cfg *rest.Config, opts Options) (Cache, error) {
 		defaultCache = newCacheFunc(optionDefaultsToConfig(&opts), corev1.NamespaceAll)
 	}
 
-	if len(opts.ByObject) == 0 {
-		return defaultCache, nil
+	newByObjectCache := func(config ByObject) Cache {
+		if len(config.Namespaces) > 0 {
+			return newMultiNamespaceCache(newCacheFunc, opts.Scheme, opts.Mapper, config.Namespaces, nil)
+		}
+		return newCacheFunc(byObjectToConfig(config), corev1.NamespaceAll)
 	}
 
 	delegating := &delegatingByGVKCache{
 		scheme:       opts.Scheme,
 		caches:       make(map[schema.GroupVersionKind]Cache, len(opts.ByObject)),
 		defaultCache: defaultCache,
+		newCache: func(obj client.Object, config ByObject) (Cache, error) {
+			config, err := defaultByObject(obj, config, &opts, defaultNamespaces)
+			if err != nil {
+				return nil, err
+			}
+			return newByObjectCache(config), nil
+		},
 	}
 
 	for obj, config := range opts.ByObject {
@@ -335,18 +585,24 @@ func New(cfg *rest.Config, opts Options) (Cache, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to get GVK for type %T: %w", obj, err)
 		}
-		var cache Cache
-		if len(config.Namespaces) > 0 {
-			cache = newMultiNamespaceCache(newCacheFunc, opts.Scheme, opts.Mapper, config.Namespaces, nil)
-		} else {
-			cache = newCacheFunc(byObjectToConfig(config), corev1.NamespaceAll)
-		}
-		delegating.caches[gvk] = cache
+		delegating.caches[gvk] = newByObjectCache(config)
 	}
 
 	return delegating, nil
 }
 
+// AddByObject configures how c caches the objects of the type of obj, as if byObject had been
+// set for it in Options.ByObject when c was created with New, e.g. to let the controller that
+// watches obj decide on a Transform. It fails if c already has an informer or ByObject options
+// for the type, if c was already started, or if c was not created by New.
+func AddByObject(c Cache, obj client.Object, byObject ByObject) error {
+	delegating, ok := c.(*delegatingByGVKCache)
+	if !ok {
+		return fmt.Errorf("cache of type %T does not support adding ByObject options", c)
+	}
+	return delegating.addByObject(obj, byObject)
+}
+
 // TransformStripManagedFields strips the managed fields of an object before it is committed to the cache.
 // If you are not explicitly accessing managedFields from your code, setting this as `DefaultTransform`
 // on the cache can lead to a significant reduction in memory usage.
@@ -361,6 +617,63 @@ func TransformStripManagedFields() toolscache.TransformFunc {
 	}
 }
 
+// RedactedSecretValue is the value TransformRedactSecrets replaces the values of Secrets with.
+const RedactedSecretValue = "REDACTED"
+
+// TransformRedactSecrets replaces the values of the data of Secrets with RedactedSecretValue before
+// they are committed to the cache, preserving their keys, to reduce the risk of leaking secret material,
+// e.g. by logging cached objects. It also drops the last-applied-configuration annotation of kubectl,
+// which contains the data. It is meant to be set as the Transform of Secrets in ByObject, other
+// objects are passed through unchanged.
+//
+// WARNING: a redacted Secret must never be written back, as that would replace its data with
+// RedactedSecretValue. TransformRedactSecrets therefore marks the Secrets with client.RedactedAnnotation,
+// which makes the client refuse to create, update or patch them. Controllers that need the data of
+// Secrets, or need to write them, have to read them from the API server, e.g. using the APIReader of
+// the manager.
+func TransformRedactSecrets() toolscache.TransformFunc {
+	return func(in any) (any, error) {
+		switch obj := in.(type) {
+		case *corev1.Secret:
+			for key := range obj.Data {
+				obj.Data[key] = []byte(RedactedSecretValue)
+			}
+			for key := range obj.StringData {
+				obj.StringData[key] = RedactedSecretValue
+			}
+			delete(obj.Annotations, corev1.LastAppliedConfigAnnotation)
+			if obj.Annotations == nil {
+				obj.Annotations = map[string]string{}
+			}
+			obj.Annotations[client.RedactedAnnotation] = "true"
+		case *unstructured.Unstructured:
+			if obj.GroupVersionKind() != corev1.SchemeGroupVersion.WithKind("Secret") {
+				break
+			}
+			redacted := base64.StdEncoding.EncodeToString([]byte(RedactedSecretValue))
+			if data, ok := obj.Object["data"].(map[string]any); ok {
+				for key := range data {
+					data[key] = redacted
+				}
+			}
+			if stringData, ok := obj.Object["stringData"].(map[string]any); ok {
+				for key := range stringData {
+					stringData[key] = RedactedSecretValue
+				}
+			}
+			annotations := obj.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			delete(annotations, corev1.LastAppliedConfigAnnotation)
+			annotations[client.RedactedAnnotation] = "true"
+			obj.SetAnnotations(annotations)
+		}
+
+		return in, nil
+	}
+}
+
 func optionDefaultsToConfig(opts *Options) Config {
 	return Config{
 		LabelSelector:         opts.DefaultLabelSelector,
@@ -376,6 +689,9 @@ func byObjectToConfig(byObject ByObject) Config {
 		FieldSelector:         byObject.Field,
 		Transform:             byObject.Transform,
 		UnsafeDisableDeepCopy: byObject.UnsafeDisableDeepCopy,
+		SyncPeriod:            byObject.SyncPeriod,
+		ListerWatcher:         byObject.ListerWatcher,
+		Indexers:              byObject.Indexers,
 	}
 }
 
@@ -389,16 +705,24 @@ func newCache(restConfig *rest.Config, opts Options) newCacheFunc {
 				HTTPClient:   opts.HTTPClient,
 				Scheme:       opts.Scheme,
 				Mapper:       opts.Mapper,
-				ResyncPeriod: *opts.SyncPeriod,
+				ResyncPeriod: ptr.Deref(config.SyncPeriod, *opts.SyncPeriod),
 				Namespace:    namespace,
 				Selector: internal.Selector{
 					Label: config.LabelSelector,
 					Field: config.FieldSelector,
 				},
 				Transform:             config.Transform,
+				BestEffort:            opts.bestEffortGVKs,
 				WatchErrorHandler:     opts.DefaultWatchErrorHandler,
 				UnsafeDisableDeepCopy: ptr.Deref(config.UnsafeDisableDeepCopy, false),
 				NewInformer:           opts.newInformer,
+				NewListerWatcher:      config.ListerWatcher,
+				Indexers:              fieldIndexers(config.Indexers),
+				WaitForSyncBackoff:    opts.WaitForSyncBackoff,
+				ResumeStore:           opts.ResumeStore,
+				OnStore:               opts.OnStore,
+				OnDelete:              opts.OnDelete,
+				RecordLastResync:      opts.RecordLastResync,
 			}),
 			readerFailOnMissingInformer: opts.ReaderFailOnMissingInformer,
 		}
@@ -435,55 +759,10 @@ func defaultOpts(config *rest.Config, opts Options) (Options, error) {
 	}
 
 	for obj, byObject := range opts.ByObject {
-		isNamespaced, err := apiutil.IsObjectNamespaced(obj, opts.Scheme, opts.Mapper)
+		byObject, err := defaultByObject(obj, byObject, &opts, opts.DefaultNamespaces)
 		if err != nil {
-			return opts, fmt.Errorf("failed to determine if %T is namespaced: %w", obj, err)
+			return opts, err
 		}
-		if !isNamespaced && byObject.Namespaces != nil {
-			return opts, fmt.Errorf("type %T is not namespaced, but its ByObject.Namespaces setting is not nil", obj)
-		}
-
-		if isNamespaced && byObject.Namespaces == nil {
-			byObject.Namespaces = maps.Clone(opts.DefaultNamespaces)
-		}
-
-		// Default the namespace-level configs first, because they need to use the undefaulted type-level config
-		// to be able to potentially fall through to settings from DefaultNamespaces.
-		for namespace, config := range byObject.Namespaces {
-			// 1. Default from the undefaulted type-level config
-			config = defaultConfig(config, byObjectToConfig(byObject))
-
-			// 2. Default from the namespace-level config. This was defaulted from the global default config earlier, but
-			//    might not have an entry for the current namespace.
-			if defaultNamespaceSettings, hasDefaultNamespace := opts.DefaultNamespaces[namespace]; hasDefaultNamespace {
-				config = defaultConfig(config, defaultNamespaceSettings)
-			}
-
-			// 3. Default from the global defaults
-			config = defaultConfig(config, optionDefaultsToConfig(&opts))
-
-			if namespace == metav1.NamespaceAll {
-				config.FieldSelector = fields.AndSelectors(
-					appendIfNotNil(
-						namespaceAllSelector(maps.Keys(byObject.Namespaces)),
-						config.FieldSelector,
-					)...,
-				)
-			}
-
-			byObject.Namespaces[namespace] = config
-		}
-
-		// Only default ByObject iself if it isn't namespaced or has no namespaces configured, as only
-		// then any of this will be honored.
-		if !isNamespaced || len(byObject.Namespaces) == 0 {
-			defaultedConfig := defaultConfig(byObjectToConfig(byObject), optionDefaultsToConfig(&opts))
-			byObject.Label = defaultedConfig.LabelSelector
-			byObject.Field = defaultedConfig.FieldSelector
-			byObject.Transform = defaultedConfig.Transform
-			byObject.UnsafeDisableDeepCopy = defaultedConfig.UnsafeDisableDeepCopy
-		}
-
 		opts.ByObject[obj] = byObject
 	}
 
@@ -503,6 +782,23 @@ func defaultOpts(config *rest.Config, opts Options) (Options, error) {
 		opts.DefaultNamespaces[namespace] = cfg
 	}
 
+	for obj, policy := range opts.InformerStartPolicy {
+		switch policy {
+		case InformerStartRequired:
+		case InformerStartBestEffort:
+			gvk, err := apiutil.GVKForObject(obj, opts.Scheme)
+			if err != nil {
+				return opts, fmt.Errorf("failed to get GVK for %T in InformerStartPolicy: %w", obj, err)
+			}
+			if opts.bestEffortGVKs == nil {
+				opts.bestEffortGVKs = map[schema.GroupVersionKind]bool{}
+			}
+			opts.bestEffortGVKs[gvk] = true
+		default:
+			return opts, fmt.Errorf("unknown InformerStartPolicy %q for %T", policy, obj)
+		}
+	}
+
 	// Default the resync period to 10 hours if unset
 	if opts.SyncPeriod == nil {
 		opts.SyncPeriod = &defaultSyncPeriod
@@ -510,6 +806,62 @@ func defaultOpts(config *rest.Config, opts Options) (Options, error) {
 	return opts, nil
 }
 
+// defaultByObject defaults the ByObject options of obj from opts and the DefaultNamespaces,
+// which must not have been defaulted yet.
+func defaultByObject(obj client.Object, byObject ByObject, opts *Options, defaultNamespaces map[string]Config) (ByObject, error) {
+	isNamespaced, err := apiutil.IsObjectNamespaced(obj, opts.Scheme, opts.Mapper)
+	if err != nil {
+		return byObject, fmt.Errorf("failed to determine if %T is namespaced: %w", obj, err)
+	}
+	if !isNamespaced && byObject.Namespaces != nil {
+		return byObject, fmt.Errorf("type %T is not namespaced, but its ByObject.Namespaces setting is not nil", obj)
+	}
+
+	if isNamespaced && byObject.Namespaces == nil {
+		byObject.Namespaces = maps.Clone(defaultNamespaces)
+	}
+
+	// Default the namespace-level configs first, because they need to use the undefaulted type-level config
+	// to be able to potentially fall through to settings from DefaultNamespaces.
+	for namespace, config := range byObject.Namespaces {
+		// 1. Default from the undefaulted type-level config
+		config = defaultConfig(config, byObjectToConfig(byObject))
+
+		// 2. Default from the namespace-level config. This was defaulted from the global default config earlier, but
+		//    might not have an entry for the current namespace.
+		if defaultNamespaceSettings, hasDefaultNamespace := defaultNamespaces[namespace]; hasDefaultNamespace {
+			config = defaultConfig(config, defaultNamespaceSettings)
+		}
+
+		// 3. Default from the global defaults
+		config = defaultConfig(config, optionDefaultsToConfig(opts))
+
+		if namespace == metav1.NamespaceAll {
+			config.FieldSelector = fields.AndSelectors(
+				appendIfNotNil(
+					namespaceAllSelector(maps.Keys(byObject.Namespaces)),
+					config.FieldSelector,
+				)...,
+			)
+		}
+
+		byObject.Namespaces[namespace] = config
+	}
+
+	// Only default ByObject iself if it isn't namespaced or has no namespaces configured, as only
+	// then any of this will be honored.
+	if !isNamespaced || len(byObject.Namespaces) == 0 {
+		defaultedConfig := defaultConfig(byObjectToConfig(byObject), optionDefaultsToConfig(opts))
+		byObject.Label = defaultedConfig.LabelSelector
+		byObject.Field = defaultedConfig.FieldSelector
+		byObject.Transform = defaultedConfig.Transform
+		byObject.UnsafeDisableDeepCopy = defaultedConfig.UnsafeDisableDeepCopy
+		byObject.SyncPeriod = defaultedConfig.SyncPeriod
+	}
+
+	return byObject, nil
+}
+
 func defaultConfig(toDefault, defaultFrom Config) Config {
 	if toDefault.LabelSelector == nil {
 		toDefault.LabelSelector = defaultFrom.LabelSelector
@@ -523,6 +875,15 @@ func defaultConfig(toDefault, defaultFrom Config) Config {
 	if toDefault.UnsafeDisableDeepCopy == nil {
 		toDefault.UnsafeDisableDeepCopy = defaultFrom.UnsafeDisableDeepCopy
 	}
+	if toDefault.SyncPeriod == nil {
+		toDefault.SyncPeriod = defaultFrom.SyncPeriod
+	}
+	if toDefault.ListerWatcher == nil {
+		toDefault.ListerWatcher = defaultFrom.ListerWatcher
+	}
+	if toDefault.Indexers == nil {
+		toDefault.Indexers = defaultFrom.Indexers
+	}
 
 	return toDefault
 }
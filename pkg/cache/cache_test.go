@@ -37,6 +37,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
 	kscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	kcache "k8s.io/client-go/tools/cache"
@@ -442,6 +443,93 @@ var _ = Describe("Cache with selectors", func() {
 	})
 })
 
+var _ = Describe("Cache with a custom ListerWatcher", func() {
+	var (
+		informerCache       cache.Cache
+		informerCacheCtx    context.Context
+		informerCacheCancel context.CancelFunc
+		namespaces          []string
+	)
+
+	BeforeEach(func() {
+		informerCacheCtx, informerCacheCancel = context.WithCancel(context.Background())
+		cl, err := client.New(cfg, client.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ensureNamespace(testNamespaceOne, cl)).To(Succeed())
+		for _, name := range []string{"lw-foo", "lw-bar"} {
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespaceOne, Name: name}}
+			Expect(cl.Create(informerCacheCtx, cm)).To(Succeed())
+		}
+
+		// The ListerWatcher adds a field selector to the ones configured for the cache.
+		addNameSelector := func(opts *metav1.ListOptions) {
+			selector := fields.OneTermEqualSelector("metadata.name", "lw-foo").String()
+			if opts.FieldSelector != "" {
+				selector = opts.FieldSelector + "," + selector
+			}
+			opts.FieldSelector = selector
+		}
+		namespaces = nil
+		informerCache, err = cache.New(cfg, cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				&corev1.ConfigMap{}: {
+					Field: fields.OneTermEqualSelector("metadata.namespace", testNamespaceOne),
+					ListerWatcher: func(obj runtime.Object, namespace string) (kcache.ListerWatcher, error) {
+						namespaces = append(namespaces, namespace)
+						return &kcache.ListWatch{
+							ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+								addNameSelector(&opts)
+								return clientset.CoreV1().ConfigMaps(namespace).List(informerCacheCtx, opts)
+							},
+							WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+								addNameSelector(&opts)
+								return clientset.CoreV1().ConfigMaps(namespace).Watch(informerCacheCtx, opts)
+							},
+						}, nil
+					},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		go func(ctx context.Context) {
+			defer GinkgoRecover()
+			Expect(informerCache.Start(ctx)).To(Succeed())
+		}(informerCacheCtx)
+		Expect(informerCache.WaitForCacheSync(informerCacheCtx)).To(BeTrue())
+	})
+
+	AfterEach(func() {
+		informerCacheCancel()
+		cl, err := client.New(cfg, client.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		for _, name := range []string{"lw-foo", "lw-bar"} {
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespaceOne, Name: name}}
+			Expect(client.IgnoreNotFound(cl.Delete(context.Background(), cm))).To(Succeed())
+		}
+	})
+
+	It("should only cache the objects the ListerWatcher returns", func() {
+		var cms corev1.ConfigMapList
+		Expect(informerCache.List(informerCacheCtx, &cms)).To(Succeed())
+		Expect(cms.Items).To(HaveLen(1))
+		Expect(cms.Items[0].Name).To(Equal("lw-foo"))
+		Expect(namespaces).To(Equal([]string{metav1.NamespaceAll}))
+
+		By("updating the filtered object")
+		cl, err := client.New(cfg, client.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespaceOne, Name: "lw-bar"}}
+		Expect(cl.Get(informerCacheCtx, client.ObjectKeyFromObject(cm), cm)).To(Succeed())
+		cm.Data = map[string]string{"updated": "true"}
+		Expect(cl.Update(informerCacheCtx, cm)).To(Succeed())
+
+		By("expecting it to never be cached")
+		Consistently(func() error {
+			return informerCache.Get(informerCacheCtx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})
+		}).Should(Satisfy(apierrors.IsNotFound))
+	})
+})
+
 func CacheTestReaderFailOnMissingInformer(createCacheFunc func(config *rest.Config, opts cache.Options) (cache.Cache, error), opts cache.Options) {
 	Describe("Cache test with ReaderFailOnMissingInformer = true", func() {
 		var (
@@ -1794,6 +1882,44 @@ func CacheTest(createCacheFunc func(config *rest.Config, opts cache.Options) (ca
 					}},
 					expectedPods: []string{},
 				}),
+				Entry("type-level label selectors on two namespaces only apply to their namespace", selectorsTestCase{
+					options: cache.Options{ByObject: map[client.Object]cache.ByObject{
+						&corev1.Pod{}: {Namespaces: map[string]cache.Config{
+							testNamespaceTwo: {
+								LabelSelector: labels.SelectorFromSet(map[string]string{
+									"common-label": "common",
+								}),
+							},
+							testNamespaceThree: {
+								LabelSelector: labels.SelectorFromSet(map[string]string{
+									"test-label": "test-pod-2",
+								}),
+							},
+						}},
+					}},
+					// test-pod-4 has the common label, but lives in testNamespaceThree,
+					// test-pod-2 lives in testNamespaceTwo.
+					expectedPods: []string{"test-pod-3"},
+				}),
+				Entry("type-level label selector on namespace overrides type selector, which overrides global selector", selectorsTestCase{
+					options: cache.Options{
+						ByObject: map[client.Object]cache.ByObject{
+							&corev1.Pod{}: {
+								Label: labels.SelectorFromSet(map[string]string{"test-label": "test-pod-5"}),
+								Namespaces: map[string]cache.Config{
+									testNamespaceOne: {},
+									testNamespaceTwo: {
+										LabelSelector: labels.SelectorFromSet(map[string]string{
+											"common-label": "common",
+										}),
+									},
+								},
+							},
+						},
+						DefaultLabelSelector: labels.SelectorFromSet(map[string]string{"does-not": "match-anything"}),
+					},
+					expectedPods: []string{"test-pod-3", "test-pod-5"},
+				}),
 				Entry("global label selector on namespace matches one pod", selectorsTestCase{
 					options: cache.Options{
 						DefaultNamespaces: map[string]cache.Config{
@@ -2441,6 +2567,147 @@ func CacheTest(createCacheFunc func(config *rest.Config, opts cache.Options) (ca
 	})
 }
 
+var _ = Describe("Cache with TransformStripManagedFields", func() {
+	var (
+		informerCache       cache.Cache
+		informerCacheCtx    context.Context
+		informerCacheCancel context.CancelFunc
+		knownPod1           client.Object
+		knownPod2           client.Object
+	)
+
+	BeforeEach(func() {
+		informerCacheCtx, informerCacheCancel = context.WithCancel(context.Background())
+		Expect(cfg).NotTo(BeNil())
+
+		By("creating two pods")
+		cl, err := client.New(cfg, client.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		err = ensureNode(testNodeOne, cl)
+		Expect(err).NotTo(HaveOccurred())
+		err = ensureNamespace(testNamespaceOne, cl)
+		Expect(err).NotTo(HaveOccurred())
+		knownPod1 = createPod("test-pod-1", testNamespaceOne, corev1.RestartPolicyNever)
+		knownPod2 = createPod("test-pod-2", testNamespaceOne, corev1.RestartPolicyAlways)
+
+		By("verifying that the API server sets managed fields")
+		pod := &corev1.Pod{}
+		Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(knownPod1), pod)).To(Succeed())
+		Expect(pod.ManagedFields).NotTo(BeEmpty())
+
+		By("creating the informer cache")
+		informerCache, err = cache.New(cfg, cache.Options{
+			DefaultTransform: cache.TransformStripManagedFields(),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("indexing pods by restart policy")
+		Expect(informerCache.IndexField(context.Background(), &corev1.Pod{}, "spec.restartPolicy", func(obj client.Object) []string {
+			return []string{string(obj.(*corev1.Pod).Spec.RestartPolicy)}
+		})).To(Succeed())
+
+		By("running the cache and waiting for it to sync")
+		// pass as an arg so that we don't race between close and re-assign
+		go func(ctx context.Context) {
+			defer GinkgoRecover()
+			Expect(informerCache.Start(ctx)).To(Succeed())
+		}(informerCacheCtx)
+		Expect(informerCache.WaitForCacheSync(informerCacheCtx)).To(BeTrue())
+	})
+
+	AfterEach(func() {
+		By("cleaning up created pods")
+		deletePod(knownPod1)
+		deletePod(knownPod2)
+
+		informerCacheCancel()
+	})
+
+	It("should not store managed fields in the cache", func() {
+		pod := &corev1.Pod{}
+		Expect(informerCache.Get(context.Background(), client.ObjectKeyFromObject(knownPod1), pod)).To(Succeed())
+		Expect(pod.ManagedFields).To(BeEmpty())
+
+		informer, err := informerCache.GetInformer(context.Background(), &corev1.Pod{})
+		Expect(err).NotTo(HaveOccurred())
+		stored, exists, err := informer.(kcache.SharedIndexInformer).GetStore().GetByKey(testNamespaceOne + "/test-pod-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeTrue())
+		Expect(stored.(*corev1.Pod).ManagedFields).To(BeEmpty())
+	})
+
+	It("should still serve lists from field indexes", func() {
+		pods := &corev1.PodList{}
+		Expect(informerCache.List(context.Background(), pods,
+			client.InNamespace(testNamespaceOne),
+			client.MatchingFields{"spec.restartPolicy": string(corev1.RestartPolicyNever)},
+		)).To(Succeed())
+		Expect(pods.Items).To(HaveLen(1))
+		Expect(pods.Items[0].Name).To(Equal("test-pod-1"))
+		Expect(pods.Items[0].ManagedFields).To(BeEmpty())
+	})
+})
+
+var _ = Describe("IndexFieldTyped", func() {
+	var (
+		informerCache       cache.Cache
+		informerCacheCtx    context.Context
+		informerCacheCancel context.CancelFunc
+		knownPod1           client.Object
+		knownPod2           client.Object
+	)
+
+	BeforeEach(func() {
+		informerCacheCtx, informerCacheCancel = context.WithCancel(context.Background())
+		Expect(cfg).NotTo(BeNil())
+
+		By("creating two pods")
+		cl, err := client.New(cfg, client.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		err = ensureNode(testNodeOne, cl)
+		Expect(err).NotTo(HaveOccurred())
+		err = ensureNamespace(testNamespaceOne, cl)
+		Expect(err).NotTo(HaveOccurred())
+		knownPod1 = createPod("test-pod-1", testNamespaceOne, corev1.RestartPolicyNever)
+		knownPod2 = createPod("test-pod-2", testNamespaceOne, corev1.RestartPolicyAlways)
+
+		By("creating the informer cache")
+		informerCache, err = cache.New(cfg, cache.Options{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		By("cleaning up created pods")
+		deletePod(knownPod1)
+		deletePod(knownPod2)
+
+		informerCacheCancel()
+	})
+
+	It("should serve lists from a typed field index", func() {
+		By("indexing pods by restart policy")
+		Expect(cache.IndexFieldTyped(context.Background(), informerCache, "spec.restartPolicy", func(pod *corev1.Pod) []string {
+			return []string{string(pod.Spec.RestartPolicy)}
+		})).To(Succeed())
+
+		By("running the cache and waiting for it to sync")
+		go func(ctx context.Context) {
+			defer GinkgoRecover()
+			Expect(informerCache.Start(ctx)).To(Succeed())
+		}(informerCacheCtx)
+		Expect(informerCache.WaitForCacheSync(informerCacheCtx)).To(BeTrue())
+
+		By("listing pods matching the index")
+		pods := &corev1.PodList{}
+		Expect(informerCache.List(context.Background(), pods,
+			client.InNamespace(testNamespaceOne),
+			client.MatchingFields{"spec.restartPolicy": string(corev1.RestartPolicyAlways)},
+		)).To(Succeed())
+		Expect(pods.Items).To(HaveLen(1))
+		Expect(pods.Items[0].Name).To(Equal("test-pod-2"))
+	})
+})
+
 var _ = Describe("TransformStripManagedFields", func() {
 	It("should strip managed fields from an object", func() {
 		obj := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
@@ -2460,6 +2727,101 @@ var _ = Describe("TransformStripManagedFields", func() {
 	})
 })
 
+var _ = Describe("TransformRedactSecrets", func() {
+	It("should redact the values of a Secret and preserve its keys", func() {
+		obj := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Annotations: map[string]string{
+				corev1.LastAppliedConfigAnnotation: `{"data":{"password":"c2VjcmV0"}}`,
+				"keep":                             "me",
+			}},
+			Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("secret")},
+			StringData: map[string]string{"token": "secret"},
+		}
+		transformed, err := cache.TransformRedactSecrets()(obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transformed).To(Equal(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Annotations: map[string]string{
+				"keep":                    "me",
+				client.RedactedAnnotation: "true",
+			}},
+			Data: map[string][]byte{
+				"username": []byte(cache.RedactedSecretValue),
+				"password": []byte(cache.RedactedSecretValue),
+			},
+			StringData: map[string]string{"token": cache.RedactedSecretValue},
+		}))
+	})
+
+	It("should redact the values of an unstructured Secret and preserve its keys", func() {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]any{"name": "foo"},
+			"data":       map[string]any{"password": "c2VjcmV0"},
+		}}
+		transformed, err := cache.TransformRedactSecrets()(obj)
+		Expect(err).NotTo(HaveOccurred())
+
+		secret := &corev1.Secret{}
+		Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(transformed.(*unstructured.Unstructured).Object, secret)).To(Succeed())
+		Expect(secret.Data).To(Equal(map[string][]byte{"password": []byte(cache.RedactedSecretValue)}))
+		Expect(secret.Annotations).To(Equal(map[string]string{client.RedactedAnnotation: "true"}))
+	})
+
+	It("should not modify other objects", func() {
+		obj := &corev1.ConfigMap{Data: map[string]string{"foo": "bar"}}
+		transformed, err := cache.TransformRedactSecrets()(obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transformed).To(Equal(&corev1.ConfigMap{Data: map[string]string{"foo": "bar"}}))
+
+		transformed, err = cache.TransformRedactSecrets()("foo")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transformed).To(Equal("foo"))
+	})
+
+	It("should redact Secrets in the cache", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cl, err := client.New(cfg, client.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ensureNamespace(testNamespaceOne, cl)).To(Succeed())
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNamespaceOne, Name: "redacted-secret"},
+			Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("secret")},
+		}
+		Expect(cl.Create(ctx, secret)).To(Succeed())
+		defer func() {
+			Expect(cl.Delete(context.Background(), secret)).To(Succeed())
+		}()
+
+		c, err := cache.New(cfg, cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				&corev1.Secret{}: {Transform: cache.TransformRedactSecrets()},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		go func() {
+			defer GinkgoRecover()
+			Expect(c.Start(ctx)).To(Succeed())
+		}()
+		Expect(c.WaitForCacheSync(ctx)).To(BeTrue())
+
+		cached := &corev1.Secret{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(secret), cached)).To(Succeed())
+		Expect(cached.Data).To(Equal(map[string][]byte{
+			"username": []byte(cache.RedactedSecretValue),
+			"password": []byte(cache.RedactedSecretValue),
+		}))
+
+		By("refusing to write the redacted Secret back")
+		Expect(cl.Update(ctx, cached)).NotTo(Succeed())
+		Expect(cl.Patch(ctx, cached, client.MergeFrom(cached.DeepCopy()))).NotTo(Succeed())
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(secret), secret)).To(Succeed())
+		Expect(secret.Data).To(Equal(map[string][]byte{"username": []byte("admin"), "password": []byte("secret")}))
+	})
+})
+
 // ensureNamespace installs namespace of a given name if not exists.
 func ensureNamespace(namespace string, client client.Client) error {
 	ns := corev1.Namespace{
@@ -17,7 +17,12 @@ limitations under the License.
 package cache
 
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -26,13 +31,17 @@ import (
 	fuzz "github.com/google/gofuzz"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
 )
 
 func TestDefaultOpts(t *testing.T) {
@@ -83,6 +92,26 @@ func TestDefaultOpts(t *testing.T) {
 				return cmp.Diff(expected, o.ByObject[pod].Namespaces)
 			},
 		},
+		{
+			name: "ByObject.Namespaces gets SyncPeriod defaulted from ByObject",
+			in: Options{
+				ByObject: map[client.Object]ByObject{pod: {
+					Namespaces: map[string]Config{
+						"default": {},
+						"other":   {SyncPeriod: ptr.To(time.Duration(0))},
+					},
+					SyncPeriod: ptr.To(time.Minute),
+				}},
+			},
+
+			verification: func(o Options) string {
+				expected := map[string]Config{
+					"default": {SyncPeriod: ptr.To(time.Minute)},
+					"other":   {SyncPeriod: ptr.To(time.Duration(0))},
+				}
+				return cmp.Diff(expected, o.ByObject[pod].Namespaces)
+			},
+		},
 		{
 			name: "ByObject.Namespaces gets defaulted from DefaultNamespaces",
 			in: Options{
@@ -352,6 +381,29 @@ func TestDefaultOpts(t *testing.T) {
 				return compare(expected, o)
 			},
 		},
+		{
+			name: "Two namespaces in ByObject.Namespaces with different selectors take precedence over ByObject and global selectors",
+			in: Options{
+				ByObject: map[client.Object]ByObject{pod: {
+					Label: labels.SelectorFromSet(map[string]string{"from": "pod"}),
+					Namespaces: map[string]Config{
+						"kube-public": {LabelSelector: labels.SelectorFromSet(map[string]string{"from": "kube-public"})},
+						"kube-system": {LabelSelector: labels.SelectorFromSet(map[string]string{"from": "kube-system"})},
+						"default":     {},
+					},
+				}},
+				DefaultLabelSelector: labels.SelectorFromSet(map[string]string{"from": "default-label-selector"}),
+			},
+
+			verification: func(o Options) string {
+				expected := map[string]Config{
+					"kube-public": {LabelSelector: labels.SelectorFromSet(map[string]string{"from": "kube-public"})},
+					"kube-system": {LabelSelector: labels.SelectorFromSet(map[string]string{"from": "kube-system"})},
+					"default":     {LabelSelector: labels.SelectorFromSet(map[string]string{"from": "pod"})},
+				}
+				return compare(expected, o.ByObject[pod].Namespaces)
+			},
+		},
 		{
 			name: "DefaultNamespace label selector doesn't get defaulted when set",
 			in: Options{
@@ -390,6 +442,20 @@ func TestDefaultOpts(t *testing.T) {
 				return compare(expected, o)
 			},
 		},
+		{
+			name: "InformerStartPolicy BestEffort is resolved to the GVK of the object",
+			in: Options{
+				InformerStartPolicy: map[client.Object]InformerStartPolicy{
+					pod:                 InformerStartBestEffort,
+					&corev1.ConfigMap{}: InformerStartRequired,
+				},
+			},
+
+			verification: func(o Options) string {
+				expected := map[schema.GroupVersionKind]bool{corev1.SchemeGroupVersion.WithKind("Pod"): true}
+				return cmp.Diff(expected, o.bestEffortGVKs)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -408,6 +474,18 @@ func TestDefaultOpts(t *testing.T) {
 	}
 }
 
+func TestDefaultOptsRejectsUnknownInformerStartPolicy(t *testing.T) {
+	t.Parallel()
+
+	_, err := defaultOpts(&rest.Config{}, Options{
+		Mapper:              &fakeRESTMapper{},
+		InformerStartPolicy: map[client.Object]InformerStartPolicy{&corev1.Pod{}: "Sometimes"},
+	})
+	if err == nil || !strings.Contains(err.Error(), `unknown InformerStartPolicy "Sometimes"`) {
+		t.Errorf("expected an unknown InformerStartPolicy error, got %v", err)
+	}
+}
+
 type fakeRESTMapper struct {
 	meta.RESTMapper
 }
@@ -416,6 +494,287 @@ func (f *fakeRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*
 	return &meta.RESTMapping{Scope: meta.RESTScopeNamespace}, nil
 }
 
+func TestSyncPeriodByObject(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		resyncs = map[reflect.Type]time.Duration{}
+	)
+	newInformer := func(_ cache.ListerWatcher, obj runtime.Object, resync time.Duration, _ cache.Indexers) cache.SharedIndexInformer {
+		mu.Lock()
+		defer mu.Unlock()
+		resyncs[reflect.TypeOf(obj)] = resync
+		return &controllertest.FakeInformer{}
+	}
+
+	c, err := New(&rest.Config{Host: "https://localhost"}, Options{
+		Mapper:     &fakeRESTMapper{},
+		SyncPeriod: ptr.To(time.Hour),
+		ByObject: map[client.Object]ByObject{
+			&corev1.Pod{}:       {SyncPeriod: ptr.To(time.Minute)},
+			&corev1.Secret{}:    {SyncPeriod: ptr.To(time.Duration(0))},
+			&corev1.ConfigMap{}: {},
+		},
+		newInformer: &newInformer,
+	})
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+
+	for _, tc := range []struct {
+		obj    client.Object
+		resync time.Duration
+	}{
+		{obj: &corev1.Pod{}, resync: time.Minute},
+		{obj: &corev1.Secret{}, resync: 0},
+		{obj: &corev1.ConfigMap{}, resync: time.Hour},
+		{obj: &corev1.Service{}, resync: time.Hour},
+	} {
+		if _, err := c.GetInformer(context.Background(), tc.obj, BlockUntilSynced(false)); err != nil {
+			t.Fatalf("failed to get informer for %T: %v", tc.obj, err)
+		}
+
+		mu.Lock()
+		resync := resyncs[reflect.TypeOf(tc.obj)]
+		mu.Unlock()
+		// The resync period gets a jitter of [0.9, 1.1) applied.
+		if float64(resync) < 0.9*float64(tc.resync) || float64(resync) > 1.1*float64(tc.resync) {
+			t.Errorf("expected informer for %T to have a resync period of about %s, got %s", tc.obj, tc.resync, resync)
+		}
+	}
+}
+
+func TestListerWatcherByObject(t *testing.T) {
+	t.Parallel()
+
+	pods := &corev1.PodList{
+		ListMeta: metav1.ListMeta{ResourceVersion: "2"},
+		Items: []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo", ResourceVersion: "1"}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bar", ResourceVersion: "2"}},
+		},
+	}
+	var (
+		mu         sync.Mutex
+		namespaces []string
+	)
+	c, err := New(&rest.Config{Host: "https://localhost"}, Options{
+		Mapper: &fakeRESTMapper{},
+		ByObject: map[client.Object]ByObject{
+			&corev1.Pod{}: {
+				ListerWatcher: func(obj runtime.Object, namespace string) (cache.ListerWatcher, error) {
+					if _, ok := obj.(*corev1.Pod); !ok {
+						return nil, fmt.Errorf("unexpected object %T", obj)
+					}
+					mu.Lock()
+					defer mu.Unlock()
+					namespaces = append(namespaces, namespace)
+					return &cache.ListWatch{
+						ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+							return pods.DeepCopy(), nil
+						},
+						WatchFunc: func(metav1.ListOptions) (watch.Interface, error) {
+							return watch.NewFake(), nil
+						},
+					}, nil
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = c.Start(ctx)
+	}()
+	if !c.WaitForCacheSync(ctx) {
+		t.Fatal("failed to wait for the cache to sync")
+	}
+
+	list := &corev1.PodList{}
+	if err := c.List(ctx, list); err != nil {
+		t.Fatalf("failed to list pods: %v", err)
+	}
+	names := make([]string, 0, len(list.Items))
+	for _, pod := range list.Items {
+		names = append(names, pod.Name)
+	}
+	sort.Strings(names)
+	if diff := cmp.Diff([]string{"bar", "foo"}, names); diff != "" {
+		t.Errorf("unexpected pods listed: %s", diff)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if diff := cmp.Diff([]string{metav1.NamespaceAll}, namespaces); diff != "" {
+		t.Errorf("unexpected namespaces the ListerWatcher was created for: %s", diff)
+	}
+}
+
+func TestAddByObject(t *testing.T) {
+	t.Parallel()
+
+	pods := &corev1.PodList{
+		ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+		Items: []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo", ResourceVersion: "1"}},
+		},
+	}
+	var (
+		mu         sync.Mutex
+		namespaces []string
+	)
+	c, err := New(&rest.Config{Host: "https://localhost"}, Options{
+		Mapper:            &fakeRESTMapper{},
+		DefaultNamespaces: map[string]Config{"default": {}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+
+	if err := AddByObject(c, &corev1.Pod{}, ByObject{
+		ListerWatcher: func(_ runtime.Object, namespace string) (cache.ListerWatcher, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			namespaces = append(namespaces, namespace)
+			return &cache.ListWatch{
+				ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+					return pods.DeepCopy(), nil
+				},
+				WatchFunc: func(metav1.ListOptions) (watch.Interface, error) {
+					return watch.NewFake(), nil
+				},
+			}, nil
+		},
+	}); err != nil {
+		t.Fatalf("failed to add ByObject options for pods: %v", err)
+	}
+	if err := AddByObject(c, &corev1.Pod{}, ByObject{}); err == nil || !strings.Contains(err.Error(), "already has ByObject options") {
+		t.Errorf("expected adding ByObject options for pods twice to fail, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := c.GetInformer(ctx, &corev1.ConfigMap{}, BlockUntilSynced(false)); err != nil {
+		t.Fatalf("failed to get informer for configmaps: %v", err)
+	}
+	if err := AddByObject(c, &corev1.ConfigMap{}, ByObject{}); err == nil || !strings.Contains(err.Error(), "already has an informer") {
+		t.Errorf("expected adding ByObject options for configmaps with an existing informer to fail, got %v", err)
+	}
+	if err := c.RemoveInformer(ctx, &corev1.ConfigMap{}); err != nil {
+		t.Fatalf("failed to remove informer for configmaps: %v", err)
+	}
+
+	go func() {
+		_ = c.Start(ctx)
+	}()
+	if !c.WaitForCacheSync(ctx) {
+		t.Fatal("failed to wait for the cache to sync")
+	}
+	list := &corev1.PodList{}
+	if err := c.List(ctx, list); err != nil {
+		t.Fatalf("failed to list pods: %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Name != "foo" {
+		t.Errorf("expected to list pod foo, got %v", list.Items)
+	}
+	mu.Lock()
+	if diff := cmp.Diff([]string{"default"}, namespaces); diff != "" {
+		t.Errorf("expected the ByObject options to be defaulted from DefaultNamespaces: %s", diff)
+	}
+	mu.Unlock()
+
+	if err := AddByObject(c, &corev1.Secret{}, ByObject{}); err == nil || !strings.Contains(err.Error(), "already started") {
+		t.Errorf("expected adding ByObject options to a started cache to fail, got %v", err)
+	}
+}
+
+func TestIndexersByObject(t *testing.T) {
+	t.Parallel()
+
+	pods := &corev1.PodList{
+		ListMeta: metav1.ListMeta{ResourceVersion: "3"},
+		Items: []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo", ResourceVersion: "1"}, Spec: corev1.PodSpec{NodeName: "node-a"}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bar", ResourceVersion: "2"}, Spec: corev1.PodSpec{NodeName: "node-b"}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "baz", ResourceVersion: "3"}, Spec: corev1.PodSpec{NodeName: "node-a"}},
+		},
+	}
+	var (
+		mu    sync.Mutex
+		lists int
+	)
+	c, err := New(&rest.Config{Host: "https://localhost"}, Options{
+		Mapper: &fakeRESTMapper{},
+		ByObject: map[client.Object]ByObject{
+			&corev1.Pod{}: {
+				ListerWatcher: func(runtime.Object, string) (cache.ListerWatcher, error) {
+					return &cache.ListWatch{
+						ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+							mu.Lock()
+							defer mu.Unlock()
+							lists++
+							return pods.DeepCopy(), nil
+						},
+						WatchFunc: func(metav1.ListOptions) (watch.Interface, error) {
+							return watch.NewFake(), nil
+						},
+					}, nil
+				},
+				Indexers: map[string]client.IndexerFunc{
+					"spec.nodeName": func(obj client.Object) []string {
+						return []string{obj.(*corev1.Pod).Spec.NodeName}
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = c.Start(ctx)
+	}()
+	if !c.WaitForCacheSync(ctx) {
+		t.Fatal("failed to wait for the cache to sync")
+	}
+
+	for _, tc := range []struct {
+		opts     []client.ListOption
+		expected []string
+	}{
+		{opts: []client.ListOption{client.MatchingFields{"spec.nodeName": "node-a"}}, expected: []string{"baz", "foo"}},
+		{opts: []client.ListOption{client.MatchingFields{"spec.nodeName": "node-a"}, client.InNamespace("default")}, expected: []string{"foo"}},
+		{opts: []client.ListOption{client.MatchingFields{"spec.nodeName": "node-c"}}, expected: []string{}},
+	} {
+		list := &corev1.PodList{}
+		if err := c.List(ctx, list, tc.opts...); err != nil {
+			t.Fatalf("failed to list pods: %v", err)
+		}
+		names := make([]string, 0, len(list.Items))
+		for _, pod := range list.Items {
+			names = append(names, pod.Name)
+		}
+		sort.Strings(names)
+		if diff := cmp.Diff(tc.expected, names); diff != "" {
+			t.Errorf("unexpected pods listed: %s", diff)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if lists != 1 {
+		t.Errorf("expected the pods to be listed once, got %d lists", lists)
+	}
+}
+
 func TestDefaultConfigConsidersAllFields(t *testing.T) {
 	t.Parallel()
 	seed := time.Now().UnixNano()
@@ -430,6 +789,13 @@ func TestDefaultConfigConsidersAllFields(t *testing.T) {
 		func(tf *cache.TransformFunc, _ fuzz.Continue) {
 			// never default this, as functions can not be compared so we fail down the line
 		},
+		func(lw *NewListerWatcherFunc, _ fuzz.Continue) {
+			// never default this, as functions can not be compared so we fail down the line
+		},
+		func(idx *map[string]client.IndexerFunc, _ fuzz.Continue) {
+			// functions can only be compared if they are nil
+			*idx = map[string]client.IndexerFunc{"spec.foo": nil}
+		},
 	)
 
 	for i := 0; i < 100; i++ {
@@ -18,6 +18,8 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"sync"
 
@@ -32,8 +34,14 @@ import (
 // and uses the defaultCache otherwise.
 type delegatingByGVKCache struct {
 	scheme       *runtime.Scheme
-	caches       map[schema.GroupVersionKind]Cache
 	defaultCache Cache
+
+	// newCache creates the type-specific cache for the given ByObject options, see AddByObject.
+	newCache func(obj client.Object, byObject ByObject) (Cache, error)
+
+	mu      sync.RWMutex
+	caches  map[schema.GroupVersionKind]Cache
+	started bool
 }
 
 func (dbt *delegatingByGVKCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
@@ -73,8 +81,10 @@ func (dbt *delegatingByGVKCache) GetInformerForKind(ctx context.Context, gvk sch
 }
 
 func (dbt *delegatingByGVKCache) Start(ctx context.Context) error {
-	allCaches := maps.Values(dbt.caches)
-	allCaches = append(allCaches, dbt.defaultCache)
+	dbt.mu.Lock()
+	dbt.started = true
+	dbt.mu.Unlock()
+	allCaches := dbt.allCaches()
 
 	wg := &sync.WaitGroup{}
 	errs := make(chan error)
@@ -100,7 +110,7 @@ func (dbt *delegatingByGVKCache) Start(ctx context.Context) error {
 
 func (dbt *delegatingByGVKCache) WaitForCacheSync(ctx context.Context) bool {
 	synced := true
-	for _, cache := range append(maps.Values(dbt.caches), dbt.defaultCache) {
+	for _, cache := range dbt.allCaches() {
 		if !cache.WaitForCacheSync(ctx) {
 			synced = false
 		}
@@ -109,6 +119,12 @@ func (dbt *delegatingByGVKCache) WaitForCacheSync(ctx context.Context) bool {
 	return synced
 }
 
+// NeedLeaderElection implements the LeaderElectionRunnable interface
+// to indicate that this can be started without requiring the leader lock.
+func (dbt *delegatingByGVKCache) NeedLeaderElection() bool {
+	return false
+}
+
 func (dbt *delegatingByGVKCache) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
 	cache, err := dbt.cacheForObject(obj)
 	if err != nil {
@@ -127,9 +143,45 @@ func (dbt *delegatingByGVKCache) cacheForObject(o runtime.Object) (Cache, error)
 }
 
 func (dbt *delegatingByGVKCache) cacheForGVK(gvk schema.GroupVersionKind) Cache {
+	dbt.mu.RLock()
+	defer dbt.mu.RUnlock()
 	if specific, hasSpecific := dbt.caches[gvk]; hasSpecific {
 		return specific
 	}
 
 	return dbt.defaultCache
 }
+
+// allCaches returns the type-specific caches followed by the default cache.
+func (dbt *delegatingByGVKCache) allCaches() []Cache {
+	dbt.mu.RLock()
+	defer dbt.mu.RUnlock()
+	return append(maps.Values(dbt.caches), dbt.defaultCache)
+}
+
+// addByObject adds a type-specific cache for the type of obj with the given ByObject options,
+// unless the default cache already has an informer for it.
+func (dbt *delegatingByGVKCache) addByObject(obj client.Object, byObject ByObject) error {
+	gvk, err := apiutil.GVKForObject(obj, dbt.scheme)
+	if err != nil {
+		return err
+	}
+
+	dbt.mu.Lock()
+	defer dbt.mu.Unlock()
+	if dbt.started {
+		return errors.New("cache was already started")
+	}
+	if _, ok := dbt.caches[gvk]; ok {
+		return fmt.Errorf("cache already has ByObject options for %s", gvk)
+	}
+	if _, ok := ActiveInformers(dbt.defaultCache)[gvk]; ok {
+		return fmt.Errorf("cache already has an informer for %s", gvk)
+	}
+	cache, err := dbt.newCache(obj, byObject)
+	if err != nil {
+		return err
+	}
+	dbt.caches[gvk] = cache
+	return nil
+}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/controller-runtime/pkg/cache/internal"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -187,7 +188,8 @@ func (ic *informerCache) getInformerForKind(ctx context.Context, gvk schema.Grou
 		return started, cache, nil
 	}
 
-	return ic.Informers.Get(ctx, gvk, obj, &internal.GetOptions{})
+	// Reads always wait for the informer to sync, even with InformerStartBestEffort.
+	return ic.Informers.Get(ctx, gvk, obj, &internal.GetOptions{BlockUntilSynced: ptr.To(true)})
 }
 
 // RemoveInformer deactivates and removes the informer from the cache.
@@ -221,7 +223,24 @@ func (ic *informerCache) IndexField(ctx context.Context, obj client.Object, fiel
 }
 
 func indexByField(informer Informer, field string, extractValue client.IndexerFunc) error {
-	indexFunc := func(objRaw interface{}) ([]string, error) {
+	return informer.AddIndexers(cache.Indexers{internal.FieldIndexName(field): fieldIndexFunc(extractValue)})
+}
+
+// fieldIndexers returns the informer indexers for the given fields, see ByObject.Indexers.
+func fieldIndexers(extractors map[string]client.IndexerFunc) cache.Indexers {
+	if len(extractors) == 0 {
+		return nil
+	}
+	indexers := make(cache.Indexers, len(extractors))
+	for field, extractValue := range extractors {
+		indexers[internal.FieldIndexName(field)] = fieldIndexFunc(extractValue)
+	}
+	return indexers
+}
+
+// fieldIndexFunc returns the informer index func of a field whose values extractValue extracts.
+func fieldIndexFunc(extractValue client.IndexerFunc) cache.IndexFunc {
+	return func(objRaw interface{}) ([]string, error) {
 		// TODO(directxman12): check if this is the correct type?
 		obj, isObj := objRaw.(client.Object)
 		if !isObj {
@@ -255,6 +274,34 @@ func indexByField(informer Informer, field string, extractValue client.IndexerFu
 
 		return vals, nil
 	}
+}
 
-	return informer.AddIndexers(cache.Indexers{internal.FieldIndexName(field): indexFunc})
+// HasIndex returns whether the informer of c for the type of obj has an index on the given
+// field, e.g. to verify that the indexes a controller relies on for List calls with field
+// selectors were added with IndexField. It creates the informer if it doesn't exist yet,
+// without waiting for it to sync.
+func HasIndex(ctx context.Context, c Cache, obj client.Object, field string) (bool, error) {
+	informer, err := c.GetInformer(ctx, obj, BlockUntilSynced(false))
+	if err != nil {
+		return false, err
+	}
+	return informerHasIndex(informer, internal.FieldIndexName(field))
+}
+
+func informerHasIndex(informer Informer, indexName string) (bool, error) {
+	switch i := informer.(type) {
+	case *multiNamespaceInformer:
+		for _, informer := range i.namespaceToInformer {
+			if ok, err := informerHasIndex(informer, indexName); err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case cache.SharedIndexInformer:
+		if indexer := i.GetIndexer(); indexer != nil {
+			_, ok := indexer.GetIndexers()[indexName]
+			return ok, nil
+		}
+	}
+	return false, fmt.Errorf("unable to determine the indexes of informer %T", informer)
 }
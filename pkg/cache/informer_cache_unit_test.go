@@ -26,8 +26,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
+	toolscache "k8s.io/client-go/tools/cache"
 
 	"sigs.k8s.io/controller-runtime/pkg/cache/internal"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
 	crscheme "sigs.k8s.io/controller-runtime/pkg/scheme"
 )
@@ -107,3 +109,37 @@ var _ = Describe("ip.objectTypeForListObject", func() {
 		})
 	})
 })
+
+var _ = Describe("informerHasIndex", func() {
+	newInformer := func() toolscache.SharedIndexInformer {
+		return toolscache.NewSharedIndexInformer(&toolscache.ListWatch{}, &corev1.Pod{}, 0, toolscache.Indexers{})
+	}
+	indexFunc := func(obj client.Object) []string {
+		return []string{obj.(*corev1.Pod).Spec.NodeName}
+	}
+
+	It("should report whether a field is indexed", func() {
+		informer := newInformer()
+		Expect(informerHasIndex(informer, internal.FieldIndexName("spec.nodeName"))).To(BeFalse())
+
+		Expect(indexByField(informer, "spec.nodeName", indexFunc)).To(Succeed())
+		Expect(informerHasIndex(informer, internal.FieldIndexName("spec.nodeName"))).To(BeTrue())
+		Expect(informerHasIndex(informer, internal.FieldIndexName("spec.restartPolicy"))).To(BeFalse())
+	})
+
+	It("should only report a field as indexed if it is indexed in every namespace", func() {
+		indexed, notIndexed := newInformer(), newInformer()
+		Expect(indexByField(indexed, "spec.nodeName", indexFunc)).To(Succeed())
+
+		informer := &multiNamespaceInformer{namespaceToInformer: map[string]Informer{"a": indexed, "b": notIndexed}}
+		Expect(informerHasIndex(informer, internal.FieldIndexName("spec.nodeName"))).To(BeFalse())
+
+		Expect(indexByField(notIndexed, "spec.nodeName", indexFunc)).To(Succeed())
+		Expect(informerHasIndex(informer, internal.FieldIndexName("spec.nodeName"))).To(BeTrue())
+	})
+
+	It("should return an error for informers it doesn't know the indexes of", func() {
+		_, err := informerHasIndex(&controllertest.FakeInformer{}, internal.FieldIndexName("spec.nodeName"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -23,23 +23,44 @@ import (
 	"math/rand"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/go-logr/logr"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/cache/internal/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
 	"sigs.k8s.io/controller-runtime/pkg/internal/syncs"
 )
 
+// objectCountSamplePeriod is how often the number of objects held by the
+// informers is sampled into the CachedObjects metric.
+var objectCountSamplePeriod = 30 * time.Second
+
+// syncedPollPeriod is how often WaitForCacheSync polls whether the informers
+// synced by default, like cache.WaitForCacheSync.
+const syncedPollPeriod = 100 * time.Millisecond
+
+// waitForSyncLogPeriod is how often WaitForCacheSync logs how many informers
+// synced while it waits for them.
+var waitForSyncLogPeriod = 10 * time.Second
+
+var log = logf.RuntimeLog.WithName("cache")
+
 // InformersOpts configures an InformerMap.
 type InformersOpts struct {
 	HTTPClient            *http.Client
@@ -48,10 +69,18 @@ type InformersOpts struct {
 	ResyncPeriod          time.Duration
 	Namespace             string
 	NewInformer           *func(cache.ListerWatcher, runtime.Object, time.Duration, cache.Indexers) cache.SharedIndexInformer
+	NewListerWatcher      func(obj runtime.Object, namespace string) (cache.ListerWatcher, error)
 	Selector              Selector
 	Transform             cache.TransformFunc
+	BestEffort            map[schema.GroupVersionKind]bool
 	UnsafeDisableDeepCopy bool
 	WatchErrorHandler     cache.WatchErrorHandler
+	Indexers              cache.Indexers
+	WaitForSyncBackoff    *wait.Backoff
+	ResumeStore           ResumeStore
+	OnStore               func(gvk schema.GroupVersionKind, key client.ObjectKey, obj client.Object)
+	OnDelete              func(gvk schema.GroupVersionKind, key client.ObjectKey)
+	RecordLastResync      bool
 }
 
 // NewInformers creates a new InformersMap that can create informers under the hood.
@@ -60,6 +89,13 @@ func NewInformers(config *rest.Config, options *InformersOpts) *Informers {
 	if options.NewInformer != nil {
 		newInformer = *options.NewInformer
 	}
+	waitForSyncBackoff := wait.Backoff{Duration: syncedPollPeriod}
+	if options.WaitForSyncBackoff != nil {
+		waitForSyncBackoff = *options.WaitForSyncBackoff
+		if waitForSyncBackoff.Duration <= 0 {
+			waitForSyncBackoff.Duration = syncedPollPeriod
+		}
+	}
 	return &Informers{
 		config:     config,
 		httpClient: options.HTTPClient,
@@ -78,8 +114,18 @@ func NewInformers(config *rest.Config, options *InformersOpts) *Informers {
 		selector:              options.Selector,
 		transform:             options.Transform,
 		unsafeDisableDeepCopy: options.UnsafeDisableDeepCopy,
+		bestEffort:            options.BestEffort,
 		newInformer:           newInformer,
+		newListerWatcher:      options.NewListerWatcher,
 		watchErrorHandler:     options.WatchErrorHandler,
+		indexers:              options.Indexers,
+		waitForSyncBackoff:    waitForSyncBackoff,
+		resumeStore:           options.ResumeStore,
+		onStore:               options.OnStore,
+		onDelete:              options.OnDelete,
+		recordLastResync:      options.RecordLastResync,
+		objectCounts:          metrics.NewObjectCounts(options.Namespace),
+		objectCountPeriod:     objectCountSamplePeriod,
 	}
 }
 
@@ -93,6 +139,25 @@ type Cache struct {
 
 	// Stop can be used to stop this individual informer.
 	stop chan struct{}
+
+	// lastResync is the time in Unix nanoseconds the informer last delivered a resync, or 0.
+	lastResync atomic.Int64
+
+	// objects is the number of objects the informer delivered adds for minus the number
+	// of objects it delivered deletes for, i.e. the number of objects it holds.
+	objects atomic.Int64
+
+	// resumer keeps the informer from listing an older state than the resourceVersion it last observed,
+	// nil without a ResumeStore.
+	resumer *resumer
+}
+
+// Status is the status of an informer, see Informers.Statuses.
+type Status struct {
+	// Synced is true if the informer has synced.
+	Synced bool
+	// LastResync is the time the informer last delivered a periodic resync, or zero.
+	LastResync time.Time
 }
 
 // Start starts the informer managed by a MapEntry.
@@ -103,7 +168,14 @@ func (c *Cache) Start(stop <-chan struct{}) {
 	// Stop on either the whole map stopping or just this informer being removed.
 	internalStop, cancel := syncs.MergeChans(stop, c.stop)
 	defer cancel()
+	if c.resumer == nil {
+		c.Informer.Run(internalStop)
+		return
+	}
+
+	go c.resumer.storeUntil(c.Informer, internalStop)
 	c.Informer.Run(internalStop)
+	c.resumer.storeResourceVersion(c.Informer)
 }
 
 type tracker struct {
@@ -175,13 +247,41 @@ type Informers struct {
 	transform             cache.TransformFunc
 	unsafeDisableDeepCopy bool
 
+	// bestEffort are the GVKs whose informers WaitForCacheSync and Get don't wait for by default.
+	bestEffort map[schema.GroupVersionKind]bool
+
 	// NewInformer allows overriding of the shared index informer constructor for testing.
 	newInformer func(cache.ListerWatcher, runtime.Object, time.Duration, cache.Indexers) cache.SharedIndexInformer
 
+	// newListerWatcher, if set, replaces the ListerWatcher backed by the API server.
+	newListerWatcher func(obj runtime.Object, namespace string) (cache.ListerWatcher, error)
+
 	// WatchErrorHandler allows the shared index informer's
 	// watchErrorHandler to be set by overriding the options
 	// or to use the default watchErrorHandler
 	watchErrorHandler cache.WatchErrorHandler
+
+	// indexers are added to the informers when they are created, in addition to the namespace index.
+	indexers cache.Indexers
+
+	// waitForSyncBackoff is the backoff with which WaitForCacheSync polls whether the informers synced.
+	waitForSyncBackoff wait.Backoff
+
+	// resumeStore, if set, persists the resourceVersion each informer last observed, which its first
+	// list after a restart must not be older than.
+	resumeStore ResumeStore
+
+	// onStore and onDelete, if set, are called when an informer stores or removes an object.
+	onStore  func(gvk schema.GroupVersionKind, key client.ObjectKey, obj client.Object)
+	onDelete func(gvk schema.GroupVersionKind, key client.ObjectKey)
+
+	// recordLastResync makes the informers record when they last delivered a resync.
+	recordLastResync bool
+
+	// objectCounts reports the number of objects held by the informers in the CachedObjects metric.
+	objectCounts *metrics.ObjectCounts
+	// objectCountPeriod is how often objectCounts is updated.
+	objectCountPeriod time.Duration
 }
 
 // Start calls Run on each of the informers and sets started to true. Blocks on the context.
@@ -213,6 +313,8 @@ func (ip *Informers) Start(ctx context.Context) error {
 		ip.started = true
 		close(ip.startWait)
 
+		go ip.sampleObjectCounts(ctx)
+
 		return nil
 	}(); err != nil {
 		return err
@@ -222,6 +324,7 @@ func (ip *Informers) Start(ctx context.Context) error {
 	ip.stopped = true // Set stopped to true so we don't start any new informers
 	ip.mu.Unlock()
 	ip.waitGroup.Wait() // Block until all informers have stopped
+	ip.objectCounts.Set(nil)
 	return nil
 }
 
@@ -240,6 +343,43 @@ func (ip *Informers) startInformerLocked(cacheEntry *Cache) {
 	}()
 }
 
+// sampleObjectCounts periodically updates the CachedObjects metric until the
+// context is done. Sampling keeps the overhead of the metric independent of
+// how often the cached objects change.
+func (ip *Informers) sampleObjectCounts(ctx context.Context) {
+	ticker := time.NewTicker(ip.objectCountPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ip.updateObjectCounts()
+		}
+	}
+}
+
+func (ip *Informers) updateObjectCounts() {
+	ip.mu.RLock()
+	defer ip.mu.RUnlock()
+	ip.updateObjectCountsLocked()
+}
+
+func (ip *Informers) updateObjectCountsLocked() {
+	// Don't report the informers before they start or after they stopped.
+	if !ip.started || ip.stopped {
+		return
+	}
+
+	counts := map[schema.GroupVersionKind]int{}
+	for _, informers := range []map[schema.GroupVersionKind]*Cache{ip.tracker.Structured, ip.tracker.Unstructured, ip.tracker.Metadata} {
+		for gvk, entry := range informers {
+			counts[gvk] += int(entry.objects.Load())
+		}
+	}
+	ip.objectCounts.Set(counts)
+}
+
 func (ip *Informers) waitForStarted(ctx context.Context) bool {
 	select {
 	case <-ip.startWait:
@@ -257,24 +397,109 @@ func (ip *Informers) getHasSyncedFuncs() []cache.InformerSynced {
 	res := make([]cache.InformerSynced, 0,
 		len(ip.tracker.Structured)+len(ip.tracker.Unstructured)+len(ip.tracker.Metadata),
 	)
-	for _, i := range ip.tracker.Structured {
-		res = append(res, i.Informer.HasSynced)
+	for _, informers := range []map[schema.GroupVersionKind]*Cache{ip.tracker.Structured, ip.tracker.Unstructured, ip.tracker.Metadata} {
+		for gvk, i := range informers {
+			if ip.bestEffort[gvk] {
+				continue
+			}
+			res = append(res, i.Informer.HasSynced)
+		}
 	}
-	for _, i := range ip.tracker.Unstructured {
-		res = append(res, i.Informer.HasSynced)
+	return res
+}
+
+// UnsyncedBestEffort returns the GVKs of the best-effort informers that did not sync yet.
+func (ip *Informers) UnsyncedBestEffort() []schema.GroupVersionKind {
+	ip.mu.RLock()
+	defer ip.mu.RUnlock()
+
+	var res []schema.GroupVersionKind
+	for _, informers := range []map[schema.GroupVersionKind]*Cache{ip.tracker.Structured, ip.tracker.Unstructured, ip.tracker.Metadata} {
+		for gvk, i := range informers {
+			if ip.bestEffort[gvk] && !i.Informer.HasSynced() {
+				res = append(res, gvk)
+			}
+		}
 	}
-	for _, i := range ip.tracker.Metadata {
-		res = append(res, i.Informer.HasSynced)
+	return res
+}
+
+// Statuses returns the status of every informer. It is safe to call concurrently with
+// the creation of informers.
+func (ip *Informers) Statuses() map[schema.GroupVersionKind][]Status {
+	ip.mu.RLock()
+	defer ip.mu.RUnlock()
+
+	res := map[schema.GroupVersionKind][]Status{}
+	for _, informers := range []map[schema.GroupVersionKind]*Cache{ip.tracker.Structured, ip.tracker.Unstructured, ip.tracker.Metadata} {
+		for gvk, i := range informers {
+			status := Status{Synced: i.Informer.HasSynced()}
+			if lastResync := i.lastResync.Load(); lastResync != 0 {
+				status.LastResync = time.Unix(0, lastResync)
+			}
+			res[gvk] = append(res[gvk], status)
+		}
 	}
 	return res
 }
 
-// WaitForCacheSync waits until all the caches have been started and synced.
+// WaitForCacheSync waits until all the caches have been started and synced,
+// except for the best-effort ones.
 func (ip *Informers) WaitForCacheSync(ctx context.Context) bool {
 	if !ip.waitForStarted(ctx) {
 		return false
 	}
-	return cache.WaitForCacheSync(ctx.Done(), ip.getHasSyncedFuncs()...)
+	waiter := syncWaiter{
+		backoff:   ip.waitForSyncBackoff,
+		logPeriod: waitForSyncLogPeriod,
+		clock:     clock.RealClock{},
+		log:       log,
+	}
+	return waiter.wait(ctx, ip.getHasSyncedFuncs())
+}
+
+// syncWaitClock is the part of clock.Clock used by syncWaiter.
+type syncWaitClock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// syncWaiter polls whether informers synced with a backoff, and logs
+// how many of them synced every logPeriod.
+type syncWaiter struct {
+	backoff   wait.Backoff
+	logPeriod time.Duration
+	clock     syncWaitClock
+	log       logr.Logger
+}
+
+// wait returns true once all synced funcs return true, or false if ctx is done before.
+func (w syncWaiter) wait(ctx context.Context, synced []cache.InformerSynced) bool {
+	backoff := w.backoff
+	start := w.clock.Now()
+	lastLog := start
+	for {
+		syncedCount := 0
+		for _, hasSynced := range synced {
+			if hasSynced() {
+				syncedCount++
+			}
+		}
+		if syncedCount == len(synced) {
+			return true
+		}
+
+		if now := w.clock.Now(); now.Sub(lastLog) >= w.logPeriod {
+			w.log.Info("Still waiting for informers to sync", "synced", syncedCount, "total", len(synced), "elapsed", now.Sub(start))
+			lastLog = now
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-w.clock.After(backoff.Step()):
+		}
+	}
 }
 
 // Peek attempts to get the informer for the GVK, but does not start one if one does not exist.
@@ -286,18 +511,19 @@ func (ip *Informers) Peek(gvk schema.GroupVersionKind, obj runtime.Object) (res
 }
 
 // Get will create a new Informer and add it to the map of specificInformersMap if none exists. Returns
-// the Informer from the map.
+// the Informer from the map. A timeout error is returned if ctx is done before the Informer has been
+// created or, when blocking, has synced.
 func (ip *Informers) Get(ctx context.Context, gvk schema.GroupVersionKind, obj runtime.Object, opts *GetOptions) (bool, *Cache, error) {
 	// Return the informer if it is found
 	i, started, ok := ip.Peek(gvk, obj)
 	if !ok {
 		var err error
-		if i, started, err = ip.addInformerToMap(gvk, obj); err != nil {
+		if i, started, err = ip.addInformerToMapWithContext(ctx, gvk, obj); err != nil {
 			return started, nil, err
 		}
 	}
 
-	shouldBlock := true
+	shouldBlock := !ip.bestEffort[gvk]
 	if opts.BlockUntilSynced != nil {
 		shouldBlock = *opts.BlockUntilSynced
 	}
@@ -325,6 +551,7 @@ func (ip *Informers) Remove(gvk schema.GroupVersionKind, obj runtime.Object) {
 	}
 	close(entry.stop)
 	delete(informerMap, gvk)
+	ip.updateObjectCountsLocked()
 }
 
 func (ip *Informers) informersByType(obj runtime.Object) map[schema.GroupVersionKind]*Cache {
@@ -338,6 +565,29 @@ func (ip *Informers) informersByType(obj runtime.Object) map[schema.GroupVersion
 	}
 }
 
+// addInformerToMapWithContext is like addInformerToMap, but gives up waiting once ctx is done,
+// as creating the informer may require discovery requests to a slow API server. The informer is
+// still added to the map, and started if the Informers have been started, in the background.
+func (ip *Informers) addInformerToMapWithContext(ctx context.Context, gvk schema.GroupVersionKind, obj runtime.Object) (*Cache, bool, error) {
+	type result struct {
+		cache   *Cache
+		started bool
+		err     error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		i, started, err := ip.addInformerToMap(gvk, obj)
+		resCh <- result{cache: i, started: started, err: err}
+	}()
+
+	select {
+	case res := <-resCh:
+		return res.cache, res.started, res.err
+	case <-ctx.Done():
+		return nil, false, apierrors.NewTimeoutError(fmt.Sprintf("failed waiting for %T Informer to be created", obj), 0)
+	}
+}
+
 // addInformerToMap either returns an existing informer or creates a new informer, adds it to the map and returns it.
 func (ip *Informers) addInformerToMap(gvk schema.GroupVersionKind, obj runtime.Object) (*Cache, bool, error) {
 	ip.mu.Lock()
@@ -351,23 +601,31 @@ func (ip *Informers) addInformerToMap(gvk schema.GroupVersionKind, obj runtime.O
 	}
 
 	// Create a NewSharedIndexInformer and add it to the map.
-	listWatcher, err := ip.makeListWatcher(gvk, obj)
+	var listWatcher cache.ListerWatcher
+	var err error
+	if ip.newListerWatcher != nil {
+		listWatcher, err = ip.newListerWatcher(obj, ip.namespace)
+	} else {
+		listWatcher, err = ip.makeListWatcher(gvk, obj)
+	}
 	if err != nil {
 		return nil, false, err
 	}
+	var informerResumer *resumer
+	if ip.resumeStore != nil {
+		informerResumer = &resumer{store: ip.resumeStore, key: resumeKey(gvk, ip.namespace)}
+	}
 	sharedIndexInformer := ip.newInformer(&cache.ListWatch{
 		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
 			ip.selector.ApplyToList(&opts)
-			return listWatcher.ListFunc(opts)
+			return informerResumer.list(listWatcher, opts)
 		},
 		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
 			ip.selector.ApplyToList(&opts)
 			opts.Watch = true // Watch needs to be set to true separately
-			return listWatcher.WatchFunc(opts)
+			return listWatcher.Watch(opts)
 		},
-	}, obj, calculateResyncPeriod(ip.resync), cache.Indexers{
-		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
-	})
+	}, obj, calculateResyncPeriod(ip.resync), ip.informerIndexers())
 
 	// Set WatchErrorHandler on SharedIndexInformer if set
 	if ip.watchErrorHandler != nil {
@@ -395,7 +653,35 @@ func (ip *Informers) addInformerToMap(gvk schema.GroupVersionKind, obj runtime.O
 			scopeName:        mapping.Scope.Name(),
 			disableDeepCopy:  ip.unsafeDisableDeepCopy,
 		},
-		stop: make(chan struct{}),
+		stop:    make(chan struct{}),
+		resumer: informerResumer,
+	}
+	// Count the objects the informer holds as it adds and deletes them, rather than
+	// listing its store whenever the count is sampled.
+	if _, err := sharedIndexInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { i.objects.Add(1) },
+		DeleteFunc: func(interface{}) { i.objects.Add(-1) },
+	}); err != nil {
+		return nil, false, err
+	}
+	if ip.recordLastResync {
+		// Resyncs deliver updates with unchanged objects, record when they happen.
+		if _, err := sharedIndexInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldMeta, oldErr := meta.Accessor(oldObj)
+				newMeta, newErr := meta.Accessor(newObj)
+				if oldErr == nil && newErr == nil && oldMeta.GetResourceVersion() == newMeta.GetResourceVersion() {
+					i.lastResync.Store(time.Now().UnixNano())
+				}
+			},
+		}); err != nil {
+			return nil, false, err
+		}
+	}
+	if ip.onStore != nil || ip.onDelete != nil {
+		if _, err := sharedIndexInformer.AddEventHandler(ip.storeObserver(gvk)); err != nil {
+			return nil, false, err
+		}
 	}
 	ip.informersByType(obj)[gvk] = i
 
@@ -407,6 +693,54 @@ func (ip *Informers) addInformerToMap(gvk schema.GroupVersionKind, obj runtime.O
 	return i, ip.started, nil
 }
 
+// storeObserver returns an event handler calling onStore and onDelete for the objects the
+// informer of gvk stores and removes. Resyncs don't change the stored objects and are skipped.
+func (ip *Informers) storeObserver(gvk schema.GroupVersionKind) cache.ResourceEventHandler {
+	stored := func(obj interface{}) {
+		if o, ok := obj.(client.Object); ok && ip.onStore != nil {
+			ip.onStore(gvk, client.ObjectKeyFromObject(o), o)
+		}
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: stored,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldMeta, oldErr := meta.Accessor(oldObj)
+			newMeta, newErr := meta.Accessor(newObj)
+			if oldErr == nil && newErr == nil && oldMeta.GetResourceVersion() == newMeta.GetResourceVersion() {
+				return
+			}
+			stored(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if ip.onDelete == nil {
+				return
+			}
+			key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+			if err != nil {
+				log.Error(err, "Failed to get the key of a deleted object", "gvk", gvk)
+				return
+			}
+			namespace, name, err := cache.SplitMetaNamespaceKey(key)
+			if err != nil {
+				log.Error(err, "Failed to split the key of a deleted object", "gvk", gvk, "key", key)
+				return
+			}
+			ip.onDelete(gvk, client.ObjectKey{Namespace: namespace, Name: name})
+		},
+	}
+}
+
+// informerIndexers returns the indexers new informers are created with.
+func (ip *Informers) informerIndexers() cache.Indexers {
+	indexers := cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+	}
+	for name, indexFunc := range ip.indexers {
+		indexers[name] = indexFunc
+	}
+	return indexers
+}
+
 func (ip *Informers) makeListWatcher(gvk schema.GroupVersionKind, obj runtime.Object) (*cache.ListWatch, error) {
 	// Kubernetes APIs work against Resources, not GroupVersionKinds.  Map the
 	// groupVersionKind to the Resource API we will use.
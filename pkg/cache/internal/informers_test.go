@@ -17,14 +17,32 @@ limitations under the License.
 package internal
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // Test that gvkFixupWatcher behaves like watch.FakeWatcher
@@ -92,3 +110,555 @@ var _ = Describe("gvkFixupWatcher", func() {
 		consumer(gvkfw)
 	})
 })
+
+var _ = Describe("Informers with best-effort informers", func() {
+	podGVK := corev1.SchemeGroupVersion.WithKind("Pod")
+
+	var (
+		ctx          context.Context
+		apiAvailable atomic.Bool
+	)
+
+	BeforeEach(func() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		apiAvailable.Store(false)
+	})
+
+	startInformers := func(bestEffort map[schema.GroupVersionKind]bool) *Informers {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(podGVK, meta.RESTScopeNamespace)
+
+		// Fail to list pods until the API becomes available.
+		newInformer := func(_ cache.ListerWatcher, obj runtime.Object, resync time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+			lw := &cache.ListWatch{
+				ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+					if !apiAvailable.Load() {
+						return nil, errors.New("the server is currently unable to handle the request")
+					}
+					return &corev1.PodList{Items: []corev1.Pod{
+						{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod"}},
+					}}, nil
+				},
+				WatchFunc: func(metav1.ListOptions) (watch.Interface, error) {
+					return watch.NewFake(), nil
+				},
+			}
+			return cache.NewSharedIndexInformer(lw, obj, resync, indexers)
+		}
+
+		informers := NewInformers(&rest.Config{Host: "http://localhost"}, &InformersOpts{
+			HTTPClient:  http.DefaultClient,
+			Scheme:      scheme.Scheme,
+			Mapper:      mapper,
+			BestEffort:  bestEffort,
+			NewInformer: &newInformer,
+		})
+		go func() {
+			defer GinkgoRecover()
+			Expect(informers.Start(ctx)).To(Succeed())
+		}()
+		return informers
+	}
+
+	It("should not wait for a best-effort informer that fails to start and deliver its events once it succeeds", func() {
+		informers := startInformers(map[schema.GroupVersionKind]bool{podGVK: true})
+
+		By("Getting the informer without blocking on its sync")
+		_, entry, err := informers.Get(ctx, podGVK, &corev1.Pod{}, &GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		added := make(chan string, 1)
+		_, err = entry.Informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) { added <- obj.(*corev1.Pod).Name },
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Not blocking WaitForCacheSync on the failing informer")
+		Expect(informers.WaitForCacheSync(ctx)).To(BeTrue())
+		Expect(informers.UnsyncedBestEffort()).To(ConsistOf(podGVK))
+		Consistently(added).ShouldNot(Receive())
+
+		By("Delivering events once the informer succeeds")
+		apiAvailable.Store(true)
+		Eventually(added, 10*time.Second).Should(Receive(Equal("pod")))
+		Eventually(informers.UnsyncedBestEffort).Should(BeEmpty())
+	})
+
+	It("should make WaitForCacheSync wait for a required informer that fails to start", func() {
+		informers := startInformers(nil)
+
+		_, _, err := informers.Get(ctx, podGVK, &corev1.Pod{}, &GetOptions{BlockUntilSynced: ptr.To(false)})
+		Expect(err).NotTo(HaveOccurred())
+
+		syncCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+		defer cancel()
+		Expect(informers.WaitForCacheSync(syncCtx)).To(BeFalse())
+		Expect(informers.UnsyncedBestEffort()).To(BeEmpty())
+	})
+})
+
+// slowRESTMapper blocks RESTMapping until released, like a mapper waiting on a slow API server.
+type slowRESTMapper struct {
+	meta.RESTMapper
+	release chan struct{}
+}
+
+func (m *slowRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	<-m.release
+	return m.RESTMapper.RESTMapping(gk, versions...)
+}
+
+var _ = Describe("Informers with a slow API server", func() {
+	podGVK := corev1.SchemeGroupVersion.WithKind("Pod")
+
+	It("should respect the context deadline while creating an informer and create it in the background", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		defaultMapper := meta.NewDefaultRESTMapper(nil)
+		defaultMapper.Add(podGVK, meta.RESTScopeNamespace)
+		mapper := &slowRESTMapper{RESTMapper: defaultMapper, release: make(chan struct{})}
+
+		newInformer := func(_ cache.ListerWatcher, obj runtime.Object, resync time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+			lw := &cache.ListWatch{
+				ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+					return &corev1.PodList{}, nil
+				},
+				WatchFunc: func(metav1.ListOptions) (watch.Interface, error) {
+					return watch.NewFake(), nil
+				},
+			}
+			return cache.NewSharedIndexInformer(lw, obj, resync, indexers)
+		}
+		informers := NewInformers(&rest.Config{Host: "http://localhost"}, &InformersOpts{
+			HTTPClient:  http.DefaultClient,
+			Scheme:      scheme.Scheme,
+			Mapper:      mapper,
+			NewInformer: &newInformer,
+		})
+		go func() {
+			defer GinkgoRecover()
+			Expect(informers.Start(ctx)).To(Succeed())
+		}()
+
+		By("Timing out while the API server is slow")
+		getCtx, getCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer getCancel()
+		_, _, err := informers.Get(getCtx, podGVK, &corev1.Pod{}, &GetOptions{})
+		Expect(err).To(HaveOccurred())
+		Expect(apierrors.IsTimeout(err)).To(BeTrue())
+
+		By("Creating and syncing the informer in the background once the API server responds")
+		close(mapper.release)
+		Eventually(func() bool {
+			entry, _, ok := informers.Peek(podGVK, &corev1.Pod{})
+			return ok && entry.Informer.HasSynced()
+		}).Should(BeTrue())
+
+		_, entry, err := informers.Get(ctx, podGVK, &corev1.Pod{}, &GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entry.Informer.HasSynced()).To(BeTrue())
+	})
+})
+
+var _ = Describe("Informers statuses", func() {
+	podGVK := corev1.SchemeGroupVersion.WithKind("Pod")
+	configMapGVK := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+
+	newInformers := func(recordLastResync bool) *Informers {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(podGVK, meta.RESTScopeNamespace)
+		mapper.Add(configMapGVK, meta.RESTScopeNamespace)
+
+		newInformer := func(_ cache.ListerWatcher, obj runtime.Object, resync time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+			lw := &cache.ListWatch{
+				ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+					if _, ok := obj.(*corev1.Pod); ok {
+						return &corev1.PodList{Items: []corev1.Pod{
+							{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod", ResourceVersion: "1"}},
+						}}, nil
+					}
+					return &corev1.ConfigMapList{}, nil
+				},
+				WatchFunc: func(metav1.ListOptions) (watch.Interface, error) {
+					return watch.NewFake(), nil
+				},
+			}
+			return cache.NewSharedIndexInformer(lw, obj, resync, indexers)
+		}
+		return NewInformers(&rest.Config{Host: "http://localhost"}, &InformersOpts{
+			HTTPClient:       http.DefaultClient,
+			Scheme:           scheme.Scheme,
+			Mapper:           mapper,
+			ResyncPeriod:     100 * time.Millisecond,
+			NewInformer:      &newInformer,
+			RecordLastResync: recordLastResync,
+		})
+	}
+
+	It("should report the sync status and last resync of every informer", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		informers := newInformers(true)
+		Expect(informers.Statuses()).To(BeEmpty())
+		go func() {
+			defer GinkgoRecover()
+			Expect(informers.Start(ctx)).To(Succeed())
+		}()
+
+		_, _, err := informers.Get(ctx, podGVK, &corev1.Pod{}, &GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		_, _, err = informers.Get(ctx, configMapGVK, &corev1.ConfigMap{}, &GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(informers.Statuses()).To(HaveLen(2))
+		Eventually(informers.Statuses).Should(And(
+			HaveKeyWithValue(podGVK, ConsistOf(HaveField("Synced", BeTrue()))),
+			HaveKeyWithValue(configMapGVK, ConsistOf(HaveField("Synced", BeTrue()))),
+		))
+
+		By("Recording the resyncs of the informer with objects")
+		Eventually(func() time.Time {
+			return informers.Statuses()[podGVK][0].LastResync
+		}, 5*time.Second).ShouldNot(BeZero())
+		Expect(informers.Statuses()[configMapGVK][0].LastResync).To(BeZero())
+	})
+
+	It("should not record the last resync unless asked to", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		informers := newInformers(false)
+		go func() {
+			defer GinkgoRecover()
+			Expect(informers.Start(ctx)).To(Succeed())
+		}()
+
+		_, _, err := informers.Get(ctx, podGVK, &corev1.Pod{}, &GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(informers.Statuses).Should(HaveKeyWithValue(podGVK, ConsistOf(HaveField("Synced", BeTrue()))))
+		Consistently(func() time.Time {
+			return informers.Statuses()[podGVK][0].LastResync
+		}, 500*time.Millisecond).Should(BeZero())
+	})
+})
+
+// fakeSyncWaitClock advances its time by the durations waited for, and records them.
+type fakeSyncWaitClock struct {
+	now   time.Time
+	waits []time.Duration
+}
+
+func (c *fakeSyncWaitClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeSyncWaitClock) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+var _ = Describe("Waiting for informers to sync", func() {
+	syncedAfterPolls := func(polls int) cache.InformerSynced {
+		calls := 0
+		return func() bool {
+			calls++
+			return calls >= polls
+		}
+	}
+
+	It("should poll with the backoff and log its progress", func() {
+		var logs []string
+		clock := &fakeSyncWaitClock{now: time.Now()}
+		waiter := syncWaiter{
+			backoff:   wait.Backoff{Duration: time.Second, Factor: 2, Steps: 10, Cap: 8 * time.Second},
+			logPeriod: 10 * time.Second,
+			clock:     clock,
+			log: funcr.New(func(_, args string) {
+				logs = append(logs, args)
+			}, funcr.Options{}),
+		}
+
+		Expect(waiter.wait(context.Background(), []cache.InformerSynced{syncedAfterPolls(3), syncedAfterPolls(8)})).To(BeTrue())
+
+		By("Growing the polling interval up to the cap")
+		Expect(clock.waits).To(Equal([]time.Duration{
+			time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second, 8 * time.Second, 8 * time.Second,
+		}))
+
+		By("Logging the progress every log period, after 15s and 31s")
+		Expect(logs).To(HaveLen(2))
+		for _, line := range logs {
+			Expect(line).To(ContainSubstring(`"msg"="Still waiting for informers to sync"`))
+			Expect(line).To(ContainSubstring(`"synced"=1 "total"=2`))
+		}
+		Expect(logs[0]).To(ContainSubstring(`"elapsed"="15s"`))
+		Expect(logs[1]).To(ContainSubstring(`"elapsed"="31s"`))
+	})
+
+	It("should not wait if all informers synced", func() {
+		clock := &fakeSyncWaitClock{now: time.Now()}
+		waiter := syncWaiter{backoff: wait.Backoff{Duration: time.Second}, logPeriod: time.Second, clock: clock, log: logr.Discard()}
+
+		Expect(waiter.wait(context.Background(), []cache.InformerSynced{syncedAfterPolls(1)})).To(BeTrue())
+		Expect(clock.waits).To(BeEmpty())
+	})
+
+	It("should stop waiting when the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		waiter := syncWaiter{backoff: wait.Backoff{Duration: time.Hour}, logPeriod: time.Hour, clock: clockWithoutWaits{}, log: logr.Discard()}
+
+		Expect(waiter.wait(ctx, []cache.InformerSynced{syncedAfterPolls(2)})).To(BeFalse())
+	})
+
+	It("should poll every 100ms by default", func() {
+		informers := NewInformers(&rest.Config{}, &InformersOpts{Scheme: scheme.Scheme})
+		Expect(informers.waitForSyncBackoff).To(Equal(wait.Backoff{Duration: 100 * time.Millisecond}))
+
+		informers = NewInformers(&rest.Config{}, &InformersOpts{Scheme: scheme.Scheme, WaitForSyncBackoff: &wait.Backoff{Factor: 2, Steps: 5}})
+		Expect(informers.waitForSyncBackoff).To(Equal(wait.Backoff{Duration: 100 * time.Millisecond, Factor: 2, Steps: 5}))
+	})
+})
+
+// clockWithoutWaits never fires, so that waiting only ends when the context is done.
+type clockWithoutWaits struct{}
+
+func (clockWithoutWaits) Now() time.Time {
+	return time.Time{}
+}
+
+func (clockWithoutWaits) After(time.Duration) <-chan time.Time {
+	return nil
+}
+
+// memoryResumeStore is a ResumeStore that outlives the Informers of a test, like a ConfigMap.
+type memoryResumeStore struct {
+	mu               sync.Mutex
+	resourceVersions map[string]string
+}
+
+func (s *memoryResumeStore) ResourceVersion(_ context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resourceVersions[key], nil
+}
+
+func (s *memoryResumeStore) StoreResourceVersion(_ context.Context, key, resourceVersion string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resourceVersions[key] = resourceVersion
+	return nil
+}
+
+var _ = Describe("Informers with a ResumeStore", func() {
+	podGVK := corev1.SchemeGroupVersion.WithKind("Pod")
+
+	var (
+		store    *memoryResumeStore
+		mu       sync.Mutex
+		lists    []metav1.ListOptions
+		watches  []metav1.ListOptions
+		watchers chan *watch.FakeWatcher
+		listFunc func(metav1.ListOptions) (runtime.Object, error)
+	)
+
+	BeforeEach(func() {
+		store = &memoryResumeStore{resourceVersions: map[string]string{}}
+		lists, watches = nil, nil
+		watchers = make(chan *watch.FakeWatcher, 10)
+		listFunc = func(metav1.ListOptions) (runtime.Object, error) {
+			return &corev1.PodList{ListMeta: metav1.ListMeta{ResourceVersion: "100"}}, nil
+		}
+	})
+
+	// startInformers starts Informers with store and a synced Pod informer, and returns the informer
+	// and a func stopping the Informers, like a manager shutting down.
+	startInformers := func() (entry *Cache, stop func()) {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(podGVK, meta.RESTScopeNamespace)
+
+		informers := NewInformers(&rest.Config{Host: "http://localhost"}, &InformersOpts{
+			HTTPClient:  http.DefaultClient,
+			Scheme:      scheme.Scheme,
+			Mapper:      mapper,
+			ResumeStore: store,
+			NewListerWatcher: func(runtime.Object, string) (cache.ListerWatcher, error) {
+				return &cache.ListWatch{
+					ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+						mu.Lock()
+						lists = append(lists, opts)
+						mu.Unlock()
+						return listFunc(opts)
+					},
+					WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+						mu.Lock()
+						watches = append(watches, opts)
+						mu.Unlock()
+						w := watch.NewFake()
+						watchers <- w
+						return w, nil
+					},
+				}, nil
+			},
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			Expect(informers.Start(ctx)).To(Succeed())
+		}()
+		_, entry, err := informers.Get(ctx, podGVK, &corev1.Pod{}, &GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		return entry, func() {
+			cancel()
+			<-done
+		}
+	}
+
+	It("should store the last observed resourceVersion and not list an older state after a restart", func() {
+		By("Storing the resourceVersion of the last event when stopping")
+		entry, stop := startInformers()
+		var w *watch.FakeWatcher
+		Eventually(watchers).Should(Receive(&w))
+		w.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod", ResourceVersion: "105"}})
+		Eventually(entry.Informer.LastSyncResourceVersion).Should(Equal("105"))
+		stop()
+		Expect(store.ResourceVersion(context.Background(), "Pod.v1")).To(Equal("105"))
+		mu.Lock()
+		Expect(lists).To(HaveLen(1))
+		Expect(lists[0].ResourceVersionMatch).To(BeEmpty())
+		mu.Unlock()
+
+		By("Listing all objects at a resourceVersion not older than the stored one after a restart")
+		lists, watches = nil, nil
+		listFunc = func(metav1.ListOptions) (runtime.Object, error) {
+			return &corev1.PodList{ListMeta: metav1.ListMeta{ResourceVersion: "110"}}, nil
+		}
+		_, stop = startInformers()
+		defer stop()
+		Eventually(func() []metav1.ListOptions {
+			mu.Lock()
+			defer mu.Unlock()
+			return watches
+		}).Should(ConsistOf(HaveField("ResourceVersion", "110")))
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(lists).To(HaveLen(1))
+		Expect(lists[0].ResourceVersion).To(Equal("105"))
+		Expect(lists[0].ResourceVersionMatch).To(Equal(metav1.ResourceVersionMatchNotOlderThan))
+		Expect(lists[0].Limit).To(BeZero())
+	})
+
+	It("should list from scratch if the stored resourceVersion is gone", func() {
+		store.resourceVersions["Pod.v1"] = "5"
+		listFunc = func(opts metav1.ListOptions) (runtime.Object, error) {
+			if opts.ResourceVersion == "5" {
+				return nil, apierrors.NewResourceExpired("too old resource version: 5 (100)")
+			}
+			return &corev1.PodList{ListMeta: metav1.ListMeta{ResourceVersion: "100"}}, nil
+		}
+
+		_, stop := startInformers()
+		defer stop()
+		Eventually(func() []metav1.ListOptions {
+			mu.Lock()
+			defer mu.Unlock()
+			return lists
+		}).Should(HaveLen(2))
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(lists[0].ResourceVersion).To(Equal("5"))
+		Expect(lists[1].ResourceVersion).NotTo(Equal("5"))
+		Expect(lists[1].ResourceVersionMatch).To(BeEmpty())
+	})
+
+	It("should list from scratch if the stored resourceVersion is too large", func() {
+		store.resourceVersions["Pod.v1"] = "500"
+		listFunc = func(opts metav1.ListOptions) (runtime.Object, error) {
+			if opts.ResourceVersion == "500" {
+				err := apierrors.NewTimeoutError("Too large resource version: 500, current: 100", 1)
+				err.ErrStatus.Details.Causes = []metav1.StatusCause{{Type: metav1.CauseTypeResourceVersionTooLarge}}
+				return nil, err
+			}
+			return &corev1.PodList{ListMeta: metav1.ListMeta{ResourceVersion: "100"}}, nil
+		}
+
+		_, stop := startInformers()
+		defer stop()
+		Eventually(func() []metav1.ListOptions {
+			mu.Lock()
+			defer mu.Unlock()
+			return lists
+		}).Should(HaveLen(2))
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(lists[0].ResourceVersion).To(Equal("500"))
+		Expect(lists[1].ResourceVersion).NotTo(Equal("500"))
+		Expect(lists[1].ResourceVersionMatch).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Informers with store observers", func() {
+	It("should call OnStore and OnDelete with the keys of the objects the informers store and remove", func() {
+		podGVK := corev1.SchemeGroupVersion.WithKind("Pod")
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(podGVK, meta.RESTScopeNamespace)
+
+		observed := make(chan string, 10)
+		watchers := make(chan *watch.FakeWatcher, 1)
+		informers := NewInformers(&rest.Config{Host: "http://localhost"}, &InformersOpts{
+			HTTPClient: http.DefaultClient,
+			Scheme:     scheme.Scheme,
+			Mapper:     mapper,
+			OnStore: func(gvk schema.GroupVersionKind, key client.ObjectKey, obj client.Object) {
+				observed <- fmt.Sprintf("store %s %s %s", gvk.Kind, key, obj.GetResourceVersion())
+			},
+			OnDelete: func(gvk schema.GroupVersionKind, key client.ObjectKey) {
+				observed <- fmt.Sprintf("delete %s %s", gvk.Kind, key)
+			},
+			NewListerWatcher: func(runtime.Object, string) (cache.ListerWatcher, error) {
+				return &cache.ListWatch{
+					ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+						return &corev1.PodList{
+							ListMeta: metav1.ListMeta{ResourceVersion: "100"},
+							Items: []corev1.Pod{
+								{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a", ResourceVersion: "99"}},
+							},
+						}, nil
+					},
+					WatchFunc: func(metav1.ListOptions) (watch.Interface, error) {
+						w := watch.NewFake()
+						watchers <- w
+						return w, nil
+					},
+				}, nil
+			},
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(informers.Start(ctx)).To(Succeed())
+		}()
+		_, _, err := informers.Get(ctx, podGVK, &corev1.Pod{}, &GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(observed).Should(Receive(Equal("store Pod default/a 99")))
+
+		var w *watch.FakeWatcher
+		Eventually(watchers).Should(Receive(&w))
+		w.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "b", ResourceVersion: "101"}})
+		Eventually(observed).Should(Receive(Equal("store Pod other/b 101")))
+		w.Modify(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a", ResourceVersion: "102"}})
+		Eventually(observed).Should(Receive(Equal("store Pod default/a 102")))
+		w.Delete(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "a", ResourceVersion: "103"}})
+		Eventually(observed).Should(Receive(Equal("delete Pod default/a")))
+		Consistently(observed).ShouldNot(Receive())
+	})
+})
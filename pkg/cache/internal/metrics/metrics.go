@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// CachedObjects is a prometheus metric which holds the number of objects
+	// held by the informers of the cache. It has four labels: group, version and
+	// kind refer to the GVK of the informer, and namespace refers to the namespace
+	// the informer is restricted to, which is empty if it isn't restricted.
+	// The metric is sampled periodically rather than updated on every event, and
+	// sums the objects of all informers with the same labels, see ObjectCounts.
+	CachedObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_runtime_cache_objects",
+		Help: "Number of objects held by the cache per informer",
+	}, []string{"group", "version", "kind", "namespace"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		CachedObjects,
+	)
+}
+
+// objectLabels are the label values of a CachedObjects series.
+type objectLabels struct {
+	group, version, kind, namespace string
+}
+
+// objectTotal is the sum of the object counts reported for a CachedObjects series,
+// and the number of ObjectCounts reporting them.
+type objectTotal struct {
+	objects   int
+	reporters int
+}
+
+var (
+	objectTotalsMu sync.Mutex
+	objectTotals   = map[objectLabels]*objectTotal{}
+)
+
+// ObjectCounts reports the number of objects held by the informers of one cache
+// restricted to a namespace in CachedObjects. The informers of different caches,
+// like the structured, unstructured and metadata informers of a GVK or the caches
+// of the types configured in ByObject, share labels, so CachedObjects sums the
+// counts of all ObjectCounts. A series is deleted once no ObjectCounts reports it.
+type ObjectCounts struct {
+	namespace string
+	counts    map[schema.GroupVersionKind]int
+}
+
+// NewObjectCounts returns an ObjectCounts for the informers restricted to namespace.
+func NewObjectCounts(namespace string) *ObjectCounts {
+	return &ObjectCounts{namespace: namespace, counts: map[schema.GroupVersionKind]int{}}
+}
+
+// Set replaces the counts reported by c with counts. Setting no counts stops c
+// from reporting anything.
+func (c *ObjectCounts) Set(counts map[schema.GroupVersionKind]int) {
+	objectTotalsMu.Lock()
+	defer objectTotalsMu.Unlock()
+
+	for gvk, objects := range c.counts {
+		if _, ok := counts[gvk]; !ok {
+			c.update(gvk, -objects, -1)
+		}
+	}
+	for gvk, objects := range counts {
+		if previous, ok := c.counts[gvk]; ok {
+			c.update(gvk, objects-previous, 0)
+		} else {
+			c.update(gvk, objects, 1)
+		}
+	}
+	c.counts = make(map[schema.GroupVersionKind]int, len(counts))
+	for gvk, objects := range counts {
+		c.counts[gvk] = objects
+	}
+}
+
+// update adds objects and reporters to the total of the series of gvk and
+// updates or deletes the series. It must be called with objectTotalsMu held.
+func (c *ObjectCounts) update(gvk schema.GroupVersionKind, objects, reporters int) {
+	labels := objectLabels{group: gvk.Group, version: gvk.Version, kind: gvk.Kind, namespace: c.namespace}
+	total, ok := objectTotals[labels]
+	if !ok {
+		total = &objectTotal{}
+		objectTotals[labels] = total
+	}
+	total.objects += objects
+	total.reporters += reporters
+	if total.reporters == 0 {
+		delete(objectTotals, labels)
+		CachedObjects.DeleteLabelValues(labels.group, labels.version, labels.kind, labels.namespace)
+		return
+	}
+	CachedObjects.WithLabelValues(labels.group, labels.version, labels.kind, labels.namespace).Set(float64(total.objects))
+}
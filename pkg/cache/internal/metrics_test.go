@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache/internal/metrics"
+)
+
+var _ = Describe("Informers object count metrics", func() {
+	const namespace = "object-count-metrics"
+	podGVK := corev1.SchemeGroupVersion.WithKind("Pod")
+
+	var (
+		ctx          context.Context
+		cancel       context.CancelFunc
+		newInformers func() *Informers
+		informers    *Informers
+	)
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		originalSamplePeriod := objectCountSamplePeriod
+		objectCountSamplePeriod = 10 * time.Millisecond
+		DeferCleanup(func() { objectCountSamplePeriod = originalSamplePeriod })
+
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(podGVK, meta.RESTScopeNamespace)
+
+		// Serve the informer from a static list of pods rather than the apiserver.
+		newInformer := func(_ cache.ListerWatcher, obj runtime.Object, resync time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+			lw := &cache.ListWatch{
+				ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+					pods := &corev1.PodList{}
+					for i := 0; i < 3; i++ {
+						pods.Items = append(pods.Items, corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+							Namespace: namespace,
+							Name:      fmt.Sprintf("pod-%d", i),
+						}})
+					}
+					return pods, nil
+				},
+				WatchFunc: func(metav1.ListOptions) (watch.Interface, error) {
+					return watch.NewFake(), nil
+				},
+			}
+			return cache.NewSharedIndexInformer(lw, obj, resync, indexers)
+		}
+
+		newInformers = func() *Informers {
+			informers := NewInformers(&rest.Config{Host: "http://localhost"}, &InformersOpts{
+				HTTPClient:  http.DefaultClient,
+				Scheme:      scheme.Scheme,
+				Mapper:      mapper,
+				Namespace:   namespace,
+				NewInformer: &newInformer,
+			})
+			stopped := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(stopped)
+				Expect(informers.Start(ctx)).To(Succeed())
+			}()
+			// Wait for the informers to stop reporting before the next test.
+			DeferCleanup(func() {
+				cancel()
+				Eventually(stopped).Should(BeClosed())
+			})
+			return informers
+		}
+		informers = newInformers()
+	})
+
+	It("should report the number of cached objects per GVK", func() {
+		_, _, err := informers.Get(ctx, podGVK, &corev1.Pod{}, &GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() float64 {
+			return testutil.ToFloat64(metrics.CachedObjects.WithLabelValues("", "v1", "Pod", namespace))
+		}).Should(Equal(3.0))
+	})
+
+	It("should stop reporting an informer once it is removed", func() {
+		_, _, err := informers.Get(ctx, podGVK, &corev1.Pod{}, &GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func() float64 {
+			return testutil.ToFloat64(metrics.CachedObjects.WithLabelValues("", "v1", "Pod", namespace))
+		}).Should(Equal(3.0))
+
+		informers.Remove(podGVK, &corev1.Pod{})
+		Consistently(func() bool {
+			return metrics.CachedObjects.DeleteLabelValues("", "v1", "Pod", namespace)
+		}).Should(BeFalse())
+	})
+
+	It("should sum the objects of the informers of different caches with the same labels", func() {
+		_, _, err := informers.Get(ctx, podGVK, &corev1.Pod{}, &GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		_, _, err = newInformers().Get(ctx, podGVK, &corev1.Pod{}, &GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() float64 {
+			return testutil.ToFloat64(metrics.CachedObjects.WithLabelValues("", "v1", "Pod", namespace))
+		}).Should(Equal(6.0))
+
+		By("removing the informer of one of the caches")
+		informers.Remove(podGVK, &corev1.Pod{})
+		Eventually(func() float64 {
+			return testutil.ToFloat64(metrics.CachedObjects.WithLabelValues("", "v1", "Pod", namespace))
+		}).Should(Equal(3.0))
+	})
+
+	It("should stop reporting the informers once they stop", func() {
+		_, _, err := informers.Get(ctx, podGVK, &corev1.Pod{}, &GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func() float64 {
+			return testutil.ToFloat64(metrics.CachedObjects.WithLabelValues("", "v1", "Pod", namespace))
+		}).Should(Equal(3.0))
+
+		cancel()
+		Eventually(func() int {
+			return testutil.CollectAndCount(metrics.CachedObjects)
+		}).Should(BeZero())
+	})
+})
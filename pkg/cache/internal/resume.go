@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+// resumeStorePeriod is how often informers store the resourceVersion they last observed
+// while they run. They also store it when they stop.
+var resumeStorePeriod = time.Minute
+
+// resumeStoreTimeout bounds the calls to a ResumeStore.
+const resumeStoreTimeout = 10 * time.Second
+
+// ResumeStore persists the resourceVersion informers last observed, see cache.Options.ResumeStore.
+type ResumeStore interface {
+	ResourceVersion(ctx context.Context, key string) (string, error)
+	StoreResourceVersion(ctx context.Context, key, resourceVersion string) error
+}
+
+// resumeKey returns the key the resourceVersion of the informer of gvk in namespace is stored with,
+// e.g. "Deployment.v1.apps" or "Pod.v1_default". It only consists of characters valid in ConfigMap keys.
+func resumeKey(gvk schema.GroupVersionKind, namespace string) string {
+	key := strings.TrimSuffix(strings.Join([]string{gvk.Kind, gvk.Version, gvk.Group}, "."), ".")
+	if namespace != "" {
+		key += "_" + namespace
+	}
+	return key
+}
+
+// resumer keeps an informer from starting from an older state than the resourceVersion stored
+// for it when it first lists, and stores the resourceVersion it observes. It doesn't resume the
+// watch of the informer, which still lists all objects.
+type resumer struct {
+	store ResumeStore
+	key   string
+
+	// listed is true once the informer listed for the first time.
+	listed atomic.Bool
+
+	// mu guards stored.
+	mu sync.Mutex
+	// stored is the resourceVersion last stored.
+	stored string
+}
+
+// list lists with lw. The first list of the informer is for a resourceVersion not older than
+// the stored one, so that the informer doesn't start from an older state than it observed
+// before. It is unpaginated, like a list for any resourceVersion served from the watch cache. It falls back to opts if the API server can't
+// serve the stored resourceVersion, e.g. because it is too old or was issued by another etcd.
+func (r *resumer) list(lw cache.ListerWatcher, opts metav1.ListOptions) (runtime.Object, error) {
+	if r == nil || opts.Continue != "" || r.listed.Swap(true) {
+		return lw.List(opts)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resumeStoreTimeout)
+	defer cancel()
+	resourceVersion, err := r.store.ResourceVersion(ctx, r.key)
+	if err != nil {
+		log.Error(err, "Failed to get the stored resourceVersion of the informer, listing from scratch", "informer", r.key)
+		return lw.List(opts)
+	}
+	if resourceVersion == "" {
+		return lw.List(opts)
+	}
+
+	resumeOpts := opts
+	resumeOpts.ResourceVersion = resourceVersion
+	resumeOpts.ResourceVersionMatch = metav1.ResourceVersionMatchNotOlderThan
+	resumeOpts.Limit = 0
+	list, err := lw.List(resumeOpts)
+	if apierrors.IsGone(err) || apierrors.IsResourceExpired(err) || isTooLargeResourceVersion(err) {
+		log.V(1).Info("Stored resourceVersion of the informer can't be served, listing from scratch",
+			"informer", r.key, "resourceVersion", resourceVersion, "reason", err.Error())
+		return lw.List(opts)
+	}
+	return list, err
+}
+
+// isTooLargeResourceVersion returns true if err reports that the API server can't serve a
+// resourceVersion yet, e.g. one issued by another etcd.
+func isTooLargeResourceVersion(err error) bool {
+	return apierrors.IsTimeout(err) && apierrors.HasStatusCause(err, metav1.CauseTypeResourceVersionTooLarge)
+}
+
+// storeUntil stores the resourceVersion informer last observed periodically until stop is closed.
+func (r *resumer) storeUntil(informer cache.SharedIndexInformer, stop <-chan struct{}) {
+	ticker := time.NewTicker(resumeStorePeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			r.storeResourceVersion(informer)
+		}
+	}
+}
+
+// storeResourceVersion stores the resourceVersion informer last observed, if it changed.
+func (r *resumer) storeResourceVersion(informer cache.SharedIndexInformer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	resourceVersion := informer.LastSyncResourceVersion()
+	if resourceVersion == "" || resourceVersion == r.stored {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), resumeStoreTimeout)
+	defer cancel()
+	if err := r.store.StoreResourceVersion(ctx, r.key, resourceVersion); err != nil {
+		log.Error(err, "Failed to store the resourceVersion of the informer", "informer", r.key)
+		return
+	}
+	r.stored = resourceVersion
+}
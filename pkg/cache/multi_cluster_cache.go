@@ -0,0 +1,135 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MultiCluster holds a Cache for each of a set of named clusters, e.g. to watch objects in
+// several clusters from a single manager rather than running one manager per cluster.
+// Reads are not fanned out, use Cluster to read from the Cache of a given cluster. Events are
+// delivered with the name of their cluster by source.MultiClusterKind.
+//
+// MultiCluster is a Runnable, add it to the manager to start the caches of all clusters.
+//
+// MultiCluster is experimental and subject to future change.
+type MultiCluster struct {
+	caches map[string]Cache
+}
+
+// NewMultiCluster creates a Cache with the given options for each of the given clusters, keyed
+// by their name. The HTTPClient and Mapper of opts must not be set, as they are specific to a
+// cluster: each Cache gets its own, created from the rest.Config of its cluster.
+func NewMultiCluster(configs map[string]*rest.Config, opts Options) (*MultiCluster, error) {
+	if opts.HTTPClient != nil || opts.Mapper != nil {
+		return nil, errors.New("the HTTPClient and Mapper of the options of a MultiCluster cache must not be set, they are specific to each cluster")
+	}
+	caches := make(map[string]Cache, len(configs))
+	for name, config := range configs {
+		c, err := New(config, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cache for cluster %q: %w", name, err)
+		}
+		caches[name] = c
+	}
+	return NewMultiClusterFromCaches(caches), nil
+}
+
+// NewMultiClusterFromCaches returns a MultiCluster holding the given caches, keyed by the name of
+// their cluster, e.g. the caches of cluster.Clusters.
+func NewMultiClusterFromCaches(caches map[string]Cache) *MultiCluster {
+	return &MultiCluster{caches: caches}
+}
+
+// ClusterNames returns the sorted names of the clusters.
+func (m *MultiCluster) ClusterNames() []string {
+	names := make([]string, 0, len(m.caches))
+	for name := range m.caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Cluster returns the Cache of the named cluster, if there is such a cluster.
+func (m *MultiCluster) Cluster(name string) (Cache, bool) {
+	c, ok := m.caches[name]
+	return c, ok
+}
+
+// GetInformer fetches or constructs an informer for the given object in every cluster, keyed by
+// the name of the cluster. See Informers.GetInformer.
+func (m *MultiCluster) GetInformer(ctx context.Context, obj client.Object, opts ...InformerGetOption) (map[string]Informer, error) {
+	informers := make(map[string]Informer, len(m.caches))
+	for name, c := range m.caches {
+		informer, err := c.GetInformer(ctx, obj, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get informer for cluster %q: %w", name, err)
+		}
+		informers[name] = informer
+	}
+	return informers, nil
+}
+
+// IndexField adds an index with the given field name on the given object type to the cache of
+// every cluster. See FieldIndexer.IndexField.
+func (m *MultiCluster) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	for name, c := range m.caches {
+		if err := c.IndexField(ctx, obj, field, extractValue); err != nil {
+			return fmt.Errorf("failed to add index to cache of cluster %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Start runs the caches of all clusters and blocks until the context is done or one of them
+// failed to start.
+func (m *MultiCluster) Start(ctx context.Context) error {
+	errs := make(chan error, len(m.caches))
+	for name, c := range m.caches {
+		go func(name string, c Cache) {
+			if err := c.Start(ctx); err != nil {
+				errs <- fmt.Errorf("failed to start cache for cluster %q: %w", name, err)
+			}
+		}(name, c)
+	}
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-errs:
+		return err
+	}
+}
+
+// WaitForCacheSync waits for the caches of all clusters to be synced.
+func (m *MultiCluster) WaitForCacheSync(ctx context.Context) bool {
+	synced := true
+	for _, c := range m.caches {
+		if !c.WaitForCacheSync(ctx) {
+			synced = false
+		}
+	}
+	return synced
+}
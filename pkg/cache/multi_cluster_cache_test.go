@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+)
+
+var _ = Describe("MultiCluster", func() {
+	var (
+		east, west *informertest.FakeInformers
+		mc         *cache.MultiCluster
+	)
+
+	BeforeEach(func() {
+		east = &informertest.FakeInformers{}
+		west = &informertest.FakeInformers{}
+		mc = cache.NewMultiClusterFromCaches(map[string]cache.Cache{"west": west, "east": east})
+	})
+
+	It("should return the sorted names of the clusters", func() {
+		Expect(mc.ClusterNames()).To(Equal([]string{"east", "west"}))
+	})
+
+	It("should return the cache of a cluster", func() {
+		c, ok := mc.Cluster("east")
+		Expect(ok).To(BeTrue())
+		Expect(c).To(BeIdenticalTo(east))
+
+		_, ok = mc.Cluster("north")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should get an informer in every cluster", func() {
+		ctx := context.Background()
+		informers, err := mc.GetInformer(ctx, &corev1.Pod{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(informers).To(HaveLen(2))
+
+		eastInformer, err := east.FakeInformerFor(ctx, &corev1.Pod{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(informers["east"]).To(BeIdenticalTo(eastInformer))
+		westInformer, err := west.FakeInformerFor(ctx, &corev1.Pod{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(informers["west"]).To(BeIdenticalTo(westInformer))
+	})
+
+	It("should wait for the caches of all clusters to sync", func() {
+		synced := true
+		west.Synced = &synced
+		notSynced := false
+		east.Synced = &notSynced
+		Expect(mc.WaitForCacheSync(context.Background())).To(BeFalse())
+
+		east.Synced = &synced
+		Expect(mc.WaitForCacheSync(context.Background())).To(BeTrue())
+	})
+
+	It("should refuse options specific to a cluster", func() {
+		_, err := cache.NewMultiCluster(map[string]*rest.Config{"east": cfg}, cache.Options{Mapper: meta.NewDefaultRESTMapper(nil)})
+		Expect(err).To(MatchError(ContainSubstring("must not be set")))
+	})
+})
@@ -0,0 +1,124 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package causality
+
+import (
+	"context"
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// Annotation is the annotation that carries the Chain of the reconcile that last
+// wrote an object, as a JSON list of Links.
+const Annotation = "controller-runtime.sigs.k8s.io/causality"
+
+// MaxLength is the maximum number of Links in a Chain. Appending to a full Chain
+// drops its oldest Link, so that the Annotation cannot grow without bounds.
+const MaxLength = 10
+
+// Link is a single reconcile in a Chain.
+type Link struct {
+	// Controller is the name of the controller that ran the reconcile.
+	Controller string `json:"controller"`
+
+	// Key identifies what was reconciled, e.g. the namespace/name of a reconcile.Request.
+	Key string `json:"key"`
+}
+
+// Chain is a list of reconciles, each one caused by a write of the previous one.
+// The oldest reconcile comes first.
+type Chain []Link
+
+// Append returns a copy of c with l appended. If the result would be longer than
+// MaxLength, the oldest Links are dropped.
+func (c Chain) Append(l Link) Chain {
+	if len(c) >= MaxLength {
+		c = c[len(c)-MaxLength+1:]
+	}
+	out := make(Chain, 0, len(c)+1)
+	out = append(out, c...)
+	return append(out, l)
+}
+
+type chainKey struct{}
+
+// FromContext returns the Chain of the current reconcile, or nil if there is none.
+func FromContext(ctx context.Context) Chain {
+	c, _ := ctx.Value(chainKey{}).(Chain)
+	return c
+}
+
+// IntoContext returns a copy of ctx carrying c, to be retrieved with FromContext.
+func IntoContext(ctx context.Context, c Chain) context.Context {
+	return context.WithValue(ctx, chainKey{}, c)
+}
+
+// FromObject returns the Chain recorded in the Annotation of obj. It returns nil if
+// obj has no such annotation or if it can't be parsed.
+func FromObject(obj metav1.Object) Chain {
+	value, ok := obj.GetAnnotations()[Annotation]
+	if !ok {
+		return nil
+	}
+	var c Chain
+	if err := json.Unmarshal([]byte(value), &c); err != nil {
+		return nil
+	}
+	if len(c) > MaxLength {
+		c = c[len(c)-MaxLength:]
+	}
+	return c
+}
+
+// FromEvent returns the Chain recorded on the object of evt, using the new object
+// of an event.UpdateEvent. It returns nil if evt is not one of the events of the
+// event package for client.Objects.
+func FromEvent(evt any) Chain {
+	var obj client.Object
+	switch e := evt.(type) {
+	case event.CreateEvent:
+		obj = e.Object
+	case event.UpdateEvent:
+		obj = e.ObjectNew
+	case event.DeleteEvent:
+		obj = e.Object
+	case event.GenericEvent:
+		obj = e.Object
+	}
+	if obj == nil {
+		return nil
+	}
+	return FromObject(obj)
+}
+
+// setAnnotation records c in the Annotation of obj.
+func setAnnotation(obj metav1.Object, c Chain) error {
+	value, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[Annotation] = string(value)
+	obj.SetAnnotations(annotations)
+	return nil
+}
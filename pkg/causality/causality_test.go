@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package causality_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/causality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+var _ = Describe("Chain", func() {
+	It("should be bounded to MaxLength, dropping the oldest links", func() {
+		var chain causality.Chain
+		for i := 0; i < causality.MaxLength+2; i++ {
+			chain = chain.Append(causality.Link{Controller: "c", Key: fmt.Sprint(i)})
+		}
+		Expect(chain).To(HaveLen(causality.MaxLength))
+		Expect(chain[0].Key).To(Equal("2"))
+		Expect(chain[causality.MaxLength-1].Key).To(Equal(fmt.Sprint(causality.MaxLength + 1)))
+	})
+
+	It("should not modify the chain it is appended to", func() {
+		chain := make(causality.Chain, 1, 2)
+		chain[0] = causality.Link{Controller: "a", Key: "x"}
+		_ = chain.Append(causality.Link{Controller: "b", Key: "y"})
+		Expect(chain[:2][1]).To(Equal(causality.Link{}))
+	})
+})
+
+var _ = Describe("NewClient", func() {
+	var cl client.Client
+
+	BeforeEach(func() {
+		cl = causality.NewClient(fake.NewClientBuilder().Build())
+	})
+
+	It("should record the chain of the context on written objects", func() {
+		chain := causality.Chain{{Controller: "a", Key: "foo/x"}}
+		ctx := causality.IntoContext(context.Background(), chain)
+
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "y"}}
+		Expect(cl.Create(ctx, cm)).To(Succeed())
+
+		got := &corev1.ConfigMap{}
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(cm), got)).To(Succeed())
+		Expect(causality.FromObject(got)).To(Equal(chain))
+		Expect(causality.FromEvent(event.UpdateEvent{ObjectOld: &corev1.ConfigMap{}, ObjectNew: got})).To(Equal(chain))
+
+		By("Patching the object with a longer chain")
+		chain = chain.Append(causality.Link{Controller: "b", Key: "foo/y"})
+		patch := client.MergeFrom(got.DeepCopy())
+		Expect(cl.Patch(causality.IntoContext(ctx, chain), got, patch)).To(Succeed())
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(cm), got)).To(Succeed())
+		Expect(causality.FromObject(got)).To(Equal(chain))
+	})
+
+	It("should leave objects untouched when the context has no chain", func() {
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "y"}}
+		Expect(cl.Create(context.Background(), cm)).To(Succeed())
+		Expect(cm.GetAnnotations()).NotTo(HaveKey(causality.Annotation))
+	})
+})
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package causality
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewClient wraps a Client so that Create, Update and Patch record the Chain of
+// the context they are called with in the Annotation of the object they write.
+// Writes with a context without a Chain leave the object untouched.
+//
+// Patch only propagates the Chain if the patch is computed from the object, e.g.
+// with client.MergeFrom or client.Apply; the data of a client.RawPatch is sent as is.
+func NewClient(c client.Client) client.Client {
+	return &clientWithCausality{Client: c}
+}
+
+type clientWithCausality struct {
+	client.Client
+}
+
+func (c *clientWithCausality) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := annotate(ctx, obj); err != nil {
+		return err
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *clientWithCausality) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := annotate(ctx, obj); err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *clientWithCausality) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := annotate(ctx, obj); err != nil {
+		return err
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func annotate(ctx context.Context, obj client.Object) error {
+	chain := FromContext(ctx)
+	if len(chain) == 0 {
+		return nil
+	}
+	return setAnnotation(obj, chain)
+}
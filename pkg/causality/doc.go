@@ -0,0 +1,30 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package causality traces which reconciles led to the current one, across controllers.
+
+A controller created with the TrackCausality option puts a Chain into the context of
+every reconcile: the Chain recorded on the object that triggered the reconcile, with a
+Link for the reconcile itself appended. Writes made through a Client returned by
+NewClient record that Chain in an annotation of the written objects, so that the
+reconciles they trigger in other controllers extend it in turn.
+
+For example, if controller A creates an object that controller B watches and B in turn
+updates an object watched by controller C, the reconcile of C sees the Chain
+[A, B, C]. Chains are bounded to MaxLength Links.
+*/
+package causality
@@ -19,6 +19,7 @@ package certwatcher
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"sync"
 	"time"
@@ -151,7 +152,7 @@ func (cw *CertWatcher) ReadCertificate() error {
 	cert, err := tls.LoadX509KeyPair(cw.certPath, cw.keyPath)
 	if err != nil {
 		metrics.ReadCertificateErrors.Inc()
-		return err
+		return fmt.Errorf("failed to load certificate %q and key %q: %w", cw.certPath, cw.keyPath, err)
 	}
 
 	cw.Lock()
@@ -171,6 +172,35 @@ func (cw *CertWatcher) ReadCertificate() error {
 	return nil
 }
 
+// ValidateCertificate checks that the current certificate is valid for the
+// given server name, e.g. the DNS name of the service fronting the webhook.
+// An empty serverName skips the check. Only the currently loaded certificate is
+// validated, later rotations are not.
+func (cw *CertWatcher) ValidateCertificate(serverName string) error {
+	cw.RLock()
+	cert := cw.currentCert
+	cw.RUnlock()
+
+	if cert == nil || len(cert.Certificate) == 0 {
+		return fmt.Errorf("no certificate loaded from %q", cw.certPath)
+	}
+	if serverName == "" {
+		return nil
+	}
+
+	leaf := cert.Leaf
+	if leaf == nil {
+		var err error
+		if leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return fmt.Errorf("failed to parse certificate %q: %w", cw.certPath, err)
+		}
+	}
+	if err := leaf.VerifyHostname(serverName); err != nil {
+		return fmt.Errorf("certificate %q is not valid for server name %q: %w", cw.certPath, serverName, err)
+	}
+	return nil
+}
+
 func (cw *CertWatcher) handleEvent(event fsnotify.Event) {
 	// Only care about events which may modify the contents of the file.
 	if !(isWrite(event) || isRemove(event) || isCreate(event)) {
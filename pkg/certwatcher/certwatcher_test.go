@@ -44,6 +44,45 @@ var _ = Describe("CertWatcher", func() {
 			_, err := certwatcher.New("", "")
 			Expect(err).To(HaveOccurred())
 		})
+
+		It("should error clearly with a mismatched cert/key", func() {
+			otherCertPath, otherKeyPath := certPath+".other", keyPath+".other"
+			DeferCleanup(func() {
+				_ = os.Remove(otherCertPath)
+				_ = os.Remove(otherKeyPath)
+			})
+			Expect(writeCerts(certPath, keyPath, "127.0.0.1")).To(Succeed())
+			Expect(writeCerts(otherCertPath, otherKeyPath, "127.0.0.1")).To(Succeed())
+
+			_, err := certwatcher.New(certPath, otherKeyPath)
+			Expect(err).To(MatchError(ContainSubstring("failed to load certificate %q and key %q", certPath, otherKeyPath)))
+			Expect(err).To(MatchError(ContainSubstring("private key does not match public key")))
+		})
+	})
+
+	var _ = Describe("certwatcher ValidateCertificate", func() {
+		var watcher *certwatcher.CertWatcher
+
+		BeforeEach(func() {
+			Expect(writeCerts(certPath, keyPath, "127.0.0.1")).To(Succeed())
+
+			var err error
+			watcher, err = certwatcher.New(certPath, keyPath)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should accept a certificate valid for the server name", func() {
+			Expect(watcher.ValidateCertificate("127.0.0.1")).To(Succeed())
+		})
+
+		It("should skip the check without a server name", func() {
+			Expect(watcher.ValidateCertificate("")).To(Succeed())
+		})
+
+		It("should reject a certificate not valid for the server name", func() {
+			err := watcher.ValidateCertificate("webhook-service.default.svc")
+			Expect(err).To(MatchError(ContainSubstring("is not valid for server name \"webhook-service.default.svc\"")))
+		})
 	})
 
 	var _ = Describe("certwatcher Start", func() {
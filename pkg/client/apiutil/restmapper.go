@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -32,7 +33,27 @@ import (
 
 // NewDynamicRESTMapper returns a dynamic RESTMapper for cfg. The dynamic
 // RESTMapper dynamically discovers resource types at runtime.
+//
+// The returned RESTMapper implements meta.ResettableRESTMapper. Reset drops all
+// discovery information, e.g. to pick up new versions of already discovered API
+// groups after installing CRDs, without restarting.
 func NewDynamicRESTMapper(cfg *rest.Config, httpClient *http.Client) (meta.RESTMapper, error) {
+	return NewDynamicRESTMapperWithOptions(cfg, httpClient, DynamicRESTMapperOptions{})
+}
+
+// DynamicRESTMapperOptions are the options of a dynamic RESTMapper.
+type DynamicRESTMapperOptions struct {
+	// TTL is the duration the discovery information of an API group is used for
+	// after it was fetched. Once it expired, it is discovered again the next time
+	// the group is accessed. Information that turns out to be outdated, e.g. for a
+	// version that is not served anymore, is dropped right away regardless of TTL.
+	// Defaults to 0, which keeps the discovery information until Reset is called.
+	TTL time.Duration
+}
+
+// NewDynamicRESTMapperWithOptions returns a dynamic RESTMapper for cfg like
+// NewDynamicRESTMapper, configured with opts.
+func NewDynamicRESTMapperWithOptions(cfg *rest.Config, httpClient *http.Client, opts DynamicRESTMapperOptions) (meta.RESTMapper, error) {
 	if httpClient == nil {
 		return nil, fmt.Errorf("httpClient must not be nil, consider using rest.HTTPClientFor(c) to create a client")
 	}
@@ -46,6 +67,7 @@ func NewDynamicRESTMapper(cfg *rest.Config, httpClient *http.Client) (meta.RESTM
 		client:      client,
 		knownGroups: map[string]*restmapper.APIGroupResources{},
 		apiGroups:   map[string]*metav1.APIGroup{},
+		ttl:         opts.TTL,
 	}, nil
 }
 
@@ -57,12 +79,35 @@ type mapper struct {
 	knownGroups map[string]*restmapper.APIGroupResources
 	apiGroups   map[string]*metav1.APIGroup
 
+	// ttl is the duration the information about a group in knownGroups and
+	// apiGroups is used for, if greater than 0.
+	ttl time.Duration
+	// fetchedAt is when the information about each group in knownGroups was fetched.
+	fetchedAt map[string]time.Time
+	// now returns the current time, defaults to time.Now.
+	now func() time.Time
+
 	// mutex to provide thread-safe mapper reloading.
 	mu sync.RWMutex
 }
 
+var _ meta.ResettableRESTMapper = &mapper{}
+
+// Reset implements meta.ResettableRESTMapper. It drops all discovery
+// information, which is then lazily fetched again on the next call.
+func (m *mapper) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mapper = restmapper.NewDiscoveryRESTMapper([]*restmapper.APIGroupResources{})
+	m.knownGroups = map[string]*restmapper.APIGroupResources{}
+	m.apiGroups = map[string]*metav1.APIGroup{}
+	m.fetchedAt = nil
+}
+
 // KindFor implements Mapper.KindFor.
 func (m *mapper) KindFor(resource schema.GroupVersionResource) (schema.GroupVersionKind, error) {
+	m.expireGroup(resource.Group)
 	res, err := m.getMapper().KindFor(resource)
 	if meta.IsNoMatchError(err) {
 		if err := m.addKnownGroupAndReload(resource.Group, resource.Version); err != nil {
@@ -76,6 +121,7 @@ func (m *mapper) KindFor(resource schema.GroupVersionResource) (schema.GroupVers
 
 // KindsFor implements Mapper.KindsFor.
 func (m *mapper) KindsFor(resource schema.GroupVersionResource) ([]schema.GroupVersionKind, error) {
+	m.expireGroup(resource.Group)
 	res, err := m.getMapper().KindsFor(resource)
 	if meta.IsNoMatchError(err) {
 		if err := m.addKnownGroupAndReload(resource.Group, resource.Version); err != nil {
@@ -89,6 +135,7 @@ func (m *mapper) KindsFor(resource schema.GroupVersionResource) ([]schema.GroupV
 
 // ResourceFor implements Mapper.ResourceFor.
 func (m *mapper) ResourceFor(input schema.GroupVersionResource) (schema.GroupVersionResource, error) {
+	m.expireGroup(input.Group)
 	res, err := m.getMapper().ResourceFor(input)
 	if meta.IsNoMatchError(err) {
 		if err := m.addKnownGroupAndReload(input.Group, input.Version); err != nil {
@@ -102,6 +149,7 @@ func (m *mapper) ResourceFor(input schema.GroupVersionResource) (schema.GroupVer
 
 // ResourcesFor implements Mapper.ResourcesFor.
 func (m *mapper) ResourcesFor(input schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+	m.expireGroup(input.Group)
 	res, err := m.getMapper().ResourcesFor(input)
 	if meta.IsNoMatchError(err) {
 		if err := m.addKnownGroupAndReload(input.Group, input.Version); err != nil {
@@ -115,6 +163,7 @@ func (m *mapper) ResourcesFor(input schema.GroupVersionResource) ([]schema.Group
 
 // RESTMapping implements Mapper.RESTMapping.
 func (m *mapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	m.expireGroup(gk.Group)
 	res, err := m.getMapper().RESTMapping(gk, versions...)
 	if meta.IsNoMatchError(err) {
 		if err := m.addKnownGroupAndReload(gk.Group, versions...); err != nil {
@@ -128,6 +177,7 @@ func (m *mapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RES
 
 // RESTMappings implements Mapper.RESTMappings.
 func (m *mapper) RESTMappings(gk schema.GroupKind, versions ...string) ([]*meta.RESTMapping, error) {
+	m.expireGroup(gk.Group)
 	res, err := m.getMapper().RESTMappings(gk, versions...)
 	if meta.IsNoMatchError(err) {
 		if err := m.addKnownGroupAndReload(gk.Group, versions...); err != nil {
@@ -222,15 +272,57 @@ func (m *mapper) addKnownGroupAndReload(groupName string, versions ...string) er
 
 	// Update data in the cache.
 	m.knownGroups[groupName] = groupResources
+	if m.fetchedAt == nil {
+		m.fetchedAt = map[string]time.Time{}
+	}
+	m.fetchedAt[groupName] = m.currentTime()
 
 	// Finally, update the group with received information and regenerate the mapper.
+	m.reloadLocked()
+	return nil
+}
+
+// reloadLocked regenerates the mapper from the known groups.
+func (m *mapper) reloadLocked() {
 	updatedGroupResources := make([]*restmapper.APIGroupResources, 0, len(m.knownGroups))
 	for _, agr := range m.knownGroups {
 		updatedGroupResources = append(updatedGroupResources, agr)
 	}
 
 	m.mapper = restmapper.NewDiscoveryRESTMapper(updatedGroupResources)
-	return nil
+}
+
+// expireGroup drops the information about the group if it was fetched longer than
+// ttl ago, so it is discovered again by the call that follows.
+func (m *mapper) expireGroup(groupName string) {
+	if m.ttl <= 0 {
+		return
+	}
+
+	m.mu.RLock()
+	fetchedAt, ok := m.fetchedAt[groupName]
+	m.mu.RUnlock()
+	if !ok || m.currentTime().Sub(fetchedAt) < m.ttl {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Another call might have expired or refetched the group in the meantime.
+	if fetchedAt, ok := m.fetchedAt[groupName]; !ok || m.currentTime().Sub(fetchedAt) < m.ttl {
+		return
+	}
+	delete(m.fetchedAt, groupName)
+	delete(m.knownGroups, groupName)
+	delete(m.apiGroups, groupName)
+	m.reloadLocked()
+}
+
+func (m *mapper) currentTime() time.Time {
+	if m.now != nil {
+		return m.now()
+	}
+	return time.Now()
 }
 
 // findAPIGroupByNameLocked returns API group by its name.
@@ -30,6 +30,7 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
@@ -669,6 +670,65 @@ func TestLazyRestMapperProvider(t *testing.T) {
 		g.Expect(err).NotTo(gmg.HaveOccurred())
 		g.Expect(mapping.Resource.Version).To(gmg.Equal("v1"))
 	})
+
+	t.Run("LazyRESTMapper should discover new versions of a known group after Reset", func(t *testing.T) {
+		g := gmg.NewWithT(t)
+		ctx := context.Background()
+
+		httpClient, err := rest.HTTPClientFor(restCfg)
+		g.Expect(err).NotTo(gmg.HaveOccurred())
+
+		lazyRestMapper, err := apiutil.NewDynamicRESTMapper(restCfg, httpClient)
+		g.Expect(err).NotTo(gmg.HaveOccurred())
+
+		// Fill the cache for the crew.example.com group.
+		_, err = lazyRestMapper.RESTMapping(schema.GroupKind{Group: "crew.example.com", Kind: "driver"})
+		g.Expect(err).NotTo(gmg.HaveOccurred())
+
+		s := scheme.Scheme
+		err = apiextensionsv1.AddToScheme(s)
+		g.Expect(err).NotTo(gmg.HaveOccurred())
+
+		c, err := client.New(restCfg, client.Options{Scheme: s})
+		g.Expect(err).NotTo(gmg.HaveOccurred())
+
+		// Register a CRD in the known group with a version the cache doesn't know about.
+		crd := newCRD(ctx, g, c, "crew.example.com", "Conductor", "conductors")
+		v3 := crd.Spec.Versions[0]
+		v3.Name = "v3"
+		v3.Storage = true
+		v3.Served = true
+		crd.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{v3}
+		g.Expect(c.Create(ctx, crd)).To(gmg.Succeed())
+		t.Cleanup(func() {
+			g.Expect(c.Delete(ctx, crd)).To(gmg.Succeed())
+		})
+
+		// Wait until the CRD is registered.
+		discClient, err := discovery.NewDiscoveryClientForConfigAndClient(restCfg, httpClient)
+		g.Expect(err).NotTo(gmg.HaveOccurred())
+		g.Eventually(func(g gmg.Gomega) {
+			_, err = discClient.ServerResourcesForGroupVersion("crew.example.com/v3")
+			g.Expect(err).NotTo(gmg.HaveOccurred())
+		}).Should(gmg.Succeed(), "v3 should be available")
+
+		// The cached group doesn't have v3, so the new kind can't be found.
+		_, err = lazyRestMapper.RESTMapping(schema.GroupKind{Group: "crew.example.com", Kind: "Conductor"})
+		g.Expect(err).To(beNoMatchError())
+
+		lazyRestMapper.(meta.ResettableRESTMapper).Reset()
+
+		mapping, err := lazyRestMapper.RESTMapping(schema.GroupKind{Group: "crew.example.com", Kind: "Conductor"})
+		g.Expect(err).NotTo(gmg.HaveOccurred())
+		g.Expect(mapping.GroupVersionKind.Version).To(gmg.Equal("v3"))
+
+		// A client using the mapper can now read the new kind.
+		cc, err := client.New(restCfg, client.Options{Scheme: s, Mapper: lazyRestMapper})
+		g.Expect(err).NotTo(gmg.HaveOccurred())
+		conductors := &unstructured.UnstructuredList{}
+		conductors.SetGroupVersionKind(schema.GroupVersionKind{Group: "crew.example.com", Version: "v3", Kind: "ConductorList"})
+		g.Expect(cc.List(ctx, conductors)).To(gmg.Succeed())
+	})
 }
 
 // createNewCRD creates a new CRD with the given group, kind, and plural and returns it.
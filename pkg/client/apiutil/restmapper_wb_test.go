@@ -18,9 +18,13 @@ package apiutil
 
 import (
 	"testing"
+	"time"
 
 	gmg "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/restmapper"
 )
@@ -201,3 +205,87 @@ func TestLazyRestMapper_fetchGroupVersionResourcesLocked_CacheInvalidation(t *te
 		})
 	}
 }
+
+func TestLazyRestMapper_Reset(t *testing.T) {
+	g := gmg.NewWithT(t)
+
+	discovery := fake.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "group1/v1",
+		APIResources: []metav1.APIResource{{Name: "resources1", Kind: "Kind1", Namespaced: true}},
+	}}
+	m := &mapper{
+		mapper:      restmapper.NewDiscoveryRESTMapper([]*restmapper.APIGroupResources{}),
+		client:      discovery,
+		apiGroups:   map[string]*metav1.APIGroup{},
+		knownGroups: map[string]*restmapper.APIGroupResources{},
+	}
+
+	_, err := m.RESTMapping(schema.GroupKind{Group: "group1", Kind: "Kind1"})
+	g.Expect(err).NotTo(gmg.HaveOccurred())
+
+	// A kind in a new version of the already discovered group is not found,
+	// because the versions of the group are cached.
+	discovery.Resources = append(discovery.Resources, &metav1.APIResourceList{
+		GroupVersion: "group1/v2",
+		APIResources: []metav1.APIResource{{Name: "resources2", Kind: "Kind2", Namespaced: true}},
+	})
+	_, err = m.RESTMapping(schema.GroupKind{Group: "group1", Kind: "Kind2"})
+	g.Expect(meta.IsNoMatchError(err)).To(gmg.BeTrue())
+
+	m.Reset()
+
+	mapping, err := m.RESTMapping(schema.GroupKind{Group: "group1", Kind: "Kind2"})
+	g.Expect(err).NotTo(gmg.HaveOccurred())
+	g.Expect(mapping.GroupVersionKind).To(gmg.Equal(schema.GroupVersionKind{Group: "group1", Version: "v2", Kind: "Kind2"}))
+}
+
+func TestLazyRestMapper_TTL(t *testing.T) {
+	g := gmg.NewWithT(t)
+
+	discovery := fake.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "group1/v1",
+		APIResources: []metav1.APIResource{{Name: "resources1", Kind: "Kind1", Namespaced: true}},
+	}}
+	now := time.Now()
+	m := &mapper{
+		mapper:      restmapper.NewDiscoveryRESTMapper([]*restmapper.APIGroupResources{}),
+		client:      discovery,
+		apiGroups:   map[string]*metav1.APIGroup{},
+		knownGroups: map[string]*restmapper.APIGroupResources{},
+		ttl:         time.Minute,
+		now:         func() time.Time { return now },
+	}
+
+	resourceFetches := func() int {
+		count := 0
+		for _, action := range discovery.Actions() {
+			if action.GetVerb() == "get" && action.GetResource().Resource == "resource" {
+				count++
+			}
+		}
+		return count
+	}
+
+	_, err := m.RESTMapping(schema.GroupKind{Group: "group1", Kind: "Kind1"})
+	g.Expect(err).NotTo(gmg.HaveOccurred())
+	g.Expect(resourceFetches()).To(gmg.Equal(1))
+
+	// Within the TTL the cached discovery information is used.
+	now = now.Add(30 * time.Second)
+	_, err = m.RESTMapping(schema.GroupKind{Group: "group1", Kind: "Kind1"})
+	g.Expect(err).NotTo(gmg.HaveOccurred())
+	g.Expect(resourceFetches()).To(gmg.Equal(1))
+
+	// After the TTL the group is discovered again, including new versions.
+	discovery.Resources = append(discovery.Resources, &metav1.APIResourceList{
+		GroupVersion: "group1/v2",
+		APIResources: []metav1.APIResource{{Name: "resources2", Kind: "Kind2", Namespaced: true}},
+	})
+	now = now.Add(time.Minute)
+	mapping, err := m.RESTMapping(schema.GroupKind{Group: "group1", Kind: "Kind2"})
+	g.Expect(err).NotTo(gmg.HaveOccurred())
+	g.Expect(mapping.GroupVersionKind).To(gmg.Equal(schema.GroupVersionKind{Group: "group1", Version: "v2", Kind: "Kind2"}))
+	g.Expect(resourceFetches()).To(gmg.BeNumerically(">", 1))
+}
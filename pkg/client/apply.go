@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// ApplyConfig applies the given apply configuration with server-side apply, e.g. a
+// *appsv1ac.DeploymentApplyConfiguration built with the generated builders of
+// k8s.io/client-go/applyconfigurations:
+//
+//	dep := appsv1ac.Deployment("foo", "default").
+//		WithSpec(appsv1ac.DeploymentSpec().WithReplicas(3))
+//	err := client.ApplyConfig(ctx, c, dep, client.FieldOwner("my-controller"), client.ForceOwnership)
+//
+// Unlike Patch with Apply, only the fields set in the apply configuration are sent, they
+// are not round-tripped through a typed object, which would add its zero values. The apply
+// configuration must set its kind, apiVersion and name, and its namespace if it is namespaced.
+//
+// The field manager is determined as for Patch, i.e. from opts, the context or the default
+// field manager of the client, in this order. The apply configuration is not modified.
+func ApplyConfig(ctx context.Context, c Writer, applyConfig interface{}, opts ...PatchOption) error {
+	if applyConfig == nil || (reflect.ValueOf(applyConfig).Kind() == reflect.Ptr && reflect.ValueOf(applyConfig).IsNil()) {
+		return errors.New("apply configuration must not be nil")
+	}
+	data, err := json.Marshal(applyConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal apply configuration: %w", err)
+	}
+
+	// The object only identifies the resource to apply, the patch is sent as is.
+	obj := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &obj.Object); err != nil {
+		return fmt.Errorf("failed to unmarshal apply configuration: %w", err)
+	}
+	switch {
+	case obj.GetAPIVersion() == "" || obj.GetKind() == "":
+		return errors.New("apply configuration must set apiVersion and kind")
+	case obj.GetName() == "":
+		return fmt.Errorf("apply configuration of kind %s must set a name", obj.GetKind())
+	}
+
+	return c.Patch(ctx, obj, RawPatch(types.ApplyPatchType, data), opts...)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// ErrBatchAborted is the error of the writes of a batch that were not sent because
+// an earlier write failed, see BatchWriterOptions.StopOnError.
+var ErrBatchAborted = errors.New("batch write aborted after an earlier write failed")
+
+const defaultBatchWriterMaxConcurrency = 10
+
+// BatchWriterOptions are the options of a BatchWriter.
+type BatchWriterOptions struct {
+	// MaxConcurrency is the maximum number of writes sent in parallel by Flush.
+	// Defaults to 10.
+	MaxConcurrency int
+
+	// StopOnError makes Flush stop sending writes once one of them failed. The writes
+	// that were not sent fail with ErrBatchAborted, the ones in flight are completed.
+	StopOnError bool
+}
+
+// BatchWriter is a Writer that buffers creates and updates, and sends them in parallel
+// on Flush, e.g. to create many objects in a single reconcile without paying for the
+// round-trips one after another. Patches and deletes are sent right away.
+//
+// Buffered objects are updated with the response of the API server once Flush returned,
+// so they must not be modified in the meantime.
+type BatchWriter struct {
+	writer Writer
+	opts   BatchWriterOptions
+
+	mu     sync.Mutex
+	writes []func(ctx context.Context) error
+}
+
+var _ Writer = &BatchWriter{}
+
+// NewBatchWriter returns a new BatchWriter sending writes using the given Writer.
+func NewBatchWriter(w Writer, opts BatchWriterOptions) *BatchWriter {
+	if opts.MaxConcurrency <= 0 {
+		opts.MaxConcurrency = defaultBatchWriterMaxConcurrency
+	}
+	return &BatchWriter{writer: w, opts: opts}
+}
+
+// Create buffers the creation of obj until the next Flush. It never fails, errors are
+// returned by Flush instead. The creation is sent with the values of ctx, e.g. a field
+// owner set with FieldOwnerIntoContext, but it is canceled with the context of Flush.
+func (b *BatchWriter) Create(ctx context.Context, obj Object, opts ...CreateOption) error {
+	valuesCtx := context.WithoutCancel(ctx)
+	b.add(func(flushCtx context.Context) error {
+		ctx, cancel := withCancelOf(valuesCtx, flushCtx)
+		defer cancel()
+		return b.writer.Create(ctx, obj, opts...)
+	})
+	return nil
+}
+
+// Update buffers the update of obj until the next Flush. It never fails, errors are
+// returned by Flush instead. Like for Create, the values of ctx are kept until then.
+func (b *BatchWriter) Update(ctx context.Context, obj Object, opts ...UpdateOption) error {
+	valuesCtx := context.WithoutCancel(ctx)
+	b.add(func(flushCtx context.Context) error {
+		ctx, cancel := withCancelOf(valuesCtx, flushCtx)
+		defer cancel()
+		return b.writer.Update(ctx, obj, opts...)
+	})
+	return nil
+}
+
+// Patch patches obj right away, it is not buffered.
+func (b *BatchWriter) Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error {
+	return b.writer.Patch(ctx, obj, patch, opts...)
+}
+
+// Delete deletes obj right away, it is not buffered.
+func (b *BatchWriter) Delete(ctx context.Context, obj Object, opts ...DeleteOption) error {
+	return b.writer.Delete(ctx, obj, opts...)
+}
+
+// DeleteAllOf deletes all objects of the type of obj right away, it is not buffered.
+func (b *BatchWriter) DeleteAllOf(ctx context.Context, obj Object, opts ...DeleteAllOfOption) error {
+	return b.writer.DeleteAllOf(ctx, obj, opts...)
+}
+
+// Len returns the number of buffered writes.
+func (b *BatchWriter) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.writes)
+}
+
+func (b *BatchWriter) add(write func(ctx context.Context) error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.writes = append(b.writes, write)
+}
+
+// Flush sends all buffered writes with at most MaxConcurrency of them in parallel, and
+// empties the buffer. It returns the error of each write, in the order they were buffered,
+// as well as an aggregate of all of them, which is nil if all writes succeeded.
+//
+// Writes that were not sent yet when ctx is done fail with the error of ctx.
+func (b *BatchWriter) Flush(ctx context.Context) ([]error, error) {
+	b.mu.Lock()
+	writes := b.writes
+	b.writes = nil
+	b.mu.Unlock()
+
+	errs := make([]error, len(writes))
+
+	var (
+		wg       sync.WaitGroup
+		stopOnce sync.Once
+		stop     = make(chan struct{})
+		workers  = make(chan struct{}, b.opts.MaxConcurrency)
+	)
+	for i, write := range writes {
+		select {
+		case workers <- struct{}{}:
+		case <-ctx.Done():
+		case <-stop:
+		}
+		// Prefer stopping over a free worker, select picks at random.
+		if err := ctx.Err(); err != nil {
+			markUnsent(errs[i:], err)
+			break
+		}
+		if isClosed(stop) {
+			markUnsent(errs[i:], ErrBatchAborted)
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, write func(ctx context.Context) error) {
+			defer wg.Done()
+			defer func() { <-workers }()
+
+			errs[i] = write(ctx)
+			if errs[i] != nil && b.opts.StopOnError {
+				stopOnce.Do(func() { close(stop) })
+			}
+		}(i, write)
+	}
+	wg.Wait()
+
+	return errs, kerrors.NewAggregate(errs)
+}
+
+// withCancelOf returns a copy of ctx that has the deadline of other and is canceled
+// along with it.
+func withCancelOf(ctx, other context.Context) (context.Context, context.CancelFunc) {
+	var cancel context.CancelFunc
+	if deadline, ok := other.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	stop := context.AfterFunc(other, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+func markUnsent(errs []error, err error) {
+	for i := range errs {
+		errs[i] = err
+	}
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var errRejected = errors.New("rejected")
+
+// newBatchTestClient returns a fake client that rejects the creation of objects whose
+// name is in reject and records the maximum number of creates in flight.
+func newBatchTestClient(reject map[string]bool, maxInFlight *int) client.Client {
+	var (
+		mu       sync.Mutex
+		inFlight int
+	)
+	return fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			mu.Lock()
+			inFlight++
+			if inFlight > *maxInFlight {
+				*maxInFlight = inFlight
+			}
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+			}()
+
+			time.Sleep(5 * time.Millisecond)
+			if reject[obj.GetName()] {
+				return errRejected
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+}
+
+func newBatchTestConfigMap(i int) *corev1.ConfigMap {
+	return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: fmt.Sprintf("cm-%d", i)}}
+}
+
+func TestBatchWriterCreatesInParallel(t *testing.T) {
+	ctx := context.Background()
+	reject := map[string]bool{}
+	for i := 0; i < 100; i += 10 {
+		reject[fmt.Sprintf("cm-%d", i)] = true
+	}
+	maxInFlight := 0
+	c := newBatchTestClient(reject, &maxInFlight)
+
+	b := client.NewBatchWriter(c, client.BatchWriterOptions{MaxConcurrency: 8})
+	for i := 0; i < 100; i++ {
+		if err := b.Create(ctx, newBatchTestConfigMap(i)); err != nil {
+			t.Fatalf("unexpected error buffering create: %v", err)
+		}
+	}
+	if b.Len() != 100 {
+		t.Fatalf("expected 100 buffered writes, got %d", b.Len())
+	}
+
+	errs, err := b.Flush(ctx)
+	if b.Len() != 0 {
+		t.Fatalf("expected no buffered writes after flush, got %d", b.Len())
+	}
+	if len(errs) != 100 {
+		t.Fatalf("expected 100 errors, got %d", len(errs))
+	}
+	for i, err := range errs {
+		if reject[fmt.Sprintf("cm-%d", i)] != errors.Is(err, errRejected) {
+			t.Errorf("unexpected error for object %d: %v", i, err)
+		}
+	}
+
+	var agg kerrors.Aggregate
+	if !errors.As(err, &agg) || len(agg.Errors()) != 10 {
+		t.Fatalf("expected an aggregate of 10 errors, got %v", err)
+	}
+	if maxInFlight < 2 || maxInFlight > 8 {
+		t.Fatalf("expected between 2 and 8 creates in flight, got %d", maxInFlight)
+	}
+
+	list := &corev1.ConfigMapList{}
+	if err := c.List(ctx, list); err != nil {
+		t.Fatalf("unexpected error listing objects: %v", err)
+	}
+	if len(list.Items) != 90 {
+		t.Fatalf("expected 90 objects to be created, got %d", len(list.Items))
+	}
+}
+
+func TestBatchWriterStopOnError(t *testing.T) {
+	ctx := context.Background()
+	maxInFlight := 0
+	c := newBatchTestClient(map[string]bool{"cm-3": true}, &maxInFlight)
+
+	b := client.NewBatchWriter(c, client.BatchWriterOptions{MaxConcurrency: 1, StopOnError: true})
+	for i := 0; i < 10; i++ {
+		_ = b.Create(ctx, newBatchTestConfigMap(i))
+	}
+
+	errs, err := b.Flush(ctx)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for i, err := range errs {
+		switch {
+		case i < 3 && err != nil:
+			t.Errorf("unexpected error for object %d: %v", i, err)
+		case i == 3 && !errors.Is(err, errRejected):
+			t.Errorf("expected object %d to be rejected, got %v", i, err)
+		case i > 3 && !errors.Is(err, client.ErrBatchAborted):
+			t.Errorf("expected object %d to be aborted, got %v", i, err)
+		}
+	}
+}
+
+func TestBatchWriterContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	maxInFlight := 0
+	c := newBatchTestClient(nil, &maxInFlight)
+
+	b := client.NewBatchWriter(c, client.BatchWriterOptions{})
+	for i := 0; i < 5; i++ {
+		_ = b.Update(ctx, newBatchTestConfigMap(i))
+	}
+	cancel()
+
+	errs, err := b.Flush(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the context error, got %v", err)
+	}
+	for i, err := range errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected object %d to fail with the context error, got %v", i, err)
+		}
+	}
+}
+
+func TestBatchWriterKeepsContextValues(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		fieldOwners = map[string]string{}
+	)
+	c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			owner, _ := client.FieldOwnerFromContext(ctx)
+			mu.Lock()
+			fieldOwners[obj.GetName()] = owner
+			mu.Unlock()
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+
+	b := client.NewBatchWriter(c, client.BatchWriterOptions{})
+	callCtx, cancel := context.WithCancel(client.FieldOwnerIntoContext(context.Background(), "batch-owner"))
+	_ = b.Create(callCtx, newBatchTestConfigMap(0))
+	_ = b.Create(context.Background(), newBatchTestConfigMap(1))
+	// Canceling the context of the call doesn't cancel the buffered write.
+	cancel()
+
+	if _, err := b.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error flushing: %v", err)
+	}
+	if fieldOwners["cm-0"] != "batch-owner" {
+		t.Errorf("expected the first create to be sent with the field owner of its context, got %q", fieldOwners["cm-0"])
+	}
+	if fieldOwners["cm-1"] != "" {
+		t.Errorf("expected the second create to be sent without a field owner, got %q", fieldOwners["cm-1"])
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -47,6 +48,11 @@ type Options struct {
 	// Mapper, if provided, will be used to map GroupVersionKinds to Resources
 	Mapper meta.RESTMapper
 
+	// MapperTTL is the duration the discovery information of the dynamic
+	// RESTMapper created if no Mapper is provided is used for, before it is
+	// discovered again. Defaults to 0, which keeps it until the mapper is reset.
+	MapperTTL time.Duration
+
 	// Cache, if provided, is used to read objects from the cache.
 	Cache *CacheOptions
 
@@ -56,6 +62,24 @@ type Options struct {
 
 	// DryRun instructs the client to only perform dry run requests.
 	DryRun *bool
+
+	// FieldManager, if set, is used as the field manager of all write requests
+	// of the client, including server-side apply patches. It can be overridden
+	// for individual requests by passing a FieldOwner option, e.g. for requests
+	// made on behalf of another actor. See also WithFieldOwner.
+	FieldManager string
+
+	// RequireSelectorForDeleteAllOf makes all DeleteAllOf requests of the client fail unless
+	// they are restricted by a label selector, a field selector or a namespace, as if they
+	// were passed the RequireSelector option.
+	RequireSelectorForDeleteAllOf bool
+
+	// UseProtobufForCoreTypes makes the client use protobuf for the built-in types of Kubernetes
+	// and the types added with apiutil.AddToProtobufScheme, and JSON for all other types like
+	// CRDs, even if the ContentType of the rest.Config is set, e.g. to JSON. Without it, the
+	// client only negotiates protobuf for these types if the ContentType of the rest.Config
+	// is empty. It doesn't affect unstructured objects, which always use JSON.
+	UseProtobufForCoreTypes bool
 }
 
 // WarningHandlerOptions are options for configuring a
@@ -108,6 +132,9 @@ type NewClientFunc func(config *rest.Config, options Options) (Client, error)
 // from the corresponding fields on the object.
 func New(config *rest.Config, options Options) (c Client, err error) {
 	c, err = newClient(config, options)
+	if err == nil && options.FieldManager != "" {
+		c = WithFieldOwner(c, options.FieldManager)
+	}
 	if err == nil && options.DryRun != nil && *options.DryRun {
 		c = NewDryRunClient(c)
 	}
@@ -156,15 +183,24 @@ func newClient(config *rest.Config, options Options) (*client, error) {
 	// Init a Mapper if none provided
 	if options.Mapper == nil {
 		var err error
-		options.Mapper, err = apiutil.NewDynamicRESTMapper(config, options.HTTPClient)
+		options.Mapper, err = apiutil.NewDynamicRESTMapperWithOptions(config, options.HTTPClient, apiutil.DynamicRESTMapperOptions{TTL: options.MapperTTL})
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	resourceConfig := config
+	if options.UseProtobufForCoreTypes {
+		// An empty ContentType makes the rest clients of typed objects negotiate protobuf
+		// for the types that support it, see apiutil.RESTClientForGVK.
+		resourceConfig = rest.CopyConfig(config)
+		resourceConfig.ContentType = ""
+		resourceConfig.AcceptContentTypes = ""
+	}
+
 	resources := &clientRestResources{
 		httpClient: options.HTTPClient,
-		config:     config,
+		config:     resourceConfig,
 		scheme:     options.Scheme,
 		mapper:     options.Mapper,
 		codecs:     serializer.NewCodecFactory(options.Scheme),
@@ -191,8 +227,9 @@ func newClient(config *rest.Config, options Options) (*client, error) {
 			client:     rawMetaClient,
 			restMapper: options.Mapper,
 		},
-		scheme: options.Scheme,
-		mapper: options.Mapper,
+		scheme:          options.Scheme,
+		mapper:          options.Mapper,
+		requireSelector: options.RequireSelectorForDeleteAllOf,
 	}
 	if options.Cache == nil || options.Cache.Reader == nil {
 		return c, nil
@@ -230,6 +267,9 @@ type client struct {
 	cache             Reader
 	uncachedGVKs      map[schema.GroupVersionKind]struct{}
 	cacheUnstructured bool
+
+	// requireSelector makes DeleteAllOf require a selector or a namespace, see RequireSelector.
+	requireSelector bool
 }
 
 func (c *client) shouldBypassCache(obj runtime.Object) (bool, error) {
@@ -285,8 +325,25 @@ func (c *client) RESTMapper() meta.RESTMapper {
 	return c.mapper
 }
 
+// RedactedAnnotation marks an object whose content has been redacted, e.g. by
+// cache.TransformRedactSecrets, so that it isn't written back. The client refuses
+// to create, update or patch objects carrying it.
+const RedactedAnnotation = "controller-runtime.sigs.k8s.io/redacted"
+
+// checkNotRedacted returns an error if obj carries the RedactedAnnotation.
+func checkNotRedacted(obj Object) error {
+	if _, ok := obj.GetAnnotations()[RedactedAnnotation]; ok {
+		return fmt.Errorf("refusing to write %s/%s, it has been redacted by the cache: read it from the API server instead", obj.GetNamespace(), obj.GetName())
+	}
+	return nil
+}
+
 // Create implements client.Client.
 func (c *client) Create(ctx context.Context, obj Object, opts ...CreateOption) error {
+	if err := checkNotRedacted(obj); err != nil {
+		return err
+	}
+	opts = withContextFieldOwner(ctx, opts)
 	switch obj.(type) {
 	case runtime.Unstructured:
 		return c.unstructuredClient.Create(ctx, obj, opts...)
@@ -299,6 +356,10 @@ func (c *client) Create(ctx context.Context, obj Object, opts ...CreateOption) e
 
 // Update implements client.Client.
 func (c *client) Update(ctx context.Context, obj Object, opts ...UpdateOption) error {
+	if err := checkNotRedacted(obj); err != nil {
+		return err
+	}
+	opts = withContextFieldOwner(ctx, opts)
 	defer c.resetGroupVersionKind(obj, obj.GetObjectKind().GroupVersionKind())
 	switch obj.(type) {
 	case runtime.Unstructured:
@@ -324,6 +385,13 @@ func (c *client) Delete(ctx context.Context, obj Object, opts ...DeleteOption) e
 
 // DeleteAllOf implements client.Client.
 func (c *client) DeleteAllOf(ctx context.Context, obj Object, opts ...DeleteAllOfOption) error {
+	if c.requireSelector {
+		opts = append([]DeleteAllOfOption{RequireSelector}, opts...)
+	}
+	if err := (&DeleteAllOfOptions{}).ApplyOptions(opts).CheckScope(); err != nil {
+		return err
+	}
+
 	switch obj.(type) {
 	case runtime.Unstructured:
 		return c.unstructuredClient.DeleteAllOf(ctx, obj, opts...)
@@ -336,6 +404,10 @@ func (c *client) DeleteAllOf(ctx context.Context, obj Object, opts ...DeleteAllO
 
 // Patch implements client.Client.
 func (c *client) Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error {
+	if err := checkNotRedacted(obj); err != nil {
+		return err
+	}
+	opts = withContextFieldOwner(ctx, opts)
 	defer c.resetGroupVersionKind(obj, obj.GetObjectKind().GroupVersionKind())
 	switch obj.(type) {
 	case runtime.Unstructured:
@@ -369,6 +441,19 @@ func (c *client) Get(ctx context.Context, key ObjectKey, obj Object, opts ...Get
 	}
 }
 
+// GetWithResponse retrieves obj from the API server, bypassing the cache, and returns metadata
+// of the response. See the package-level GetWithResponse.
+func (c *client) GetWithResponse(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) (*ResponseMetadata, error) {
+	switch obj.(type) {
+	case runtime.Unstructured:
+		return c.unstructuredClient.getWithResponse(ctx, key, obj, opts...)
+	case *metav1.PartialObjectMetadata:
+		return nil, fmt.Errorf("response metadata is not supported for metadata-only objects: %T", obj)
+	default:
+		return c.typedClient.getWithResponse(ctx, key, obj, opts...)
+	}
+}
+
 // List implements client.Client.
 func (c *client) List(ctx context.Context, obj ObjectList, opts ...ListOption) error {
 	if isUncached, err := c.shouldBypassCache(obj); err != nil {
@@ -565,6 +650,7 @@ func (sc *subResourceClient) Get(ctx context.Context, obj Object, subResource Ob
 
 // Create implements client.SubResourceClient
 func (sc *subResourceClient) Create(ctx context.Context, obj Object, subResource Object, opts ...SubResourceCreateOption) error {
+	opts = withContextFieldOwner(ctx, opts)
 	defer sc.client.resetGroupVersionKind(obj, obj.GetObjectKind().GroupVersionKind())
 	defer sc.client.resetGroupVersionKind(subResource, subResource.GetObjectKind().GroupVersionKind())
 
@@ -580,6 +666,7 @@ func (sc *subResourceClient) Create(ctx context.Context, obj Object, subResource
 
 // Update implements client.SubResourceClient
 func (sc *subResourceClient) Update(ctx context.Context, obj Object, opts ...SubResourceUpdateOption) error {
+	opts = withContextFieldOwner(ctx, opts)
 	defer sc.client.resetGroupVersionKind(obj, obj.GetObjectKind().GroupVersionKind())
 	switch obj.(type) {
 	case runtime.Unstructured:
@@ -593,6 +680,7 @@ func (sc *subResourceClient) Update(ctx context.Context, obj Object, opts ...Sub
 
 // Patch implements client.SubResourceWriter.
 func (sc *subResourceClient) Patch(ctx context.Context, obj Object, patch Patch, opts ...SubResourcePatchOption) error {
+	opts = withContextFieldOwner(ctx, opts)
 	defer sc.client.resetGroupVersionKind(obj, obj.GetObjectKind().GroupVersionKind())
 	switch obj.(type) {
 	case runtime.Unstructured:
@@ -40,6 +40,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
 	kscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/ptr"
 
@@ -796,6 +799,93 @@ U5wwSivyi7vmegHKmblOzNVKA5qPO8zWzqBC
 		})
 	})
 
+	Describe("ApplyConfig", func() {
+		depApplyConfig := func(name string) *appsv1ac.DeploymentApplyConfiguration {
+			return appsv1ac.Deployment(name, ns).
+				WithSpec(appsv1ac.DeploymentSpec().
+					WithReplicas(3).
+					WithSelector(metav1ac.LabelSelector().WithMatchLabels(map[string]string{"foo": "bar"})).
+					WithTemplate(corev1ac.PodTemplateSpec().
+						WithLabels(map[string]string{"foo": "bar"}).
+						WithSpec(corev1ac.PodSpec().
+							WithContainers(corev1ac.Container().WithName("nginx").WithImage("nginx")))))
+		}
+		managedFieldsOf := func(dep *appsv1.Deployment, manager string) string {
+			for _, entry := range dep.ManagedFields {
+				if entry.Manager == manager && entry.Operation == metav1.ManagedFieldsOperationApply {
+					return string(entry.FieldsV1.Raw)
+				}
+			}
+			return ""
+		}
+
+		It("should apply a Deployment from its apply configuration", func() {
+			cl, err := client.New(cfg, client.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("creating the Deployment with server-side apply")
+			Expect(client.ApplyConfig(ctx, cl, depApplyConfig(dep.Name), client.FieldOwner("test-owner"))).To(Succeed())
+
+			actual, err := clientset.AppsV1().Deployments(ns).Get(ctx, dep.Name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*actual.Spec.Replicas).To(BeEquivalentTo(3))
+			Expect(actual.Spec.Template.Spec.Containers).To(HaveLen(1))
+
+			By("validating that only the fields of the apply configuration are owned")
+			fields := managedFieldsOf(actual, "test-owner")
+			Expect(fields).To(ContainSubstring(`"f:replicas"`))
+			Expect(fields).NotTo(ContainSubstring(`"f:strategy"`))
+			Expect(fields).NotTo(ContainSubstring(`"f:revisionHistoryLimit"`))
+
+			By("applying the Deployment again with other replicas")
+			scaledDown := depApplyConfig(dep.Name)
+			scaledDown.Spec.WithReplicas(1)
+			Expect(client.ApplyConfig(ctx, cl, scaledDown, client.FieldOwner("test-owner"))).To(Succeed())
+
+			actual, err = clientset.AppsV1().Deployments(ns).Get(ctx, dep.Name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*actual.Spec.Replicas).To(BeEquivalentTo(1))
+		})
+
+		It("should use the default field manager of the client", func() {
+			cl, err := client.New(cfg, client.Options{FieldManager: "default-owner"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.ApplyConfig(ctx, cl, depApplyConfig(dep.Name))).To(Succeed())
+
+			actual, err := clientset.AppsV1().Deployments(ns).Get(ctx, dep.Name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(managedFieldsOf(actual, "default-owner")).To(ContainSubstring(`"f:replicas"`))
+		})
+
+		It("should report conflicts unless ownership is forced", func() {
+			cl, err := client.New(cfg, client.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.ApplyConfig(ctx, cl, depApplyConfig(dep.Name), client.FieldOwner("test-owner"))).To(Succeed())
+
+			conflicting := appsv1ac.Deployment(dep.Name, ns).WithSpec(appsv1ac.DeploymentSpec().WithReplicas(5))
+			err = client.ApplyConfig(ctx, cl, conflicting, client.FieldOwner("other-owner"))
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+
+			Expect(client.ApplyConfig(ctx, cl, conflicting, client.FieldOwner("other-owner"), client.ForceOwnership)).To(Succeed())
+			actual, err := clientset.AppsV1().Deployments(ns).Get(ctx, dep.Name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*actual.Spec.Replicas).To(BeEquivalentTo(5))
+		})
+
+		It("should fail for an apply configuration without a name", func() {
+			cl, err := client.New(cfg, client.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = client.ApplyConfig(ctx, cl, appsv1ac.Deployment("", ns), client.FieldOwner("test-owner"))
+			Expect(err).To(MatchError("apply configuration of kind Deployment must set a name"))
+
+			err = client.ApplyConfig(ctx, cl, &appsv1ac.DeploymentApplyConfiguration{}, client.FieldOwner("test-owner"))
+			Expect(err).To(MatchError("apply configuration must set apiVersion and kind"))
+		})
+	})
+
 	Describe("SubResourceClient", func() {
 		Context("with structured objects", func() {
 			It("should be able to read the Scale subresource", func() {
@@ -1644,6 +1734,29 @@ U5wwSivyi7vmegHKmblOzNVKA5qPO8zWzqBC
 				_, err = clientset.AppsV1().Deployments(ns).Get(ctx, dep2Name, metav1.GetOptions{})
 				Expect(err).To(HaveOccurred())
 			})
+
+			It("should refuse to delete a collection without a selector or namespace if required", func() {
+				cl, err := client.New(cfg, client.Options{RequireSelectorForDeleteAllOf: true})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("initially creating a Deployment")
+				dep, err = clientset.AppsV1().Deployments(ns).Create(ctx, dep, metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("refusing to delete Deployments of all namespaces")
+				err = cl.DeleteAllOf(context.TODO(), &appsv1.Deployment{})
+				Expect(err).To(MatchError(client.ErrUnscopedDeleteAllOf))
+				err = cl.DeleteAllOf(context.TODO(), &appsv1.Deployment{}, client.MatchingLabels{})
+				Expect(err).To(MatchError(client.ErrUnscopedDeleteAllOf))
+				_, err = clientset.AppsV1().Deployments(ns).Get(ctx, dep.Name, metav1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("deleting the Deployments of the namespace")
+				err = cl.DeleteAllOf(context.TODO(), &appsv1.Deployment{}, client.InNamespace(ns))
+				Expect(err).NotTo(HaveOccurred())
+				_, err = clientset.AppsV1().Deployments(ns).Get(ctx, dep.Name, metav1.GetOptions{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
 		})
 		Context("with unstructured objects", func() {
 			It("should delete an existing object from a go struct", func() {
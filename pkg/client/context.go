@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type schemeContextKey struct{}
+
+type restMapperContextKey struct{}
+
+// SchemeIntoContext returns a copy of ctx carrying the scheme. The manager adds its
+// scheme to the context of its runnables, and thus to the one passed to Reconcile.
+func SchemeIntoContext(ctx context.Context, scheme *runtime.Scheme) context.Context {
+	return context.WithValue(ctx, schemeContextKey{}, scheme)
+}
+
+// SchemeFromContext returns the scheme ctx carries, if any.
+func SchemeFromContext(ctx context.Context) (*runtime.Scheme, bool) {
+	scheme, ok := ctx.Value(schemeContextKey{}).(*runtime.Scheme)
+	return scheme, ok
+}
+
+// RESTMapperIntoContext returns a copy of ctx carrying the RESTMapper. The manager adds
+// its RESTMapper to the context of its runnables, and thus to the one passed to Reconcile.
+func RESTMapperIntoContext(ctx context.Context, mapper meta.RESTMapper) context.Context {
+	return context.WithValue(ctx, restMapperContextKey{}, mapper)
+}
+
+// RESTMapperFromContext returns the RESTMapper ctx carries, if any.
+func RESTMapperFromContext(ctx context.Context) (meta.RESTMapper, bool) {
+	mapper, ok := ctx.Value(restMapperContextKey{}).(meta.RESTMapper)
+	return mapper, ok
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+type namespaceContextKey struct{}
+
+// NamespaceIntoContext returns a copy of ctx carrying the namespace ns, to be enforced
+// by a client returned by NewContextNamespacedClient.
+func NamespaceIntoContext(ctx context.Context, ns string) context.Context {
+	return context.WithValue(ctx, namespaceContextKey{}, ns)
+}
+
+// NamespaceFromContext returns the namespace ctx carries, if any.
+func NamespaceFromContext(ctx context.Context) (string, bool) {
+	ns, ok := ctx.Value(namespaceContextKey{}).(string)
+	return ns, ok
+}
+
+// NewContextNamespacedClient wraps an existing client enforcing the namespace carried by
+// the context of each call, see NamespaceIntoContext. It is the counterpart of
+// NewNamespacedClient for e.g. request-scoped handlers that serve multiple tenants.
+//
+// Operations on namespace-scoped objects default to the namespace of the context, and
+// error out if they specify another namespace or if the context carries none. Operations
+// on cluster-scoped objects are passed through unchanged.
+func NewContextNamespacedClient(c Client) Client {
+	return &contextNamespacedClient{client: c}
+}
+
+var _ Client = &contextNamespacedClient{}
+
+// contextNamespacedClient is a Client that wraps another Client in order to enforce the
+// namespace carried by the context.
+type contextNamespacedClient struct {
+	client Client
+}
+
+// Scheme returns the scheme this client is using.
+func (n *contextNamespacedClient) Scheme() *runtime.Scheme {
+	return n.client.Scheme()
+}
+
+// RESTMapper returns the scheme this client is using.
+func (n *contextNamespacedClient) RESTMapper() meta.RESTMapper {
+	return n.client.RESTMapper()
+}
+
+// GroupVersionKindFor returns the GroupVersionKind for the given object.
+func (n *contextNamespacedClient) GroupVersionKindFor(obj runtime.Object) (schema.GroupVersionKind, error) {
+	return n.client.GroupVersionKindFor(obj)
+}
+
+// IsObjectNamespaced returns true if the GroupVersionKind of the object is namespaced.
+func (n *contextNamespacedClient) IsObjectNamespaced(obj runtime.Object) (bool, error) {
+	return n.client.IsObjectNamespaced(obj)
+}
+
+// Create implements client.Client.
+func (n *contextNamespacedClient) Create(ctx context.Context, obj Object, opts ...CreateOption) error {
+	if err := n.setNamespace(ctx, obj); err != nil {
+		return err
+	}
+	return n.client.Create(ctx, obj, opts...)
+}
+
+// Update implements client.Client.
+func (n *contextNamespacedClient) Update(ctx context.Context, obj Object, opts ...UpdateOption) error {
+	if err := n.setNamespace(ctx, obj); err != nil {
+		return err
+	}
+	return n.client.Update(ctx, obj, opts...)
+}
+
+// Delete implements client.Client.
+func (n *contextNamespacedClient) Delete(ctx context.Context, obj Object, opts ...DeleteOption) error {
+	if err := n.setNamespace(ctx, obj); err != nil {
+		return err
+	}
+	return n.client.Delete(ctx, obj, opts...)
+}
+
+// DeleteAllOf implements client.Client.
+func (n *contextNamespacedClient) DeleteAllOf(ctx context.Context, obj Object, opts ...DeleteAllOfOption) error {
+	ns, isNamespaceScoped, err := n.namespaceFor(ctx, obj)
+	if err != nil {
+		return err
+	}
+	if isNamespaceScoped {
+		deleteAllOfOpts := DeleteAllOfOptions{}
+		deleteAllOfOpts.ApplyOptions(opts)
+		if deleteAllOfOpts.Namespace != "" && deleteAllOfOpts.Namespace != ns {
+			return fmt.Errorf("namespace %s provided for DeleteAllOf does not match the namespace %s in the context", deleteAllOfOpts.Namespace, ns)
+		}
+		opts = append(opts, InNamespace(ns))
+	}
+	return n.client.DeleteAllOf(ctx, obj, opts...)
+}
+
+// Patch implements client.Client.
+func (n *contextNamespacedClient) Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error {
+	if err := n.setNamespace(ctx, obj); err != nil {
+		return err
+	}
+	return n.client.Patch(ctx, obj, patch, opts...)
+}
+
+// Get implements client.Client.
+func (n *contextNamespacedClient) Get(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) error {
+	ns, isNamespaceScoped, err := n.namespaceFor(ctx, obj)
+	if err != nil {
+		return err
+	}
+	if isNamespaceScoped {
+		if key.Namespace != "" && key.Namespace != ns {
+			return fmt.Errorf("namespace %s provided for the object %s does not match the namespace %s in the context", key.Namespace, key.Name, ns)
+		}
+		key.Namespace = ns
+	}
+	return n.client.Get(ctx, key, obj, opts...)
+}
+
+// List implements client.Client.
+func (n *contextNamespacedClient) List(ctx context.Context, obj ObjectList, opts ...ListOption) error {
+	gvk, err := n.client.GroupVersionKindFor(obj)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	isNamespaceScoped, err := apiutil.IsGVKNamespaced(gvk, n.client.RESTMapper())
+	if err != nil {
+		return fmt.Errorf("error finding the scope of the object: %w", err)
+	}
+	if isNamespaceScoped {
+		ns, ok := NamespaceFromContext(ctx)
+		if !ok {
+			return fmt.Errorf("no namespace in the context to list %s in", gvk.Kind)
+		}
+		listOpts := ListOptions{}
+		listOpts.ApplyOptions(opts)
+		if listOpts.Namespace != "" && listOpts.Namespace != ns {
+			return fmt.Errorf("namespace %s provided for List does not match the namespace %s in the context", listOpts.Namespace, ns)
+		}
+		opts = append(opts, InNamespace(ns))
+	}
+	return n.client.List(ctx, obj, opts...)
+}
+
+// Status implements client.StatusClient.
+func (n *contextNamespacedClient) Status() SubResourceWriter {
+	return n.SubResource("status")
+}
+
+// SubResource implements client.SubResourceClient.
+func (n *contextNamespacedClient) SubResource(subResource string) SubResourceClient {
+	return &contextNamespacedSubResourceClient{client: n.client.SubResource(subResource), namespacedClient: n}
+}
+
+// namespaceFor returns the namespace in ctx if obj is namespace-scoped. It errors
+// out if obj is namespace-scoped and ctx carries no namespace.
+func (n *contextNamespacedClient) namespaceFor(ctx context.Context, obj Object) (string, bool, error) {
+	isNamespaceScoped, err := n.IsObjectNamespaced(obj)
+	if err != nil {
+		return "", false, fmt.Errorf("error finding the scope of the object: %w", err)
+	}
+	if !isNamespaceScoped {
+		return "", false, nil
+	}
+	ns, ok := NamespaceFromContext(ctx)
+	if !ok {
+		return "", false, fmt.Errorf("no namespace in the context for the object %s", obj.GetName())
+	}
+	return ns, true, nil
+}
+
+// setNamespace defaults the namespace of obj to the one in ctx if obj is namespace-scoped,
+// and errors out if obj already has another namespace.
+func (n *contextNamespacedClient) setNamespace(ctx context.Context, obj Object) error {
+	ns, isNamespaceScoped, err := n.namespaceFor(ctx, obj)
+	if err != nil || !isNamespaceScoped {
+		return err
+	}
+	if objectNamespace := obj.GetNamespace(); objectNamespace != "" && objectNamespace != ns {
+		return fmt.Errorf("namespace %s of the object %s does not match the namespace %s in the context", objectNamespace, obj.GetName(), ns)
+	}
+	obj.SetNamespace(ns)
+	return nil
+}
+
+// ensure contextNamespacedSubResourceClient implements client.SubResourceClient.
+var _ SubResourceClient = &contextNamespacedSubResourceClient{}
+
+type contextNamespacedSubResourceClient struct {
+	client           SubResourceClient
+	namespacedClient *contextNamespacedClient
+}
+
+// Get implements client.SubResourceReader.
+func (nsw *contextNamespacedSubResourceClient) Get(ctx context.Context, obj, subResource Object, opts ...SubResourceGetOption) error {
+	if err := nsw.namespacedClient.setNamespace(ctx, obj); err != nil {
+		return err
+	}
+	return nsw.client.Get(ctx, obj, subResource, opts...)
+}
+
+// Create implements client.SubResourceWriter.
+func (nsw *contextNamespacedSubResourceClient) Create(ctx context.Context, obj, subResource Object, opts ...SubResourceCreateOption) error {
+	if err := nsw.namespacedClient.setNamespace(ctx, obj); err != nil {
+		return err
+	}
+	return nsw.client.Create(ctx, obj, subResource, opts...)
+}
+
+// Update implements client.SubResourceWriter.
+func (nsw *contextNamespacedSubResourceClient) Update(ctx context.Context, obj Object, opts ...SubResourceUpdateOption) error {
+	if err := nsw.namespacedClient.setNamespace(ctx, obj); err != nil {
+		return err
+	}
+	return nsw.client.Update(ctx, obj, opts...)
+}
+
+// Patch implements client.SubResourceWriter.
+func (nsw *contextNamespacedSubResourceClient) Patch(ctx context.Context, obj Object, patch Patch, opts ...SubResourcePatchOption) error {
+	if err := nsw.namespacedClient.setNamespace(ctx, obj); err != nil {
+		return err
+	}
+	return nsw.client.Patch(ctx, obj, patch, opts...)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newContextNamespacedClient(objs ...client.Object) client.Client {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	return client.NewContextNamespacedClient(fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(objs...).Build())
+}
+
+func TestContextNamespacedClientUsesContextNamespace(t *testing.T) {
+	c := newContextNamespacedClient(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-a", Name: "existing"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-b", Name: "existing"}},
+	)
+	ctx := client.NamespaceIntoContext(context.Background(), "tenant-a")
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "created"}}
+	if err := c.Create(ctx, cm); err != nil {
+		t.Fatalf("unexpected error creating object: %v", err)
+	}
+	if cm.Namespace != "tenant-a" {
+		t.Fatalf("expected object to be created in namespace tenant-a, got %q", cm.Namespace)
+	}
+
+	got := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{Name: "existing"}, got); err != nil {
+		t.Fatalf("unexpected error getting object: %v", err)
+	}
+	if got.Namespace != "tenant-a" {
+		t.Fatalf("expected object from namespace tenant-a, got %q", got.Namespace)
+	}
+
+	list := &corev1.ConfigMapList{}
+	if err := c.List(ctx, list); err != nil {
+		t.Fatalf("unexpected error listing objects: %v", err)
+	}
+	if len(list.Items) != 2 {
+		t.Fatalf("expected 2 objects in namespace tenant-a, got %d", len(list.Items))
+	}
+	for _, item := range list.Items {
+		if item.Namespace != "tenant-a" {
+			t.Fatalf("expected only objects from namespace tenant-a, got one from %q", item.Namespace)
+		}
+	}
+
+	cm.Data = map[string]string{"foo": "bar"}
+	cm.Namespace = ""
+	if err := c.Update(ctx, cm); err != nil {
+		t.Fatalf("unexpected error updating object: %v", err)
+	}
+	if err := c.Delete(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "created"}}); err != nil {
+		t.Fatalf("unexpected error deleting object: %v", err)
+	}
+}
+
+func TestContextNamespacedClientRejectsConflictingNamespace(t *testing.T) {
+	c := newContextNamespacedClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-b", Name: "existing"}})
+	ctx := client.NamespaceIntoContext(context.Background(), "tenant-a")
+
+	if err := c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-b", Name: "created"}}); err == nil {
+		t.Fatal("expected an error creating an object in another namespace")
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "tenant-b", Name: "existing"}, &corev1.ConfigMap{}); err == nil {
+		t.Fatal("expected an error getting an object from another namespace")
+	}
+	if err := c.List(ctx, &corev1.ConfigMapList{}, client.InNamespace("tenant-b")); err == nil {
+		t.Fatal("expected an error listing objects in another namespace")
+	}
+	if err := c.Delete(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-b", Name: "existing"}}); err == nil {
+		t.Fatal("expected an error deleting an object from another namespace")
+	}
+	if err := c.DeleteAllOf(ctx, &corev1.ConfigMap{}, client.InNamespace("tenant-b")); err == nil {
+		t.Fatal("expected an error deleting all objects of another namespace")
+	}
+	if err := c.Status().Update(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-b", Name: "existing"}}); err == nil {
+		t.Fatal("expected an error updating the status of an object in another namespace")
+	}
+
+	if err := c.Get(context.Background(), client.ObjectKey{Name: "existing"}, &corev1.ConfigMap{}); err == nil {
+		t.Fatal("expected an error getting a namespace-scoped object without a namespace in the context")
+	}
+}
+
+func TestContextNamespacedClientBypassesClusterScopedObjects(t *testing.T) {
+	c := newContextNamespacedClient()
+
+	for _, ctx := range []context.Context{context.Background(), client.NamespaceIntoContext(context.Background(), "tenant-a")} {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-c"}}
+		if err := c.Create(ctx, ns); err != nil {
+			t.Fatalf("unexpected error creating cluster-scoped object: %v", err)
+		}
+		if ns.Namespace != "" {
+			t.Fatalf("expected cluster-scoped object to have no namespace, got %q", ns.Namespace)
+		}
+		if err := c.Get(ctx, client.ObjectKey{Name: "tenant-c"}, &corev1.Namespace{}); err != nil {
+			t.Fatalf("unexpected error getting cluster-scoped object: %v", err)
+		}
+		list := &corev1.NamespaceList{}
+		if err := c.List(ctx, list); err != nil {
+			t.Fatalf("unexpected error listing cluster-scoped objects: %v", err)
+		}
+		if len(list.Items) != 1 {
+			t.Fatalf("expected 1 cluster-scoped object, got %d", len(list.Items))
+		}
+		if err := c.Delete(ctx, ns); err != nil {
+			t.Fatalf("unexpected error deleting cluster-scoped object: %v", err)
+		}
+	}
+}
@@ -87,6 +87,11 @@ func (c *dryRunClient) Get(ctx context.Context, key ObjectKey, obj Object, opts
 	return c.client.Get(ctx, key, obj, opts...)
 }
 
+// GetWithResponse retrieves obj with the wrapped client, see client.GetWithResponse.
+func (c *dryRunClient) GetWithResponse(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) (*ResponseMetadata, error) {
+	return GetWithResponse(ctx, c.client, key, obj, opts...)
+}
+
 // List implements client.Client.
 func (c *dryRunClient) List(ctx context.Context, obj ObjectList, opts ...ListOption) error {
 	return c.client.List(ctx, obj, opts...)
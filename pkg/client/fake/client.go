@@ -106,6 +106,8 @@ type ClientBuilder struct {
 	withStatusSubresource []client.Object
 	objectTracker         testing.ObjectTracker
 	interceptorFuncs      *interceptor.Funcs
+	latency               time.Duration
+	errorInjector         ErrorInjector
 
 	// indexes maps each GroupVersionKind (GVK) to the indexes registered for that GVK.
 	// The inner map maps from index name to IndexerFunc.
@@ -158,6 +160,9 @@ func (f *ClientBuilder) WithObjectTracker(ot testing.ObjectTracker) *ClientBuild
 // Invoking WithIndex twice with the same `field` and GVK (via `obj`) arguments will panic.
 // WithIndex retrieves the GVK of `obj` using the scheme registered via WithScheme if
 // WithScheme was previously invoked, the default scheme otherwise.
+//
+// Field selectors on metadata.name, metadata.namespace and status.phase are supported
+// without registering an index, WithIndex overrides those.
 func (f *ClientBuilder) WithIndex(obj runtime.Object, field string, extractValue client.IndexerFunc) *ClientBuilder {
 	objScheme := f.scheme
 	if objScheme == nil {
@@ -203,6 +208,32 @@ func (f *ClientBuilder) WithInterceptorFuncs(interceptorFuncs interceptor.Funcs)
 	return f
 }
 
+// WithLatency makes every request of the client take at least the given duration, as if it was
+// sent to an API server. A request fails with the error of its context if the context is done
+// before.
+func (f *ClientBuilder) WithLatency(latency time.Duration) *ClientBuilder {
+	f.latency = latency
+	return f
+}
+
+// ErrorInjector decides whether a request of the fake client fails, and with which error, e.g.
+// a conflict, a timeout or a throttling error created with the k8s.io/apimachinery/pkg/api/errors
+// package. The request fails if it returns an error, and is served otherwise.
+//
+// verb is one of "get", "list", "watch", "create", "update", "patch", "delete" and "deletecollection",
+// and is also used for requests to subresources. obj is the object or list the request is for, for a
+// get request a copy of the object passed to Get with the name and namespace of the request set.
+// The object must not be modified.
+type ErrorInjector func(ctx context.Context, verb string, obj runtime.Object) error
+
+// WithErrorInjection makes the client consult the given ErrorInjector before serving each request,
+// after the latency configured with WithLatency, if any. Combined with state kept by the ErrorInjector,
+// e.g. a counter, this allows to deterministically simulate failures like conflicts.
+func (f *ClientBuilder) WithErrorInjection(injector ErrorInjector) *ClientBuilder {
+	f.errorInjector = injector
+	return f
+}
+
 // Build builds and returns a new fake client.
 func (f *ClientBuilder) Build() client.WithWatch {
 	if f.scheme == nil {
@@ -253,6 +284,9 @@ func (f *ClientBuilder) Build() client.WithWatch {
 		withStatusSubresource: withStatusSubResource,
 	}
 
+	if f.latency > 0 || f.errorInjector != nil {
+		result = interceptor.NewClient(result, f.faultFuncs())
+	}
 	if f.interceptorFuncs != nil {
 		result = interceptor.NewClient(result, *f.interceptorFuncs)
 	}
@@ -260,6 +294,104 @@ func (f *ClientBuilder) Build() client.WithWatch {
 	return result
 }
 
+// faultFuncs returns the interceptor.Funcs delaying requests by the latency and failing them
+// as decided by the ErrorInjector.
+func (f *ClientBuilder) faultFuncs() interceptor.Funcs {
+	simulate := func(ctx context.Context, verb string, obj runtime.Object) error {
+		if f.latency > 0 {
+			timer := time.NewTimer(f.latency)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if f.errorInjector != nil {
+			return f.errorInjector(ctx, verb, obj)
+		}
+		return nil
+	}
+
+	return interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			requested := obj.DeepCopyObject().(client.Object)
+			requested.SetName(key.Name)
+			requested.SetNamespace(key.Namespace)
+			if err := simulate(ctx, "get", requested); err != nil {
+				return err
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			if err := simulate(ctx, "list", list); err != nil {
+				return err
+			}
+			return c.List(ctx, list, opts...)
+		},
+		Watch: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+			if err := simulate(ctx, "watch", list); err != nil {
+				return nil, err
+			}
+			return c.Watch(ctx, list, opts...)
+		},
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if err := simulate(ctx, "create", obj); err != nil {
+				return err
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if err := simulate(ctx, "update", obj); err != nil {
+				return err
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if err := simulate(ctx, "patch", obj); err != nil {
+				return err
+			}
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			if err := simulate(ctx, "delete", obj); err != nil {
+				return err
+			}
+			return c.Delete(ctx, obj, opts...)
+		},
+		DeleteAllOf: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteAllOfOption) error {
+			if err := simulate(ctx, "deletecollection", obj); err != nil {
+				return err
+			}
+			return c.DeleteAllOf(ctx, obj, opts...)
+		},
+		SubResourceGet: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceGetOption) error {
+			if err := simulate(ctx, "get", obj); err != nil {
+				return err
+			}
+			return c.SubResource(subResourceName).Get(ctx, obj, subResource, opts...)
+		},
+		SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+			if err := simulate(ctx, "create", obj); err != nil {
+				return err
+			}
+			return c.SubResource(subResourceName).Create(ctx, obj, subResource, opts...)
+		},
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			if err := simulate(ctx, "update", obj); err != nil {
+				return err
+			}
+			return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+		},
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			if err := simulate(ctx, "patch", obj); err != nil {
+				return err
+			}
+			return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+		},
+	}
+}
+
 const trackerAddResourceVersion = "999"
 
 func (t versionedTracker) Add(obj runtime.Object) error {
@@ -603,20 +735,27 @@ func (c *fakeClient) filterWithFields(list []runtime.Object, gvk schema.GroupVer
 	}
 
 	// Field selection is mimicked via indexes, so there's no sane answer this function can give
-	// if there are no indexes registered for the GroupVersionKind of the objects in the list.
+	// if there is neither an index registered for the GroupVersionKind of the objects in the list
+	// nor a built-in one for a field.
 	indexes := c.indexes[gvk]
+	extractors := make(map[string]client.IndexerFunc, len(fs.Requirements()))
 	for _, req := range fs.Requirements() {
-		if len(indexes) == 0 || indexes[req.Field] == nil {
+		extractor := indexes[req.Field]
+		if extractor == nil {
+			extractor = builtinFieldIndexes[req.Field]
+		}
+		if extractor == nil {
 			return nil, fmt.Errorf("List on GroupVersionKind %v specifies selector on field %s, but no "+
 				"index with name %s has been registered for GroupVersionKind %v", gvk, req.Field, req.Field, gvk)
 		}
+		extractors[req.Field] = extractor
 	}
 
 	filteredList := make([]runtime.Object, 0, len(list))
 	for _, obj := range list {
 		matches := true
 		for _, req := range fs.Requirements() {
-			indexExtractor := indexes[req.Field]
+			indexExtractor := extractors[req.Field]
 			if !c.objMatchesFieldSelector(obj, indexExtractor, req.Value) {
 				matches = false
 				break
@@ -629,6 +768,31 @@ func (c *fakeClient) filterWithFields(list []runtime.Object, gvk schema.GroupVer
 	return filteredList, nil
 }
 
+// builtinFieldIndexes are the indexes of the fields that can be used in field selectors without
+// registering an index, like the fields the API server supports field selectors on for all or
+// many types. Indexes registered with WithIndex take precedence over them.
+var builtinFieldIndexes = map[string]client.IndexerFunc{
+	"metadata.name": func(obj client.Object) []string {
+		return []string{obj.GetName()}
+	},
+	"metadata.namespace": func(obj client.Object) []string {
+		return []string{obj.GetNamespace()}
+	},
+	"status.phase": func(obj client.Object) []string {
+		var content map[string]interface{}
+		if u, ok := obj.(runtime.Unstructured); ok {
+			content = u.UnstructuredContent()
+		} else {
+			var err error
+			if content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj); err != nil {
+				return nil
+			}
+		}
+		phase, _, _ := unstructured.NestedString(content, "status", "phase")
+		return []string{phase}
+	},
+}
+
 func (c *fakeClient) objMatchesFieldSelector(o runtime.Object, extractIndex client.IndexerFunc, val string) bool {
 	obj, isClientObject := o.(client.Object)
 	if !isClientObject {
@@ -747,6 +911,9 @@ func (c *fakeClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ..
 
 	dcOptions := client.DeleteAllOfOptions{}
 	dcOptions.ApplyOptions(opts)
+	if err := dcOptions.CheckScope(); err != nil {
+		return err
+	}
 
 	for _, dryRunOpt := range dcOptions.DryRun {
 		if dryRunOpt == metav1.DryRunAll {
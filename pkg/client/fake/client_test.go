@@ -19,8 +19,10 @@ package fake
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
@@ -41,6 +43,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -845,6 +848,20 @@ var _ = Describe("Fake client", func() {
 			Expect(list.Items).To(BeEmpty())
 		})
 
+		It("should refuse to delete a collection without a selector or namespace if required", func() {
+			err := cl.DeleteAllOf(context.Background(), &appsv1.Deployment{}, client.RequireSelector)
+			Expect(err).To(MatchError(client.ErrUnscopedDeleteAllOf))
+
+			list := &appsv1.DeploymentList{}
+			Expect(cl.List(context.Background(), list, client.InNamespace("ns1"))).To(Succeed())
+			Expect(list.Items).NotTo(BeEmpty())
+
+			err = cl.DeleteAllOf(context.Background(), &appsv1.Deployment{}, client.RequireSelector, client.InNamespace("ns1"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cl.List(context.Background(), list, client.InNamespace("ns1"))).To(Succeed())
+			Expect(list.Items).To(BeEmpty())
+		})
+
 		It("should handle finalizers deleting a collection", func() {
 			for i := 0; i < 5; i++ {
 				namespacedName := types.NamespacedName{
@@ -1401,6 +1418,76 @@ var _ = Describe("Fake client", func() {
 		})
 	})
 
+	Context("with built-in field selectors", func() {
+		BeforeEach(func() {
+			cl = NewClientBuilder().
+				WithObjects(dep, dep2, cm).
+				WithObjects(
+					&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "ns1"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+					&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "ns1"}, Status: corev1.PodStatus{Phase: corev1.PodPending}},
+				).
+				Build()
+		})
+
+		It("filters by metadata.name", func() {
+			list := &appsv1.DeploymentList{}
+			Expect(cl.List(context.Background(), list, client.MatchingFields{"metadata.name": dep2.Name})).To(Succeed())
+			Expect(list.Items).To(ConsistOf(*dep2))
+		})
+
+		It("filters by metadata.namespace", func() {
+			list := &corev1.ConfigMapList{}
+			Expect(cl.List(context.Background(), list, client.MatchingFields{"metadata.namespace": "ns2"})).To(Succeed())
+			Expect(list.Items).To(ConsistOf(*cm))
+
+			Expect(cl.List(context.Background(), list, client.MatchingFields{"metadata.namespace": "ns1"})).To(Succeed())
+			Expect(list.Items).To(BeEmpty())
+		})
+
+		It("filters by status.phase", func() {
+			list := &corev1.PodList{}
+			Expect(cl.List(context.Background(), list, client.MatchingFields{"status.phase": string(corev1.PodRunning)})).To(Succeed())
+			Expect(list.Items).To(HaveLen(1))
+			Expect(list.Items[0].Name).To(Equal("running"))
+		})
+
+		It("filters unstructured objects by status.phase", func() {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("PodList"))
+			Expect(cl.List(context.Background(), list, client.MatchingFields{"status.phase": string(corev1.PodPending)})).To(Succeed())
+			Expect(list.Items).To(HaveLen(1))
+			Expect(list.Items[0].GetName()).To(Equal("pending"))
+		})
+
+		It("combines built-in field selectors", func() {
+			list := &corev1.PodList{}
+			Expect(cl.List(context.Background(), list, client.MatchingFields{
+				"metadata.namespace": "ns1",
+				"metadata.name":      "pending",
+			})).To(Succeed())
+			Expect(list.Items).To(HaveLen(1))
+			Expect(list.Items[0].Name).To(Equal("pending"))
+		})
+
+		It("errors on unknown fields without an index", func() {
+			err := cl.List(context.Background(), &appsv1.DeploymentList{}, client.MatchingFields{"spec.paused": "false"})
+			Expect(err).To(MatchError(ContainSubstring("no index with name spec.paused has been registered")))
+		})
+
+		It("prefers a registered index over a built-in one", func() {
+			cl = NewClientBuilder().
+				WithObjects(dep, dep2).
+				WithIndex(&appsv1.Deployment{}, "metadata.name", func(obj client.Object) []string {
+					return []string{strings.ToUpper(obj.GetName())}
+				}).
+				Build()
+
+			list := &appsv1.DeploymentList{}
+			Expect(cl.List(context.Background(), list, client.MatchingFields{"metadata.name": "TEST-DEPLOYMENT"})).To(Succeed())
+			Expect(list.Items).To(ConsistOf(*dep))
+		})
+	})
+
 	It("should set the ResourceVersion to 999 when adding an object to the tracker", func() {
 		cl := NewClientBuilder().WithObjects(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "cm"}}).Build()
 
@@ -2146,4 +2233,83 @@ var _ = Describe("Fake client builder", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(called).To(BeTrue())
 	})
+
+	It("should delay requests by the latency configured with WithLatency", func() {
+		cli := NewClientBuilder().WithLatency(100 * time.Millisecond).Build()
+
+		start := time.Now()
+		Expect(cli.Create(context.Background(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"}})).To(Succeed())
+		Expect(time.Since(start)).To(BeNumerically(">=", 100*time.Millisecond))
+
+		By("failing requests whose context is done before")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := cli.Get(ctx, client.ObjectKey{Namespace: "ns", Name: "cm"}, &corev1.ConfigMap{})
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+	})
+
+	It("should pass the verb and object of requests to the ErrorInjector", func() {
+		type request struct {
+			verb string
+			key  client.ObjectKey
+		}
+		var requests []request
+		cli := NewClientBuilder().WithErrorInjection(func(_ context.Context, verb string, obj runtime.Object) error {
+			key := client.ObjectKey{}
+			if o, ok := obj.(client.Object); ok {
+				key = client.ObjectKeyFromObject(o)
+			}
+			requests = append(requests, request{verb: verb, key: key})
+			if verb == "delete" {
+				return apierrors.NewTooManyRequestsError("slow down")
+			}
+			return nil
+		}).Build()
+
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"}}
+		Expect(cli.Create(context.Background(), cm)).To(Succeed())
+		Expect(cli.Get(context.Background(), client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})).To(Succeed())
+		Expect(cli.List(context.Background(), &corev1.ConfigMapList{})).To(Succeed())
+		Expect(cli.Status().Update(context.Background(), &corev1.Pod{})).NotTo(Succeed())
+		err := cli.Delete(context.Background(), cm)
+		Expect(apierrors.IsTooManyRequests(err)).To(BeTrue())
+
+		Expect(requests).To(Equal([]request{
+			{verb: "create", key: client.ObjectKey{Namespace: "ns", Name: "cm"}},
+			{verb: "get", key: client.ObjectKey{Namespace: "ns", Name: "cm"}},
+			{verb: "list"},
+			{verb: "update"},
+			{verb: "delete", key: client.ObjectKey{Namespace: "ns", Name: "cm"}},
+		}))
+		Expect(cli.Get(context.Background(), client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})).To(Succeed())
+	})
+
+	It("should allow exercising conflict retries with the ErrorInjector", func() {
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"}}
+		conflicts := 2
+		cli := NewClientBuilder().WithObjects(cm).WithErrorInjection(func(_ context.Context, verb string, obj runtime.Object) error {
+			if verb == "update" && conflicts > 0 {
+				conflicts--
+				return apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "cm", errors.New("the object has been modified"))
+			}
+			return nil
+		}).Build()
+
+		attempts := 0
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			attempts++
+			latest := &corev1.ConfigMap{}
+			if err := cli.Get(context.Background(), client.ObjectKeyFromObject(cm), latest); err != nil {
+				return err
+			}
+			latest.Data = map[string]string{"attempt": strconv.Itoa(attempts)}
+			return cli.Update(context.Background(), latest)
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attempts).To(Equal(3))
+
+		latest := &corev1.ConfigMap{}
+		Expect(cli.Get(context.Background(), client.ObjectKeyFromObject(cm), latest)).To(Succeed())
+		Expect(latest.Data).To(HaveKeyWithValue("attempt", "3"))
+	})
 })
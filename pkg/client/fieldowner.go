@@ -24,9 +24,43 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
+type fieldOwnerContextKey struct{}
+
+// FieldOwnerIntoContext returns a copy of ctx carrying the field owner, to be used
+// as the field manager of write requests that don't specify a [FieldOwner] option.
+// It takes precedence over the field owner of a client returned by WithFieldOwner.
+func FieldOwnerIntoContext(ctx context.Context, fieldOwner string) context.Context {
+	return context.WithValue(ctx, fieldOwnerContextKey{}, fieldOwner)
+}
+
+// FieldOwnerFromContext returns the field owner ctx carries, if any.
+func FieldOwnerFromContext(ctx context.Context) (string, bool) {
+	owner, ok := ctx.Value(fieldOwnerContextKey{}).(string)
+	return owner, ok
+}
+
+// withContextFieldOwner prepends the field owner carried by ctx, if any, to opts,
+// so that explicit FieldOwner options still take precedence.
+func withContextFieldOwner[O any](ctx context.Context, opts []O) []O {
+	owner, ok := FieldOwnerFromContext(ctx)
+	if !ok {
+		return opts
+	}
+	return append([]O{any(FieldOwner(owner)).(O)}, opts...)
+}
+
+// contextFieldOwnerOr returns the field owner carried by ctx, or fieldOwner if it carries none.
+func contextFieldOwnerOr(ctx context.Context, fieldOwner string) string {
+	if owner, ok := FieldOwnerFromContext(ctx); ok {
+		return owner
+	}
+	return fieldOwner
+}
+
 // WithFieldOwner wraps a Client and adds the fieldOwner as the field
 // manager to all write requests from this client. If additional [FieldOwner]
-// options are specified on methods of this client, the value specified here
+// options are specified on methods of this client, or the context of a call
+// carries a field owner (see FieldOwnerIntoContext), the value specified here
 // will be overridden.
 func WithFieldOwner(c Client, fieldOwner string) Client {
 	return &clientWithFieldManager{
@@ -43,15 +77,15 @@ type clientWithFieldManager struct {
 }
 
 func (f *clientWithFieldManager) Create(ctx context.Context, obj Object, opts ...CreateOption) error {
-	return f.c.Create(ctx, obj, append([]CreateOption{FieldOwner(f.owner)}, opts...)...)
+	return f.c.Create(ctx, obj, append([]CreateOption{FieldOwner(contextFieldOwnerOr(ctx, f.owner))}, opts...)...)
 }
 
 func (f *clientWithFieldManager) Update(ctx context.Context, obj Object, opts ...UpdateOption) error {
-	return f.c.Update(ctx, obj, append([]UpdateOption{FieldOwner(f.owner)}, opts...)...)
+	return f.c.Update(ctx, obj, append([]UpdateOption{FieldOwner(contextFieldOwnerOr(ctx, f.owner))}, opts...)...)
 }
 
 func (f *clientWithFieldManager) Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error {
-	return f.c.Patch(ctx, obj, patch, append([]PatchOption{FieldOwner(f.owner)}, opts...)...)
+	return f.c.Patch(ctx, obj, patch, append([]PatchOption{FieldOwner(contextFieldOwnerOr(ctx, f.owner))}, opts...)...)
 }
 
 func (f *clientWithFieldManager) Delete(ctx context.Context, obj Object, opts ...DeleteOption) error {
@@ -62,6 +96,10 @@ func (f *clientWithFieldManager) DeleteAllOf(ctx context.Context, obj Object, op
 	return f.c.DeleteAllOf(ctx, obj, opts...)
 }
 
+func (f *clientWithFieldManager) GetWithResponse(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) (*ResponseMetadata, error) {
+	return GetWithResponse(ctx, f.c, key, obj, opts...)
+}
+
 func (f *clientWithFieldManager) Scheme() *runtime.Scheme     { return f.c.Scheme() }
 func (f *clientWithFieldManager) RESTMapper() meta.RESTMapper { return f.c.RESTMapper() }
 func (f *clientWithFieldManager) GroupVersionKindFor(obj runtime.Object) (schema.GroupVersionKind, error) {
@@ -94,13 +132,13 @@ type subresourceClientWithFieldOwner struct {
 }
 
 func (f *subresourceClientWithFieldOwner) Create(ctx context.Context, obj Object, subresource Object, opts ...SubResourceCreateOption) error {
-	return f.subresourceWriter.Create(ctx, obj, subresource, append([]SubResourceCreateOption{FieldOwner(f.owner)}, opts...)...)
+	return f.subresourceWriter.Create(ctx, obj, subresource, append([]SubResourceCreateOption{FieldOwner(contextFieldOwnerOr(ctx, f.owner))}, opts...)...)
 }
 
 func (f *subresourceClientWithFieldOwner) Update(ctx context.Context, obj Object, opts ...SubResourceUpdateOption) error {
-	return f.subresourceWriter.Update(ctx, obj, append([]SubResourceUpdateOption{FieldOwner(f.owner)}, opts...)...)
+	return f.subresourceWriter.Update(ctx, obj, append([]SubResourceUpdateOption{FieldOwner(contextFieldOwnerOr(ctx, f.owner))}, opts...)...)
 }
 
 func (f *subresourceClientWithFieldOwner) Patch(ctx context.Context, obj Object, patch Patch, opts ...SubResourcePatchOption) error {
-	return f.subresourceWriter.Patch(ctx, obj, patch, append([]SubResourcePatchOption{FieldOwner(f.owner)}, opts...)...)
+	return f.subresourceWriter.Patch(ctx, obj, patch, append([]SubResourcePatchOption{FieldOwner(contextFieldOwnerOr(ctx, f.owner))}, opts...)...)
 }
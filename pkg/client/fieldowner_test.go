@@ -18,9 +18,15 @@ package client_test
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
@@ -73,6 +79,125 @@ func TestWithFieldOwnerOverridden(t *testing.T) {
 	}
 }
 
+func TestNewWithFieldManager(t *testing.T) {
+	var (
+		mu            sync.Mutex
+		fieldManagers []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fieldManagers = append(fieldManagers, r.URL.Query().Get("fieldManager"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"foo","namespace":"default"}}`))
+	}))
+	defer srv.Close()
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	c, err := client.New(&rest.Config{Host: srv.URL}, client.Options{
+		Mapper:       mapper,
+		FieldManager: "default-field-mgr",
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+	newObj := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		}
+	}
+
+	if err := c.Patch(ctx, newObj(), client.Apply); err != nil {
+		t.Fatalf("failed to apply: %v", err)
+	}
+	if err := c.Create(ctx, newObj()); err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+	if err := c.Patch(ctx, newObj(), client.Apply, client.FieldOwner("impersonated-field-mgr")); err != nil {
+		t.Fatalf("failed to apply: %v", err)
+	}
+
+	expected := []string{"default-field-mgr", "default-field-mgr", "impersonated-field-mgr"}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(fieldManagers) != len(expected) {
+		t.Fatalf("wrong number of requests: expected=%d; got=%d", len(expected), len(fieldManagers))
+	}
+	for i := range expected {
+		if fieldManagers[i] != expected[i] {
+			t.Errorf("wrong field manager for request %d: expected=%q; got=%q", i, expected[i], fieldManagers[i])
+		}
+	}
+}
+
+func TestFieldOwnerFromContext(t *testing.T) {
+	var (
+		mu            sync.Mutex
+		fieldManagers []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fieldManagers = append(fieldManagers, r.URL.Query().Get("fieldManager"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"foo","namespace":"default"}}`))
+	}))
+	defer srv.Close()
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	c, err := client.New(&rest.Config{Host: srv.URL}, client.Options{
+		Mapper:       mapper,
+		FieldManager: "default-field-mgr",
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx := client.FieldOwnerIntoContext(context.Background(), "context-field-mgr")
+	if owner, ok := client.FieldOwnerFromContext(ctx); !ok || owner != "context-field-mgr" {
+		t.Fatalf("wrong field owner in context: expected=%q; got=%q (found=%t)", "context-field-mgr", owner, ok)
+	}
+	newObj := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		}
+	}
+
+	if err := c.Patch(ctx, newObj(), client.Apply); err != nil {
+		t.Fatalf("failed to apply: %v", err)
+	}
+	if err := c.Create(ctx, newObj()); err != nil {
+		t.Fatalf("failed to create: %v", err)
+	}
+	if err := c.Update(ctx, newObj()); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if err := c.Patch(ctx, newObj(), client.Apply, client.FieldOwner("explicit-field-mgr")); err != nil {
+		t.Fatalf("failed to apply: %v", err)
+	}
+	if err := c.Patch(context.Background(), newObj(), client.Apply); err != nil {
+		t.Fatalf("failed to apply: %v", err)
+	}
+
+	expected := []string{"context-field-mgr", "context-field-mgr", "context-field-mgr", "explicit-field-mgr", "default-field-mgr"}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(fieldManagers) != len(expected) {
+		t.Fatalf("wrong number of requests: expected=%d; got=%d", len(expected), len(fieldManagers))
+	}
+	for i := range expected {
+		if fieldManagers[i] != expected[i] {
+			t.Errorf("wrong field manager for request %d: expected=%q; got=%q", i, expected[i], fieldManagers[i])
+		}
+	}
+}
+
 // testClient is a helper function that checks if calls have the expected field manager,
 // and calls the callback function on each intercepted call.
 func testClient(t *testing.T, expectedFieldManager string, callback func()) client.Client {
@@ -0,0 +1,232 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// NewNamespaceRestrictedClient wraps an existing client so that it errors out on any operation
+// on namespace-scoped objects outside the allowed namespaces, e.g. as defense in depth for a
+// controller in a shared cluster. Unlike NewNamespacedClient it never defaults the namespace.
+//
+// List and DeleteAllOf of namespace-scoped objects without a namespace span all namespaces and are
+// only allowed if allowed contains metav1.NamespaceAll, i.e. the empty string. Operations on
+// cluster-scoped objects are passed through unchanged.
+func NewNamespaceRestrictedClient(c Client, allowed sets.Set[string]) Client {
+	return &namespaceRestrictedClient{
+		client:  c,
+		allowed: allowed.Clone(),
+	}
+}
+
+var _ Client = &namespaceRestrictedClient{}
+
+// namespaceRestrictedClient is a Client that wraps another Client in order to restrict
+// the namespaces it operates on.
+type namespaceRestrictedClient struct {
+	client  Client
+	allowed sets.Set[string]
+}
+
+// Scheme returns the scheme this client is using.
+func (n *namespaceRestrictedClient) Scheme() *runtime.Scheme {
+	return n.client.Scheme()
+}
+
+// RESTMapper returns the scheme this client is using.
+func (n *namespaceRestrictedClient) RESTMapper() meta.RESTMapper {
+	return n.client.RESTMapper()
+}
+
+// GroupVersionKindFor returns the GroupVersionKind for the given object.
+func (n *namespaceRestrictedClient) GroupVersionKindFor(obj runtime.Object) (schema.GroupVersionKind, error) {
+	return n.client.GroupVersionKindFor(obj)
+}
+
+// IsObjectNamespaced returns true if the GroupVersionKind of the object is namespaced.
+func (n *namespaceRestrictedClient) IsObjectNamespaced(obj runtime.Object) (bool, error) {
+	return n.client.IsObjectNamespaced(obj)
+}
+
+// Create implements client.Client.
+func (n *namespaceRestrictedClient) Create(ctx context.Context, obj Object, opts ...CreateOption) error {
+	if err := n.checkObject(obj); err != nil {
+		return err
+	}
+	return n.client.Create(ctx, obj, opts...)
+}
+
+// Update implements client.Client.
+func (n *namespaceRestrictedClient) Update(ctx context.Context, obj Object, opts ...UpdateOption) error {
+	if err := n.checkObject(obj); err != nil {
+		return err
+	}
+	return n.client.Update(ctx, obj, opts...)
+}
+
+// Delete implements client.Client.
+func (n *namespaceRestrictedClient) Delete(ctx context.Context, obj Object, opts ...DeleteOption) error {
+	if err := n.checkObject(obj); err != nil {
+		return err
+	}
+	return n.client.Delete(ctx, obj, opts...)
+}
+
+// DeleteAllOf implements client.Client.
+func (n *namespaceRestrictedClient) DeleteAllOf(ctx context.Context, obj Object, opts ...DeleteAllOfOption) error {
+	isNamespaceScoped, err := n.IsObjectNamespaced(obj)
+	if err != nil {
+		return fmt.Errorf("error finding the scope of the object: %w", err)
+	}
+	if isNamespaceScoped {
+		deleteAllOfOpts := DeleteAllOfOptions{}
+		deleteAllOfOpts.ApplyOptions(opts)
+		if err := n.checkNamespace(deleteAllOfOpts.Namespace); err != nil {
+			return fmt.Errorf("refusing to delete all objects: %w", err)
+		}
+	}
+	return n.client.DeleteAllOf(ctx, obj, opts...)
+}
+
+// Patch implements client.Client.
+func (n *namespaceRestrictedClient) Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error {
+	if err := n.checkObject(obj); err != nil {
+		return err
+	}
+	return n.client.Patch(ctx, obj, patch, opts...)
+}
+
+// Get implements client.Client.
+func (n *namespaceRestrictedClient) Get(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) error {
+	isNamespaceScoped, err := n.IsObjectNamespaced(obj)
+	if err != nil {
+		return fmt.Errorf("error finding the scope of the object: %w", err)
+	}
+	if isNamespaceScoped {
+		if err := n.checkNamespace(key.Namespace); err != nil {
+			return fmt.Errorf("refusing to get the object %s: %w", key.Name, err)
+		}
+	}
+	return n.client.Get(ctx, key, obj, opts...)
+}
+
+// List implements client.Client.
+func (n *namespaceRestrictedClient) List(ctx context.Context, obj ObjectList, opts ...ListOption) error {
+	gvk, err := n.client.GroupVersionKindFor(obj)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	isNamespaceScoped, err := apiutil.IsGVKNamespaced(gvk, n.client.RESTMapper())
+	if err != nil {
+		return fmt.Errorf("error finding the scope of the object: %w", err)
+	}
+	if isNamespaceScoped {
+		listOpts := ListOptions{}
+		listOpts.ApplyOptions(opts)
+		if err := n.checkNamespace(listOpts.Namespace); err != nil {
+			return fmt.Errorf("refusing to list %s: %w", gvk.Kind, err)
+		}
+	}
+	return n.client.List(ctx, obj, opts...)
+}
+
+// Status implements client.StatusClient.
+func (n *namespaceRestrictedClient) Status() SubResourceWriter {
+	return n.SubResource("status")
+}
+
+// SubResource implements client.SubResourceClient.
+func (n *namespaceRestrictedClient) SubResource(subResource string) SubResourceClient {
+	return &namespaceRestrictedSubResourceClient{client: n.client.SubResource(subResource), restrictedClient: n}
+}
+
+// checkNamespace errors out if ns isn't allowed.
+func (n *namespaceRestrictedClient) checkNamespace(ns string) error {
+	if n.allowed.Has(ns) {
+		return nil
+	}
+	if ns == "" {
+		return fmt.Errorf("operations across all namespaces are not allowed")
+	}
+	return fmt.Errorf("namespace %s is not allowed", ns)
+}
+
+// checkObject errors out if obj is namespace-scoped and its namespace isn't allowed.
+func (n *namespaceRestrictedClient) checkObject(obj Object) error {
+	isNamespaceScoped, err := n.IsObjectNamespaced(obj)
+	if err != nil {
+		return fmt.Errorf("error finding the scope of the object: %w", err)
+	}
+	if !isNamespaceScoped {
+		return nil
+	}
+	if err := n.checkNamespace(obj.GetNamespace()); err != nil {
+		return fmt.Errorf("refusing to operate on the object %s: %w", obj.GetName(), err)
+	}
+	return nil
+}
+
+// ensure namespaceRestrictedSubResourceClient implements client.SubResourceClient.
+var _ SubResourceClient = &namespaceRestrictedSubResourceClient{}
+
+type namespaceRestrictedSubResourceClient struct {
+	client           SubResourceClient
+	restrictedClient *namespaceRestrictedClient
+}
+
+// Get implements client.SubResourceReader.
+func (nsw *namespaceRestrictedSubResourceClient) Get(ctx context.Context, obj, subResource Object, opts ...SubResourceGetOption) error {
+	if err := nsw.restrictedClient.checkObject(obj); err != nil {
+		return err
+	}
+	return nsw.client.Get(ctx, obj, subResource, opts...)
+}
+
+// Create implements client.SubResourceWriter.
+func (nsw *namespaceRestrictedSubResourceClient) Create(ctx context.Context, obj, subResource Object, opts ...SubResourceCreateOption) error {
+	if err := nsw.restrictedClient.checkObject(obj); err != nil {
+		return err
+	}
+	return nsw.client.Create(ctx, obj, subResource, opts...)
+}
+
+// Update implements client.SubResourceWriter.
+func (nsw *namespaceRestrictedSubResourceClient) Update(ctx context.Context, obj Object, opts ...SubResourceUpdateOption) error {
+	if err := nsw.restrictedClient.checkObject(obj); err != nil {
+		return err
+	}
+	return nsw.client.Update(ctx, obj, opts...)
+}
+
+// Patch implements client.SubResourceWriter.
+func (nsw *namespaceRestrictedSubResourceClient) Patch(ctx context.Context, obj Object, patch Patch, opts ...SubResourcePatchOption) error {
+	if err := nsw.restrictedClient.checkObject(obj); err != nil {
+		return err
+	}
+	return nsw.client.Patch(ctx, obj, patch, opts...)
+}
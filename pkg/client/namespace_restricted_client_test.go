@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newNamespaceRestrictedClient(allowed sets.Set[string], objs ...client.Object) client.Client {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	return client.NewNamespaceRestrictedClient(fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(objs...).Build(), allowed)
+}
+
+func TestNamespaceRestrictedClientAllowsAllowedNamespaces(t *testing.T) {
+	ctx := context.Background()
+	c := newNamespaceRestrictedClient(sets.New("tenant-a", "tenant-b"),
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-a", Name: "existing"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-c", Name: "existing"}},
+	)
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-b", Name: "created"}}
+	if err := c.Create(ctx, cm); err != nil {
+		t.Fatalf("unexpected error creating object: %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "tenant-a", Name: "existing"}, &corev1.ConfigMap{}); err != nil {
+		t.Fatalf("unexpected error getting object: %v", err)
+	}
+	list := &corev1.ConfigMapList{}
+	if err := c.List(ctx, list, client.InNamespace("tenant-a")); err != nil {
+		t.Fatalf("unexpected error listing objects: %v", err)
+	}
+	if len(list.Items) != 1 {
+		t.Fatalf("expected 1 object in namespace tenant-a, got %d", len(list.Items))
+	}
+	cm.Data = map[string]string{"foo": "bar"}
+	if err := c.Update(ctx, cm); err != nil {
+		t.Fatalf("unexpected error updating object: %v", err)
+	}
+	if err := c.Patch(ctx, cm, client.MergeFrom(&corev1.ConfigMap{})); err != nil {
+		t.Fatalf("unexpected error patching object: %v", err)
+	}
+	if err := c.Delete(ctx, cm); err != nil {
+		t.Fatalf("unexpected error deleting object: %v", err)
+	}
+	if err := c.DeleteAllOf(ctx, &corev1.ConfigMap{}, client.InNamespace("tenant-a")); err != nil {
+		t.Fatalf("unexpected error deleting all objects of an allowed namespace: %v", err)
+	}
+}
+
+func TestNamespaceRestrictedClientRejectsDisallowedNamespaces(t *testing.T) {
+	ctx := context.Background()
+	existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-c", Name: "existing"}}
+	c := newNamespaceRestrictedClient(sets.New("tenant-a"), existing.DeepCopy())
+
+	if err := c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-c", Name: "created"}}); err == nil {
+		t.Fatal("expected an error creating an object in a disallowed namespace")
+	}
+	if err := c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "created"}}); err == nil {
+		t.Fatal("expected an error creating an object without a namespace")
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(existing), &corev1.ConfigMap{}); err == nil {
+		t.Fatal("expected an error getting an object from a disallowed namespace")
+	}
+	if err := c.List(ctx, &corev1.ConfigMapList{}, client.InNamespace("tenant-c")); err == nil {
+		t.Fatal("expected an error listing objects in a disallowed namespace")
+	}
+	if err := c.List(ctx, &corev1.ConfigMapList{}); err == nil {
+		t.Fatal("expected an error listing objects across all namespaces")
+	}
+	if err := c.Update(ctx, existing.DeepCopy()); err == nil {
+		t.Fatal("expected an error updating an object in a disallowed namespace")
+	}
+	if err := c.Status().Update(ctx, existing.DeepCopy()); err == nil {
+		t.Fatal("expected an error updating the status of an object in a disallowed namespace")
+	}
+	if err := c.Patch(ctx, existing.DeepCopy(), client.MergeFrom(existing)); err == nil {
+		t.Fatal("expected an error patching an object in a disallowed namespace")
+	}
+	if err := c.Delete(ctx, existing.DeepCopy()); err == nil {
+		t.Fatal("expected an error deleting an object from a disallowed namespace")
+	}
+	if err := c.DeleteAllOf(ctx, &corev1.ConfigMap{}); err == nil {
+		t.Fatal("expected an error deleting all objects across all namespaces")
+	}
+
+	// Nothing must have reached the wrapped client.
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "tenant-a", Name: "created"}, &corev1.ConfigMap{}); err == nil {
+		t.Fatal("expected the object without a namespace not to be created")
+	}
+}
+
+func TestNamespaceRestrictedClientAllowsClusterWideWithNamespaceAll(t *testing.T) {
+	c := newNamespaceRestrictedClient(sets.New(metav1.NamespaceAll, "tenant-a"),
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-a", Name: "existing"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant-c", Name: "existing"}},
+	)
+
+	list := &corev1.ConfigMapList{}
+	if err := c.List(context.Background(), list); err != nil {
+		t.Fatalf("unexpected error listing objects across all namespaces: %v", err)
+	}
+	if len(list.Items) != 2 {
+		t.Fatalf("expected 2 objects across all namespaces, got %d", len(list.Items))
+	}
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "tenant-c", Name: "existing"}, &corev1.ConfigMap{}); err == nil {
+		t.Fatal("expected an error getting an object from a disallowed namespace")
+	}
+}
+
+func TestNamespaceRestrictedClientBypassesClusterScopedObjects(t *testing.T) {
+	ctx := context.Background()
+	c := newNamespaceRestrictedClient(sets.New("tenant-a"))
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-c"}}
+	if err := c.Create(ctx, ns); err != nil {
+		t.Fatalf("unexpected error creating cluster-scoped object: %v", err)
+	}
+	if err := c.Get(ctx, client.ObjectKey{Name: "tenant-c"}, &corev1.Namespace{}); err != nil {
+		t.Fatalf("unexpected error getting cluster-scoped object: %v", err)
+	}
+	list := &corev1.NamespaceList{}
+	if err := c.List(ctx, list); err != nil {
+		t.Fatalf("unexpected error listing cluster-scoped objects: %v", err)
+	}
+	if len(list.Items) != 1 {
+		t.Fatalf("expected 1 cluster-scoped object, got %d", len(list.Items))
+	}
+	if err := c.Delete(ctx, ns); err != nil {
+		t.Fatalf("unexpected error deleting cluster-scoped object: %v", err)
+	}
+}
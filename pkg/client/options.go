@@ -17,6 +17,8 @@ limitations under the License.
 package client
 
 import (
+	"errors"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
@@ -98,6 +100,22 @@ type SubResourcePatchOption interface {
 // validation, etc without persisting the change to storage.
 var DryRunAll = dryRunAll{}
 
+// RequireSelector makes DeleteAllOf fail with ErrUnscopedDeleteAllOf unless it is also
+// passed a label selector, a field selector or a namespace, to guard against accidentally
+// deleting all objects of a type. See also Options.RequireSelectorForDeleteAllOf.
+var RequireSelector = requireSelector{}
+
+// ErrUnscopedDeleteAllOf is returned by DeleteAllOf requests with the RequireSelector option
+// that are neither restricted by a label selector, a field selector nor a namespace.
+var ErrUnscopedDeleteAllOf = errors.New("refusing to delete all objects: DeleteAllOf requires a label selector, a field selector or a namespace")
+
+type requireSelector struct{}
+
+// ApplyToDeleteAllOf applies this configuration to the given deletecollection options.
+func (requireSelector) ApplyToDeleteAllOf(opts *DeleteAllOfOptions) {
+	opts.RequireSelector = true
+}
+
 type dryRunAll struct{}
 
 // ApplyToCreate applies this configuration to the given create options.
@@ -820,6 +838,10 @@ func (forceOwnership) ApplyToSubResourcePatch(opts *SubResourcePatchOptions) {
 type DeleteAllOfOptions struct {
 	ListOptions
 	DeleteOptions
+
+	// RequireSelector makes DeleteAllOf fail unless the options restrict it by a
+	// label selector, a field selector or a namespace. See RequireSelector.
+	RequireSelector bool
 }
 
 // ApplyOptions applies the given deleteallof options on these options,
@@ -837,6 +859,21 @@ var _ DeleteAllOfOption = &DeleteAllOfOptions{}
 func (o *DeleteAllOfOptions) ApplyToDeleteAllOf(do *DeleteAllOfOptions) {
 	o.ApplyToList(&do.ListOptions)
 	o.ApplyToDelete(&do.DeleteOptions)
+	if o.RequireSelector {
+		do.RequireSelector = true
+	}
+}
+
+// CheckScope returns ErrUnscopedDeleteAllOf if the options require a selector, but don't
+// restrict the objects to delete by a label selector, a field selector or a namespace.
+// Clients call it before deleting any objects.
+func (o *DeleteAllOfOptions) CheckScope() error {
+	if !o.RequireSelector || o.Namespace != "" ||
+		(o.LabelSelector != nil && !o.LabelSelector.Empty()) ||
+		(o.FieldSelector != nil && !o.FieldSelector.Empty()) {
+		return nil
+	}
+	return ErrUnscopedDeleteAllOf
 }
 
 // }}}
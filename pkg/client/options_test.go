@@ -223,6 +223,21 @@ var _ = Describe("DeleteAllOfOptions", func() {
 		o.ApplyToDeleteAllOf(newDeleteAllOfOpts)
 		Expect(newDeleteAllOfOpts).To(Equal(o))
 	})
+	It("Should set RequireSelector", func() {
+		o := &client.DeleteAllOfOptions{RequireSelector: true}
+		newDeleteAllOfOpts := &client.DeleteAllOfOptions{}
+		o.ApplyToDeleteAllOf(newDeleteAllOfOpts)
+		Expect(newDeleteAllOfOpts).To(Equal(o))
+	})
+	It("Should only require a selector if RequireSelector is set", func() {
+		Expect((&client.DeleteAllOfOptions{}).CheckScope()).To(Succeed())
+		Expect((&client.DeleteAllOfOptions{}).ApplyOptions([]client.DeleteAllOfOption{client.RequireSelector}).CheckScope()).
+			To(MatchError(client.ErrUnscopedDeleteAllOf))
+		Expect((&client.DeleteAllOfOptions{}).ApplyOptions([]client.DeleteAllOfOption{client.RequireSelector, client.InNamespace("ns")}).CheckScope()).
+			To(Succeed())
+		Expect((&client.DeleteAllOfOptions{}).ApplyOptions([]client.DeleteAllOfOption{client.RequireSelector, client.MatchingLabels{"app": "foo"}}).CheckScope()).
+			To(Succeed())
+	})
 })
 
 var _ = Describe("MatchingLabels", func() {
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/examples/crd/pkg"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestUseProtobufForCoreTypes(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		accept = map[string]string{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		accept[r.URL.Path] = r.Header.Get("Accept")
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"foo","namespace":"default"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"apiVersion":"chaosapps.metamagical.io/v1","kind":"ChaosPod","metadata":{"name":"foo","namespace":"default"}}`))
+	}))
+	defer srv.Close()
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := pkg.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(pkg.SchemeGroupVersion.WithKind("ChaosPod"), meta.RESTScopeNamespace)
+
+	for _, tc := range []struct {
+		name               string
+		useProtobuf        bool
+		expectedCoreType   string
+		expectedCustomType string
+	}{
+		{
+			name:               "without UseProtobufForCoreTypes",
+			expectedCoreType:   runtime.ContentTypeJSON,
+			expectedCustomType: runtime.ContentTypeJSON,
+		},
+		{
+			name:               "with UseProtobufForCoreTypes",
+			useProtobuf:        true,
+			expectedCoreType:   runtime.ContentTypeProtobuf,
+			expectedCustomType: runtime.ContentTypeJSON,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &rest.Config{Host: srv.URL, ContentConfig: rest.ContentConfig{ContentType: runtime.ContentTypeJSON}}
+			c, err := client.New(config, client.Options{Scheme: scheme, Mapper: mapper, UseProtobufForCoreTypes: tc.useProtobuf})
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			key := client.ObjectKey{Namespace: "default", Name: "foo"}
+			if err := c.Get(context.Background(), key, &corev1.ConfigMap{}); err != nil {
+				t.Fatalf("unexpected error getting ConfigMap: %v", err)
+			}
+			if err := c.Get(context.Background(), key, &pkg.ChaosPod{}); err != nil {
+				t.Fatalf("unexpected error getting ChaosPod: %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if got := accept["/api/v1/namespaces/default/configmaps/foo"]; !strings.HasPrefix(got, tc.expectedCoreType) {
+				t.Errorf("expected the ConfigMap to be requested as %q, got Accept header %q", tc.expectedCoreType, got)
+			}
+			if got := accept["/apis/chaosapps.metamagical.io/v1/namespaces/default/chaospods/foo"]; !strings.HasPrefix(got, tc.expectedCustomType) {
+				t.Errorf("expected the ChaosPod to be requested as %q, got Accept header %q", tc.expectedCustomType, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// MutationRecord is a write request recorded by a client returned by NewRecordingClient.
+type MutationRecord struct {
+	// Time is when the request returned.
+	Time time.Time
+
+	// Verb is the verb of the request, i.e. "Create", "Update", "Patch", "Delete" or "DeleteAllOf".
+	Verb string
+
+	// SubResource is the subresource the request was made to, e.g. "status", or empty for
+	// requests to the main resource.
+	SubResource string
+
+	// GroupVersionKind is the GroupVersionKind of the object, if it could be determined.
+	GroupVersionKind schema.GroupVersionKind
+
+	// Key is the namespace and name of the object. For DeleteAllOf requests, it only carries
+	// the namespace the objects were deleted in.
+	Key ObjectKey
+
+	// Diff is a JSON merge patch from the object before the request, as read with the client,
+	// to the object the request returned. It contains the whole object for creations and is
+	// "null" for deletions. It is empty for failed and DeleteAllOf requests.
+	Diff string
+
+	// Err is the error the request failed with, if any.
+	Err error
+}
+
+// RecordingClient is a Client that records the write requests made with it.
+type RecordingClient interface {
+	Client
+
+	// Journal returns the write requests made with the client so far, in the order they
+	// were made.
+	Journal() []MutationRecord
+}
+
+// NewRecordingClient wraps a Client and records every write request made with it, including
+// the ones to subresources and the failed ones, in an in-memory journal, e.g. to assert the
+// writes a controller makes in integration tests.
+//
+// To compute the Diff of their records, Update and Patch requests first get the object with c.
+// If c reads from a cache, the object before the request may thus be outdated.
+func NewRecordingClient(c Client) RecordingClient {
+	return &recordingClient{
+		client: c,
+	}
+}
+
+var _ Client = &recordingClient{}
+
+type recordingClient struct {
+	client Client
+
+	mu      sync.Mutex
+	journal []MutationRecord
+}
+
+// Journal implements RecordingClient.
+func (r *recordingClient) Journal() []MutationRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]MutationRecord(nil), r.journal...)
+}
+
+// before returns the object as currently read with the client, or nil if it doesn't exist
+// or can't be read.
+func (r *recordingClient) before(ctx context.Context, obj Object) runtime.Object {
+	current, ok := obj.DeepCopyObject().(Object)
+	if !ok {
+		return nil
+	}
+	if err := r.client.Get(ctx, ObjectKeyFromObject(obj), current); err != nil {
+		return nil
+	}
+	return current
+}
+
+// record appends a record of the request of verb on obj to the journal, with the diff from
+// before to after, which are nil if the object didn't exist before or doesn't exist after the
+// request.
+func (r *recordingClient) record(verb, subResource string, obj Object, before, after runtime.Object, err error) {
+	record := MutationRecord{
+		Time:        time.Now(),
+		Verb:        verb,
+		SubResource: subResource,
+		Key:         ObjectKeyFromObject(obj),
+		Err:         err,
+	}
+	if gvk, gvkErr := r.client.GroupVersionKindFor(obj); gvkErr == nil {
+		record.GroupVersionKind = gvk
+	}
+	if err == nil {
+		// Objects that can't be marshalled are recorded without a diff.
+		record.Diff, _ = mergePatchBetween(before, after)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.journal = append(r.journal, record)
+}
+
+// mergePatchBetween returns a JSON merge patch from before to after, treating nil as an
+// object that doesn't exist.
+func mergePatchBetween(before, after runtime.Object) (string, error) {
+	if after == nil {
+		return "null", nil
+	}
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return "", err
+	}
+	if before == nil {
+		return string(afterJSON), nil
+	}
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return "", err
+	}
+	patch, err := jsonpatch.CreateMergePatch(beforeJSON, afterJSON)
+	if err != nil {
+		return "", err
+	}
+	return string(patch), nil
+}
+
+// Scheme returns the scheme this client is using.
+func (r *recordingClient) Scheme() *runtime.Scheme {
+	return r.client.Scheme()
+}
+
+// RESTMapper returns the rest mapper this client is using.
+func (r *recordingClient) RESTMapper() meta.RESTMapper {
+	return r.client.RESTMapper()
+}
+
+// GroupVersionKindFor returns the GroupVersionKind for the given object.
+func (r *recordingClient) GroupVersionKindFor(obj runtime.Object) (schema.GroupVersionKind, error) {
+	return r.client.GroupVersionKindFor(obj)
+}
+
+// IsObjectNamespaced returns true if the GroupVersionKind of the object is namespaced.
+func (r *recordingClient) IsObjectNamespaced(obj runtime.Object) (bool, error) {
+	return r.client.IsObjectNamespaced(obj)
+}
+
+// Get implements client.Client.
+func (r *recordingClient) Get(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) error {
+	return r.client.Get(ctx, key, obj, opts...)
+}
+
+// List implements client.Client.
+func (r *recordingClient) List(ctx context.Context, list ObjectList, opts ...ListOption) error {
+	return r.client.List(ctx, list, opts...)
+}
+
+// Create implements client.Client.
+func (r *recordingClient) Create(ctx context.Context, obj Object, opts ...CreateOption) error {
+	err := r.client.Create(ctx, obj, opts...)
+	r.record("Create", "", obj, nil, obj, err)
+	return err
+}
+
+// Update implements client.Client.
+func (r *recordingClient) Update(ctx context.Context, obj Object, opts ...UpdateOption) error {
+	before := r.before(ctx, obj)
+	err := r.client.Update(ctx, obj, opts...)
+	r.record("Update", "", obj, before, obj, err)
+	return err
+}
+
+// Patch implements client.Client.
+func (r *recordingClient) Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error {
+	before := r.before(ctx, obj)
+	err := r.client.Patch(ctx, obj, patch, opts...)
+	r.record("Patch", "", obj, before, obj, err)
+	return err
+}
+
+// Delete implements client.Client.
+func (r *recordingClient) Delete(ctx context.Context, obj Object, opts ...DeleteOption) error {
+	err := r.client.Delete(ctx, obj, opts...)
+	r.record("Delete", "", obj, nil, nil, err)
+	return err
+}
+
+// DeleteAllOf implements client.Client.
+func (r *recordingClient) DeleteAllOf(ctx context.Context, obj Object, opts ...DeleteAllOfOption) error {
+	err := r.client.DeleteAllOf(ctx, obj, opts...)
+
+	deleteAllOfOpts := &DeleteAllOfOptions{}
+	deleteAllOfOpts.ApplyOptions(opts)
+	record := MutationRecord{
+		Time: time.Now(),
+		Verb: "DeleteAllOf",
+		Key:  ObjectKey{Namespace: deleteAllOfOpts.Namespace},
+		Err:  err,
+	}
+	if gvk, gvkErr := r.client.GroupVersionKindFor(obj); gvkErr == nil {
+		record.GroupVersionKind = gvk
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.journal = append(r.journal, record)
+	return err
+}
+
+// Status implements client.StatusClient.
+func (r *recordingClient) Status() SubResourceWriter {
+	return r.SubResource("status")
+}
+
+// SubResource implements client.SubResourceClientConstructor.
+func (r *recordingClient) SubResource(subResource string) SubResourceClient {
+	return &recordingSubResourceClient{
+		client:      r,
+		subResource: subResource,
+		inner:       r.client.SubResource(subResource),
+	}
+}
+
+var _ SubResourceClient = &recordingSubResourceClient{}
+
+type recordingSubResourceClient struct {
+	client      *recordingClient
+	subResource string
+	inner       SubResourceClient
+}
+
+// Get implements client.SubResourceReader.
+func (r *recordingSubResourceClient) Get(ctx context.Context, obj Object, subResource Object, opts ...SubResourceGetOption) error {
+	return r.inner.Get(ctx, obj, subResource, opts...)
+}
+
+// Create implements client.SubResourceWriter. The Diff of its record contains the whole
+// subresource object, e.g. the Eviction of a Pod.
+func (r *recordingSubResourceClient) Create(ctx context.Context, obj Object, subResource Object, opts ...SubResourceCreateOption) error {
+	err := r.inner.Create(ctx, obj, subResource, opts...)
+	r.client.record("Create", r.subResource, obj, nil, subResource, err)
+	return err
+}
+
+// Update implements client.SubResourceWriter.
+func (r *recordingSubResourceClient) Update(ctx context.Context, obj Object, opts ...SubResourceUpdateOption) error {
+	before := r.client.before(ctx, obj)
+	err := r.inner.Update(ctx, obj, opts...)
+	r.client.record("Update", r.subResource, obj, before, obj, err)
+	return err
+}
+
+// Patch implements client.SubResourceWriter.
+func (r *recordingSubResourceClient) Patch(ctx context.Context, obj Object, patch Patch, opts ...SubResourcePatchOption) error {
+	before := r.client.before(ctx, obj)
+	err := r.inner.Patch(ctx, obj, patch, opts...)
+	r.client.record("Patch", r.subResource, obj, before, obj, err)
+	return err
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRecordingClientRecordsSequenceOfWrites(t *testing.T) {
+	ctx := context.Background()
+	c := client.NewRecordingClient(fake.NewClientBuilder().Build())
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+		Data:       map[string]string{"key": "old"},
+	}
+	if err := c.Create(ctx, cm); err != nil {
+		t.Fatalf("unexpected error creating object: %v", err)
+	}
+	cm.Data["key"] = "new"
+	if err := c.Update(ctx, cm); err != nil {
+		t.Fatalf("unexpected error updating object: %v", err)
+	}
+	patch := client.MergeFrom(cm.DeepCopy())
+	cm.Labels = map[string]string{"app": "foo"}
+	if err := c.Patch(ctx, cm, patch); err != nil {
+		t.Fatalf("unexpected error patching object: %v", err)
+	}
+	if err := c.Delete(ctx, cm); err != nil {
+		t.Fatalf("unexpected error deleting object: %v", err)
+	}
+	if err := c.DeleteAllOf(ctx, &corev1.ConfigMap{}, client.InNamespace("default")); err != nil {
+		t.Fatalf("unexpected error deleting objects: %v", err)
+	}
+
+	journal := c.Journal()
+	var verbs []string
+	for _, record := range journal {
+		verbs = append(verbs, record.Verb)
+	}
+	if got, want := strings.Join(verbs, ","), "Create,Update,Patch,Delete,DeleteAllOf"; got != want {
+		t.Fatalf("expected verbs %s, got %s", want, got)
+	}
+
+	key := client.ObjectKey{Namespace: "default", Name: "foo"}
+	for _, record := range journal[:4] {
+		if record.Key != key {
+			t.Errorf("expected %s record to have key %s, got %s", record.Verb, key, record.Key)
+		}
+		if record.GroupVersionKind != corev1.SchemeGroupVersion.WithKind("ConfigMap") {
+			t.Errorf("expected %s record to be for a ConfigMap, got %s", record.Verb, record.GroupVersionKind)
+		}
+		if record.Err != nil {
+			t.Errorf("expected %s record to have no error, got %v", record.Verb, record.Err)
+		}
+		if record.Time.IsZero() {
+			t.Errorf("expected %s record to have a time", record.Verb)
+		}
+	}
+
+	for i, want := range []string{`"data":{"key":"old"}`, `"data":{"key":"new"}`, `"labels":{"app":"foo"}`} {
+		if !strings.Contains(journal[i].Diff, want) {
+			t.Errorf("expected %s diff to contain %s, got %s", journal[i].Verb, want, journal[i].Diff)
+		}
+	}
+	if strings.Contains(journal[2].Diff, `"data"`) {
+		t.Errorf("expected Patch diff to only contain changed fields, got %s", journal[2].Diff)
+	}
+	if journal[3].Diff != "null" {
+		t.Errorf("expected Delete diff to be null, got %s", journal[3].Diff)
+	}
+	if got := journal[4].Key; got != (client.ObjectKey{Namespace: "default"}) {
+		t.Errorf("expected DeleteAllOf record to carry the namespace only, got %s", got)
+	}
+}
+
+func TestRecordingClientRecordsFailedWrites(t *testing.T) {
+	ctx := context.Background()
+	c := client.NewRecordingClient(fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}},
+	).Build())
+
+	err := c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}})
+	if !apierrors.IsAlreadyExists(err) {
+		t.Fatalf("expected an AlreadyExists error, got %v", err)
+	}
+
+	journal := c.Journal()
+	if len(journal) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(journal))
+	}
+	if journal[0].Err != err {
+		t.Errorf("expected record to carry the error %v, got %v", err, journal[0].Err)
+	}
+	if journal[0].Diff != "" {
+		t.Errorf("expected record of a failed write to have no diff, got %s", journal[0].Diff)
+	}
+}
+
+func TestRecordingClientRecordsSubResourceWrites(t *testing.T) {
+	ctx := context.Background()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}}
+	c := client.NewRecordingClient(fake.NewClientBuilder().
+		WithObjects(pod).
+		WithStatusSubresource(&corev1.Pod{}).
+		Build())
+
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pod), pod); err != nil {
+		t.Fatalf("unexpected error getting object: %v", err)
+	}
+	pod.Status.Phase = corev1.PodRunning
+	if err := c.Status().Update(ctx, pod); err != nil {
+		t.Fatalf("unexpected error updating status: %v", err)
+	}
+
+	journal := c.Journal()
+	if len(journal) != 1 {
+		t.Fatalf("expected only the status update to be recorded, got %d records", len(journal))
+	}
+	if journal[0].Verb != "Update" || journal[0].SubResource != "status" {
+		t.Errorf("expected a status Update record, got %s of %q", journal[0].Verb, journal[0].SubResource)
+	}
+	if want := `"phase":"Running"`; !strings.Contains(journal[0].Diff, want) {
+		t.Errorf("expected diff to contain %s, got %s", want, journal[0].Diff)
+	}
+}
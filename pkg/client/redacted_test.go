@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestClientRefusesToWriteRedactedObjects(t *testing.T) {
+	c := newResponseTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s request for a redacted object to %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	ctx := context.Background()
+
+	redacted := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "default",
+		Name:        "foo",
+		Annotations: map[string]string{client.RedactedAnnotation: "true"},
+	}}
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind("ConfigMap")
+	u.SetNamespace("default")
+	u.SetName("foo")
+	u.SetAnnotations(map[string]string{client.RedactedAnnotation: "true"})
+
+	for _, obj := range []client.Object{redacted, u} {
+		if err := c.Create(ctx, obj); err == nil {
+			t.Errorf("expected creating a redacted %T to fail", obj)
+		}
+		if err := c.Update(ctx, obj); err == nil {
+			t.Errorf("expected updating a redacted %T to fail", obj)
+		}
+		if err := c.Patch(ctx, obj, client.MergeFrom(obj.DeepCopyObject().(client.Object))); err == nil {
+			t.Errorf("expected patching a redacted %T to fail", obj)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+)
+
+// ResponseMetadata contains metadata of the response of the API server to a request,
+// for diagnostics.
+type ResponseMetadata struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// ContentType is the value of the Content-Type header of the response.
+	ContentType string
+
+	// Warnings are the parsed Warning headers of the response.
+	Warnings []utilnet.WarningHeader
+}
+
+// GetWithResponse retrieves obj for the given object key from the API server, like Get, and
+// returns metadata of the response, e.g. its Warning headers. It always bypasses the cache.
+// The metadata is returned along with the error if the API server responded with one.
+//
+// c must be created by New, possibly with Options.FieldManager or Options.DryRun set, and obj
+// must not be a metav1.PartialObjectMetadata.
+func GetWithResponse(ctx context.Context, c Reader, key ObjectKey, obj Object, opts ...GetOption) (*ResponseMetadata, error) {
+	getter, ok := c.(interface {
+		GetWithResponse(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) (*ResponseMetadata, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("client %T does not return response metadata", c)
+	}
+	return getter.GetWithResponse(ctx, key, obj, opts...)
+}
+
+func responseMetadataOf(result rest.Result) *ResponseMetadata {
+	md := &ResponseMetadata{Warnings: result.Warnings()}
+	result.StatusCode(&md.StatusCode).ContentType(&md.ContentType)
+	return md
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newResponseTestClient(t *testing.T, handler http.HandlerFunc) client.Client {
+	return newResponseTestClientWithOptions(t, client.Options{}, handler)
+}
+
+func newResponseTestClientWithOptions(t *testing.T, options client.Options, handler http.HandlerFunc) client.Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	options.Mapper = mapper
+	c, err := client.New(&rest.Config{Host: srv.URL}, options)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return c
+}
+
+func TestGetWithResponse(t *testing.T) {
+	c := newResponseTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Add("Warning", `299 - "v1 ConfigMap is deprecated"`)
+		w.Header().Add("Warning", `299 - "another warning"`)
+		_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"foo","namespace":"default"},"data":{"foo":"bar"}}`))
+	})
+
+	for _, obj := range []client.Object{&corev1.ConfigMap{}, &unstructured.Unstructured{Object: map[string]any{"apiVersion": "v1", "kind": "ConfigMap"}}} {
+		md, err := client.GetWithResponse(context.Background(), c, client.ObjectKey{Namespace: "default", Name: "foo"}, obj)
+		if err != nil {
+			t.Fatalf("unexpected error getting %T: %v", obj, err)
+		}
+		if obj.GetName() != "foo" {
+			t.Errorf("expected %T to be decoded, got name %q", obj, obj.GetName())
+		}
+		if md.StatusCode != http.StatusOK {
+			t.Errorf("wrong status code for %T: expected=%d; got=%d", obj, http.StatusOK, md.StatusCode)
+		}
+		if md.ContentType != "application/json" {
+			t.Errorf("wrong content type for %T: expected=%q; got=%q", obj, "application/json", md.ContentType)
+		}
+		if len(md.Warnings) != 2 || md.Warnings[0].Text != "v1 ConfigMap is deprecated" || md.Warnings[1].Text != "another warning" {
+			t.Errorf("wrong warnings for %T: %v", obj, md.Warnings)
+		}
+	}
+}
+
+func TestGetWithResponseWrappedClient(t *testing.T) {
+	for name, options := range map[string]client.Options{
+		"FieldManager": {FieldManager: "manager"},
+		"DryRun":       {DryRun: ptr.To(true)},
+		"both":         {FieldManager: "manager", DryRun: ptr.To(true)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := newResponseTestClientWithOptions(t, options, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Add("Warning", `299 - "v1 ConfigMap is deprecated"`)
+				_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"foo","namespace":"default"}}`))
+			})
+
+			obj := &corev1.ConfigMap{}
+			md, err := client.GetWithResponse(context.Background(), c, client.ObjectKey{Namespace: "default", Name: "foo"}, obj)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if obj.GetName() != "foo" {
+				t.Errorf("expected the object to be decoded, got name %q", obj.GetName())
+			}
+			if len(md.Warnings) != 1 || md.Warnings[0].Text != "v1 ConfigMap is deprecated" {
+				t.Errorf("wrong warnings: %v", md.Warnings)
+			}
+		})
+	}
+}
+
+func TestGetWithResponseError(t *testing.T) {
+	c := newResponseTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"apiVersion":"v1","kind":"Status","status":"Failure","reason":"NotFound","code":404}`))
+	})
+
+	md, err := client.GetWithResponse(context.Background(), c, client.ObjectKey{Namespace: "default", Name: "foo"}, &corev1.ConfigMap{})
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected a NotFound error, got %v", err)
+	}
+	if md.StatusCode != http.StatusNotFound {
+		t.Errorf("wrong status code: expected=%d; got=%d", http.StatusNotFound, md.StatusCode)
+	}
+}
+
+func TestGetWithResponseUnsupportedClient(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	if _, err := client.GetWithResponse(context.Background(), c, client.ObjectKey{Name: "foo"}, &corev1.ConfigMap{}); err == nil {
+		t.Fatal("expected an error for a client not returning response metadata")
+	}
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// snapshotList holds the objects of a type in a snapshot.
+type snapshotList struct {
+	resourceVersion string
+	objects         []*unstructured.Unstructured
+	byKey           map[ObjectKey]*unstructured.Unstructured
+}
+
+// snapshotReader is a Reader serving the objects of a snapshot.
+type snapshotReader struct {
+	scheme *runtime.Scheme
+	lists  map[schema.GroupVersionKind]*snapshotList
+}
+
+var _ Reader = &snapshotReader{}
+
+// Snapshot lists all objects of the types of the given lists with c once, and returns a Reader
+// serving them, e.g. to give a batch job a consistent view of the cluster at a point in time.
+// Gets and Lists of the Reader never reach c, and don't reflect later changes. Objects that are
+// not in the snapshot are not found, reading types that were not listed fails.
+//
+// The lists are passed as is to c, e.g. a *corev1.PodList, or an *unstructured.UnstructuredList or
+// a *metav1.PartialObjectMetadataList with its GroupVersionKind set. Objects can be read as any of
+// these representations. The lists of the Reader only support selecting objects by namespace, labels,
+// and by their name and namespace with field selectors.
+func Snapshot(ctx context.Context, c Client, lists ...ObjectList) (Reader, error) {
+	r := &snapshotReader{
+		scheme: c.Scheme(),
+		lists:  make(map[schema.GroupVersionKind]*snapshotList, len(lists)),
+	}
+	for _, list := range lists {
+		gvk, err := r.objectGVK(list)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.List(ctx, list); err != nil {
+			return nil, fmt.Errorf("failed to list %s for the snapshot: %w", gvk.Kind, err)
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		snapshot := &snapshotList{
+			resourceVersion: list.GetResourceVersion(),
+			objects:         make([]*unstructured.Unstructured, 0, len(items)),
+			byKey:           make(map[ObjectKey]*unstructured.Unstructured, len(items)),
+		}
+		for _, item := range items {
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(item)
+			if err != nil {
+				return nil, err
+			}
+			u := &unstructured.Unstructured{Object: content}
+			u.SetGroupVersionKind(gvk)
+			snapshot.objects = append(snapshot.objects, u)
+			snapshot.byKey[ObjectKeyFromObject(u)] = u
+		}
+		sort.Slice(snapshot.objects, func(i, j int) bool {
+			return ObjectKeyFromObject(snapshot.objects[i]).String() < ObjectKeyFromObject(snapshot.objects[j]).String()
+		})
+		r.lists[gvk] = snapshot
+	}
+	return r, nil
+}
+
+// Get implements Reader.
+func (r *snapshotReader) Get(_ context.Context, key ObjectKey, obj Object, _ ...GetOption) error {
+	gvk, err := r.objectGVK(obj)
+	if err != nil {
+		return err
+	}
+	snapshot, err := r.snapshotFor(gvk)
+	if err != nil {
+		return err
+	}
+	u, found := snapshot.byKey[key]
+	if !found {
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		return apierrors.NewNotFound(gvr.GroupResource(), key.Name)
+	}
+	return copyInto(u.Object, obj)
+}
+
+// List implements Reader.
+func (r *snapshotReader) List(_ context.Context, list ObjectList, opts ...ListOption) error {
+	gvk, err := r.objectGVK(list)
+	if err != nil {
+		return err
+	}
+	snapshot, err := r.snapshotFor(gvk)
+	if err != nil {
+		return err
+	}
+
+	listOpts := ListOptions{}
+	listOpts.ApplyOptions(opts)
+	fieldMatches, err := snapshotFieldMatcher(listOpts.FieldSelector)
+	if err != nil {
+		return err
+	}
+
+	items := make([]interface{}, 0, len(snapshot.objects))
+	for _, u := range snapshot.objects {
+		if listOpts.Limit > 0 && int64(len(items)) >= listOpts.Limit {
+			break
+		}
+		if listOpts.Namespace != "" && u.GetNamespace() != listOpts.Namespace {
+			continue
+		}
+		if listOpts.LabelSelector != nil && !listOpts.LabelSelector.Matches(labelsSet(u.GetLabels())) {
+			continue
+		}
+		if !fieldMatches(u) {
+			continue
+		}
+		items = append(items, u.Object)
+	}
+
+	return copyInto(map[string]interface{}{
+		"apiVersion": gvk.GroupVersion().String(),
+		"kind":       gvk.Kind + "List",
+		"metadata":   map[string]interface{}{"resourceVersion": snapshot.resourceVersion},
+		"items":      items,
+	}, list)
+}
+
+// objectGVK returns the GroupVersionKind of obj, or of the items of obj if it is a list.
+func (r *snapshotReader) objectGVK(obj runtime.Object) (schema.GroupVersionKind, error) {
+	gvk, err := apiutil.GVKForObject(obj, r.scheme)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	if meta.IsListType(obj) {
+		gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	}
+	return gvk, nil
+}
+
+func (r *snapshotReader) snapshotFor(gvk schema.GroupVersionKind) (*snapshotList, error) {
+	snapshot, found := r.lists[gvk]
+	if !found {
+		return nil, fmt.Errorf("%s is not in the snapshot", gvk)
+	}
+	return snapshot, nil
+}
+
+// snapshotFieldMatcher returns a function matching objects against a field selector on
+// metadata.name and metadata.namespace.
+func snapshotFieldMatcher(selector fields.Selector) (func(*unstructured.Unstructured) bool, error) {
+	if selector == nil || selector.Empty() {
+		return func(*unstructured.Unstructured) bool { return true }, nil
+	}
+	for _, req := range selector.Requirements() {
+		if req.Field != "metadata.name" && req.Field != "metadata.namespace" {
+			return nil, fmt.Errorf("field selector on %q is not supported by snapshots, only metadata.name and metadata.namespace are", req.Field)
+		}
+	}
+	return func(u *unstructured.Unstructured) bool {
+		return selector.Matches(fields.Set{"metadata.name": u.GetName(), "metadata.namespace": u.GetNamespace()})
+	}, nil
+}
+
+// labelsSet is a labels.Labels that doesn't need to copy the labels of an object.
+type labelsSet map[string]string
+
+func (ls labelsSet) Has(label string) bool {
+	_, found := ls[label]
+	return found
+}
+
+func (ls labelsSet) Get(label string) string {
+	return ls[label]
+}
+
+// copyInto deep copies content into obj through its JSON representation, which works for
+// typed, unstructured and metadata-only objects.
+func copyInto(content map[string]interface{}, obj runtime.Object) error {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	v := reflect.ValueOf(obj).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	return json.Unmarshal(data, obj)
+}
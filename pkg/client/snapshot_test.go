@@ -0,0 +1,232 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newSnapshotTestClient() client.Client {
+	return fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo", Labels: map[string]string{"app": "foo"}},
+			Data:       map[string]string{"key": "old"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bar", Labels: map[string]string{"app": "bar"}},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "foo", Labels: map[string]string{"app": "foo"}},
+		},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}},
+	).Build()
+}
+
+func TestSnapshotDoesNotReflectLaterChanges(t *testing.T) {
+	ctx := context.Background()
+	c := newSnapshotTestClient()
+	snapshot, err := client.Snapshot(ctx, c, &corev1.ConfigMapList{})
+	if err != nil {
+		t.Fatalf("unexpected error taking snapshot: %v", err)
+	}
+
+	foo := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "foo"}, foo); err != nil {
+		t.Fatalf("unexpected error getting object: %v", err)
+	}
+	foo.Data["key"] = "new"
+	if err := c.Update(ctx, foo); err != nil {
+		t.Fatalf("unexpected error updating object: %v", err)
+	}
+	if err := c.Delete(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bar"}}); err != nil {
+		t.Fatalf("unexpected error deleting object: %v", err)
+	}
+	if err := c.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "baz"}}); err != nil {
+		t.Fatalf("unexpected error creating object: %v", err)
+	}
+
+	got := &corev1.ConfigMap{}
+	if err := snapshot.Get(ctx, client.ObjectKey{Namespace: "default", Name: "foo"}, got); err != nil {
+		t.Fatalf("unexpected error getting object from snapshot: %v", err)
+	}
+	if got.Data["key"] != "old" {
+		t.Errorf("expected the snapshot to serve the old data, got %q", got.Data["key"])
+	}
+	if err := snapshot.Get(ctx, client.ObjectKey{Namespace: "default", Name: "bar"}, &corev1.ConfigMap{}); err != nil {
+		t.Errorf("expected the deleted object to still be in the snapshot, got %v", err)
+	}
+	if err := snapshot.Get(ctx, client.ObjectKey{Namespace: "default", Name: "baz"}, &corev1.ConfigMap{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected a not found error for an object created after the snapshot, got %v", err)
+	}
+
+	list := &corev1.ConfigMapList{}
+	if err := snapshot.List(ctx, list); err != nil {
+		t.Fatalf("unexpected error listing objects from snapshot: %v", err)
+	}
+	if len(list.Items) != 3 {
+		t.Fatalf("expected 3 objects in the snapshot, got %d", len(list.Items))
+	}
+
+	// Modifying the objects read from the snapshot doesn't modify the snapshot.
+	got.Data["key"] = "modified"
+	list.Items[0].Labels["app"] = "modified"
+	got = &corev1.ConfigMap{}
+	if err := snapshot.Get(ctx, client.ObjectKey{Namespace: "default", Name: "foo"}, got); err != nil {
+		t.Fatalf("unexpected error getting object from snapshot: %v", err)
+	}
+	if got.Data["key"] != "old" {
+		t.Errorf("expected the snapshot to be unaffected by changes to read objects, got %q", got.Data["key"])
+	}
+	list = &corev1.ConfigMapList{}
+	if err := snapshot.List(ctx, list, client.MatchingLabels{"app": "modified"}); err != nil {
+		t.Fatalf("unexpected error listing objects from snapshot: %v", err)
+	}
+	if len(list.Items) != 0 {
+		t.Errorf("expected the snapshot to be unaffected by changes to listed objects, got %d objects", len(list.Items))
+	}
+}
+
+func TestSnapshotListOptions(t *testing.T) {
+	ctx := context.Background()
+	snapshot, err := client.Snapshot(ctx, newSnapshotTestClient(), &corev1.ConfigMapList{})
+	if err != nil {
+		t.Fatalf("unexpected error taking snapshot: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		opts     []client.ListOption
+		expected []client.ObjectKey
+	}{
+		{
+			name: "all",
+			expected: []client.ObjectKey{
+				{Namespace: "default", Name: "bar"},
+				{Namespace: "default", Name: "foo"},
+				{Namespace: "other", Name: "foo"},
+			},
+		},
+		{
+			name:     "namespace",
+			opts:     []client.ListOption{client.InNamespace("other")},
+			expected: []client.ObjectKey{{Namespace: "other", Name: "foo"}},
+		},
+		{
+			name: "labels",
+			opts: []client.ListOption{client.MatchingLabels{"app": "foo"}},
+			expected: []client.ObjectKey{
+				{Namespace: "default", Name: "foo"},
+				{Namespace: "other", Name: "foo"},
+			},
+		},
+		{
+			name:     "fields",
+			opts:     []client.ListOption{client.InNamespace("default"), client.MatchingFields{"metadata.name": "foo"}},
+			expected: []client.ObjectKey{{Namespace: "default", Name: "foo"}},
+		},
+		{
+			name:     "limit",
+			opts:     []client.ListOption{client.Limit(1)},
+			expected: []client.ObjectKey{{Namespace: "default", Name: "bar"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			list := &corev1.ConfigMapList{}
+			if err := snapshot.List(ctx, list, tc.opts...); err != nil {
+				t.Fatalf("unexpected error listing objects from snapshot: %v", err)
+			}
+			if len(list.Items) != len(tc.expected) {
+				t.Fatalf("expected %d objects, got %d", len(tc.expected), len(list.Items))
+			}
+			for i, key := range tc.expected {
+				if got := client.ObjectKeyFromObject(&list.Items[i]); got != key {
+					t.Errorf("expected object %d to be %v, got %v", i, key, got)
+				}
+			}
+		})
+	}
+
+	err = snapshot.List(ctx, &corev1.ConfigMapList{}, client.MatchingFieldsSelector{Selector: fields.OneTermEqualSelector("data.key", "old")})
+	if err == nil {
+		t.Errorf("expected an error for a field selector on an unsupported field")
+	}
+}
+
+func TestSnapshotUnknownTypes(t *testing.T) {
+	ctx := context.Background()
+	snapshot, err := client.Snapshot(ctx, newSnapshotTestClient(), &corev1.ConfigMapList{})
+	if err != nil {
+		t.Fatalf("unexpected error taking snapshot: %v", err)
+	}
+
+	err = snapshot.Get(ctx, client.ObjectKey{Namespace: "default", Name: "foo"}, &corev1.Secret{})
+	if err == nil || apierrors.IsNotFound(err) {
+		t.Errorf("expected an error other than not found for a type that is not in the snapshot, got %v", err)
+	}
+	if err := snapshot.List(ctx, &corev1.SecretList{}); err == nil {
+		t.Errorf("expected an error listing a type that is not in the snapshot")
+	}
+	err = snapshot.Get(ctx, client.ObjectKey{Namespace: "default", Name: "missing"}, &corev1.ConfigMap{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected a not found error for an object that is not in the snapshot, got %v", err)
+	}
+}
+
+func TestSnapshotUnstructuredAndMetadata(t *testing.T) {
+	ctx := context.Background()
+	secrets := &unstructured.UnstructuredList{}
+	secrets.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("SecretList"))
+	snapshot, err := client.Snapshot(ctx, newSnapshotTestClient(), &corev1.ConfigMapList{}, secrets)
+	if err != nil {
+		t.Fatalf("unexpected error taking snapshot: %v", err)
+	}
+
+	secret := &corev1.Secret{}
+	if err := snapshot.Get(ctx, client.ObjectKey{Namespace: "default", Name: "foo"}, secret); err != nil {
+		t.Fatalf("unexpected error getting typed object listed as unstructured: %v", err)
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+	if err := snapshot.Get(ctx, client.ObjectKey{Namespace: "default", Name: "foo"}, u); err != nil {
+		t.Fatalf("unexpected error getting unstructured object: %v", err)
+	}
+	if value, _, _ := unstructured.NestedString(u.Object, "data", "key"); value != "old" {
+		t.Errorf("expected the unstructured object to have the data of the snapshot, got %q", value)
+	}
+	if u.GetKind() != "ConfigMap" || u.GetAPIVersion() != "v1" {
+		t.Errorf("expected the unstructured object to have its type set, got %s %s", u.GetAPIVersion(), u.GetKind())
+	}
+
+	metadataList := &metav1.PartialObjectMetadataList{}
+	metadataList.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMapList"))
+	if err := snapshot.List(ctx, metadataList, client.MatchingLabels{"app": "bar"}); err != nil {
+		t.Fatalf("unexpected error listing metadata: %v", err)
+	}
+	if len(metadataList.Items) != 1 || metadataList.Items[0].Name != "bar" {
+		t.Errorf("expected the metadata of bar, got %v", metadataList.Items)
+	}
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing contains a client.Client that creates OpenTelemetry spans for its calls.
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Attributes of the spans created by a client returned by NewClient.
+const (
+	AttributeVerb        = attribute.Key("k8s.verb")
+	AttributeGroup       = attribute.Key("k8s.group")
+	AttributeVersion     = attribute.Key("k8s.version")
+	AttributeKind        = attribute.Key("k8s.kind")
+	AttributeNamespace   = attribute.Key("k8s.namespace")
+	AttributeName        = attribute.Key("k8s.name")
+	AttributeSubResource = attribute.Key("k8s.subresource")
+)
+
+// NewClient wraps a client.Client and creates an OpenTelemetry span with the given tracer for
+// each of its calls, as a child of the span carried by the context of the call, if any. The
+// spans are named after the verb and kind of the call, e.g. "Get Pod", and carry the verb, the
+// group, version and kind, and the namespace and name of the object as attributes. Failed calls
+// record their error on the span.
+func NewClient(c client.Client, tracer trace.Tracer) client.Client {
+	return &tracingClient{
+		client: c,
+		tracer: tracer,
+	}
+}
+
+var _ client.Client = &tracingClient{}
+
+type tracingClient struct {
+	client client.Client
+	tracer trace.Tracer
+}
+
+// startSpan starts a span for the call of verb on obj. It falls back to the type of obj
+// if its GroupVersionKind can't be determined, so that tracing never fails a call.
+func (t *tracingClient) startSpan(ctx context.Context, verb string, obj runtime.Object, namespace, name, subResource string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{AttributeVerb.String(verb)}
+
+	kind := ""
+	if gvk, err := t.client.GroupVersionKindFor(obj); err == nil {
+		kind = gvk.Kind
+		if _, isList := obj.(client.ObjectList); isList {
+			kind = strings.TrimSuffix(kind, "List")
+		}
+		attrs = append(attrs,
+			AttributeGroup.String(gvk.Group),
+			AttributeVersion.String(gvk.Version),
+			AttributeKind.String(kind),
+		)
+	}
+	if namespace != "" {
+		attrs = append(attrs, AttributeNamespace.String(namespace))
+	}
+	if name != "" {
+		attrs = append(attrs, AttributeName.String(name))
+	}
+	if subResource != "" {
+		attrs = append(attrs, AttributeSubResource.String(subResource))
+	}
+
+	spanName := verb
+	if kind != "" {
+		spanName += " " + kind
+	}
+	return t.tracer.Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// endSpan records err, if any, on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Scheme returns the scheme this client is using.
+func (t *tracingClient) Scheme() *runtime.Scheme {
+	return t.client.Scheme()
+}
+
+// RESTMapper returns the rest mapper this client is using.
+func (t *tracingClient) RESTMapper() meta.RESTMapper {
+	return t.client.RESTMapper()
+}
+
+// GroupVersionKindFor returns the GroupVersionKind for the given object.
+func (t *tracingClient) GroupVersionKindFor(obj runtime.Object) (schema.GroupVersionKind, error) {
+	return t.client.GroupVersionKindFor(obj)
+}
+
+// IsObjectNamespaced returns true if the GroupVersionKind of the object is namespaced.
+func (t *tracingClient) IsObjectNamespaced(obj runtime.Object) (bool, error) {
+	return t.client.IsObjectNamespaced(obj)
+}
+
+// Get implements client.Client.
+func (t *tracingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) (err error) {
+	ctx, span := t.startSpan(ctx, "Get", obj, key.Namespace, key.Name, "")
+	defer func() { endSpan(span, err) }()
+	return t.client.Get(ctx, key, obj, opts...)
+}
+
+// List implements client.Client.
+func (t *tracingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) (err error) {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	ctx, span := t.startSpan(ctx, "List", list, listOpts.Namespace, "", "")
+	defer func() { endSpan(span, err) }()
+	return t.client.List(ctx, list, opts...)
+}
+
+// Create implements client.Client.
+func (t *tracingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) (err error) {
+	ctx, span := t.startSpan(ctx, "Create", obj, obj.GetNamespace(), obj.GetName(), "")
+	defer func() { endSpan(span, err) }()
+	return t.client.Create(ctx, obj, opts...)
+}
+
+// Update implements client.Client.
+func (t *tracingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) (err error) {
+	ctx, span := t.startSpan(ctx, "Update", obj, obj.GetNamespace(), obj.GetName(), "")
+	defer func() { endSpan(span, err) }()
+	return t.client.Update(ctx, obj, opts...)
+}
+
+// Patch implements client.Client.
+func (t *tracingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) (err error) {
+	ctx, span := t.startSpan(ctx, "client.Patch", obj, obj.GetNamespace(), obj.GetName(), "")
+	defer func() { endSpan(span, err) }()
+	return t.client.Patch(ctx, obj, patch, opts...)
+}
+
+// Delete implements client.Client.
+func (t *tracingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) (err error) {
+	ctx, span := t.startSpan(ctx, "Delete", obj, obj.GetNamespace(), obj.GetName(), "")
+	defer func() { endSpan(span, err) }()
+	return t.client.Delete(ctx, obj, opts...)
+}
+
+// DeleteAllOf implements client.Client.
+func (t *tracingClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) (err error) {
+	deleteAllOfOpts := &client.DeleteAllOfOptions{}
+	deleteAllOfOpts.ApplyOptions(opts)
+	ctx, span := t.startSpan(ctx, "DeleteAllOf", obj, deleteAllOfOpts.Namespace, "", "")
+	defer func() { endSpan(span, err) }()
+	return t.client.DeleteAllOf(ctx, obj, opts...)
+}
+
+// Status implements client.StatusClient.
+func (t *tracingClient) Status() client.SubResourceWriter {
+	return t.SubResource("status")
+}
+
+// SubResource implements client.SubResourceClientConstructor.
+func (t *tracingClient) SubResource(subResource string) client.SubResourceClient {
+	return &tracingSubResourceClient{
+		client:      t,
+		subResource: subResource,
+		inner:       t.client.SubResource(subResource),
+	}
+}
+
+var _ client.SubResourceClient = &tracingSubResourceClient{}
+
+type tracingSubResourceClient struct {
+	client      *tracingClient
+	subResource string
+	inner       client.SubResourceClient
+}
+
+// Get implements client.SubResourceReader.
+func (t *tracingSubResourceClient) Get(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceGetOption) (err error) {
+	ctx, span := t.client.startSpan(ctx, "Get", obj, obj.GetNamespace(), obj.GetName(), t.subResource)
+	defer func() { endSpan(span, err) }()
+	return t.inner.Get(ctx, obj, subResource, opts...)
+}
+
+// Create implements client.SubResourceWriter.
+func (t *tracingSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) (err error) {
+	ctx, span := t.client.startSpan(ctx, "Create", obj, obj.GetNamespace(), obj.GetName(), t.subResource)
+	defer func() { endSpan(span, err) }()
+	return t.inner.Create(ctx, obj, subResource, opts...)
+}
+
+// Update implements client.SubResourceWriter.
+func (t *tracingSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) (err error) {
+	ctx, span := t.client.startSpan(ctx, "Update", obj, obj.GetNamespace(), obj.GetName(), t.subResource)
+	defer func() { endSpan(span, err) }()
+	return t.inner.Update(ctx, obj, opts...)
+}
+
+// Patch implements client.SubResourceWriter.
+func (t *tracingSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) (err error) {
+	ctx, span := t.client.startSpan(ctx, "client.Patch", obj, obj.GetNamespace(), obj.GetName(), t.subResource)
+	defer func() { endSpan(span, err) }()
+	return t.inner.Patch(ctx, obj, patch, opts...)
+}
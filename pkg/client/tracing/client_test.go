@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/tracing"
+)
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]string {
+	attrs := map[attribute.Key]string{}
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value.AsString()
+	}
+	return attrs
+}
+
+func TestClient(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}}
+	c := tracing.NewClient(fake.NewClientBuilder().WithObjects(pod).WithStatusSubresource(pod).Build(), tracer)
+
+	ctx, parent := tracer.Start(context.Background(), "reconcile")
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pod), &corev1.Pod{}); err != nil {
+		t.Fatalf("unexpected error getting pod: %v", err)
+	}
+	if err := c.List(ctx, &corev1.PodList{}, client.InNamespace("default")); err != nil {
+		t.Fatalf("unexpected error listing pods: %v", err)
+	}
+	if err := c.Status().Update(ctx, pod); err != nil {
+		t.Fatalf("unexpected error updating pod status: %v", err)
+	}
+	if err := c.Delete(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bar"}}); err == nil {
+		t.Fatal("expected an error deleting a missing pod")
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	expected := []struct {
+		name  string
+		attrs map[attribute.Key]string
+		err   bool
+	}{
+		{name: "Get Pod", attrs: map[attribute.Key]string{
+			tracing.AttributeVerb: "Get", tracing.AttributeGroup: "", tracing.AttributeVersion: "v1",
+			tracing.AttributeKind: "Pod", tracing.AttributeNamespace: "default", tracing.AttributeName: "foo",
+		}},
+		{name: "List Pod", attrs: map[attribute.Key]string{
+			tracing.AttributeVerb: "List", tracing.AttributeGroup: "", tracing.AttributeVersion: "v1",
+			tracing.AttributeKind: "Pod", tracing.AttributeNamespace: "default",
+		}},
+		{name: "Update Pod", attrs: map[attribute.Key]string{
+			tracing.AttributeVerb: "Update", tracing.AttributeGroup: "", tracing.AttributeVersion: "v1",
+			tracing.AttributeKind: "Pod", tracing.AttributeNamespace: "default", tracing.AttributeName: "foo",
+			tracing.AttributeSubResource: "status",
+		}},
+		{name: "Delete Pod", err: true, attrs: map[attribute.Key]string{
+			tracing.AttributeVerb: "Delete", tracing.AttributeGroup: "", tracing.AttributeVersion: "v1",
+			tracing.AttributeKind: "Pod", tracing.AttributeNamespace: "default", tracing.AttributeName: "bar",
+		}},
+	}
+	// The last span is the parent.
+	if len(spans) != len(expected)+1 {
+		t.Fatalf("wrong number of spans: expected=%d; got=%d", len(expected)+1, len(spans))
+	}
+	for i, exp := range expected {
+		span := spans[i]
+		if span.Name() != exp.name {
+			t.Errorf("wrong name of span %d: expected=%q; got=%q", i, exp.name, span.Name())
+		}
+		if span.SpanKind() != trace.SpanKindClient {
+			t.Errorf("wrong kind of span %q: %v", span.Name(), span.SpanKind())
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %q is not a child of the span of the context", span.Name())
+		}
+		attrs := spanAttributes(span)
+		if len(attrs) != len(exp.attrs) {
+			t.Errorf("wrong attributes of span %q: expected=%v; got=%v", span.Name(), exp.attrs, attrs)
+		}
+		for key, value := range exp.attrs {
+			if got, ok := attrs[key]; !ok || got != value {
+				t.Errorf("wrong attribute %q of span %q: expected=%q; got=%q", key, span.Name(), value, got)
+			}
+		}
+		if gotErr := span.Status().Code == codes.Error; gotErr != exp.err {
+			t.Errorf("wrong status of span %q: %v", span.Name(), span.Status())
+		}
+	}
+}
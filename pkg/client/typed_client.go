@@ -134,17 +134,23 @@ func (c *typedClient) Patch(ctx context.Context, obj Object, patch Patch, opts .
 
 // Get implements client.Client.
 func (c *typedClient) Get(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) error {
+	_, err := c.getWithResponse(ctx, key, obj, opts...)
+	return err
+}
+
+func (c *typedClient) getWithResponse(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) (*ResponseMetadata, error) {
 	r, err := c.resources.getResource(obj)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	getOpts := GetOptions{}
 	getOpts.ApplyOptions(opts)
-	return r.Get().
+	result := r.Get().
 		NamespaceIfScoped(key.Namespace, r.isNamespaced()).
 		Resource(r.resource()).
 		VersionedParams(getOpts.AsGetOptions(), c.paramCodec).
-		Name(key.Name).Do(ctx).Into(obj)
+		Name(key.Name).Do(ctx)
+	return responseMetadataOf(result), result.Into(obj)
 }
 
 // List implements client.Client.
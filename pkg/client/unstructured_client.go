@@ -168,9 +168,14 @@ func (uc *unstructuredClient) Patch(ctx context.Context, obj Object, patch Patch
 
 // Get implements client.Client.
 func (uc *unstructuredClient) Get(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) error {
+	_, err := uc.getWithResponse(ctx, key, obj, opts...)
+	return err
+}
+
+func (uc *unstructuredClient) getWithResponse(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) (*ResponseMetadata, error) {
 	u, ok := obj.(runtime.Unstructured)
 	if !ok {
-		return fmt.Errorf("unstructured client did not understand object: %T", obj)
+		return nil, fmt.Errorf("unstructured client did not understand object: %T", obj)
 	}
 
 	gvk := u.GetObjectKind().GroupVersionKind()
@@ -180,7 +185,7 @@ func (uc *unstructuredClient) Get(ctx context.Context, key ObjectKey, obj Object
 
 	r, err := uc.resources.getResource(obj)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	result := r.Get().
@@ -188,12 +193,12 @@ func (uc *unstructuredClient) Get(ctx context.Context, key ObjectKey, obj Object
 		Resource(r.resource()).
 		VersionedParams(getOpts.AsGetOptions(), uc.paramCodec).
 		Name(key.Name).
-		Do(ctx).
-		Into(obj)
+		Do(ctx)
+	err = result.Into(obj)
 
 	u.GetObjectKind().SetGroupVersionKind(gvk)
 
-	return result
+	return responseMetadataOf(result), err
 }
 
 // List implements client.Client.
@@ -261,6 +261,19 @@ func New(config *rest.Config, opts ...Option) (Cluster, error) {
 	}, nil
 }
 
+// NewMultiClusterCache returns a cache.MultiCluster holding the caches of the given clusters, keyed
+// by their name, to watch objects across them with source.MultiClusterKind. The clusters must be
+// added to the manager, which starts their caches.
+//
+// NewMultiClusterCache is experimental and subject to future change.
+func NewMultiClusterCache(clusters map[string]Cluster) *cache.MultiCluster {
+	caches := make(map[string]cache.Cache, len(clusters))
+	for name, cl := range clusters {
+		caches[name] = cl.GetCache()
+	}
+	return cache.NewMultiClusterFromCaches(caches)
+}
+
 // setOptionsDefaults set default values for Options fields.
 func setOptionsDefaults(options Options, config *rest.Config) (Options, error) {
 	if options.HTTPClient == nil {
@@ -27,6 +27,7 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/internal/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -42,7 +43,8 @@ type Options struct {
 	CacheSyncTimeout time.Duration
 
 	// RecoverPanic indicates whether the panic caused by reconcile should be recovered.
-	// Defaults to the Controller.RecoverPanic setting from the Manager if unset.
+	// Defaults to the Controller.RecoverPanic setting from the Manager if unset, and to
+	// false, i.e. a panic crashes the process, if that is unset too.
 	RecoverPanic *bool
 
 	// NeedLeaderElection indicates whether the controller needs to use leader election.
@@ -52,11 +54,105 @@ type Options struct {
 	// Reconciler reconciles an object
 	Reconciler reconcile.Reconciler
 
+	// DefaultRequeueAfter, if greater than 0, makes the controller periodically reconcile objects: the
+	// reconcile key is requeued after the duration whenever the Reconciler succeeds with a zero Result.
+	// A Result with Requeue or RequeueAfter set is respected as-is, and a Result with NoRequeue set
+	// disables the periodic requeue for that reconciliation.
+	// Requests for objects ObjectExists reports as gone are not requeued periodically, so that the keys
+	// of deleted objects, e.g. for which the Reconciler returned client.IgnoreNotFound(err), don't pile
+	// up in the queue. Without ObjectExists, the Reconciler must return a Result with NoRequeue set for them.
+	DefaultRequeueAfter time.Duration
+
+	// DeletedObjectPolicy determines what the controller does with a requeue requested by the Reconciler
+	// when ObjectExists reports the object of the request as gone: honor it, drop it, or honor it only
+	// once so the Reconciler can clean up. Objects that still exist with a deletion timestamp set are
+	// not gone and are always requeued as requested.
+	// Defaults to reconcile.DeletedObjectHonorRequeue.
+	DeletedObjectPolicy reconcile.DeletedObjectPolicy
+
+	// ObjectExists reports whether the object a request refers to still exists. It is required if
+	// DeletedObjectPolicy is set to anything but reconcile.DeletedObjectHonorRequeue, and keeps
+	// DefaultRequeueAfter from requeueing the requests for objects that are gone.
+	// The builder defaults it to looking up the object passed to For() in the manager's cache.
+	ObjectExists func(ctx context.Context, req reconcile.Request) (bool, error)
+
+	// RecordTriggeringEvents makes the controller record the event that enqueued each reconcile.Request,
+	// e.g. an event.UpdateEvent with both the old and the new object, so that the Reconciler can
+	// retrieve it with reconcile.TriggeringEvent. Only the EventHandlers of the handler package record
+	// events; requeued Requests carry none. Defaults to false.
+	RecordTriggeringEvents bool
+
+	// TrackCausality puts a causality.Chain into the context of every reconcile, so the Reconciler
+	// can see which reconciles of other controllers led to it. The Chain is read from the object of
+	// the triggering event; writes only propagate it when made through a causality.NewClient.
+	// Defaults to false.
+	TrackCausality bool
+
+	// RecordChangedChildren makes the controller accumulate the owned objects whose events enqueued
+	// each reconcile.Request since its owner was last reconciled, so that the Reconciler can retrieve
+	// them with reconcile.ChangedChildren and act on exactly what changed. Only the EventHandler
+	// returned by handler.EnqueueRequestForOwner records them. Defaults to false.
+	RecordChangedChildren bool
+
+	// InitialSyncRateLimiter, if set, rate limits the requests added while the controller waits for its
+	// sources to sync, separately from RateLimiter. Those are mostly the requests for the objects that exist
+	// when the controller starts, of which there is one for every object in the cache, so this keeps a
+	// restart from causing a reconcile storm. Use an overall rate limiter, e.g. a workqueue.BucketRateLimiter.
+	// To skip the objects that were already reconciled instead, see predicate.ObservedGenerationPredicate.
+	InitialSyncRateLimiter ratelimiter.RateLimiter
+
+	// LogReconcileSpans makes the controller log a "Reconcile started" and a "Reconcile finished"
+	// message at V(1) around every reconcile, with the logger passed to the Reconciler in the context,
+	// so they carry the request. The latter includes the duration of the reconcile, the requeue it
+	// resulted in and its error, if any. Defaults to false.
+	LogReconcileSpans bool
+
 	// RateLimiter is used to limit how frequently requests may be queued.
 	// Defaults to MaxOfRateLimiter which has both overall and per-item rate limiting.
 	// The overall is a token bucket and the per-item is exponential.
+	// Use ratelimiter.NewPerClassRateLimiter to keep a chatty class of requests from
+	// exhausting the overall rate limit of the others, together with ClassifyRequest.
 	RateLimiter ratelimiter.RateLimiter
 
+	// ClassifyRequest, if set, makes the controller queue the requests of every class it returns, e.g.
+	// the type of object they refer to, separately and hand them to the workers one class after the
+	// other, so that a chatty class of requests can't starve the others. Every class gets a queue of its
+	// own from NewQueue, named <controller name>/<class>, so that the workqueue metrics of the classes
+	// don't overwrite each other. Classes should therefore be few, like the values of any metric label.
+	// Combine it with ratelimiter.NewPerClassRateLimiter to also rate limit every class separately.
+	ClassifyRequest func(req reconcile.Request) string
+
+	// PrioritizeRequest, if set, makes the controller hand the requests with a higher priority to the
+	// workers first, e.g. to reconcile the objects users changed before those enqueued by a resync.
+	// Priorities are grouped in a small fixed set of bands, high for positive priorities, low for
+	// negative ones and normal for 0, and the depth and latency of the queue of every band are
+	// exposed by the controller_runtime_workqueue_depth and
+	// controller_runtime_workqueue_queue_duration_seconds metrics with a priority label.
+	// PrioritizeRequest must always give a request the same priority. It can't be used together with
+	// ClassifyRequest or a custom NewQueue, and QueueLatencyBuckets and WorkDurationBuckets have no
+	// effect with it.
+	PrioritizeRequest func(req reconcile.Request) int
+
+	// GlobalRateLimiter, if set, rate limits the requests of this controller in addition to RateLimiter.
+	// Share it across controllers, e.g. ones that call the same external API, to enforce an aggregate
+	// retry budget on all of them: a request is delayed by the longer of the delays of both rate limiters.
+	// As the controllers may enqueue requests for the same objects, use an overall rate limiter that is
+	// safe for concurrent use, e.g. a workqueue.BucketRateLimiter, rather than a per-item one.
+	// It is also passed to NewQueue as part of the RateLimiter.
+	GlobalRateLimiter ratelimiter.RateLimiter
+
+	// QueueLatencyBuckets are the bucket boundaries, in seconds, of the histogram of how long requests stay
+	// in the workqueue of this controller, e.g. prometheus.ExponentialBuckets(1e-5, 10, 6) for a controller
+	// that is expected to keep up within milliseconds. Defaults to the buckets of all other workqueues.
+	// It has no effect with a custom NewQueue.
+	QueueLatencyBuckets []float64
+
+	// WorkDurationBuckets are the bucket boundaries, in seconds, of the histogram of how long processing
+	// a request of the workqueue of this controller takes, e.g. prometheus.LinearBuckets(60, 60, 10) for
+	// a controller whose reconciles take minutes. Defaults to the buckets of all other workqueues.
+	// It has no effect with a custom NewQueue.
+	WorkDurationBuckets []float64
+
 	// NewQueue constructs the queue for this controller once the controller is ready to start.
 	// With NewQueue a custom queue implementation can be used, e.g. a priority queue to prioritize with which
 	// priority/order objects are reconciled (e.g. to reconcile objects with changes first).
@@ -93,6 +189,26 @@ type Controller interface {
 	GetLogger() logr.Logger
 }
 
+// Pausable is implemented by the controllers that can be paused, like the Controllers and
+// TypedControllers created by New, NewUnmanaged, NewTyped and NewTypedUnmanaged. It is not part of
+// Controller, so that other implementations of Controller don't have to support pausing:
+//
+//	if p, ok := c.(controller.Pausable); ok {
+//		p.Pause()
+//	}
+type Pausable interface {
+	// Pause stops the controller from dispatching requests to the Reconciler without stopping it,
+	// e.g. during a maintenance window. Requests are still enqueued while it is paused, so that no
+	// events are lost, and are reconciled after Resume. Reconciles in progress are not interrupted,
+	// and a worker that is already waiting for a request when the controller is paused may still
+	// take one off the queue, which it holds on to until Resume.
+	Pause()
+
+	// Resume makes the controller dispatch requests to the Reconciler again after Pause, starting
+	// with the ones enqueued while it was paused.
+	Resume()
+}
+
 // New returns a new Controller registered with the Manager.  The Manager will ensure that shared Caches have
 // been synced before the Controller is Started.
 func New(name string, mgr manager.Manager, options Options) (Controller, error) {
@@ -132,54 +248,310 @@ func NewUnmanaged(name string, mgr manager.Manager, options Options) (Controller
 		}
 	}
 
-	if options.MaxConcurrentReconciles <= 0 {
+	if options.PrioritizeRequest != nil && (options.ClassifyRequest != nil || options.NewQueue != nil) {
+		return nil, fmt.Errorf("PrioritizeRequest can't be used together with ClassifyRequest or NewQueue")
+	}
+
+	shared := sharedOptions{
+		MaxConcurrentReconciles: options.MaxConcurrentReconciles,
+		CacheSyncTimeout:        options.CacheSyncTimeout,
+		RecoverPanic:            options.RecoverPanic,
+		NeedLeaderElection:      options.NeedLeaderElection,
+		RateLimiter:             options.RateLimiter,
+		GlobalRateLimiter:       options.GlobalRateLimiter,
+		QueueLatencyBuckets:     options.QueueLatencyBuckets,
+		WorkDurationBuckets:     options.WorkDurationBuckets,
+		NewQueue:                options.NewQueue,
+	}
+	shared.setDefaults(mgr)
+
+	switch options.DeletedObjectPolicy {
+	case "", reconcile.DeletedObjectHonorRequeue:
+	case reconcile.DeletedObjectDropRequeue, reconcile.DeletedObjectReconcileOnce:
+		if options.ObjectExists == nil {
+			return nil, fmt.Errorf("must specify ObjectExists to use DeletedObjectPolicy %s", options.DeletedObjectPolicy)
+		}
+	default:
+		return nil, fmt.Errorf("unknown DeletedObjectPolicy %q", options.DeletedObjectPolicy)
+	}
+
+	// Create controller with dependencies set
+	return &controller.Controller[reconcile.Request]{
+		Do:                      options.Reconciler,
+		RateLimiter:             shared.RateLimiter,
+		NewQueue:                shared.NewQueue,
+		Classify:                options.ClassifyRequest,
+		Prioritize:              options.PrioritizeRequest,
+		MaxConcurrentReconciles: shared.MaxConcurrentReconciles,
+		CacheSyncTimeout:        shared.CacheSyncTimeout,
+		Name:                    name,
+		LogConstructor:          options.LogConstructor,
+		RecoverPanic:            shared.RecoverPanic,
+		DefaultRequeueAfter:     options.DefaultRequeueAfter,
+		DeletedObjectPolicy:     options.DeletedObjectPolicy,
+		ObjectExists:            options.ObjectExists,
+		RecordTriggeringEvents:  options.RecordTriggeringEvents,
+		TrackCausality:          options.TrackCausality,
+		RecordChangedChildren:   options.RecordChangedChildren,
+		InitialSyncRateLimiter:  options.InitialSyncRateLimiter,
+		LogReconcileSpans:       options.LogReconcileSpans,
+		LeaderElected:           shared.NeedLeaderElection,
+	}, nil
+}
+
+// TypedOptions are the arguments for creating a new TypedController.
+// See Options for the details of each field.
+//
+// TypedOptions is experimental and subject to future change.
+type TypedOptions[K comparable] struct {
+	// MaxConcurrentReconciles is the maximum number of concurrent Reconciles which can be run. Defaults to 1.
+	MaxConcurrentReconciles int
+
+	// CacheSyncTimeout refers to the time limit set to wait for syncing caches.
+	// Defaults to 2 minutes if not set.
+	CacheSyncTimeout time.Duration
+
+	// RecoverPanic indicates whether the panic caused by reconcile should be recovered.
+	// Defaults to the Controller.RecoverPanic setting from the Manager if unset, and to
+	// false, i.e. a panic crashes the process, if that is unset too.
+	RecoverPanic *bool
+
+	// NeedLeaderElection indicates whether the controller needs to use leader election.
+	// Defaults to true, which means the controller will use leader election.
+	NeedLeaderElection *bool
+
+	// Reconciler reconciles the keys of the TypedRequests.
+	Reconciler reconcile.TypedReconciler[K]
+
+	// DefaultRequeueAfter, if greater than 0, makes the controller periodically reconcile keys.
+	// The Reconciler must return a Result with NoRequeue set for keys that are gone, otherwise
+	// they are requeued forever.
+	DefaultRequeueAfter time.Duration
+
+	// RecordTriggeringEvents makes the controller record the event that enqueued each TypedRequest.
+	RecordTriggeringEvents bool
+
+	// TrackCausality puts a causality.Chain into the context of every reconcile.
+	TrackCausality bool
+
+	// RecordChangedChildren makes the controller accumulate the owned objects that enqueued each TypedRequest.
+	RecordChangedChildren bool
+
+	// InitialSyncRateLimiter rate limits the requests added while the controller waits for its sources to sync.
+	InitialSyncRateLimiter ratelimiter.RateLimiter
+
+	// LogReconcileSpans makes the controller log the start and end of every reconcile at V(1).
+	LogReconcileSpans bool
+
+	// RateLimiter is used to limit how frequently requests may be queued.
+	RateLimiter ratelimiter.RateLimiter
+
+	// ClassifyRequest, if set, makes the controller queue the TypedRequests of every class separately
+	// and hand them to the workers one class after the other.
+	ClassifyRequest func(req reconcile.TypedRequest[K]) string
+
+	// PrioritizeRequest, if set, makes the controller hand the TypedRequests with a higher priority
+	// to the workers first, see Options.PrioritizeRequest.
+	PrioritizeRequest func(req reconcile.TypedRequest[K]) int
+
+	// GlobalRateLimiter rate limits the requests of this controller in addition to RateLimiter,
+	// and is meant to be shared across controllers.
+	GlobalRateLimiter ratelimiter.RateLimiter
+
+	// QueueLatencyBuckets are the bucket boundaries of the queue latency histogram of the workqueue.
+	QueueLatencyBuckets []float64
+
+	// WorkDurationBuckets are the bucket boundaries of the work duration histogram of the workqueue.
+	WorkDurationBuckets []float64
+
+	// NewQueue constructs the queue for this controller once the controller is ready to start.
+	NewQueue func(controllerName string, rateLimiter ratelimiter.RateLimiter) workqueue.RateLimitingInterface
+
+	// LogConstructor is used to construct a logger used for this controller and passed
+	// to each reconciliation via the context field.
+	LogConstructor func(request *reconcile.TypedRequest[K]) logr.Logger
+}
+
+// TypedController is a Controller for reconcile.TypedRequests keyed on K rather than
+// on the name and namespace of an object. Use handler.EnqueueTypedRequestsFromMapFunc
+// to enqueue TypedRequests from the events of its sources. Its Reconciler can't request follow-ups
+// with reconcile.EnqueueFollowUps, which returns false for it.
+//
+// TypedController is experimental and subject to future change.
+type TypedController[K comparable] interface {
+	// Reconciler is called to reconcile a key.
+	reconcile.TypedReconciler[K]
+
+	// Watch watches the provided Source.
+	Watch(src source.Source) error
+
+	// Start starts the controller.  Start blocks until the context is closed or a
+	// controller has an error starting.
+	Start(ctx context.Context) error
+
+	// GetLogger returns this controller logger prefilled with basic information.
+	GetLogger() logr.Logger
+}
+
+// NewTyped returns a new TypedController registered with the Manager.
+//
+// NewTyped is experimental and subject to future change.
+func NewTyped[K comparable](name string, mgr manager.Manager, options TypedOptions[K]) (TypedController[K], error) {
+	c, err := NewTypedUnmanaged(name, mgr, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add the controller as a Manager components
+	return c, mgr.Add(c)
+}
+
+// NewTypedUnmanaged returns a new TypedController without adding it to the manager. The
+// caller is responsible for starting the returned controller.
+//
+// NewTypedUnmanaged is experimental and subject to future change.
+func NewTypedUnmanaged[K comparable](name string, mgr manager.Manager, options TypedOptions[K]) (TypedController[K], error) {
+	if options.Reconciler == nil {
+		return nil, fmt.Errorf("must specify Reconciler")
+	}
+
+	if len(name) == 0 {
+		return nil, fmt.Errorf("must specify Name for Controller")
+	}
+
+	if options.LogConstructor == nil {
+		log := mgr.GetLogger().WithValues(
+			"controller", name,
+		)
+		options.LogConstructor = func(req *reconcile.TypedRequest[K]) logr.Logger {
+			if req != nil {
+				return log.WithValues("key", req.Key)
+			}
+			return log
+		}
+	}
+
+	if options.PrioritizeRequest != nil && (options.ClassifyRequest != nil || options.NewQueue != nil) {
+		return nil, fmt.Errorf("PrioritizeRequest can't be used together with ClassifyRequest or NewQueue")
+	}
+
+	shared := sharedOptions{
+		MaxConcurrentReconciles: options.MaxConcurrentReconciles,
+		CacheSyncTimeout:        options.CacheSyncTimeout,
+		RecoverPanic:            options.RecoverPanic,
+		NeedLeaderElection:      options.NeedLeaderElection,
+		RateLimiter:             options.RateLimiter,
+		GlobalRateLimiter:       options.GlobalRateLimiter,
+		QueueLatencyBuckets:     options.QueueLatencyBuckets,
+		WorkDurationBuckets:     options.WorkDurationBuckets,
+		NewQueue:                options.NewQueue,
+	}
+	shared.setDefaults(mgr)
+
+	return &controller.Controller[reconcile.TypedRequest[K]]{
+		Do:                      options.Reconciler,
+		RateLimiter:             shared.RateLimiter,
+		NewQueue:                shared.NewQueue,
+		Classify:                options.ClassifyRequest,
+		Prioritize:              options.PrioritizeRequest,
+		MaxConcurrentReconciles: shared.MaxConcurrentReconciles,
+		CacheSyncTimeout:        shared.CacheSyncTimeout,
+		Name:                    name,
+		LogConstructor:          options.LogConstructor,
+		RecoverPanic:            shared.RecoverPanic,
+		DefaultRequeueAfter:     options.DefaultRequeueAfter,
+		RecordTriggeringEvents:  options.RecordTriggeringEvents,
+		TrackCausality:          options.TrackCausality,
+		RecordChangedChildren:   options.RecordChangedChildren,
+		InitialSyncRateLimiter:  options.InitialSyncRateLimiter,
+		LogReconcileSpans:       options.LogReconcileSpans,
+		LeaderElected:           shared.NeedLeaderElection,
+	}, nil
+}
+
+// sharedOptions are the options of Options and TypedOptions that are defaulted
+// from the Manager.
+type sharedOptions struct {
+	MaxConcurrentReconciles int
+	CacheSyncTimeout        time.Duration
+	RecoverPanic            *bool
+	NeedLeaderElection      *bool
+	RateLimiter             ratelimiter.RateLimiter
+	GlobalRateLimiter       ratelimiter.RateLimiter
+	QueueLatencyBuckets     []float64
+	WorkDurationBuckets     []float64
+	NewQueue                func(controllerName string, rateLimiter ratelimiter.RateLimiter) workqueue.RateLimitingInterface
+}
+
+func (o *sharedOptions) setDefaults(mgr manager.Manager) {
+	if o.MaxConcurrentReconciles <= 0 {
 		if mgr.GetControllerOptions().MaxConcurrentReconciles > 0 {
-			options.MaxConcurrentReconciles = mgr.GetControllerOptions().MaxConcurrentReconciles
+			o.MaxConcurrentReconciles = mgr.GetControllerOptions().MaxConcurrentReconciles
 		} else {
-			options.MaxConcurrentReconciles = 1
+			o.MaxConcurrentReconciles = 1
 		}
 	}
 
-	if options.CacheSyncTimeout == 0 {
+	if o.CacheSyncTimeout == 0 {
 		if mgr.GetControllerOptions().CacheSyncTimeout != 0 {
-			options.CacheSyncTimeout = mgr.GetControllerOptions().CacheSyncTimeout
+			o.CacheSyncTimeout = mgr.GetControllerOptions().CacheSyncTimeout
 		} else {
-			options.CacheSyncTimeout = 2 * time.Minute
+			o.CacheSyncTimeout = 2 * time.Minute
 		}
 	}
 
-	if options.RateLimiter == nil {
-		options.RateLimiter = workqueue.DefaultControllerRateLimiter()
+	if o.RateLimiter == nil {
+		o.RateLimiter = workqueue.DefaultControllerRateLimiter()
+	}
+	if o.GlobalRateLimiter != nil {
+		o.RateLimiter = workqueue.NewMaxOfRateLimiter(o.RateLimiter, o.GlobalRateLimiter)
 	}
 
-	if options.NewQueue == nil {
-		options.NewQueue = func(controllerName string, rateLimiter ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
+	if o.NewQueue == nil {
+		var metricsProvider workqueue.MetricsProvider
+		if o.QueueLatencyBuckets != nil || o.WorkDurationBuckets != nil {
+			metricsProvider = metrics.NewWorkQueueMetricsProvider(o.QueueLatencyBuckets, o.WorkDurationBuckets)
+		}
+		o.NewQueue = func(controllerName string, rateLimiter ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
 			return workqueue.NewRateLimitingQueueWithConfig(rateLimiter, workqueue.RateLimitingQueueConfig{
-				Name: controllerName,
+				Name:            controllerName,
+				MetricsProvider: metricsProvider,
 			})
 		}
 	}
 
-	if options.RecoverPanic == nil {
-		options.RecoverPanic = mgr.GetControllerOptions().RecoverPanic
+	if o.RecoverPanic == nil {
+		o.RecoverPanic = mgr.GetControllerOptions().RecoverPanic
 	}
 
-	if options.NeedLeaderElection == nil {
-		options.NeedLeaderElection = mgr.GetControllerOptions().NeedLeaderElection
+	if o.NeedLeaderElection == nil {
+		o.NeedLeaderElection = mgr.GetControllerOptions().NeedLeaderElection
 	}
+}
 
-	// Create controller with dependencies set
-	return &controller.Controller{
-		Do:                      options.Reconciler,
-		RateLimiter:             options.RateLimiter,
-		NewQueue:                options.NewQueue,
-		MaxConcurrentReconciles: options.MaxConcurrentReconciles,
-		CacheSyncTimeout:        options.CacheSyncTimeout,
-		Name:                    name,
-		LogConstructor:          options.LogConstructor,
-		RecoverPanic:            options.RecoverPanic,
-		LeaderElected:           options.NeedLeaderElection,
-	}, nil
+// Backoff returns the delay the rate limiter of c gave req when it was last requeued with rate
+// limiting, e.g. after a failed reconcile, and true. It returns false if req is not backing off,
+// the controller did not start yet or it was not created by New or NewUnmanaged. It is meant
+// for diagnostics, e.g. of a hot-looping reconcile, and has no effect on the rate limiter.
+func Backoff(c Controller, req reconcile.Request) (time.Duration, bool) {
+	return backoff(c, req)
+}
+
+// TypedBackoff is Backoff for a TypedController.
+//
+// TypedBackoff is experimental and subject to future change.
+func TypedBackoff[K comparable](c TypedController[K], req reconcile.TypedRequest[K]) (time.Duration, bool) {
+	return backoff(c, req)
+}
+
+func backoff[request comparable](c any, req request) (time.Duration, bool) {
+	ctrl, ok := c.(interface {
+		Backoff(req request) (time.Duration, bool)
+	})
+	if !ok {
+		return 0, false
+	}
+	return ctrl.Backoff(req)
 }
 
 // ReconcileIDFromContext gets the reconcileID from the current context.
@@ -21,12 +21,16 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -48,6 +52,45 @@ var _ = Describe("controller", func() {
 	Describe("controller", func() {
 		// TODO(directxman12): write a whole suite of controller-client interaction tests
 
+		It("should pass the scheme and RESTMapper of the manager to Reconcile", func() {
+			cm, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			type reconcileContext struct {
+				scheme *runtime.Scheme
+				mapper meta.RESTMapper
+			}
+			reconcileContexts := make(chan reconcileContext, 1)
+			instance, err := controller.New("context-values-controller", cm, controller.Options{
+				Reconciler: reconcile.Func(
+					func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+						scheme, ok := client.SchemeFromContext(ctx)
+						Expect(ok).To(BeTrue())
+						mapper, ok := client.RESTMapperFromContext(ctx)
+						Expect(ok).To(BeTrue())
+						reconcileContexts <- reconcileContext{scheme: scheme, mapper: mapper}
+						return reconcile.Result{}, nil
+					}),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			events := make(chan event.GenericEvent, 1)
+			Expect(instance.Watch(source.Channel(events, &handler.EnqueueRequestForObject{}))).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(cm.Start(ctx)).NotTo(HaveOccurred())
+			}()
+
+			events <- event.GenericEvent{Object: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}}
+			var got reconcileContext
+			Eventually(reconcileContexts).Should(Receive(&got))
+			Expect(got.scheme).To(BeIdenticalTo(cm.GetScheme()))
+			Expect(got.mapper).To(BeIdenticalTo(cm.GetRESTMapper()))
+		})
+
 		It("should reconcile", func() {
 			By("Creating the Manager")
 			cm, err := manager.New(cfg, manager.Options{})
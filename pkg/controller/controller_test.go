@@ -24,6 +24,7 @@ import (
 	. "github.com/onsi/gomega"
 	"go.uber.org/goleak"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/ptr"
 
@@ -33,6 +34,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	internalcontroller "sigs.k8s.io/controller-runtime/pkg/internal/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -61,6 +63,44 @@ var _ = Describe("controller.Controller", func() {
 			Expect(err.Error()).To(ContainSubstring("must specify Reconciler"))
 		})
 
+		It("should return an error if DeletedObjectPolicy is set without ObjectExists", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("foo", m, controller.Options{
+				Reconciler:          rec,
+				DeletedObjectPolicy: reconcile.DeletedObjectDropRequeue,
+			})
+			Expect(c).To(BeNil())
+			Expect(err.Error()).To(ContainSubstring("must specify ObjectExists to use DeletedObjectPolicy DropRequeue"))
+		})
+
+		It("should return an error if DeletedObjectPolicy is unknown", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("foo", m, controller.Options{
+				Reconciler:          rec,
+				DeletedObjectPolicy: "Unknown",
+				ObjectExists:        func(context.Context, reconcile.Request) (bool, error) { return false, nil },
+			})
+			Expect(c).To(BeNil())
+			Expect(err.Error()).To(ContainSubstring(`unknown DeletedObjectPolicy "Unknown"`))
+		})
+
+		It("should return an error if PrioritizeRequest is set together with ClassifyRequest", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("foo", m, controller.Options{
+				Reconciler:        rec,
+				ClassifyRequest:   func(req reconcile.Request) string { return req.Namespace },
+				PrioritizeRequest: func(reconcile.Request) int { return 0 },
+			})
+			Expect(c).To(BeNil())
+			Expect(err.Error()).To(ContainSubstring("PrioritizeRequest can't be used together with ClassifyRequest or NewQueue"))
+		})
+
 		It("should not return an error if two controllers are registered with different names", func() {
 			m, err := manager.New(cfg, manager.Options{})
 			Expect(err).NotTo(HaveOccurred())
@@ -144,7 +184,7 @@ var _ = Describe("controller.Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			ctrl, ok := c.(*internalcontroller.Controller)
+			ctrl, ok := c.(*internalcontroller.Controller[reconcile.Request])
 			Expect(ok).To(BeTrue())
 
 			Expect(ctrl.RateLimiter).NotTo(BeNil())
@@ -169,7 +209,7 @@ var _ = Describe("controller.Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			ctrl, ok := c.(*internalcontroller.Controller)
+			ctrl, ok := c.(*internalcontroller.Controller[reconcile.Request])
 			Expect(ok).To(BeTrue())
 
 			Expect(ctrl.RateLimiter).To(BeIdenticalTo(customRateLimiter))
@@ -177,6 +217,80 @@ var _ = Describe("controller.Controller", func() {
 			Expect(customNewQueueCalled).To(BeTrue(), "Expected customNewQueue to be called")
 		})
 
+		It("should throttle controllers sharing a GlobalRateLimiter together", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Two fast requeues, then one per minute, for all requests of both controllers.
+			globalRateLimiter := overallRateLimiter{workqueue.NewItemFastSlowRateLimiter(time.Millisecond, time.Minute, 2)}
+			newController := func(name string) *internalcontroller.Controller[reconcile.Request] {
+				c, err := controller.New(name, m, controller.Options{
+					Reconciler:        reconcile.Func(nil),
+					RateLimiter:       workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, time.Millisecond),
+					GlobalRateLimiter: globalRateLimiter,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				ctrl, ok := c.(*internalcontroller.Controller[reconcile.Request])
+				Expect(ok).To(BeTrue())
+				return ctrl
+			}
+			first := newController("global-rate-limited-1")
+			second := newController("global-rate-limited-2")
+
+			Expect(first.RateLimiter.When(reconcile.Request{NamespacedName: types.NamespacedName{Name: "a"}})).To(Equal(time.Millisecond))
+			Expect(second.RateLimiter.When(reconcile.Request{NamespacedName: types.NamespacedName{Name: "b"}})).To(Equal(time.Millisecond))
+			// Each controller requeued only once, but together they exhausted the budget.
+			Expect(first.RateLimiter.When(reconcile.Request{NamespacedName: types.NamespacedName{Name: "c"}})).To(Equal(time.Minute))
+			Expect(second.RateLimiter.When(reconcile.Request{NamespacedName: types.NamespacedName{Name: "d"}})).To(Equal(time.Minute))
+		})
+
+		It("should observe the workqueue latency and work duration with custom buckets", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("custom-buckets-controller", m, controller.Options{
+				Reconciler:          reconcile.Func(nil),
+				QueueLatencyBuckets: []float64{0.0001, 0.001},
+				WorkDurationBuckets: []float64{60, 120, 300},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ctrl, ok := c.(*internalcontroller.Controller[reconcile.Request])
+			Expect(ok).To(BeTrue())
+
+			q := ctrl.NewQueue("custom-buckets-controller", ctrl.RateLimiter)
+			defer q.ShutDown()
+			q.Add(reconcile.Request{})
+			item, _ := q.Get()
+			q.Done(item)
+
+			bucketsOf := func(metricName string) []float64 {
+				families, err := metrics.Registry.Gather()
+				Expect(err).NotTo(HaveOccurred())
+				for _, family := range families {
+					if family.GetName() != metricName {
+						continue
+					}
+					for _, metric := range family.GetMetric() {
+						for _, label := range metric.GetLabel() {
+							if label.GetName() != "name" || label.GetValue() != "custom-buckets-controller" {
+								continue
+							}
+							var buckets []float64
+							for _, bucket := range metric.GetHistogram().GetBucket() {
+								buckets = append(buckets, bucket.GetUpperBound())
+							}
+							Expect(metric.GetHistogram().GetSampleCount()).To(Equal(uint64(1)))
+							return buckets
+						}
+					}
+				}
+				return nil
+			}
+			Expect(bucketsOf("workqueue_queue_duration_seconds")).To(Equal([]float64{0.0001, 0.001}))
+			Expect(bucketsOf("workqueue_work_duration_seconds")).To(Equal([]float64{60, 120, 300}))
+		})
+
 		It("should default RecoverPanic from the manager", func() {
 			m, err := manager.New(cfg, manager.Options{Controller: config.Controller{RecoverPanic: ptr.To(true)}})
 			Expect(err).NotTo(HaveOccurred())
@@ -186,7 +300,7 @@ var _ = Describe("controller.Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			ctrl, ok := c.(*internalcontroller.Controller)
+			ctrl, ok := c.(*internalcontroller.Controller[reconcile.Request])
 			Expect(ok).To(BeTrue())
 
 			Expect(ctrl.RecoverPanic).NotTo(BeNil())
@@ -203,7 +317,7 @@ var _ = Describe("controller.Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			ctrl, ok := c.(*internalcontroller.Controller)
+			ctrl, ok := c.(*internalcontroller.Controller[reconcile.Request])
 			Expect(ok).To(BeTrue())
 
 			Expect(ctrl.RecoverPanic).NotTo(BeNil())
@@ -219,7 +333,7 @@ var _ = Describe("controller.Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			ctrl, ok := c.(*internalcontroller.Controller)
+			ctrl, ok := c.(*internalcontroller.Controller[reconcile.Request])
 			Expect(ok).To(BeTrue())
 
 			Expect(ctrl.NeedLeaderElection()).To(BeTrue())
@@ -235,7 +349,7 @@ var _ = Describe("controller.Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			ctrl, ok := c.(*internalcontroller.Controller)
+			ctrl, ok := c.(*internalcontroller.Controller[reconcile.Request])
 			Expect(ok).To(BeTrue())
 
 			Expect(ctrl.NeedLeaderElection()).To(BeFalse())
@@ -250,7 +364,7 @@ var _ = Describe("controller.Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			ctrl, ok := c.(*internalcontroller.Controller)
+			ctrl, ok := c.(*internalcontroller.Controller[reconcile.Request])
 			Expect(ok).To(BeTrue())
 
 			Expect(ctrl.MaxConcurrentReconciles).To(BeEquivalentTo(5))
@@ -265,7 +379,7 @@ var _ = Describe("controller.Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			ctrl, ok := c.(*internalcontroller.Controller)
+			ctrl, ok := c.(*internalcontroller.Controller[reconcile.Request])
 			Expect(ok).To(BeTrue())
 
 			Expect(ctrl.MaxConcurrentReconciles).To(BeEquivalentTo(1))
@@ -281,7 +395,7 @@ var _ = Describe("controller.Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			ctrl, ok := c.(*internalcontroller.Controller)
+			ctrl, ok := c.(*internalcontroller.Controller[reconcile.Request])
 			Expect(ok).To(BeTrue())
 
 			Expect(ctrl.MaxConcurrentReconciles).To(BeEquivalentTo(5))
@@ -296,7 +410,7 @@ var _ = Describe("controller.Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			ctrl, ok := c.(*internalcontroller.Controller)
+			ctrl, ok := c.(*internalcontroller.Controller[reconcile.Request])
 			Expect(ok).To(BeTrue())
 
 			Expect(ctrl.CacheSyncTimeout).To(BeEquivalentTo(5))
@@ -311,7 +425,7 @@ var _ = Describe("controller.Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			ctrl, ok := c.(*internalcontroller.Controller)
+			ctrl, ok := c.(*internalcontroller.Controller[reconcile.Request])
 			Expect(ok).To(BeTrue())
 
 			Expect(ctrl.CacheSyncTimeout).To(BeEquivalentTo(2 * time.Minute))
@@ -327,7 +441,7 @@ var _ = Describe("controller.Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			ctrl, ok := c.(*internalcontroller.Controller)
+			ctrl, ok := c.(*internalcontroller.Controller[reconcile.Request])
 			Expect(ok).To(BeTrue())
 
 			Expect(ctrl.CacheSyncTimeout).To(BeEquivalentTo(5))
@@ -342,7 +456,7 @@ var _ = Describe("controller.Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			ctrl, ok := c.(*internalcontroller.Controller)
+			ctrl, ok := c.(*internalcontroller.Controller[reconcile.Request])
 			Expect(ok).To(BeTrue())
 
 			Expect(ctrl.NeedLeaderElection()).To(BeTrue())
@@ -358,7 +472,7 @@ var _ = Describe("controller.Controller", func() {
 			})
 			Expect(err).NotTo(HaveOccurred())
 
-			ctrl, ok := c.(*internalcontroller.Controller)
+			ctrl, ok := c.(*internalcontroller.Controller[reconcile.Request])
 			Expect(ok).To(BeTrue())
 
 			Expect(ctrl.NeedLeaderElection()).To(BeFalse())
@@ -378,3 +492,20 @@ var _ = Describe("controller.Controller", func() {
 		})
 	})
 })
+
+// overallRateLimiter rate limits all items together with a per-item RateLimiter.
+type overallRateLimiter struct {
+	workqueue.RateLimiter
+}
+
+func (r overallRateLimiter) When(interface{}) time.Duration {
+	return r.RateLimiter.When("")
+}
+
+func (r overallRateLimiter) Forget(interface{}) {
+	r.RateLimiter.Forget("")
+}
+
+func (r overallRateLimiter) NumRequeues(interface{}) int {
+	return r.RateLimiter.NumRequeues("")
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -263,6 +264,23 @@ const ( // They should complete the sentence "Deployment default/foo has been ..
 	OperationResultUpdatedStatusOnly OperationResult = "updatedStatusOnly"
 )
 
+// CreateOrUpdateOption is an option of CreateOrUpdate.
+type CreateOrUpdateOption func(*createOrUpdateOptions)
+
+type createOrUpdateOptions struct {
+	dryRun bool
+}
+
+// WithDryRun makes CreateOrUpdate create or update the object in server-side
+// dry-run mode: nothing is persisted, but the returned OperationResult is the
+// one of the intended operation, and the object is set to the one the API
+// server would have persisted, e.g. to preview changes.
+func WithDryRun() CreateOrUpdateOption {
+	return func(o *createOrUpdateOptions) {
+		o.dryRun = true
+	}
+}
+
 // CreateOrUpdate creates or updates the given object in the Kubernetes
 // cluster. The object's desired state must be reconciled with the existing
 // state inside the passed in callback MutateFn.
@@ -273,7 +291,15 @@ const ( // They should complete the sentence "Deployment default/foo has been ..
 //
 // Note: changes made by MutateFn to any sub-resource (status...), will be
 // discarded.
-func CreateOrUpdate(ctx context.Context, c client.Client, obj client.Object, f MutateFn) (OperationResult, error) {
+func CreateOrUpdate(ctx context.Context, c client.Client, obj client.Object, f MutateFn, opts ...CreateOrUpdateOption) (OperationResult, error) {
+	options := &createOrUpdateOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.dryRun {
+		c = client.NewDryRunClient(c)
+	}
+
 	key := client.ObjectKeyFromObject(obj)
 	if err := c.Get(ctx, key, obj); err != nil {
 		if !apierrors.IsNotFound(err) {
@@ -421,6 +447,73 @@ func CreateOrPatch(ctx context.Context, c client.Client, obj client.Object, f Mu
 	return result, nil
 }
 
+// DefaultNeedsUpdateIgnoredFields are the fields NeedsUpdate ignores when no fields are given:
+// the metadata populated by the API server, and the status, which is usually written separately
+// through the status subresource.
+var DefaultNeedsUpdateIgnoredFields = []string{
+	"metadata.uid",
+	"metadata.resourceVersion",
+	"metadata.generation",
+	"metadata.creationTimestamp",
+	"metadata.managedFields",
+	"metadata.selfLink",
+	"status",
+}
+
+// NeedsUpdate reports whether observed, e.g. as read from the API server, differs semantically
+// from desired, i.e. whether writing desired would be a no-op that can be skipped to avoid
+// unnecessary API requests and events.
+//
+// The fields in ignoreFields, given as dot-separated paths like "metadata.labels", are not
+// compared. If no fields are given, DefaultNeedsUpdateIgnoredFields are ignored; to ignore
+// more fields, append them to DefaultNeedsUpdateIgnoredFields. Unset and empty fields compare
+// as equal. NeedsUpdate reports true if the objects can not be compared, e.g. because they
+// are of different types.
+func NeedsUpdate(observed, desired client.Object, ignoreFields ...string) bool {
+	if len(ignoreFields) == 0 {
+		ignoreFields = DefaultNeedsUpdateIgnoredFields
+	}
+	if reflect.TypeOf(observed) != reflect.TypeOf(desired) {
+		return true
+	}
+
+	observedCmp, err := withoutFields(observed, ignoreFields)
+	if err != nil {
+		return true
+	}
+	desiredCmp, err := withoutFields(desired, ignoreFields)
+	if err != nil {
+		return true
+	}
+	return !equality.Semantic.DeepEqual(observedCmp, desiredCmp)
+}
+
+// withoutFields returns a copy of obj without the given fields, converted back to the
+// type of obj unless it is unstructured, so that it can be compared semantically.
+func withoutFields(obj client.Object, fields []string) (interface{}, error) {
+	var content map[string]interface{}
+	if u, ok := obj.(runtime.Unstructured); ok {
+		content = runtime.DeepCopyJSON(u.UnstructuredContent())
+	} else {
+		var err error
+		if content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj); err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range fields {
+		unstructured.RemoveNestedField(content, strings.Split(field, ".")...)
+	}
+	if _, ok := obj.(runtime.Unstructured); ok {
+		return content, nil
+	}
+
+	out := reflect.New(reflect.TypeOf(obj).Elem()).Interface()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // mutate wraps a MutateFn and applies validation to its result.
 func mutate(f MutateFn, key client.ObjectKey, obj client.Object) error {
 	if err := f(); err != nil {
@@ -435,6 +528,62 @@ func mutate(f MutateFn, key client.ObjectKey, obj client.Object) error {
 // MutateFn is a function which mutates the existing object into its desired state.
 type MutateFn func() error
 
+// Prune deletes the objects of the given GVKs that are controlled by owner but are not
+// in desired, e.g. the child objects created for an earlier spec of owner. This allows
+// reconcilers to apply a set of objects and prune the rest.
+//
+// Only objects with a controller reference to owner are deleted, objects owned by anyone
+// else are never touched. For a namespaced owner, only objects in its namespace are
+// considered. Desired objects without a namespace are assumed to be in the namespace of
+// owner. Objects are listed as metav1.PartialObjectMetadata, so with a cache-backed client
+// a metadata-only informer is used for each GVK.
+func Prune(ctx context.Context, c client.Client, owner client.Object, desired []client.Object, gvks ...schema.GroupVersionKind) error {
+	type objectKey struct {
+		gvk schema.GroupVersionKind
+		client.ObjectKey
+	}
+	keep := make(map[objectKey]struct{}, len(desired))
+	for _, obj := range desired {
+		gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+		if err != nil {
+			return err
+		}
+		key := client.ObjectKeyFromObject(obj)
+		if key.Namespace == "" {
+			key.Namespace = owner.GetNamespace()
+		}
+		keep[objectKey{gvk: gvk, ObjectKey: key}] = struct{}{}
+	}
+
+	for _, gvk := range gvks {
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.List(ctx, list, client.InNamespace(owner.GetNamespace())); err != nil {
+			return fmt.Errorf("failed to list %s to prune: %w", gvk.Kind, err)
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if !metav1.IsControlledBy(obj, owner) {
+				continue
+			}
+			key := client.ObjectKeyFromObject(obj)
+			if key.Namespace == "" {
+				key.Namespace = owner.GetNamespace()
+			}
+			if _, ok := keep[objectKey{gvk: gvk, ObjectKey: key}]; ok {
+				continue
+			}
+
+			obj.SetGroupVersionKind(gvk)
+			if err := c.Delete(ctx, obj, client.Preconditions{UID: ptr.To(obj.GetUID())}, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("failed to prune %s %s: %w", gvk.Kind, client.ObjectKeyFromObject(obj), err)
+			}
+		}
+	}
+	return nil
+}
+
 // AddFinalizer accepts an Object and adds the provided finalizer if not present.
 // It returns an indication of whether it updated the object's list of finalizers.
 func AddFinalizer(o client.Object, finalizer string) (finalizersUpdated bool) {
@@ -26,12 +26,15 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -493,6 +496,45 @@ var _ = Describe("Controllerutil", func() {
 			Expect(*fetched.Spec.Replicas).To(Equal(scale))
 		})
 
+		It("does not persist a new object in dry-run mode", func() {
+			op, err := controllerutil.CreateOrUpdate(context.TODO(), c, deploy, specr, controllerutil.WithDryRun())
+
+			By("returning no error")
+			Expect(err).NotTo(HaveOccurred())
+
+			By("returning OperationResultCreated")
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultCreated))
+
+			By("returning the would-be object")
+			Expect(deploy.UID).NotTo(BeEmpty())
+			Expect(deploy.Spec.Template.Spec.Containers).To(HaveLen(1))
+
+			By("not having the deployment created")
+			Expect(apierrors.IsNotFound(c.Get(context.TODO(), deplKey, &appsv1.Deployment{}))).To(BeTrue())
+		})
+
+		It("does not persist an update in dry-run mode", func() {
+			var scale int32 = 2
+			op, err := controllerutil.CreateOrUpdate(context.TODO(), c, deploy, specr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultCreated))
+
+			op, err = controllerutil.CreateOrUpdate(context.TODO(), c, deploy, deploymentScaler(deploy, scale), controllerutil.WithDryRun())
+			By("returning no error")
+			Expect(err).NotTo(HaveOccurred())
+
+			By("returning OperationResultUpdated")
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultUpdated))
+
+			By("returning the would-be object")
+			Expect(*deploy.Spec.Replicas).To(Equal(scale))
+
+			By("not having the deployment scaled")
+			fetched := &appsv1.Deployment{}
+			Expect(c.Get(context.TODO(), deplKey, fetched)).To(Succeed())
+			Expect(*fetched.Spec.Replicas).NotTo(Equal(scale))
+		})
+
 		It("updates only changed objects", func() {
 			op, err := controllerutil.CreateOrUpdate(context.TODO(), c, deploy, specr)
 
@@ -820,6 +862,114 @@ var _ = Describe("Controllerutil", func() {
 		})
 	})
 
+	Describe("Prune", func() {
+		var (
+			owner *corev1.ConfigMap
+			cl    client.Client
+		)
+
+		newChild := func(name string, controller metav1.Object) *corev1.ConfigMap {
+			child := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+			if controller != nil {
+				Expect(controllerutil.SetControllerReference(controller, child, scheme.Scheme)).To(Succeed())
+			}
+			return child
+		}
+
+		BeforeEach(func() {
+			owner = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "default", UID: "owner-uid"}}
+			otherOwner := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other-owner", Namespace: "default", UID: "other-owner-uid"}}
+			cl = fake.NewClientBuilder().WithObjects(
+				owner,
+				newChild("desired", owner),
+				newChild("undesired", owner),
+				newChild("foreign", otherOwner),
+				newChild("unowned", nil),
+			).Build()
+		})
+
+		It("should delete undesired owned objects and leave foreign ones untouched", func() {
+			desired := []client.Object{newChild("desired", owner)}
+			Expect(controllerutil.Prune(context.Background(), cl, owner, desired, corev1.SchemeGroupVersion.WithKind("ConfigMap"))).To(Succeed())
+
+			cms := &corev1.ConfigMapList{}
+			Expect(cl.List(context.Background(), cms)).To(Succeed())
+			var names []string
+			for _, cm := range cms.Items {
+				names = append(names, cm.Name)
+			}
+			Expect(names).To(ConsistOf("owner", "desired", "foreign", "unowned"))
+		})
+
+		It("should only consider the given GVKs", func() {
+			Expect(controllerutil.Prune(context.Background(), cl, owner, nil, corev1.SchemeGroupVersion.WithKind("Secret"))).To(Succeed())
+
+			cms := &corev1.ConfigMapList{}
+			Expect(cl.List(context.Background(), cms)).To(Succeed())
+			Expect(cms.Items).To(HaveLen(5))
+		})
+	})
+
+	Describe("NeedsUpdate", func() {
+		var observed, desired *appsv1.Deployment
+
+		BeforeEach(func() {
+			desired = &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Labels: map[string]string{"app": "foo"}},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: ptr.To(int32(2)),
+					Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "foo", Image: "foo:v1"}},
+					}},
+				},
+			}
+			observed = desired.DeepCopy()
+			observed.UID = "foo-uid"
+			observed.ResourceVersion = "42"
+			observed.Generation = 3
+			observed.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "foo", Operation: metav1.ManagedFieldsOperationApply}}
+			observed.Status.Replicas = 2
+		})
+
+		It("should report no update is needed for objects differing only in server-populated fields", func() {
+			Expect(controllerutil.NeedsUpdate(observed, desired)).To(BeFalse())
+		})
+
+		It("should report an update is needed when a single field changed", func() {
+			desired.Spec.Template.Spec.Containers[0].Image = "foo:v2"
+			Expect(controllerutil.NeedsUpdate(observed, desired)).To(BeTrue())
+		})
+
+		It("should compare unset and empty fields as equal", func() {
+			observed.Annotations = map[string]string{}
+			Expect(controllerutil.NeedsUpdate(observed, desired)).To(BeFalse())
+		})
+
+		It("should only ignore the given fields", func() {
+			Expect(controllerutil.NeedsUpdate(observed, desired, "metadata.managedFields")).To(BeTrue())
+
+			desired.Labels["extra"] = "label"
+			ignored := append([]string{"metadata.labels"}, controllerutil.DefaultNeedsUpdateIgnoredFields...)
+			Expect(controllerutil.NeedsUpdate(observed, desired, ignored...)).To(BeFalse())
+		})
+
+		It("should compare unstructured objects", func() {
+			observedU := &unstructured.Unstructured{}
+			observedU.SetUnstructuredContent(map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": "foo", "resourceVersion": "42"},
+				"data":       map[string]interface{}{"foo": "bar"},
+			})
+			desiredU := observedU.DeepCopy()
+			unstructured.RemoveNestedField(desiredU.Object, "metadata", "resourceVersion")
+			Expect(controllerutil.NeedsUpdate(observedU, desiredU)).To(BeFalse())
+
+			Expect(unstructured.SetNestedField(desiredU.Object, "baz", "data", "foo")).To(Succeed())
+			Expect(controllerutil.NeedsUpdate(observedU, desiredU)).To(BeTrue())
+		})
+	})
+
 	Describe("Finalizers", func() {
 		var deploy *appsv1.Deployment
 
@@ -38,3 +38,25 @@ type Hub interface {
 	runtime.Object
 	Hub()
 }
+
+// MultiHubConvertible can optionally be implemented by a Convertible type
+// whose group-kind has more than one hub, e.g. during a multi-step migration.
+// Hubs returns the hub types this spoke can convert to/from. The conversion
+// webhook connects every spoke to the hubs it declares and converts between
+// two versions along the (unique) path connecting them, so the declared hubs
+// must not form a cycle.
+type MultiHubConvertible interface {
+	Convertible
+	Hubs() []Hub
+}
+
+// WarningConvertible can optionally be implemented by a Convertible type whose conversions
+// can produce warnings, e.g. that a field was dropped because the destination version cannot
+// represent it. The conversion webhook calls ConvertToWithWarnings and ConvertFromWithWarnings
+// instead of ConvertTo and ConvertFrom. As the ConversionReview API has no field for warnings,
+// the webhook logs them and reports them in the message of the successful response.
+type WarningConvertible interface {
+	Convertible
+	ConvertToWithWarnings(dst Hub) (warnings []string, err error)
+	ConvertFromWithWarnings(src Hub) (warnings []string, err error)
+}
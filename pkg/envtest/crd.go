@@ -23,7 +23,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"time"
 
@@ -59,10 +61,17 @@ type CRDInstallOptions struct {
 	// Paths is a list of paths to the directories or files containing CRDs
 	Paths []string
 
+	// Filesystem is a filesystem containing CRDs, e.g. an embed.FS.
+	Filesystem fs.FS
+
+	// FilesystemPaths is a list of paths to the directories or files containing
+	// CRDs in Filesystem. Defaults to the root of Filesystem if Filesystem is set.
+	FilesystemPaths []string
+
 	// CRDs is a list of CRDs to install
 	CRDs []*apiextensionsv1.CustomResourceDefinition
 
-	// ErrorIfPathMissing will cause an error if a Path does not exist
+	// ErrorIfPathMissing will cause an error if a Path or FilesystemPath does not exist
 	ErrorIfPathMissing bool
 
 	// MaxTime is the max time to wait
@@ -115,9 +124,10 @@ func InstallCRDs(config *rest.Config, options CRDInstallOptions) ([]*apiextensio
 	return options.CRDs, nil
 }
 
-// readCRDFiles reads the directories of CRDs in options.Paths and adds the CRD structs to options.CRDs.
+// readCRDFiles reads the directories of CRDs in options.Paths and options.FilesystemPaths
+// and adds the CRD structs to options.CRDs.
 func readCRDFiles(options *CRDInstallOptions) error {
-	if len(options.Paths) > 0 {
+	if len(options.Paths) > 0 || options.Filesystem != nil {
 		crdList, err := renderCRDs(options)
 		if err != nil {
 			return err
@@ -278,7 +288,7 @@ func CreateCRDs(config *rest.Config, crds []*apiextensionsv1.CustomResourceDefin
 	return nil
 }
 
-// renderCRDs iterate through options.Paths and extract all CRD files.
+// renderCRDs iterate through options.Paths and options.FilesystemPaths and extract all CRD files.
 func renderCRDs(options *CRDInstallOptions) ([]*apiextensionsv1.CustomResourceDefinition, error) {
 	type GVKN struct {
 		GVK  schema.GroupVersionKind
@@ -286,6 +296,17 @@ func renderCRDs(options *CRDInstallOptions) ([]*apiextensionsv1.CustomResourceDe
 	}
 
 	crds := map[GVKN]*apiextensionsv1.CustomResourceDefinition{}
+	addCRDs := func(crdList []*apiextensionsv1.CustomResourceDefinition) {
+		for i, crd := range crdList {
+			gvkn := GVKN{GVK: crd.GroupVersionKind(), Name: crd.GetName()}
+			if _, found := crds[gvkn]; found {
+				// Currently, we only print a log when there are duplicates. We may want to error out if that makes more sense.
+				log.Info("there are more than one CRD definitions with the same <Group, Version, Kind, Name>", "GVKN", gvkn)
+			}
+			// We always use the CRD definition that we found last.
+			crds[gvkn] = crdList[i]
+		}
+	}
 
 	for _, path := range options.Paths {
 		var (
@@ -316,19 +337,24 @@ func renderCRDs(options *CRDInstallOptions) ([]*apiextensionsv1.CustomResourceDe
 		}
 
 		log.V(1).Info("reading CRDs from path", "path", path)
-		crdList, err := readCRDs(filePath, files)
+		crdList, err := readCRDs(os.DirFS(filePath), files)
 		if err != nil {
 			return nil, err
 		}
+		addCRDs(crdList)
+	}
 
-		for i, crd := range crdList {
-			gvkn := GVKN{GVK: crd.GroupVersionKind(), Name: crd.GetName()}
-			if _, found := crds[gvkn]; found {
-				// Currently, we only print a log when there are duplicates. We may want to error out if that makes more sense.
-				log.Info("there are more than one CRD definitions with the same <Group, Version, Kind, Name>", "GVKN", gvkn)
+	if options.Filesystem != nil {
+		fsPaths := options.FilesystemPaths
+		if len(fsPaths) == 0 {
+			fsPaths = []string{"."}
+		}
+		for _, fsPath := range fsPaths {
+			crdList, err := renderFilesystemCRDs(options.Filesystem, fsPath, options.ErrorIfPathMissing)
+			if err != nil {
+				return nil, err
 			}
-			// We always use the CRD definition that we found last.
-			crds[gvkn] = crdList[i]
+			addCRDs(crdList)
 		}
 	}
 
@@ -340,6 +366,44 @@ func renderCRDs(options *CRDInstallOptions) ([]*apiextensionsv1.CustomResourceDe
 	return res, nil
 }
 
+// renderFilesystemCRDs extracts all CRD files of the directory or file at fsPath in fsys.
+func renderFilesystemCRDs(fsys fs.FS, fsPath string, errorIfPathMissing bool) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	info, err := fs.Stat(fsys, fsPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		if errorIfPathMissing {
+			return nil, err
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		files []string
+		dir   = fsPath
+	)
+	if !info.IsDir() {
+		dir, files = path.Dir(fsPath), []string{info.Name()}
+	} else {
+		entries, err := fs.ReadDir(fsys, fsPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			files = append(files, e.Name())
+		}
+	}
+
+	subFS, err := fs.Sub(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	log.V(1).Info("reading CRDs from filesystem path", "path", fsPath)
+	return readCRDs(subFS, files)
+}
+
 // modifyConversionWebhooks takes all the registered CustomResourceDefinitions and applies modifications
 // to conditionally enable webhooks if the type is registered within the scheme.
 func modifyConversionWebhooks(crds []*apiextensionsv1.CustomResourceDefinition, scheme *runtime.Scheme, webhookOptions WebhookInstallOptions) error {
@@ -402,8 +466,8 @@ func modifyConversionWebhooks(crds []*apiextensionsv1.CustomResourceDefinition,
 	return nil
 }
 
-// readCRDs reads the CRDs from files and Unmarshals them into structs.
-func readCRDs(basePath string, files []string) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+// readCRDs reads the CRDs from files in fsys and Unmarshals them into structs.
+func readCRDs(fsys fs.FS, files []string) ([]*apiextensionsv1.CustomResourceDefinition, error) {
 	var crds []*apiextensionsv1.CustomResourceDefinition
 
 	// White list the file extensions that may contain CRDs
@@ -416,7 +480,7 @@ func readCRDs(basePath string, files []string) ([]*apiextensionsv1.CustomResourc
 		}
 
 		// Unmarshal CRDs from file into structs
-		docs, err := readDocuments(filepath.Join(basePath, file))
+		docs, err := readDocuments(fsys, file)
 		if err != nil {
 			return nil, err
 		}
@@ -438,9 +502,9 @@ func readCRDs(basePath string, files []string) ([]*apiextensionsv1.CustomResourc
 	return crds, nil
 }
 
-// readDocuments reads documents from file.
-func readDocuments(fp string) ([][]byte, error) {
-	b, err := os.ReadFile(fp)
+// readDocuments reads documents from file in fsys.
+func readDocuments(fsys fs.FS, fp string) ([][]byte, error) {
+	b, err := fs.ReadFile(fsys, fp)
 	if err != nil {
 		return nil, err
 	}
@@ -17,11 +17,16 @@ limitations under the License.
 package envtest
 
 import (
+	"embed"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
+//go:embed testdata/crdfs
+var crdFS embed.FS
+
 var _ = Describe("Test", func() {
 	Describe("readCRDFiles", func() {
 		It("should not mix up files from different directories", func() {
@@ -47,5 +52,47 @@ var _ = Describe("Test", func() {
 
 			Expect(expectedCRDs).To(Equal(foundCRDs))
 		})
+
+		It("should read YAML and JSON files from a filesystem", func() {
+			opt := CRDInstallOptions{
+				Filesystem:      crdFS,
+				FilesystemPaths: []string{"testdata/crdfs"},
+			}
+			err := readCRDFiles(&opt)
+			Expect(err).NotTo(HaveOccurred())
+
+			foundCRDs := sets.NewString()
+			for _, crd := range opt.CRDs {
+				foundCRDs.Insert(crd.Name)
+			}
+
+			Expect(foundCRDs).To(Equal(sets.NewString(
+				"widgets.embed.example.com",
+				"gadgets.embed.example.com",
+			)))
+		})
+
+		It("should read a single file from a filesystem", func() {
+			opt := CRDInstallOptions{
+				Filesystem:      crdFS,
+				FilesystemPaths: []string{"testdata/crdfs/gadgets.json"},
+			}
+			err := readCRDFiles(&opt)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(opt.CRDs).To(HaveLen(1))
+			Expect(opt.CRDs[0].Name).To(Equal("gadgets.embed.example.com"))
+		})
+
+		It("should only return an error for missing filesystem paths if ErrorIfPathMissing is set", func() {
+			opt := CRDInstallOptions{
+				Filesystem:      crdFS,
+				FilesystemPaths: []string{"testdata/missing"},
+			}
+			Expect(readCRDFiles(&opt)).To(Succeed())
+			Expect(opt.CRDs).To(BeEmpty())
+
+			opt.ErrorIfPathMissing = true
+			Expect(readCRDFiles(&opt)).NotTo(Succeed())
+		})
 	})
 })
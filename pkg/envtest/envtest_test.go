@@ -331,6 +331,22 @@ var _ = Describe("Test", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("should install the CRDs into the cluster using an embedded filesystem", func() {
+			crds, err = InstallCRDs(env.Config, CRDInstallOptions{
+				Filesystem:      crdFS,
+				FilesystemPaths: []string{"testdata/crdfs"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(crds).To(HaveLen(2))
+
+			for _, name := range []string{"widgets.embed.example.com", "gadgets.embed.example.com"} {
+				crd := &apiextensionsv1.CustomResourceDefinition{}
+				err = c.Get(context.TODO(), types.NamespacedName{Name: name}, crd)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(crd.Spec.Group).To(Equal("embed.example.com"))
+			}
+		})
+
 		It("should not return an not error if the directory doesn't exist", func() {
 			crds, err = InstallCRDs(env.Config, CRDInstallOptions{Paths: []string{invalidDirectory}})
 			Expect(err).NotTo(HaveOccurred())
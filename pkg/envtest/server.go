@@ -19,6 +19,7 @@ package envtest
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"os"
 	"strings"
 	"time"
@@ -147,6 +148,15 @@ type Environment struct {
 	// values are merged.
 	CRDDirectoryPaths []string
 
+	// CRDFilesystem is a filesystem containing CRD yaml or json configs, e.g. an
+	// embed.FS. If set, it overrides the Filesystem field in CRDInstallOptions.
+	CRDFilesystem fs.FS
+
+	// CRDFilesystemPaths is a list of paths in CRDFilesystem containing CRD yaml
+	// or json configs. If both this field and FilesystemPaths field in
+	// CRDInstallOptions are specified, the values are merged.
+	CRDFilesystemPaths []string
+
 	// BinaryAssetsDirectory is the path where the binaries required for the envtest are
 	// located in the local environment. This field can be overridden by setting KUBEBUILDER_ASSETS.
 	BinaryAssetsDirectory string
@@ -288,6 +298,10 @@ func (te *Environment) Start() (*rest.Config, error) {
 	}
 	te.CRDInstallOptions.CRDs = mergeCRDs(te.CRDInstallOptions.CRDs, te.CRDs)
 	te.CRDInstallOptions.Paths = mergePaths(te.CRDInstallOptions.Paths, te.CRDDirectoryPaths)
+	if te.CRDFilesystem != nil {
+		te.CRDInstallOptions.Filesystem = te.CRDFilesystem
+	}
+	te.CRDInstallOptions.FilesystemPaths = mergePaths(te.CRDInstallOptions.FilesystemPaths, te.CRDFilesystemPaths)
 	te.CRDInstallOptions.ErrorIfPathMissing = te.ErrorIfCRDPathMissing
 	te.CRDInstallOptions.WebhookOptions = te.WebhookInstallOptions
 	crds, err := InstallCRDs(te.Config, te.CRDInstallOptions)
@@ -407,7 +407,7 @@ func readWebhooks(path string) ([]*admissionv1.MutatingWebhookConfiguration, []*
 		}
 
 		// Unmarshal Webhooks from file into structs
-		docs, err := readDocuments(filepath.Join(path, file))
+		docs, err := readDocuments(os.DirFS(path), file)
 		if err != nil {
 			return nil, nil, err
 		}
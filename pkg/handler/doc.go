@@ -31,8 +31,15 @@ EnqueueRequestForOwner - Enqueues a reconcile.Request containing the Name and Na
 This will cause owner of the object that was the source of the Event (e.g. the owner object that created the object)
 to be reconciled.
 
+EnqueueRequestForTopOwner - Enqueues a reconcile.Request containing the Name and Namespace of the Owner of the given type
+found by following the controller owners of the object in the Event.  This will cause e.g. the Deployment owning the
+ReplicaSet which owns the Pod that was the source of the Event to be reconciled.
+
 EnqueueRequestsFromMapFunc - Enqueues reconcile.Requests resulting from a user provided transformation function run against the
 object in the Event.  This will cause an arbitrary collection of objects (defined from a transformation of the
 source object) to be reconciled.
+
+EnqueueRequestsFromIndex - Enqueues reconcile.Requests for the objects found with a field index to reference the object in
+the Event.  This will cause e.g. the Pods mounting the Secret that was the source of the Event to be reconciled.
 */
 package handler
@@ -52,25 +52,25 @@ func (e *TypedEnqueueRequestForObject[T]) Create(ctx context.Context, evt event.
 		enqueueLog.Error(nil, "CreateEvent received with no metadata", "event", evt)
 		return
 	}
-	q.Add(reconcile.Request{NamespacedName: types.NamespacedName{
+	add(q, reconcile.Request{NamespacedName: types.NamespacedName{
 		Name:      evt.Object.GetName(),
 		Namespace: evt.Object.GetNamespace(),
-	}})
+	}}, evt)
 }
 
 // Update implements EventHandler.
 func (e *TypedEnqueueRequestForObject[T]) Update(ctx context.Context, evt event.TypedUpdateEvent[T], q workqueue.RateLimitingInterface) {
 	switch {
 	case !isNil(evt.ObjectNew):
-		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{
+		add(q, reconcile.Request{NamespacedName: types.NamespacedName{
 			Name:      evt.ObjectNew.GetName(),
 			Namespace: evt.ObjectNew.GetNamespace(),
-		}})
+		}}, evt)
 	case !isNil(evt.ObjectOld):
-		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{
+		add(q, reconcile.Request{NamespacedName: types.NamespacedName{
 			Name:      evt.ObjectOld.GetName(),
 			Namespace: evt.ObjectOld.GetNamespace(),
-		}})
+		}}, evt)
 	default:
 		enqueueLog.Error(nil, "UpdateEvent received with no metadata", "event", evt)
 	}
@@ -82,10 +82,10 @@ func (e *TypedEnqueueRequestForObject[T]) Delete(ctx context.Context, evt event.
 		enqueueLog.Error(nil, "DeleteEvent received with no metadata", "event", evt)
 		return
 	}
-	q.Add(reconcile.Request{NamespacedName: types.NamespacedName{
+	add(q, reconcile.Request{NamespacedName: types.NamespacedName{
 		Name:      evt.Object.GetName(),
 		Namespace: evt.Object.GetNamespace(),
-	}})
+	}}, evt)
 }
 
 // Generic implements EventHandler.
@@ -94,10 +94,41 @@ func (e *TypedEnqueueRequestForObject[T]) Generic(ctx context.Context, evt event
 		enqueueLog.Error(nil, "GenericEvent received with no metadata", "event", evt)
 		return
 	}
-	q.Add(reconcile.Request{NamespacedName: types.NamespacedName{
+	add(q, reconcile.Request{NamespacedName: types.NamespacedName{
 		Name:      evt.Object.GetName(),
 		Namespace: evt.Object.GetNamespace(),
-	}})
+	}}, evt)
+}
+
+// triggeringEventRecorder is implemented by the queues of controllers that record
+// the events that triggered a Request, see reconcile.TriggeringEvent.
+type triggeringEventRecorder interface {
+	AddWithTriggeringEvent(item interface{}, evt any)
+}
+
+// add adds req to q, along with the event that triggered it if q records triggering events.
+func add(q workqueue.RateLimitingInterface, req interface{}, evt any) {
+	if recorder, ok := q.(triggeringEventRecorder); ok {
+		recorder.AddWithTriggeringEvent(req, evt)
+		return
+	}
+	q.Add(req)
+}
+
+// changedChildRecorder is implemented by the queues of controllers that record the owned
+// objects that enqueued a Request, see reconcile.ChangedChildren.
+type changedChildRecorder interface {
+	AddWithChangedChild(item interface{}, child reconcile.ChildKey, evt any)
+}
+
+// addForChild adds req to q like add, along with the child whose event triggered it if q
+// records changed children.
+func addForChild(q workqueue.RateLimitingInterface, req interface{}, child reconcile.ChildKey, evt any) {
+	if recorder, ok := q.(changedChildRecorder); ok {
+		recorder.AddWithChangedChild(req, child, evt)
+		return
+	}
+	add(q, req, evt)
 }
 
 func isNil(arg any) bool {
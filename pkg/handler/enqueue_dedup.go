@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// EnqueueDedup enqueues Requests like EnqueueRequestsFromMapFunc, but holds every Request for the
+// given window before adding it to the queue. Identical Requests mapped during that window, from
+// any event and any source the handler is used with, are coalesced into a single enqueue. The
+// most recent of the coalesced events is recorded as its reconcile.TriggeringEvent.
+//
+// Unlike the deduplication of the workqueue, which only merges Requests that are waiting in the
+// queue, this merges bursts of events that would otherwise be reconciled one after the other. To
+// coalesce Requests across sources, pass the same handler to every watch. Requests for different
+// controllers the handler is shared with are coalesced separately.
+func EnqueueDedup(fn MapFunc, window time.Duration) EventHandler {
+	return TypedEnqueueDedup(fn, window)
+}
+
+// TypedEnqueueDedup enqueues Requests like TypedEnqueueRequestsFromMapFunc, but holds every Request
+// for the given window before adding it to the queue. Identical Requests mapped during that window,
+// from any event and any source the handler is used with, are coalesced into a single enqueue.
+// The most recent of the coalesced events is recorded as its reconcile.TriggeringEvent.
+//
+// Unlike the deduplication of the workqueue, which only merges Requests that are waiting in the
+// queue, this merges bursts of events that would otherwise be reconciled one after the other. To
+// coalesce Requests across sources, pass the same handler to every watch.
+//
+// TypedEnqueueDedup is experimental and subject to future change.
+func TypedEnqueueDedup[T any](fn TypedMapFunc[T], window time.Duration) TypedEventHandler[T] {
+	return &enqueueDedup[T]{
+		toRequests: fn,
+		window:     window,
+		pending:    map[dedupKey]any{},
+	}
+}
+
+var _ EventHandler = &enqueueDedup[client.Object]{}
+
+type enqueueDedup[T any] struct {
+	// toRequests transforms the argument into a slice of keys to be reconciled
+	toRequests TypedMapFunc[T]
+	window     time.Duration
+
+	mu sync.Mutex
+	// pending are the Requests waiting for the window to pass before being added to their queue,
+	// along with the most recent event that mapped to them.
+	pending map[dedupKey]any
+}
+
+// dedupKey identifies a pending Request. It includes the queue, so that a handler shared by several
+// controllers coalesces the Requests of each controller separately.
+type dedupKey struct {
+	q   workqueue.RateLimitingInterface
+	req reconcile.Request
+}
+
+// Create implements EventHandler.
+func (e *enqueueDedup[T]) Create(ctx context.Context, evt event.TypedCreateEvent[T], q workqueue.RateLimitingInterface) {
+	e.mapAndEnqueue(ctx, q, evt.Object, evt)
+}
+
+// Update implements EventHandler.
+func (e *enqueueDedup[T]) Update(ctx context.Context, evt event.TypedUpdateEvent[T], q workqueue.RateLimitingInterface) {
+	e.mapAndEnqueue(ctx, q, evt.ObjectOld, evt)
+	e.mapAndEnqueue(ctx, q, evt.ObjectNew, evt)
+}
+
+// Delete implements EventHandler.
+func (e *enqueueDedup[T]) Delete(ctx context.Context, evt event.TypedDeleteEvent[T], q workqueue.RateLimitingInterface) {
+	e.mapAndEnqueue(ctx, q, evt.Object, evt)
+}
+
+// Generic implements EventHandler.
+func (e *enqueueDedup[T]) Generic(ctx context.Context, evt event.TypedGenericEvent[T], q workqueue.RateLimitingInterface) {
+	e.mapAndEnqueue(ctx, q, evt.Object, evt)
+}
+
+func (e *enqueueDedup[T]) mapAndEnqueue(ctx context.Context, q workqueue.RateLimitingInterface, object T, evt any) {
+	reqs := e.toRequests(ctx, object)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, req := range reqs {
+		key := dedupKey{q: q, req: req}
+		_, ok := e.pending[key]
+		e.pending[key] = evt
+		if ok {
+			continue
+		}
+
+		time.AfterFunc(e.window, func() {
+			e.mu.Lock()
+			latest := e.pending[key]
+			delete(e.pending, key)
+			e.mu.Unlock()
+			add(q, key.req, latest)
+		})
+	}
+}
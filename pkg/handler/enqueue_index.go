@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var indexLog = logf.RuntimeLog.WithName("eventhandler").WithName("enqueueRequestsFromIndex")
+
+// EnqueueRequestsFromIndex enqueues Requests for the objects that reference the object of an Event,
+// as found with a field index, e.g. for the Pods that mount a Secret when the Secret changes.
+//
+// On each Event, the objects of the type of list are listed with reader, typically the cache of the
+// manager, for those whose indexField matches the value extract returns for the object of the Event.
+// The index must have been registered with the FieldIndexer of the cache, e.g.:
+//
+//	mgr.GetFieldIndexer().IndexField(ctx, &corev1.Pod{}, "spec.secretNames", func(o client.Object) []string {
+//		... // the names of the Secrets the Pod mounts
+//	})
+//	handler.EnqueueRequestsFromIndex(mgr.GetCache(), &corev1.PodList{}, "spec.secretNames",
+//		func(o client.Object) string { return o.GetName() })
+//
+// If the object of the Event is namespaced, only the referencing objects in its namespace are listed.
+// Nothing is enqueued if extract returns an empty string. For UpdateEvents, the referencing objects of
+// both the old and the new object are enqueued.
+func EnqueueRequestsFromIndex(reader client.Reader, list client.ObjectList, indexField string, extract func(o client.Object) string) EventHandler {
+	return TypedEnqueueRequestsFromIndex(reader, list, indexField, extract)
+}
+
+// TypedEnqueueRequestsFromIndex enqueues Requests for the objects that reference the object of an Event,
+// as found with a field index, e.g. for the Pods that mount a Secret when the Secret changes.
+// See EnqueueRequestsFromIndex for the details.
+//
+// TypedEnqueueRequestsFromIndex is experimental and subject to future change.
+func TypedEnqueueRequestsFromIndex[T client.Object](reader client.Reader, list client.ObjectList, indexField string, extract func(o T) string) TypedEventHandler[T] {
+	return TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, o T) []reconcile.Request {
+		if isNil(o) {
+			return nil
+		}
+		value := extract(o)
+		if value == "" {
+			return nil
+		}
+
+		referencing := list.DeepCopyObject().(client.ObjectList)
+		opts := []client.ListOption{client.MatchingFields{indexField: value}}
+		if o.GetNamespace() != "" {
+			opts = append(opts, client.InNamespace(o.GetNamespace()))
+		}
+		if err := reader.List(ctx, referencing, opts...); err != nil {
+			indexLog.Error(err, "Could not list referencing objects", "index", indexField, "value", value)
+			return nil
+		}
+
+		var reqs []reconcile.Request
+		if err := meta.EachListItem(referencing, func(item runtime.Object) error {
+			if obj, ok := item.(client.Object); ok {
+				reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+			}
+			return nil
+		}); err != nil {
+			indexLog.Error(err, "Could not extract referencing objects", "index", indexField, "value", value)
+			return nil
+		}
+		return reqs
+	})
+}
@@ -35,6 +35,12 @@ type MapFunc = TypedMapFunc[client.Object]
 // TypedMapFunc is experimental and subject to future change.
 type TypedMapFunc[T any] func(context.Context, T) []reconcile.Request
 
+// TypedRequestMapFunc is the signature required for enqueueing reconcile.TypedRequests from a generic function.
+// This type is usually used with EnqueueTypedRequestsFromMapFunc when registering an event handler.
+//
+// TypedRequestMapFunc is experimental and subject to future change.
+type TypedRequestMapFunc[T any, K comparable] func(context.Context, T) []reconcile.TypedRequest[K]
+
 // EnqueueRequestsFromMapFunc enqueues Requests by running a transformation function that outputs a collection
 // of reconcile.Requests on each Event.  The reconcile.Requests may be for an arbitrary set of objects
 // defined by some user specified transformation of the source Event.  (e.g. trigger Reconciler for a set of objects
@@ -62,48 +68,63 @@ func EnqueueRequestsFromMapFunc(fn MapFunc) EventHandler {
 //
 // TypedEnqueueRequestsFromMapFunc is experimental and subject to future change.
 func TypedEnqueueRequestsFromMapFunc[T any](fn TypedMapFunc[T]) TypedEventHandler[T] {
-	return &enqueueRequestsFromMapFunc[T]{
+	return &enqueueRequestsFromMapFunc[T, reconcile.Request]{
+		toRequests: fn,
+	}
+}
+
+// EnqueueTypedRequestsFromMapFunc enqueues reconcile.TypedRequests by running a transformation function
+// that outputs a collection of reconcile.TypedRequests on each Event. It is the counterpart of
+// TypedEnqueueRequestsFromMapFunc for controllers created with controller.NewTyped, which reconcile
+// keys of type K rather than the name and namespace of an object.
+//
+// For TypedUpdateEvents which contain both a new and old object, the transformation function is run on both
+// objects and both sets of TypedRequests are enqueued.
+//
+// EnqueueTypedRequestsFromMapFunc is experimental and subject to future change.
+func EnqueueTypedRequestsFromMapFunc[T any, K comparable](fn TypedRequestMapFunc[T, K]) TypedEventHandler[T] {
+	return &enqueueRequestsFromMapFunc[T, reconcile.TypedRequest[K]]{
 		toRequests: fn,
 	}
 }
 
-var _ EventHandler = &enqueueRequestsFromMapFunc[client.Object]{}
+var _ EventHandler = &enqueueRequestsFromMapFunc[client.Object, reconcile.Request]{}
 
-type enqueueRequestsFromMapFunc[T any] struct {
+type enqueueRequestsFromMapFunc[T any, request comparable] struct {
 	// Mapper transforms the argument into a slice of keys to be reconciled
-	toRequests TypedMapFunc[T]
+	toRequests func(context.Context, T) []request
 }
 
 // Create implements EventHandler.
-func (e *enqueueRequestsFromMapFunc[T]) Create(ctx context.Context, evt event.TypedCreateEvent[T], q workqueue.RateLimitingInterface) {
-	reqs := map[reconcile.Request]empty{}
-	e.mapAndEnqueue(ctx, q, evt.Object, reqs)
+func (e *enqueueRequestsFromMapFunc[T, request]) Create(ctx context.Context, evt event.TypedCreateEvent[T], q workqueue.RateLimitingInterface) {
+	reqs := map[request]empty{}
+	e.mapAndEnqueue(ctx, q, evt.Object, reqs, evt)
 }
 
 // Update implements EventHandler.
-func (e *enqueueRequestsFromMapFunc[T]) Update(ctx context.Context, evt event.TypedUpdateEvent[T], q workqueue.RateLimitingInterface) {
-	reqs := map[reconcile.Request]empty{}
-	e.mapAndEnqueue(ctx, q, evt.ObjectOld, reqs)
-	e.mapAndEnqueue(ctx, q, evt.ObjectNew, reqs)
+func (e *enqueueRequestsFromMapFunc[T, request]) Update(ctx context.Context, evt event.TypedUpdateEvent[T], q workqueue.RateLimitingInterface) {
+	reqs := map[request]empty{}
+	e.mapAndEnqueue(ctx, q, evt.ObjectOld, reqs, evt)
+	e.mapAndEnqueue(ctx, q, evt.ObjectNew, reqs, evt)
 }
 
 // Delete implements EventHandler.
-func (e *enqueueRequestsFromMapFunc[T]) Delete(ctx context.Context, evt event.TypedDeleteEvent[T], q workqueue.RateLimitingInterface) {
-	reqs := map[reconcile.Request]empty{}
-	e.mapAndEnqueue(ctx, q, evt.Object, reqs)
+func (e *enqueueRequestsFromMapFunc[T, request]) Delete(ctx context.Context, evt event.TypedDeleteEvent[T], q workqueue.RateLimitingInterface) {
+	reqs := map[request]empty{}
+	e.mapAndEnqueue(ctx, q, evt.Object, reqs, evt)
 }
 
 // Generic implements EventHandler.
-func (e *enqueueRequestsFromMapFunc[T]) Generic(ctx context.Context, evt event.TypedGenericEvent[T], q workqueue.RateLimitingInterface) {
-	reqs := map[reconcile.Request]empty{}
-	e.mapAndEnqueue(ctx, q, evt.Object, reqs)
+func (e *enqueueRequestsFromMapFunc[T, request]) Generic(ctx context.Context, evt event.TypedGenericEvent[T], q workqueue.RateLimitingInterface) {
+	reqs := map[request]empty{}
+	e.mapAndEnqueue(ctx, q, evt.Object, reqs, evt)
 }
 
-func (e *enqueueRequestsFromMapFunc[T]) mapAndEnqueue(ctx context.Context, q workqueue.RateLimitingInterface, object T, reqs map[reconcile.Request]empty) {
+func (e *enqueueRequestsFromMapFunc[T, request]) mapAndEnqueue(ctx context.Context, q workqueue.RateLimitingInterface, object T, reqs map[request]empty, evt any) {
 	for _, req := range e.toRequests(ctx, object) {
 		_, ok := reqs[req]
 		if !ok {
-			q.Add(req)
+			add(q, req, evt)
 			reqs[req] = empty{}
 		}
 	}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -65,6 +66,7 @@ func TypedEnqueueRequestForOwner[T client.Object](scheme *runtime.Scheme, mapper
 	e := &enqueueRequestForOwner[T]{
 		ownerType: ownerType,
 		mapper:    mapper,
+		scheme:    scheme,
 	}
 	if err := e.parseOwnerTypeGroupKind(scheme); err != nil {
 		panic(err)
@@ -98,6 +100,9 @@ type enqueueRequestForOwner[T client.Object] struct {
 
 	// mapper maps GroupVersionKinds to Resources
 	mapper meta.RESTMapper
+
+	// scheme is used to determine the GroupKind of owned objects
+	scheme *runtime.Scheme
 }
 
 func (e *enqueueRequestForOwner[T]) setIsController(isController bool) {
@@ -108,8 +113,9 @@ func (e *enqueueRequestForOwner[T]) setIsController(isController bool) {
 func (e *enqueueRequestForOwner[T]) Create(ctx context.Context, evt event.TypedCreateEvent[T], q workqueue.RateLimitingInterface) {
 	reqs := map[reconcile.Request]empty{}
 	e.getOwnerReconcileRequest(evt.Object, reqs)
+	child := e.childKey(evt.Object)
 	for req := range reqs {
-		q.Add(req)
+		addForChild(q, req, child, evt)
 	}
 }
 
@@ -118,8 +124,12 @@ func (e *enqueueRequestForOwner[T]) Update(ctx context.Context, evt event.TypedU
 	reqs := map[reconcile.Request]empty{}
 	e.getOwnerReconcileRequest(evt.ObjectOld, reqs)
 	e.getOwnerReconcileRequest(evt.ObjectNew, reqs)
+	child := e.childKey(evt.ObjectNew)
+	if isNil(evt.ObjectNew) {
+		child = e.childKey(evt.ObjectOld)
+	}
 	for req := range reqs {
-		q.Add(req)
+		addForChild(q, req, child, evt)
 	}
 }
 
@@ -127,8 +137,9 @@ func (e *enqueueRequestForOwner[T]) Update(ctx context.Context, evt event.TypedU
 func (e *enqueueRequestForOwner[T]) Delete(ctx context.Context, evt event.TypedDeleteEvent[T], q workqueue.RateLimitingInterface) {
 	reqs := map[reconcile.Request]empty{}
 	e.getOwnerReconcileRequest(evt.Object, reqs)
+	child := e.childKey(evt.Object)
 	for req := range reqs {
-		q.Add(req)
+		addForChild(q, req, child, evt)
 	}
 }
 
@@ -136,9 +147,23 @@ func (e *enqueueRequestForOwner[T]) Delete(ctx context.Context, evt event.TypedD
 func (e *enqueueRequestForOwner[T]) Generic(ctx context.Context, evt event.TypedGenericEvent[T], q workqueue.RateLimitingInterface) {
 	reqs := map[reconcile.Request]empty{}
 	e.getOwnerReconcileRequest(evt.Object, reqs)
+	child := e.childKey(evt.Object)
 	for req := range reqs {
-		q.Add(req)
+		addForChild(q, req, child, evt)
+	}
+}
+
+// childKey returns the key of the owned object obj for reconcile.ChangedChildren. The
+// GroupKind is left empty if it can't be determined from the scheme.
+func (e *enqueueRequestForOwner[T]) childKey(obj T) reconcile.ChildKey {
+	if isNil(obj) {
+		return reconcile.ChildKey{}
+	}
+	key := reconcile.ChildKey{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}}
+	if gvk, err := apiutil.GVKForObject(obj, e.scheme); err == nil {
+		key.GroupKind = gvk.GroupKind()
 	}
+	return key
 }
 
 // parseOwnerTypeGroupKind parses the OwnerType into a Group and Kind and caches the result.  Returns false
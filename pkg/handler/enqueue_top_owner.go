@@ -0,0 +1,210 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ EventHandler = &enqueueRequestForTopOwner[client.Object]{}
+
+var topOwnerLog = logf.RuntimeLog.WithName("eventhandler").WithName("enqueueRequestForTopOwner")
+
+// EnqueueRequestForTopOwner enqueues Requests for the owner of an object that is of the given type,
+// following the chain of controller owners up from the object that was the source of the Event.
+//
+// If a Deployment creates ReplicaSets which create Pods, users may reconcile the Deployment in response
+// to Pod Events using:
+//
+// - a source.Kind Source with Type of Pod.
+//
+// - a handler.EnqueueRequestForTopOwner EventHandler with an OwnerType of Deployment.
+//
+// The intermediate owners are read with reader, e.g. the client of the manager, as metav1.PartialObjectMetadata.
+// Nothing is enqueued if the chain ends before an owner of the given type, e.g. because an owner is missing,
+// or if it loops back onto itself.
+func EnqueueRequestForTopOwner(reader client.Reader, scheme *runtime.Scheme, mapper meta.RESTMapper, ownerType client.Object) EventHandler {
+	return TypedEnqueueRequestForTopOwner[client.Object](reader, scheme, mapper, ownerType)
+}
+
+// TypedEnqueueRequestForTopOwner enqueues Requests for the owner of an object that is of the given type,
+// following the chain of controller owners up from the object that was the source of the Event.
+//
+// If a Deployment creates ReplicaSets which create Pods, users may reconcile the Deployment in response
+// to Pod Events using:
+//
+// - a source.Kind Source with Type of Pod.
+//
+// - a handler.TypedEnqueueRequestForTopOwner EventHandler with an OwnerType of Deployment.
+//
+// The intermediate owners are read with reader, e.g. the client of the manager, as metav1.PartialObjectMetadata.
+// Nothing is enqueued if the chain ends before an owner of the given type, e.g. because an owner is missing,
+// or if it loops back onto itself.
+//
+// TypedEnqueueRequestForTopOwner is experimental and subject to future change.
+func TypedEnqueueRequestForTopOwner[T client.Object](reader client.Reader, scheme *runtime.Scheme, mapper meta.RESTMapper, ownerType client.Object) TypedEventHandler[T] {
+	e := &enqueueRequestForTopOwner[T]{
+		enqueueRequestForOwner: enqueueRequestForOwner[T]{
+			ownerType:    ownerType,
+			isController: true,
+			mapper:       mapper,
+			scheme:       scheme,
+		},
+		reader: reader,
+	}
+	if err := e.parseOwnerTypeGroupKind(scheme); err != nil {
+		panic(err)
+	}
+	return e
+}
+
+type enqueueRequestForTopOwner[T client.Object] struct {
+	enqueueRequestForOwner[T]
+
+	// reader is used to get the intermediate owners.
+	reader client.Reader
+}
+
+// Create implements EventHandler.
+func (e *enqueueRequestForTopOwner[T]) Create(ctx context.Context, evt event.TypedCreateEvent[T], q workqueue.RateLimitingInterface) {
+	reqs := map[reconcile.Request]empty{}
+	e.getTopOwnerReconcileRequest(ctx, evt.Object, reqs)
+	child := e.childKey(evt.Object)
+	for req := range reqs {
+		addForChild(q, req, child, evt)
+	}
+}
+
+// Update implements EventHandler.
+func (e *enqueueRequestForTopOwner[T]) Update(ctx context.Context, evt event.TypedUpdateEvent[T], q workqueue.RateLimitingInterface) {
+	reqs := map[reconcile.Request]empty{}
+	e.getTopOwnerReconcileRequest(ctx, evt.ObjectOld, reqs)
+	e.getTopOwnerReconcileRequest(ctx, evt.ObjectNew, reqs)
+	child := e.childKey(evt.ObjectNew)
+	if isNil(evt.ObjectNew) {
+		child = e.childKey(evt.ObjectOld)
+	}
+	for req := range reqs {
+		addForChild(q, req, child, evt)
+	}
+}
+
+// Delete implements EventHandler.
+func (e *enqueueRequestForTopOwner[T]) Delete(ctx context.Context, evt event.TypedDeleteEvent[T], q workqueue.RateLimitingInterface) {
+	reqs := map[reconcile.Request]empty{}
+	e.getTopOwnerReconcileRequest(ctx, evt.Object, reqs)
+	child := e.childKey(evt.Object)
+	for req := range reqs {
+		addForChild(q, req, child, evt)
+	}
+}
+
+// Generic implements EventHandler.
+func (e *enqueueRequestForTopOwner[T]) Generic(ctx context.Context, evt event.TypedGenericEvent[T], q workqueue.RateLimitingInterface) {
+	reqs := map[reconcile.Request]empty{}
+	e.getTopOwnerReconcileRequest(ctx, evt.Object, reqs)
+	child := e.childKey(evt.Object)
+	for req := range reqs {
+		addForChild(q, req, child, evt)
+	}
+}
+
+// ownerKey identifies an object of the owner chain.
+type ownerKey struct {
+	schema.GroupKind
+	types.NamespacedName
+}
+
+// getTopOwnerReconcileRequest follows the controller references of object until it finds an owner
+// matching e.OwnerType, and adds a reconcile.Request for it to result.
+func (e *enqueueRequestForTopOwner[T]) getTopOwnerReconcileRequest(ctx context.Context, object T, result map[reconcile.Request]empty) {
+	if isNil(object) {
+		return
+	}
+
+	var current metav1.Object = object
+	visited := map[ownerKey]empty{}
+	if gvk, err := apiutil.GVKForObject(object, e.scheme); err == nil {
+		visited[ownerKey{GroupKind: gvk.GroupKind(), NamespacedName: client.ObjectKeyFromObject(object)}] = empty{}
+	}
+	for {
+		refs := e.getOwnersReferences(current)
+		if len(refs) == 0 {
+			return
+		}
+		ref := refs[0]
+
+		refGV, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			topOwnerLog.Error(err, "Could not parse OwnerReference APIVersion",
+				"api version", ref.APIVersion)
+			return
+		}
+		key := ownerKey{GroupKind: schema.GroupKind{Group: refGV.Group, Kind: ref.Kind}, NamespacedName: types.NamespacedName{Name: ref.Name}}
+
+		// if owner is not namespaced then we should not set the namespace
+		mapping, err := e.mapper.RESTMapping(key.GroupKind, refGV.Version)
+		if err != nil {
+			topOwnerLog.Error(err, "Could not retrieve rest mapping", "kind", key.GroupKind)
+			return
+		}
+		if mapping.Scope.Name() != meta.RESTScopeNameRoot {
+			key.Namespace = current.GetNamespace()
+		}
+
+		if key.GroupKind == e.groupKind {
+			result[reconcile.Request{NamespacedName: key.NamespacedName}] = empty{}
+			return
+		}
+
+		if _, found := visited[key]; found {
+			topOwnerLog.Error(nil, "Owner chain contains a cycle", "kind", key.GroupKind, "owner", key.NamespacedName)
+			return
+		}
+		visited[key] = empty{}
+
+		owner := &metav1.PartialObjectMetadata{}
+		owner.SetGroupVersionKind(refGV.WithKind(ref.Kind))
+		if err := e.reader.Get(ctx, key.NamespacedName, owner); err != nil {
+			if apierrors.IsNotFound(err) {
+				topOwnerLog.V(1).Info("Owner not found", "kind", key.GroupKind, "owner", key.NamespacedName)
+				return
+			}
+			topOwnerLog.Error(err, "Could not get owner", "kind", key.GroupKind, "owner", key.NamespacedName)
+			return
+		}
+		if ref.UID != "" && owner.GetUID() != ref.UID {
+			// The owner was recreated, the object is orphaned.
+			topOwnerLog.V(1).Info("Owner not found", "kind", key.GroupKind, "owner", key.NamespacedName, "uid", ref.UID)
+			return
+		}
+		current = owner
+	}
+}
@@ -18,6 +18,8 @@ package handler_test
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -33,6 +35,7 @@ import (
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -306,6 +309,127 @@ var _ = Describe("Eventhandler", func() {
 		})
 	})
 
+	Describe("EnqueueTypedRequestsFromMapFunc", func() {
+		type externalID struct {
+			Provider string
+			ID       int
+		}
+
+		It("should enqueue TypedRequests with a struct key for the UpdateEvent, de-duplicating them.", func() {
+			instance := handler.EnqueueTypedRequestsFromMapFunc(func(ctx context.Context, pod *corev1.Pod) []reconcile.TypedRequest[externalID] {
+				return []reconcile.TypedRequest[externalID]{
+					{Key: externalID{Provider: "acme", ID: 1}},
+					{Key: externalID{Provider: pod.Labels["provider"], ID: 2}},
+				}
+			})
+
+			newPod := pod.DeepCopy()
+			newPod.Labels = map[string]string{"provider": "acme"}
+			pod.Labels = map[string]string{"provider": "other"}
+			instance.Update(ctx, event.TypedUpdateEvent[*corev1.Pod]{ObjectOld: pod, ObjectNew: newPod}, q)
+			Expect(q.Len()).To(Equal(3))
+
+			i1, _ := q.Get()
+			i2, _ := q.Get()
+			i3, _ := q.Get()
+			Expect([]interface{}{i1, i2, i3}).To(ConsistOf(
+				reconcile.TypedRequest[externalID]{Key: externalID{Provider: "acme", ID: 1}},
+				reconcile.TypedRequest[externalID]{Key: externalID{Provider: "other", ID: 2}},
+				reconcile.TypedRequest[externalID]{Key: externalID{Provider: "acme", ID: 2}},
+			))
+		})
+	})
+
+	Describe("EnqueueDedup", func() {
+		It("should coalesce identical Requests mapped within the window into a single enqueue", func() {
+			cq := &countingQueue{RateLimitingInterface: q}
+			instance := handler.EnqueueDedup(func(ctx context.Context, a client.Object) []reconcile.Request {
+				return []reconcile.Request{
+					{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "bar"}},
+				}
+			}, 100*time.Millisecond)
+
+			for i := 0; i < 100; i++ {
+				instance.Create(ctx, event.CreateEvent{Object: pod}, cq)
+				instance.Update(ctx, event.UpdateEvent{ObjectOld: pod, ObjectNew: pod}, cq)
+				instance.Generic(ctx, event.GenericEvent{Object: pod}, cq)
+			}
+			Expect(cq.Adds()).To(Equal(0))
+
+			Eventually(cq.Adds).Should(Equal(1))
+			Consistently(cq.Adds, "200ms").Should(Equal(1))
+
+			i, _ := q.Get()
+			Expect(i).To(Equal(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "bar"}}))
+		})
+
+		It("should enqueue every distinct Request once", func() {
+			cq := &countingQueue{RateLimitingInterface: q}
+			instance := handler.EnqueueDedup(func(ctx context.Context, a client.Object) []reconcile.Request {
+				return []reconcile.Request{
+					{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "bar"}},
+					{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "baz"}},
+				}
+			}, 10*time.Millisecond)
+
+			for i := 0; i < 10; i++ {
+				instance.Create(ctx, event.CreateEvent{Object: pod}, cq)
+			}
+
+			Eventually(cq.Adds).Should(Equal(2))
+			Consistently(cq.Adds, "100ms").Should(Equal(2))
+		})
+
+		It("should enqueue a Request again once the window has passed", func() {
+			cq := &countingQueue{RateLimitingInterface: q}
+			instance := handler.EnqueueDedup(func(ctx context.Context, a client.Object) []reconcile.Request {
+				return []reconcile.Request{
+					{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "bar"}},
+				}
+			}, 10*time.Millisecond)
+
+			instance.Create(ctx, event.CreateEvent{Object: pod}, cq)
+			Eventually(cq.Adds).Should(Equal(1))
+
+			instance.Create(ctx, event.CreateEvent{Object: pod}, cq)
+			Eventually(cq.Adds).Should(Equal(2))
+		})
+
+		It("should record the most recent of the coalesced events as the triggering event", func() {
+			rq := &triggeringEventQueue{RateLimitingInterface: q}
+			instance := handler.EnqueueDedup(func(ctx context.Context, a client.Object) []reconcile.Request {
+				return []reconcile.Request{
+					{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "bar"}},
+				}
+			}, 50*time.Millisecond)
+
+			instance.Create(ctx, event.CreateEvent{Object: pod}, rq)
+			instance.Update(ctx, event.UpdateEvent{ObjectOld: pod, ObjectNew: pod}, rq)
+			instance.Delete(ctx, event.DeleteEvent{Object: pod}, rq)
+
+			Eventually(rq.Events).Should(HaveLen(1))
+			Consistently(rq.Events, "100ms").Should(HaveLen(1))
+			Expect(rq.Events()[0]).To(Equal(event.DeleteEvent{Object: pod}))
+		})
+
+		It("should coalesce the Requests of every queue it is used with separately", func() {
+			first := &countingQueue{RateLimitingInterface: q}
+			second := &countingQueue{RateLimitingInterface: workqueue.NewRateLimitingQueueWithConfig(workqueue.DefaultControllerRateLimiter(), workqueue.RateLimitingQueueConfig{})}
+			defer second.ShutDown()
+			instance := handler.EnqueueDedup(func(ctx context.Context, a client.Object) []reconcile.Request {
+				return []reconcile.Request{
+					{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "bar"}},
+				}
+			}, 10*time.Millisecond)
+
+			instance.Create(ctx, event.CreateEvent{Object: pod}, first)
+			instance.Create(ctx, event.CreateEvent{Object: pod}, second)
+
+			Eventually(first.Adds).Should(Equal(1))
+			Eventually(second.Adds).Should(Equal(1))
+		})
+	})
+
 	Describe("EnqueueRequestForOwner", func() {
 		It("should enqueue a Request with the Owner of the object in the CreateEvent.", func() {
 			instance := handler.EnqueueRequestForOwner(scheme.Scheme, mapper, &appsv1.ReplicaSet{})
@@ -675,6 +799,211 @@ var _ = Describe("Eventhandler", func() {
 		})
 	})
 
+	Describe("EnqueueRequestForTopOwner", func() {
+		controllerRef := func(apiVersion, kind, name string, uid types.UID) []metav1.OwnerReference {
+			return []metav1.OwnerReference{{APIVersion: apiVersion, Kind: kind, Name: name, UID: uid, Controller: ptr.To(true)}}
+		}
+		var deployment *appsv1.Deployment
+		var replicaSet *appsv1.ReplicaSet
+		BeforeEach(func() {
+			deployment = &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: "foo-deployment", UID: "deployment-uid"},
+			}
+			replicaSet = &appsv1.ReplicaSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       pod.Namespace,
+					Name:            "foo-replicaset",
+					UID:             "replicaset-uid",
+					OwnerReferences: controllerRef("apps/v1", "Deployment", "foo-deployment", "deployment-uid"),
+				},
+			}
+			pod.OwnerReferences = controllerRef("apps/v1", "ReplicaSet", "foo-replicaset", "replicaset-uid")
+		})
+
+		It("should enqueue a Request with the top owner of the object in the CreateEvent.", func() {
+			c := fake.NewClientBuilder().WithObjects(deployment, replicaSet).Build()
+			instance := handler.EnqueueRequestForTopOwner(c, scheme.Scheme, mapper, &appsv1.Deployment{})
+
+			instance.Create(ctx, event.CreateEvent{Object: pod}, q)
+			Expect(q.Len()).To(Equal(1))
+
+			i, _ := q.Get()
+			Expect(i).To(Equal(reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: "foo-deployment"}}))
+		})
+
+		It("should enqueue a Request with the direct owner if it is of the requested type.", func() {
+			c := fake.NewClientBuilder().Build()
+			instance := handler.EnqueueRequestForTopOwner(c, scheme.Scheme, mapper, &appsv1.ReplicaSet{})
+
+			instance.Delete(ctx, event.DeleteEvent{Object: pod}, q)
+			Expect(q.Len()).To(Equal(1))
+
+			i, _ := q.Get()
+			Expect(i).To(Equal(reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: "foo-replicaset"}}))
+		})
+
+		It("should enqueue a single Request for the top owner of both objects in the UpdateEvent.", func() {
+			c := fake.NewClientBuilder().WithObjects(deployment, replicaSet).Build()
+			instance := handler.EnqueueRequestForTopOwner(c, scheme.Scheme, mapper, &appsv1.Deployment{})
+
+			newPod := pod.DeepCopy()
+			newPod.Labels = map[string]string{"foo": "bar"}
+			instance.Update(ctx, event.UpdateEvent{ObjectOld: pod, ObjectNew: newPod}, q)
+			Expect(q.Len()).To(Equal(1))
+
+			i, _ := q.Get()
+			Expect(i).To(Equal(reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: "foo-deployment"}}))
+		})
+
+		It("should not enqueue a Request if an intermediate owner is missing.", func() {
+			c := fake.NewClientBuilder().WithObjects(deployment).Build()
+			instance := handler.EnqueueRequestForTopOwner(c, scheme.Scheme, mapper, &appsv1.Deployment{})
+
+			instance.Create(ctx, event.CreateEvent{Object: pod}, q)
+			Expect(q.Len()).To(Equal(0))
+		})
+
+		It("should not enqueue a Request if an intermediate owner was recreated.", func() {
+			replicaSet.UID = "other-uid"
+			c := fake.NewClientBuilder().WithObjects(deployment, replicaSet).Build()
+			instance := handler.EnqueueRequestForTopOwner(c, scheme.Scheme, mapper, &appsv1.Deployment{})
+
+			instance.Create(ctx, event.CreateEvent{Object: pod}, q)
+			Expect(q.Len()).To(Equal(0))
+		})
+
+		It("should not enqueue a Request if the owner chain has no owner of the requested type.", func() {
+			replicaSet.OwnerReferences = nil
+			c := fake.NewClientBuilder().WithObjects(replicaSet).Build()
+			instance := handler.EnqueueRequestForTopOwner(c, scheme.Scheme, mapper, &appsv1.Deployment{})
+
+			instance.Generic(ctx, event.GenericEvent{Object: pod}, q)
+			Expect(q.Len()).To(Equal(0))
+		})
+
+		It("should not enqueue a Request if the owner chain contains a cycle.", func() {
+			otherReplicaSet := &appsv1.ReplicaSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       pod.Namespace,
+					Name:            "bar-replicaset",
+					UID:             "other-replicaset-uid",
+					OwnerReferences: controllerRef("apps/v1", "ReplicaSet", "foo-replicaset", "replicaset-uid"),
+				},
+			}
+			replicaSet.OwnerReferences = controllerRef("apps/v1", "ReplicaSet", "bar-replicaset", "other-replicaset-uid")
+			c := fake.NewClientBuilder().WithObjects(replicaSet, otherReplicaSet).Build()
+			instance := handler.EnqueueRequestForTopOwner(c, scheme.Scheme, mapper, &appsv1.Deployment{})
+
+			instance.Create(ctx, event.CreateEvent{Object: pod}, q)
+			Expect(q.Len()).To(Equal(0))
+		})
+
+		It("should only follow the controller reference.", func() {
+			pod.OwnerReferences = append(pod.OwnerReferences, metav1.OwnerReference{
+				APIVersion: "apps/v1", Kind: "Deployment", Name: "bar-deployment",
+			})
+			c := fake.NewClientBuilder().WithObjects(deployment, replicaSet).Build()
+			instance := handler.EnqueueRequestForTopOwner(c, scheme.Scheme, mapper, &appsv1.Deployment{})
+
+			instance.Create(ctx, event.CreateEvent{Object: pod}, q)
+			Expect(q.Len()).To(Equal(1))
+
+			i, _ := q.Get()
+			Expect(i).To(Equal(reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: "foo-deployment"}}))
+		})
+	})
+
+	Describe("EnqueueRequestsFromIndex", func() {
+		const secretNameField = "spec.volumes.secret.secretName"
+		secretNames := func(o client.Object) []string {
+			var names []string
+			for _, volume := range o.(*corev1.Pod).Spec.Volumes {
+				if volume.Secret != nil {
+					names = append(names, volume.Secret.SecretName)
+				}
+			}
+			return names
+		}
+		podMountingSecret := func(namespace, name, secretName string) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+				Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+					Name:         "secret",
+					VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: secretName}},
+				}}},
+			}
+		}
+		var c client.Client
+		var secret *corev1.Secret
+		BeforeEach(func() {
+			c = fake.NewClientBuilder().
+				WithIndex(&corev1.Pod{}, secretNameField, secretNames).
+				WithObjects(
+					podMountingSecret("biz", "foo", "credentials"),
+					podMountingSecret("biz", "bar", "credentials"),
+					podMountingSecret("biz", "baz", "other"),
+					podMountingSecret("other", "foo", "credentials"),
+				).
+				Build()
+			secret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: "credentials"}}
+		})
+		secretName := func(o client.Object) string { return o.GetName() }
+
+		It("should enqueue Requests for the objects referencing the object in the UpdateEvent.", func() {
+			instance := handler.EnqueueRequestsFromIndex(c, &corev1.PodList{}, secretNameField, secretName)
+
+			newSecret := secret.DeepCopy()
+			newSecret.Data = map[string][]byte{"password": []byte("rotated")}
+			instance.Update(ctx, event.UpdateEvent{ObjectOld: secret, ObjectNew: newSecret}, q)
+			Expect(q.Len()).To(Equal(2))
+
+			var reqs []reconcile.Request
+			for q.Len() > 0 {
+				i, _ := q.Get()
+				reqs = append(reqs, i.(reconcile.Request))
+			}
+			Expect(reqs).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "foo"}},
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "bar"}},
+			))
+		})
+
+		It("should reflect the current references when the object in the Event changes.", func() {
+			instance := handler.EnqueueRequestsFromIndex(c, &corev1.PodList{}, secretNameField, secretName)
+
+			Expect(c.Delete(ctx, podMountingSecret("biz", "bar", "credentials"))).To(Succeed())
+			instance.Create(ctx, event.CreateEvent{Object: secret}, q)
+			Expect(q.Len()).To(Equal(1))
+			i, _ := q.Get()
+			Expect(i).To(Equal(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "foo"}}))
+			q.Done(i)
+
+			instance.Delete(ctx, event.DeleteEvent{Object: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: "other"}}}, q)
+			Expect(q.Len()).To(Equal(1))
+			i, _ = q.Get()
+			Expect(i).To(Equal(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "baz"}}))
+		})
+
+		It("should not enqueue a Request if no object references the object in the Event.", func() {
+			instance := handler.EnqueueRequestsFromIndex(c, &corev1.PodList{}, secretNameField, secretName)
+
+			secret.Name = "unused"
+			instance.Generic(ctx, event.GenericEvent{Object: secret}, q)
+			Expect(q.Len()).To(Equal(0))
+		})
+
+		It("should not enqueue a Request if extract returns an empty value.", func() {
+			instance := handler.EnqueueRequestsFromIndex(c, &corev1.PodList{}, secretNameField, func(client.Object) string { return "" })
+
+			instance.Create(ctx, event.CreateEvent{Object: secret}, q)
+			Expect(q.Len()).To(Equal(0))
+		})
+	})
+
 	Describe("Funcs", func() {
 		failingFuncs := handler.Funcs{
 			CreateFunc: func(context.Context, event.CreateEvent, workqueue.RateLimitingInterface) {
@@ -792,3 +1121,45 @@ var _ = Describe("Eventhandler", func() {
 		})
 	})
 })
+
+// countingQueue counts the Requests added to the underlying queue.
+type countingQueue struct {
+	workqueue.RateLimitingInterface
+
+	mu   sync.Mutex
+	adds int
+}
+
+func (q *countingQueue) Add(item interface{}) {
+	q.mu.Lock()
+	q.adds++
+	q.mu.Unlock()
+	q.RateLimitingInterface.Add(item)
+}
+
+func (q *countingQueue) Adds() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.adds
+}
+
+// triggeringEventQueue records the events passed along with the items added to it.
+type triggeringEventQueue struct {
+	workqueue.RateLimitingInterface
+
+	mu     sync.Mutex
+	events []any
+}
+
+func (q *triggeringEventQueue) AddWithTriggeringEvent(item interface{}, evt any) {
+	q.mu.Lock()
+	q.events = append(q.events, evt)
+	q.mu.Unlock()
+	q.RateLimitingInterface.Add(item)
+}
+
+func (q *triggeringEventQueue) Events() []any {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]any(nil), q.events...)
+}
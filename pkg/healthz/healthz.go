@@ -186,6 +186,25 @@ type Checker func(req *http.Request) error
 // Ping returns true automatically when checked.
 var Ping Checker = func(_ *http.Request) error { return nil }
 
+// Quorum returns a Checker that passes if at least n of the given checks pass, e.g. to consider
+// a component ready while 2 of its 3 optional dependencies are. The checks are run in order, and
+// if too few of them pass the returned error lists the failed ones by their position in checks.
+// A Quorum of more checks than are given never passes.
+func Quorum(n int, checks ...Checker) Checker {
+	return func(req *http.Request) error {
+		var failed []string
+		for i, check := range checks {
+			if err := check(req); err != nil {
+				failed = append(failed, fmt.Sprintf("check %d: %v", i, err))
+			}
+		}
+		if passed := len(checks) - len(failed); passed < n {
+			return fmt.Errorf("%d of %d checks passed, %d required: %s", passed, len(checks), n, strings.Join(failed, ", "))
+		}
+		return nil
+	}
+}
+
 // getExcludedChecks extracts the health check names to be excluded from the query param.
 func getExcludedChecks(r *http.Request) sets.Set[string] {
 	checks, found := r.URL.Query()["exclude"]
@@ -200,4 +200,45 @@ var _ = Describe("Healthz Handler", func() {
 			Expect(resp.Code).To(Equal(http.StatusOK))
 		})
 	})
+
+	Describe("Quorum", func() {
+		failing := func(msg string) healthz.Checker {
+			return func(req *http.Request) error { return errors.New(msg) }
+		}
+
+		It("should pass if enough checks pass", func() {
+			check := healthz.Quorum(2, healthz.Ping, failing("blech"), healthz.Ping)
+			req, err := http.NewRequest("GET", "/readyz", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(check(req)).To(Succeed())
+		})
+
+		It("should fail and report the failed checks if too few checks pass", func() {
+			check := healthz.Quorum(2, failing("blech"), healthz.Ping, failing("blah"))
+			req, err := http.NewRequest("GET", "/readyz", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(check(req)).To(MatchError("1 of 3 checks passed, 2 required: check 0: blech, check 2: blah"))
+		})
+
+		It("should never pass if more checks are required than given", func() {
+			check := healthz.Quorum(3, healthz.Ping, healthz.Ping)
+			req, err := http.NewRequest("GET", "/readyz", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(check(req)).NotTo(Succeed())
+		})
+
+		It("should be usable as a single check of a Handler", func() {
+			handler := &healthz.Handler{Checks: map[string]healthz.Checker{
+				"quorum": healthz.Quorum(1, failing("blech"), healthz.Ping),
+			}}
+
+			resp := requestTo(handler, "/")
+			Expect(resp.Code).To(Equal(http.StatusOK))
+
+			handler.Checks["quorum"] = healthz.Quorum(2, failing("blech"), healthz.Ping)
+			resp = requestTo(handler, "/quorum")
+			Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+			Expect(resp.Body.String()).To(ContainSubstring("check 0: blech"))
+		})
+	})
 })
@@ -20,7 +20,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -29,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/util/workqueue"
 
+	"sigs.k8s.io/controller-runtime/pkg/causality"
 	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
@@ -36,8 +39,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
-// Controller implements controller.Controller.
-type Controller struct {
+// Controller implements controller.Controller for reconcile.Requests and
+// controller.TypedController for reconcile.TypedRequests.
+type Controller[request comparable] struct {
 	// Name is used to uniquely identify a Controller in tracing, logging and monitoring.  Name is required.
 	Name string
 
@@ -47,7 +51,7 @@ type Controller struct {
 	// Reconciler is a function that can be called at any time with the Name / Namespace of an object and
 	// ensures that the state of the system matches the state specified in the object.
 	// Defaults to the DefaultReconcileFunc.
-	Do reconcile.Reconciler
+	Do Reconciler[request]
 
 	// RateLimiter is used to limit how frequently requests may be queued into the work queue.
 	RateLimiter ratelimiter.RateLimiter
@@ -57,6 +61,24 @@ type Controller struct {
 	// leads to goroutine leaks if something calls controller.New repeatedly.
 	NewQueue func(controllerName string, rateLimiter ratelimiter.RateLimiter) workqueue.RateLimitingInterface
 
+	// Classify, if set, makes the Queue keep the requests of every class it returns in a queue of
+	// their own, constructed by NewQueue with the name <Name>/<class>, and hand them out in turn,
+	// see perClassQueue.
+	Classify func(req request) string
+
+	// Prioritize, if set, makes the Queue keep the requests of every priority band, as returned by
+	// ctrlmetrics.PriorityBand for the priority Prioritize gives them, in a queue of its own and hand
+	// out those of a higher band first, see priorityQueue. It must always give a request the same
+	// priority. The queues of the bands are constructed by the Controller rather than by NewQueue,
+	// and report their depth and latency by band in ctrlmetrics.WorkQueueDepth and
+	// ctrlmetrics.WorkQueueLatency. It takes precedence over Classify.
+	Prioritize func(req request) int
+
+	// InitialSyncRateLimiter, if set, rate limits the requests added to the Queue while the sources
+	// are syncing, i.e. mostly those for the objects that exist when the Controller starts, separately
+	// from the requests added afterwards.
+	InitialSyncRateLimiter ratelimiter.RateLimiter
+
 	// Queue is an listeningQueue that listens for events from Informers and adds object keys to
 	// the Queue for processing
 	Queue workqueue.RateLimitingInterface
@@ -85,17 +107,77 @@ type Controller struct {
 	// or for example when a watch is started.
 	// Note: LogConstructor has to be able to handle nil requests as we are also using it
 	// outside the context of a reconciliation.
-	LogConstructor func(request *reconcile.Request) logr.Logger
+	LogConstructor func(request *request) logr.Logger
 
 	// RecoverPanic indicates whether the panic caused by reconcile should be recovered.
 	RecoverPanic *bool
 
+	// DefaultRequeueAfter, if greater than 0, requeues the reconcile key after the duration when the
+	// Reconciler succeeded and returned a zero Result, unless ObjectExists reports its object as gone.
+	DefaultRequeueAfter time.Duration
+
+	// DeletedObjectPolicy determines what happens to a requeue requested by the Reconciler when
+	// ObjectExists reports the object of the Request as gone. Defaults to honoring the requeue.
+	DeletedObjectPolicy reconcile.DeletedObjectPolicy
+
+	// ObjectExists reports whether the object a Request refers to still exists. It is only
+	// called for Requests that are about to be requeued.
+	ObjectExists func(ctx context.Context, req request) (bool, error)
+
+	// reconciledOnce are the Requests whose object is gone and that were already requeued once
+	// under the DeletedObjectReconcileOnce policy.
+	reconciledOnce   map[request]struct{}
+	reconciledOnceMu sync.Mutex
+
+	// RecordTriggeringEvents makes the Queue record the most recent event that enqueued each
+	// Request, so the Reconciler can retrieve it with reconcile.TriggeringEvent.
+	RecordTriggeringEvents bool
+
+	// TrackCausality puts a causality.Chain into the context of every reconcile, made of the
+	// Chain recorded on the object that triggered it and a Link for the reconcile itself.
+	// It implies recording triggering events in the Queue.
+	TrackCausality bool
+
+	// RecordChangedChildren makes the Queue accumulate the owned objects whose events enqueued
+	// each Request since it was last reconciled, so the Reconciler can retrieve them with
+	// reconcile.ChangedChildren.
+	RecordChangedChildren bool
+
+	// LogReconcileSpans logs a "Reconcile started" and a "Reconcile finished" message at V(1) around
+	// every reconcile, the latter with its duration and result.
+	LogReconcileSpans bool
+
 	// LeaderElected indicates whether the controller is leader elected or always running.
 	LeaderElected *bool
+
+	// initialPass tracks whether the Requests enqueued until the sources synced were reconciled,
+	// see WaitForInitialPass.
+	initialPass initialPass
+
+	// trackInitialPass makes Start record the Requests enqueued until the sources synced in
+	// initialPass, see TrackInitialPass.
+	trackInitialPass bool
+
+	// startGate, if set, is waited for before the sources are started, see SetStartGate.
+	startGate func(context.Context) error
+
+	// backoff wraps the RateLimiter once the Controller started, see Backoff.
+	backoff atomic.Pointer[backoffRateLimiter]
+
+	// pauseMu guards resumed.
+	pauseMu sync.Mutex
+	// resumed is closed when the Controller is resumed, and nil unless it is paused, see Pause.
+	resumed chan struct{}
+}
+
+// Reconciler reconciles requests of type request, e.g. a reconcile.Reconciler
+// for reconcile.Requests or a reconcile.TypedReconciler for reconcile.TypedRequests.
+type Reconciler[request comparable] interface {
+	Reconcile(context.Context, request) (reconcile.Result, error)
 }
 
 // Reconcile implements reconcile.Reconciler.
-func (c *Controller) Reconcile(ctx context.Context, req reconcile.Request) (_ reconcile.Result, err error) {
+func (c *Controller[request]) Reconcile(ctx context.Context, req request) (_ reconcile.Result, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			if c.RecoverPanic != nil && *c.RecoverPanic {
@@ -115,7 +197,7 @@ func (c *Controller) Reconcile(ctx context.Context, req reconcile.Request) (_ re
 }
 
 // Watch implements controller.Controller.
-func (c *Controller) Watch(src source.Source) error {
+func (c *Controller[request]) Watch(src source.Source) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -131,8 +213,89 @@ func (c *Controller) Watch(src source.Source) error {
 	return src.Start(c.ctx, c.Queue)
 }
 
+// GetName returns the name of the Controller.
+func (c *Controller[request]) GetName() string {
+	return c.Name
+}
+
+// SetStartGate sets a function that Start waits for before starting the sources, e.g. until
+// the controllers this Controller depends on completed their initial pass. Start returns the
+// error of the gate, if any.
+func (c *Controller[request]) SetStartGate(gate func(context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.startGate = gate
+}
+
+// TrackInitialPass makes the Controller track which of the Requests enqueued until its sources
+// synced were reconciled, for WaitForInitialPass. It has to be called before Start, e.g. for the
+// controllers other controllers depend on, and has no effect afterwards.
+func (c *Controller[request]) TrackInitialPass() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.trackInitialPass = true
+}
+
+// WaitForInitialPass blocks until the sources synced and all Requests enqueued until then were
+// reconciled once, successfully or not, and returns true. It returns false if ctx is done first.
+// Unless TrackInitialPass was called before Start, it only waits for the sources to sync.
+func (c *Controller[request]) WaitForInitialPass(ctx context.Context) bool {
+	return c.initialPass.wait(ctx)
+}
+
+// Pause stops the workers from dispatching Requests to the Reconciler until Resume is called.
+// Requests are still added to the Queue while the Controller is paused, and the workers stop taking
+// them off the Queue, except for those already waiting for one, which hold on to the one they get.
+func (c *Controller[request]) Pause() {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+	if c.resumed == nil {
+		c.resumed = make(chan struct{})
+		c.LogConstructor(nil).Info("Pausing controller")
+	}
+}
+
+// Resume lets the workers dispatch Requests to the Reconciler again after Pause.
+func (c *Controller[request]) Resume() {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+	if c.resumed != nil {
+		close(c.resumed)
+		c.resumed = nil
+		c.LogConstructor(nil).Info("Resuming controller")
+	}
+}
+
+// waitUntilResumed blocks while the Controller is paused and returns true. It returns false if
+// ctx is done first.
+func (c *Controller[request]) waitUntilResumed(ctx context.Context) bool {
+	c.pauseMu.Lock()
+	resumed := c.resumed
+	c.pauseMu.Unlock()
+	if resumed == nil {
+		return true
+	}
+	select {
+	case <-resumed:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Backoff returns the delay the RateLimiter gave req when it was last requeued with rate limiting,
+// e.g. after a failed reconcile, and true. It returns false if req is not backing off, i.e. it
+// was not requeued with rate limiting since it was last reconciled successfully.
+func (c *Controller[request]) Backoff(req request) (time.Duration, bool) {
+	backoff := c.backoff.Load()
+	if backoff == nil {
+		return 0, false
+	}
+	return backoff.delay(req)
+}
+
 // NeedLeaderElection implements the manager.LeaderElectionRunnable interface.
-func (c *Controller) NeedLeaderElection() bool {
+func (c *Controller[request]) NeedLeaderElection() bool {
 	if c.LeaderElected == nil {
 		return true
 	}
@@ -140,7 +303,19 @@ func (c *Controller) NeedLeaderElection() bool {
 }
 
 // Start implements controller.Controller.
-func (c *Controller) Start(ctx context.Context) error {
+func (c *Controller[request]) Start(ctx context.Context) error {
+	c.mu.Lock()
+	gate := c.startGate
+	c.mu.Unlock()
+	if gate != nil {
+		if err := gate(ctx); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+
 	// use an IIFE to get proper lock handling
 	// but lock outside to get proper handling of the queue shutdown
 	c.mu.Lock()
@@ -153,7 +328,48 @@ func (c *Controller) Start(ctx context.Context) error {
 	// Set the internal context.
 	c.ctx = ctx
 
-	c.Queue = c.NewQueue(c.Name, c.RateLimiter)
+	var rateLimiter ratelimiter.RateLimiter
+	if c.RateLimiter != nil {
+		backoff := &backoffRateLimiter{RateLimiter: c.RateLimiter, delays: map[interface{}]time.Duration{}}
+		c.backoff.Store(backoff)
+		rateLimiter = backoff
+	}
+	switch {
+	case c.Prioritize != nil:
+		bandMetrics := ctrlmetrics.NewPriorityQueueMetrics(c.Name)
+		c.Queue = newPriorityQueue(
+			func(item interface{}) string { return ctrlmetrics.PriorityBand(c.Prioritize(item.(request))) },
+			func(band string) workqueue.RateLimitingInterface {
+				return workqueue.NewRateLimitingQueueWithConfig(rateLimiter, workqueue.RateLimitingQueueConfig{
+					Name:            c.Name,
+					MetricsProvider: bandMetrics(band),
+				})
+			},
+		)
+	case c.Classify != nil:
+		c.Queue = newPerClassQueue(
+			func(item interface{}) string { return c.Classify(item.(request)) },
+			func(class string) workqueue.RateLimitingInterface { return c.NewQueue(c.Name+"/"+class, rateLimiter) },
+		)
+	default:
+		c.Queue = c.NewQueue(c.Name, rateLimiter)
+	}
+	var initialSync *initialSyncQueue
+	if c.InitialSyncRateLimiter != nil {
+		initialSync = &initialSyncQueue{RateLimitingInterface: c.Queue, rateLimiter: c.InitialSyncRateLimiter}
+		c.Queue = initialSync
+	}
+	if c.trackInitialPass {
+		c.Queue = &initialPassQueue{RateLimitingInterface: c.Queue, pass: &c.initialPass}
+	}
+	if c.RecordTriggeringEvents || c.TrackCausality || c.RecordChangedChildren {
+		c.Queue = &triggeringEventQueue{
+			RateLimitingInterface: c.Queue,
+			events:                map[interface{}]any{},
+			recordChildren:        c.RecordChangedChildren,
+			children:              map[interface{}]map[reconcile.ChildKey]struct{}{},
+		}
+	}
 	go func() {
 		<-ctx.Done()
 		c.Queue.ShutDown()
@@ -205,6 +421,11 @@ func (c *Controller) Start(ctx context.Context) error {
 			}
 		}
 
+		if initialSync != nil {
+			initialSync.synced.Store(true)
+		}
+		c.initialPass.markSynced()
+
 		// All the watches have been started, we can reset the local slice.
 		//
 		// We should never hold watches more than necessary, each watch source can hold a backing cache,
@@ -240,7 +461,13 @@ func (c *Controller) Start(ctx context.Context) error {
 
 // processNextWorkItem will read a single work item off the workqueue and
 // attempt to process it, by calling the reconcileHandler.
-func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+func (c *Controller[request]) processNextWorkItem(ctx context.Context) bool {
+	// Don't take items off the queue while the Controller is paused, so that they stay in the
+	// queue rather than being held by the workers.
+	if !c.waitUntilResumed(ctx) {
+		return false
+	}
+
 	obj, shutdown := c.Queue.Get()
 	if shutdown {
 		// Stop working
@@ -255,10 +482,17 @@ func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	// period.
 	defer c.Queue.Done(obj)
 
+	// The Controller may have been paused while waiting for the item, hold on to it until it is
+	// resumed. Events for it are not lost meanwhile, the workqueue requeues it once it is done.
+	if !c.waitUntilResumed(ctx) {
+		return false
+	}
+
 	ctrlmetrics.ActiveWorkers.WithLabelValues(c.Name).Add(1)
 	defer ctrlmetrics.ActiveWorkers.WithLabelValues(c.Name).Add(-1)
 
 	c.reconcileHandler(ctx, obj)
+	c.initialPass.processed(obj)
 	return true
 }
 
@@ -269,7 +503,7 @@ const (
 	labelSuccess      = "success"
 )
 
-func (c *Controller) initMetrics() {
+func (c *Controller[request]) initMetrics() {
 	ctrlmetrics.ActiveWorkers.WithLabelValues(c.Name).Set(0)
 	ctrlmetrics.ReconcileErrors.WithLabelValues(c.Name).Add(0)
 	ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelError).Add(0)
@@ -279,7 +513,7 @@ func (c *Controller) initMetrics() {
 	ctrlmetrics.WorkerCount.WithLabelValues(c.Name).Set(float64(c.MaxConcurrentReconciles))
 }
 
-func (c *Controller) reconcileHandler(ctx context.Context, obj interface{}) {
+func (c *Controller[request]) reconcileHandler(ctx context.Context, obj interface{}) {
 	// Update metrics after processing each item
 	reconcileStartTS := time.Now()
 	defer func() {
@@ -287,7 +521,7 @@ func (c *Controller) reconcileHandler(ctx context.Context, obj interface{}) {
 	}()
 
 	// Make sure that the object is a valid request.
-	req, ok := obj.(reconcile.Request)
+	req, ok := obj.(request)
 	if !ok {
 		// As the item in the workqueue is actually invalid, we call
 		// Forget here else we'd go into a loop of attempting to
@@ -304,11 +538,47 @@ func (c *Controller) reconcileHandler(ctx context.Context, obj interface{}) {
 	log = log.WithValues("reconcileID", reconcileID)
 	ctx = logf.IntoContext(ctx, log)
 	ctx = addReconcileID(ctx, reconcileID)
+	var evt any
+	if q, ok := c.Queue.(*triggeringEventQueue); ok {
+		evt = q.takeTriggeringEvent(req)
+		if evt != nil && c.RecordTriggeringEvents {
+			ctx = reconcile.WithTriggeringEvent(ctx, evt)
+		}
+		if c.RecordChangedChildren {
+			ctx = reconcile.WithChangedChildren(ctx, q.takeChangedChildren(req))
+		}
+	}
+	if c.TrackCausality {
+		chain := causality.FromEvent(evt).Append(causality.Link{Controller: c.Name, Key: fmt.Sprint(req)})
+		ctx = causality.IntoContext(ctx, chain)
+	}
 
 	// RunInformersAndControllers the syncHandler, passing it the Namespace/Name string of the
 	// resource to be synced.
 	log.V(5).Info("Reconciling")
+	if c.LogReconcileSpans {
+		log.V(1).Info("Reconcile started")
+	}
+	spanStart := time.Now()
+	var followUps *reconcile.FollowUps
+	if _, ok := any(req).(reconcile.Request); ok {
+		followUps = &reconcile.FollowUps{}
+		ctx = reconcile.WithFollowUps(ctx, followUps)
+	}
 	result, err := c.Reconcile(ctx, req)
+	if err == nil && result.IsZero() && c.DefaultRequeueAfter > 0 && c.objectMayExist(ctx, req) {
+		result.RequeueAfter = c.DefaultRequeueAfter
+	}
+	if err == nil {
+		result = c.applyDeletedObjectPolicy(ctx, req, result)
+		if followUps != nil {
+			c.enqueueFollowUps(log, followUps.Requests())
+		}
+	}
+	if c.LogReconcileSpans {
+		log.V(1).Info("Reconcile finished", "duration", time.Since(spanStart).String(),
+			"requeue", result.Requeue, "requeueAfter", result.RequeueAfter.String(), "requeueAt", result.RequeueAt, "error", err)
+	}
 	switch {
 	case err != nil:
 		if errors.Is(err, reconcile.TerminalError(nil)) {
@@ -331,6 +601,14 @@ func (c *Controller) reconcileHandler(ctx context.Context, obj interface{}) {
 		c.Queue.Forget(obj)
 		c.Queue.AddAfter(req, result.RequeueAfter)
 		ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelRequeueAfter).Inc()
+	case !result.RequeueAt.IsZero():
+		// Compute the delay only now, so that the time the Reconciler took doesn't drift it.
+		// A time in the past requeues the request immediately.
+		delay := max(time.Until(result.RequeueAt), 0)
+		log.V(5).Info(fmt.Sprintf("Reconcile done, requeueing at %s", result.RequeueAt))
+		c.Queue.Forget(obj)
+		c.Queue.AddAfter(req, delay)
+		ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelRequeueAfter).Inc()
 	case result.Requeue:
 		log.V(5).Info("Reconcile done, requeueing")
 		c.Queue.AddRateLimited(req)
@@ -344,13 +622,94 @@ func (c *Controller) reconcileHandler(ctx context.Context, obj interface{}) {
 	}
 }
 
+// enqueueFollowUps adds the follow-up Requests of a reconcile to the queue, at most
+// reconcile.MaxFollowUps of them.
+func (c *Controller[request]) enqueueFollowUps(log logr.Logger, followUps []reconcile.Request) {
+	if len(followUps) == 0 {
+		return
+	}
+	if len(followUps) > reconcile.MaxFollowUps {
+		log.Error(nil, "Reconciler requested too many follow-up requests, dropping the excess",
+			"followUps", len(followUps), "max", reconcile.MaxFollowUps)
+		followUps = followUps[:reconcile.MaxFollowUps]
+	}
+	for _, followUp := range followUps {
+		if req, ok := any(followUp).(request); ok {
+			c.Queue.Add(req)
+		}
+	}
+}
+
+// applyDeletedObjectPolicy drops the requeue requested in result according to the
+// DeletedObjectPolicy if the object of req is gone.
+func (c *Controller[request]) applyDeletedObjectPolicy(ctx context.Context, req request, result reconcile.Result) reconcile.Result {
+	if c.ObjectExists == nil || c.DeletedObjectPolicy == "" || c.DeletedObjectPolicy == reconcile.DeletedObjectHonorRequeue {
+		return result
+	}
+	if !result.Requeue && result.RequeueAfter == 0 && result.RequeueAt.IsZero() {
+		c.forgetReconciledOnce(req)
+		return result
+	}
+
+	log := logf.FromContext(ctx)
+	exists, err := c.ObjectExists(ctx, req)
+	if err != nil {
+		log.Error(err, "Failed to determine whether the object still exists, requeueing as requested")
+		return result
+	}
+	if exists {
+		c.forgetReconciledOnce(req)
+		return result
+	}
+
+	if c.DeletedObjectPolicy == reconcile.DeletedObjectReconcileOnce {
+		c.reconciledOnceMu.Lock()
+		_, reconciledOnce := c.reconciledOnce[req]
+		if !reconciledOnce {
+			if c.reconciledOnce == nil {
+				c.reconciledOnce = map[request]struct{}{}
+			}
+			c.reconciledOnce[req] = struct{}{}
+		}
+		c.reconciledOnceMu.Unlock()
+		if !reconciledOnce {
+			log.V(5).Info("Object is gone, requeueing once more")
+			return result
+		}
+		c.forgetReconciledOnce(req)
+	}
+
+	log.V(5).Info("Object is gone, dropping requeue")
+	return reconcile.Result{}
+}
+
+// objectMayExist returns false if ObjectExists reports the object of req as gone, so
+// that it isn't requeued after DefaultRequeueAfter forever.
+func (c *Controller[request]) objectMayExist(ctx context.Context, req request) bool {
+	if c.ObjectExists == nil {
+		return true
+	}
+	exists, err := c.ObjectExists(ctx, req)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to determine whether the object still exists, requeueing after DefaultRequeueAfter")
+		return true
+	}
+	return exists
+}
+
+func (c *Controller[request]) forgetReconciledOnce(req request) {
+	c.reconciledOnceMu.Lock()
+	defer c.reconciledOnceMu.Unlock()
+	delete(c.reconciledOnce, req)
+}
+
 // GetLogger returns this controller's logger.
-func (c *Controller) GetLogger() logr.Logger {
+func (c *Controller[request]) GetLogger() logr.Logger {
 	return c.LogConstructor(nil)
 }
 
 // updateMetrics updates prometheus metrics within the controller.
-func (c *Controller) updateMetrics(reconcileTime time.Duration) {
+func (c *Controller[request]) updateMetrics(reconcileTime time.Duration) {
 	ctrlmetrics.ReconcileTime.WithLabelValues(c.Name).Observe(reconcileTime.Seconds())
 }
 
@@ -371,3 +730,518 @@ type reconcileIDKey struct{}
 func addReconcileID(ctx context.Context, reconcileID types.UID) context.Context {
 	return context.WithValue(ctx, reconcileIDKey{}, reconcileID)
 }
+
+// initialSyncQueue rate limits the items added while the sources of the Controller are
+// syncing, until synced is set.
+type initialSyncQueue struct {
+	workqueue.RateLimitingInterface
+	rateLimiter ratelimiter.RateLimiter
+	synced      atomic.Bool
+}
+
+// Add adds item to the queue after the delay rateLimiter gives it, until synced is set.
+func (q *initialSyncQueue) Add(item interface{}) {
+	if q.synced.Load() {
+		q.RateLimitingInterface.Add(item)
+		return
+	}
+	q.RateLimitingInterface.AddAfter(item, q.rateLimiter.When(item))
+}
+
+// perClassQueue keeps the items of every class, as returned by classify, in a queue of their
+// own, constructed by newQueue for the class, and hands out the items of all classes in turn, so that a class
+// with many items can't starve the others.
+type perClassQueue struct {
+	classify func(item interface{}) string
+	newQueue func(class string) workqueue.RateLimitingInterface
+
+	mu           sync.Mutex
+	queues       map[string]workqueue.RateLimitingInterface
+	shuttingDown bool
+
+	// ready receives the next item of every class queue that has one. As a receive serves the
+	// blocked sends in the order they were made, Get takes turns among the classes.
+	ready chan interface{}
+	// stopped is closed when the queue is shut down.
+	stopped chan struct{}
+}
+
+func newPerClassQueue(classify func(item interface{}) string, newQueue func(class string) workqueue.RateLimitingInterface) *perClassQueue {
+	return &perClassQueue{
+		classify: classify,
+		newQueue: newQueue,
+		queues:   map[string]workqueue.RateLimitingInterface{},
+		ready:    make(chan interface{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// queueFor returns the queue of the class of item, constructing it if needed.
+func (q *perClassQueue) queueFor(item interface{}) workqueue.RateLimitingInterface {
+	class := q.classify(item)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	queue, ok := q.queues[class]
+	if !ok {
+		queue = q.newQueue(class)
+		q.queues[class] = queue
+		if q.shuttingDown {
+			queue.ShutDown()
+		} else {
+			go q.forward(queue)
+		}
+	}
+	return queue
+}
+
+// forward sends the items of queue to ready until either is shut down. An item that can't
+// be sent anymore is marked as done.
+func (q *perClassQueue) forward(queue workqueue.RateLimitingInterface) {
+	for {
+		item, shutdown := queue.Get()
+		if shutdown {
+			return
+		}
+		select {
+		case q.ready <- item:
+		case <-q.stopped:
+			queue.Done(item)
+			return
+		}
+	}
+}
+
+// Add implements workqueue.Interface.
+func (q *perClassQueue) Add(item interface{}) {
+	q.queueFor(item).Add(item)
+}
+
+// AddAfter implements workqueue.DelayingInterface.
+func (q *perClassQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.queueFor(item).AddAfter(item, duration)
+}
+
+// AddRateLimited implements workqueue.RateLimitingInterface.
+func (q *perClassQueue) AddRateLimited(item interface{}) {
+	q.queueFor(item).AddRateLimited(item)
+}
+
+// Forget implements workqueue.RateLimitingInterface.
+func (q *perClassQueue) Forget(item interface{}) {
+	q.queueFor(item).Forget(item)
+}
+
+// NumRequeues implements workqueue.RateLimitingInterface.
+func (q *perClassQueue) NumRequeues(item interface{}) int {
+	return q.queueFor(item).NumRequeues(item)
+}
+
+// Get implements workqueue.Interface.
+func (q *perClassQueue) Get() (interface{}, bool) {
+	select {
+	case item := <-q.ready:
+		return item, false
+	case <-q.stopped:
+		return nil, true
+	}
+}
+
+// Done implements workqueue.Interface.
+func (q *perClassQueue) Done(item interface{}) {
+	q.queueFor(item).Done(item)
+}
+
+// Len implements workqueue.Interface. It doesn't count the items that are about to be
+// handed out by Get.
+func (q *perClassQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := 0
+	for _, queue := range q.queues {
+		n += queue.Len()
+	}
+	return n
+}
+
+// ShutDown implements workqueue.Interface.
+func (q *perClassQueue) ShutDown() {
+	q.shutDown(workqueue.RateLimitingInterface.ShutDown)
+}
+
+// ShutDownWithDrain implements workqueue.Interface.
+func (q *perClassQueue) ShutDownWithDrain() {
+	q.shutDown(workqueue.RateLimitingInterface.ShutDownWithDrain)
+}
+
+func (q *perClassQueue) shutDown(shutDown func(workqueue.RateLimitingInterface)) {
+	q.mu.Lock()
+	if q.shuttingDown {
+		q.mu.Unlock()
+		return
+	}
+	q.shuttingDown = true
+	close(q.stopped)
+	queues := make([]workqueue.RateLimitingInterface, 0, len(q.queues))
+	for _, queue := range q.queues {
+		queues = append(queues, queue)
+	}
+	q.mu.Unlock()
+
+	for _, queue := range queues {
+		shutDown(queue)
+	}
+}
+
+// ShuttingDown implements workqueue.Interface.
+func (q *perClassQueue) ShuttingDown() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.shuttingDown
+}
+
+// priorityQueue keeps the items of every priority band, as returned by band, in a queue of
+// their own, constructed by newQueue, and hands out the items of a higher band before those
+// of a lower one.
+type priorityQueue struct {
+	band   func(item interface{}) string
+	queues map[string]workqueue.RateLimitingInterface
+
+	// ready receive the next item of the queue of every band, in the order of
+	// ctrlmetrics.PriorityBands.
+	ready [len(ctrlmetrics.PriorityBands)]chan interface{}
+	// stopped is closed when the queue is shut down.
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func newPriorityQueue(band func(item interface{}) string, newQueue func(band string) workqueue.RateLimitingInterface) *priorityQueue {
+	q := &priorityQueue{
+		band:    band,
+		queues:  map[string]workqueue.RateLimitingInterface{},
+		stopped: make(chan struct{}),
+	}
+	for i, band := range ctrlmetrics.PriorityBands {
+		queue := newQueue(band)
+		q.queues[band] = queue
+		q.ready[i] = make(chan interface{})
+		go q.forward(queue, q.ready[i])
+	}
+	return q
+}
+
+// queueFor returns the queue of the band of item.
+func (q *priorityQueue) queueFor(item interface{}) workqueue.RateLimitingInterface {
+	return q.queues[q.band(item)]
+}
+
+// forward sends the items of queue to ready until either is shut down. An item that can't
+// be sent anymore is marked as done.
+func (q *priorityQueue) forward(queue workqueue.RateLimitingInterface, ready chan<- interface{}) {
+	for {
+		item, shutdown := queue.Get()
+		if shutdown {
+			return
+		}
+		select {
+		case ready <- item:
+		case <-q.stopped:
+			queue.Done(item)
+			return
+		}
+	}
+}
+
+// Add implements workqueue.Interface.
+func (q *priorityQueue) Add(item interface{}) {
+	q.queueFor(item).Add(item)
+}
+
+// AddAfter implements workqueue.DelayingInterface.
+func (q *priorityQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.queueFor(item).AddAfter(item, duration)
+}
+
+// AddRateLimited implements workqueue.RateLimitingInterface.
+func (q *priorityQueue) AddRateLimited(item interface{}) {
+	q.queueFor(item).AddRateLimited(item)
+}
+
+// Forget implements workqueue.RateLimitingInterface.
+func (q *priorityQueue) Forget(item interface{}) {
+	q.queueFor(item).Forget(item)
+}
+
+// NumRequeues implements workqueue.RateLimitingInterface.
+func (q *priorityQueue) NumRequeues(item interface{}) int {
+	return q.queueFor(item).NumRequeues(item)
+}
+
+// Get implements workqueue.Interface. It hands out the item of the highest band that has
+// one, or else waits for the first item of any band.
+func (q *priorityQueue) Get() (interface{}, bool) {
+	for _, ready := range q.ready {
+		select {
+		case item := <-ready:
+			return item, false
+		default:
+		}
+	}
+	select {
+	case item := <-q.ready[0]:
+		return item, false
+	case item := <-q.ready[1]:
+		return item, false
+	case item := <-q.ready[2]:
+		return item, false
+	case <-q.stopped:
+		return nil, true
+	}
+}
+
+// Done implements workqueue.Interface.
+func (q *priorityQueue) Done(item interface{}) {
+	q.queueFor(item).Done(item)
+}
+
+// Len implements workqueue.Interface. It doesn't count the items that are about to be
+// handed out by Get.
+func (q *priorityQueue) Len() int {
+	n := 0
+	for _, queue := range q.queues {
+		n += queue.Len()
+	}
+	return n
+}
+
+// ShutDown implements workqueue.Interface.
+func (q *priorityQueue) ShutDown() {
+	q.shutDown(workqueue.RateLimitingInterface.ShutDown)
+}
+
+// ShutDownWithDrain implements workqueue.Interface.
+func (q *priorityQueue) ShutDownWithDrain() {
+	q.shutDown(workqueue.RateLimitingInterface.ShutDownWithDrain)
+}
+
+func (q *priorityQueue) shutDown(shutDown func(workqueue.RateLimitingInterface)) {
+	q.stopOnce.Do(func() {
+		close(q.stopped)
+		for _, queue := range q.queues {
+			shutDown(queue)
+		}
+	})
+}
+
+// ShuttingDown implements workqueue.Interface.
+func (q *priorityQueue) ShuttingDown() bool {
+	select {
+	case <-q.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoffRateLimiter records the delay RateLimiter gives each item until the item is forgotten.
+type backoffRateLimiter struct {
+	ratelimiter.RateLimiter
+	mu     sync.Mutex
+	delays map[interface{}]time.Duration
+}
+
+// When implements ratelimiter.RateLimiter.
+func (rl *backoffRateLimiter) When(item interface{}) time.Duration {
+	delay := rl.RateLimiter.When(item)
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.delays[item] = delay
+	return delay
+}
+
+// Forget implements ratelimiter.RateLimiter.
+func (rl *backoffRateLimiter) Forget(item interface{}) {
+	rl.RateLimiter.Forget(item)
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	delete(rl.delays, item)
+}
+
+func (rl *backoffRateLimiter) delay(item interface{}) (time.Duration, bool) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	delay, ok := rl.delays[item]
+	return delay, ok
+}
+
+// initialPass tracks the items added to the queue until the sources of the Controller synced,
+// to tell when all of them were processed once.
+type initialPass struct {
+	mu       sync.Mutex
+	synced   bool
+	pending  map[interface{}]struct{}
+	done     chan struct{}
+	complete atomic.Bool
+}
+
+// doneLocked returns the channel that is closed once the initial pass is complete.
+func (p *initialPass) doneLocked() chan struct{} {
+	if p.done == nil {
+		p.done = make(chan struct{})
+	}
+	return p.done
+}
+
+func (p *initialPass) wait(ctx context.Context) bool {
+	p.mu.Lock()
+	done := p.doneLocked()
+	p.mu.Unlock()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// added records item as pending if the sources didn't sync yet.
+func (p *initialPass) added(item interface{}) {
+	if p.complete.Load() {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.synced {
+		return
+	}
+	if p.pending == nil {
+		p.pending = map[interface{}]struct{}{}
+	}
+	p.pending[item] = struct{}{}
+}
+
+// processed records that item was processed.
+func (p *initialPass) processed(item interface{}) {
+	if p.complete.Load() {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.pending, item)
+	p.completeIfDoneLocked()
+}
+
+// markSynced records that the sources synced, so no more items are pending.
+func (p *initialPass) markSynced() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.synced = true
+	p.completeIfDoneLocked()
+}
+
+func (p *initialPass) completeIfDoneLocked() {
+	if !p.synced || len(p.pending) > 0 || p.complete.Load() {
+		return
+	}
+	close(p.doneLocked())
+	p.complete.Store(true)
+}
+
+// initialPassQueue records the items added while the sources of the Controller are
+// syncing in an initialPass.
+type initialPassQueue struct {
+	workqueue.RateLimitingInterface
+	pass *initialPass
+}
+
+// Add records item in the initialPass and adds it to the queue.
+func (q *initialPassQueue) Add(item interface{}) {
+	q.pass.added(item)
+	q.RateLimitingInterface.Add(item)
+}
+
+// AddAfter records item in the initialPass and adds it to the queue after duration.
+func (q *initialPassQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.pass.added(item)
+	q.RateLimitingInterface.AddAfter(item, duration)
+}
+
+// AddRateLimited records item in the initialPass and adds it to the queue after the
+// rate limiter says it's ok.
+func (q *initialPassQueue) AddRateLimited(item interface{}) {
+	q.pass.added(item)
+	q.RateLimitingInterface.AddRateLimited(item)
+}
+
+// triggeringEventQueue records the most recent event that enqueued each item,
+// for the Reconciler to retrieve it with reconcile.TriggeringEvent, and optionally
+// the owned objects whose events enqueued it, see reconcile.ChangedChildren.
+type triggeringEventQueue struct {
+	workqueue.RateLimitingInterface
+
+	mu             sync.Mutex
+	events         map[interface{}]any
+	recordChildren bool
+	children       map[interface{}]map[reconcile.ChildKey]struct{}
+}
+
+// AddWithTriggeringEvent records evt as the event that triggered item and adds item to the queue.
+func (q *triggeringEventQueue) AddWithTriggeringEvent(item interface{}, evt any) {
+	q.mu.Lock()
+	q.events[item] = evt
+	q.mu.Unlock()
+	q.Add(item)
+}
+
+// AddWithChangedChild records evt as the event that triggered item, adds child to the
+// changed children of item and adds item to the queue.
+func (q *triggeringEventQueue) AddWithChangedChild(item interface{}, child reconcile.ChildKey, evt any) {
+	q.mu.Lock()
+	q.events[item] = evt
+	if q.recordChildren {
+		if q.children[item] == nil {
+			q.children[item] = map[reconcile.ChildKey]struct{}{}
+		}
+		q.children[item][child] = struct{}{}
+	}
+	q.mu.Unlock()
+	q.Add(item)
+}
+
+// takeChangedChildren returns and forgets the changed children recorded for item, sorted
+// by GroupKind, Namespace and Name.
+func (q *triggeringEventQueue) takeChangedChildren(item interface{}) []reconcile.ChildKey {
+	q.mu.Lock()
+	set := q.children[item]
+	delete(q.children, item)
+	q.mu.Unlock()
+
+	children := make([]reconcile.ChildKey, 0, len(set))
+	for child := range set {
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		a, b := children[i], children[j]
+		if a.GroupKind.Group != b.GroupKind.Group {
+			return a.GroupKind.Group < b.GroupKind.Group
+		}
+		if a.GroupKind.Kind != b.GroupKind.Kind {
+			return a.GroupKind.Kind < b.GroupKind.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return children
+}
+
+// takeTriggeringEvent returns and forgets the event recorded for item, if any.
+func (q *triggeringEventQueue) takeTriggeringEvent(item interface{}) any {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	evt := q.events[item]
+	delete(q.events, item)
+	return evt
+}
@@ -20,24 +20,34 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/causality"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
@@ -49,7 +59,7 @@ import (
 
 var _ = Describe("controller", func() {
 	var fakeReconcile *fakeReconciler
-	var ctrl *Controller
+	var ctrl *Controller[reconcile.Request]
 	var queue *controllertest.Queue
 	var reconciled chan reconcile.Request
 	var request = reconcile.Request{
@@ -65,7 +75,7 @@ var _ = Describe("controller", func() {
 		queue = &controllertest.Queue{
 			Interface: workqueue.New(),
 		}
-		ctrl = &Controller{
+		ctrl = &Controller[reconcile.Request]{
 			MaxConcurrentReconciles: 1,
 			Do:                      fakeReconcile,
 			NewQueue:                func(string, ratelimiter.RateLimiter) workqueue.RateLimitingInterface { return queue },
@@ -435,6 +445,46 @@ var _ = Describe("controller", func() {
 			Eventually(func() int { return dq.NumRequeues(request) }).Should(Equal(0))
 		})
 
+		It("should report the growing backoff of a Request that fails repeatedly", func() {
+			q := make(chan workqueue.RateLimitingInterface, 1)
+			ctrl.RateLimiter = workqueue.NewItemExponentialFailureRateLimiter(10*time.Millisecond, time.Minute)
+			ctrl.NewQueue = func(name string, rateLimiter ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
+				queue := workqueue.NewRateLimitingQueueWithConfig(rateLimiter, workqueue.RateLimitingQueueConfig{Name: name})
+				q <- queue
+				return queue
+			}
+			backoff := func() time.Duration {
+				delay, _ := ctrl.Backoff(request)
+				return delay
+			}
+
+			_, ok := ctrl.Backoff(request)
+			Expect(ok).To(BeFalse())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			(<-q).Add(request)
+
+			By("Failing the reconcile repeatedly")
+			for _, delay := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond} {
+				fakeReconcile.AddResult(reconcile.Result{}, fmt.Errorf("expected error: reconcile"))
+				Expect(<-reconciled).To(Equal(request))
+				Eventually(backoff).Should(Equal(delay))
+			}
+
+			By("Resetting the backoff once the reconcile succeeds")
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(func() bool {
+				_, ok := ctrl.Backoff(request)
+				return ok
+			}).Should(BeFalse())
+		})
+
 		It("should requeue a Request with rate limiting if the Result sets Requeue:true and continue processing items", func() {
 			dq := &DelegatingQueue{RateLimitingInterface: ctrl.NewQueue("controller1", nil)}
 			ctrl.NewQueue = func(string, ratelimiter.RateLimiter) workqueue.RateLimitingInterface { return dq }
@@ -495,6 +545,892 @@ var _ = Describe("controller", func() {
 			Eventually(func() int { return dq.NumRequeues(request) }).Should(Equal(0))
 		})
 
+		It("should requeue a Request at the time the Result sets in RequeueAt", func() {
+			dq := &DelegatingQueue{RateLimitingInterface: ctrl.NewQueue("controller1", nil)}
+			ctrl.NewQueue = func(string, ratelimiter.RateLimiter) workqueue.RateLimitingInterface { return dq }
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+
+			dq.Add(request)
+			Expect(dq.getCounts()).To(Equal(countInfo{Trying: 1}))
+
+			By("Invoking Reconciler which takes a while and asks for a requeue in an hour")
+			requeueAt := time.Now().Add(time.Hour)
+			time.Sleep(200 * time.Millisecond)
+			fakeReconcile.AddResult(reconcile.RequeueAt(requeueAt), nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0, AddAfter: 1}))
+
+			By("Expecting the delay to account for the time the Reconciler took")
+			Expect(dq.getLastAddAfter()).To(BeNumerically("~", time.Until(requeueAt), 100*time.Millisecond))
+			Expect(dq.getLastAddAfter()).To(BeNumerically("<=", time.Hour-200*time.Millisecond))
+
+			By("Invoking Reconciler a second time asking for a requeue in the past")
+			dq.Add(request)
+			fakeReconcile.AddResult(reconcile.RequeueAt(time.Now().Add(-time.Minute)), nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0, AddAfter: 2}))
+			Expect(dq.getLastAddAfter()).To(BeZero())
+
+			By("Expecting the Request to be reconciled again right away")
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Eventually(reconciled).Should(Receive(Equal(request)))
+		})
+
+		It("should requeue a Request after DefaultRequeueAfter if the Result is zero", func() {
+			dq := &DelegatingQueue{RateLimitingInterface: ctrl.NewQueue("controller1", nil)}
+			ctrl.NewQueue = func(string, ratelimiter.RateLimiter) workqueue.RateLimitingInterface { return dq }
+			ctrl.DefaultRequeueAfter = time.Hour
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+
+			dq.Add(request)
+			Expect(dq.getCounts()).To(Equal(countInfo{Trying: 1}))
+
+			By("Invoking Reconciler which returns an empty result")
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0, AddAfter: 1}))
+
+			By("Invoking Reconciler a second time explicitly asking to not requeue")
+			dq.Add(request)
+			fakeReconcile.AddResult(reconcile.Result{NoRequeue: true}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0, AddAfter: 1}))
+
+			By("Invoking Reconciler a third time asking for its own requeueafter")
+			dq.Add(request)
+			fakeReconcile.AddResult(reconcile.Result{RequeueAfter: time.Millisecond * 100}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0, AddAfter: 2}))
+		})
+
+		It("should not requeue a Request after DefaultRequeueAfter if its object is gone", func() {
+			dq := &DelegatingQueue{RateLimitingInterface: ctrl.NewQueue("controller1", nil)}
+			ctrl.NewQueue = func(string, ratelimiter.RateLimiter) workqueue.RateLimitingInterface { return dq }
+			ctrl.DefaultRequeueAfter = time.Hour
+			var exists atomic.Bool
+			ctrl.ObjectExists = func(context.Context, reconcile.Request) (bool, error) {
+				return exists.Load(), nil
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+
+			By("Invoking Reconciler for an object that is gone")
+			dq.Add(request)
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0}))
+			Consistently(dq.getCounts).Should(Equal(countInfo{Trying: 0}))
+
+			By("Invoking Reconciler for an object that exists")
+			exists.Store(true)
+			dq.Add(request)
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0, AddAfter: 1}))
+		})
+
+		It("should not reconcile while paused and reconcile the requests enqueued meanwhile after resuming", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+
+			By("Reconciling a request before pausing")
+			queue.Add(request)
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Expect(<-reconciled).To(Equal(request))
+
+			By("Enqueueing requests while paused")
+			ctrl.Pause()
+			other := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "baz"}}
+			queue.Add(request)
+			queue.Add(other)
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Consistently(reconciled, 200*time.Millisecond).ShouldNot(Receive())
+
+			By("Expecting the requests to be reconciled after resuming")
+			ctrl.Resume()
+			Expect(<-reconciled).To(Equal(request))
+			Expect(<-reconciled).To(Equal(other))
+			Eventually(queue.Len).Should(Equal(0))
+		})
+
+		It("should leave the requests in the queue while paused", func() {
+			ctrl.Pause()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+
+			other := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "baz"}}
+			queue.Add(request)
+			queue.Add(other)
+			Consistently(queue.Len, 200*time.Millisecond).Should(Equal(2))
+
+			By("Expecting the requests to be reconciled after resuming")
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			ctrl.Resume()
+			Expect(<-reconciled).To(Equal(request))
+			Expect(<-reconciled).To(Equal(other))
+			Eventually(queue.Len).Should(Equal(0))
+		})
+
+		It("should enqueue the follow-up requests of a reconcile", func() {
+			dq := &DelegatingQueue{RateLimitingInterface: ctrl.NewQueue("controller1", nil)}
+			ctrl.NewQueue = func(string, ratelimiter.RateLimiter) workqueue.RateLimitingInterface { return dq }
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+
+			followUp := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "baz"}}
+			dq.Add(request)
+
+			By("Invoking Reconciler which returns a follow-up request")
+			fakeReconcile.AddResultWithFollowUps(reconcile.Result{}, nil, followUp)
+			Expect(<-reconciled).To(Equal(request))
+
+			By("Expecting the follow-up request to be reconciled")
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Expect(<-reconciled).To(Equal(followUp))
+			Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0}))
+		})
+
+		It("should not enqueue more than MaxFollowUps follow-up requests", func() {
+			var mu sync.Mutex
+			reconciledFollowUps := sets.New[reconcile.Request]()
+			ctrl.Do = reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+				if req != request {
+					mu.Lock()
+					defer mu.Unlock()
+					reconciledFollowUps.Insert(req)
+					return reconcile.Result{}, nil
+				}
+				for i := 0; i < reconcile.MaxFollowUps+10; i++ {
+					Expect(reconcile.EnqueueFollowUps(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: fmt.Sprintf("baz-%d", i)}})).To(BeTrue())
+				}
+				return reconcile.Result{}, nil
+			})
+			countFollowUps := func() int {
+				mu.Lock()
+				defer mu.Unlock()
+				return reconciledFollowUps.Len()
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+
+			queue.Add(request)
+			Eventually(countFollowUps).Should(Equal(reconcile.MaxFollowUps))
+			Consistently(countFollowUps, 200*time.Millisecond).Should(Equal(reconcile.MaxFollowUps))
+		})
+
+		It("should not enqueue the follow-up requests if the Reconciler returns an error", func() {
+			dq := &DelegatingQueue{RateLimitingInterface: ctrl.NewQueue("controller1", nil)}
+			ctrl.NewQueue = func(string, ratelimiter.RateLimiter) workqueue.RateLimitingInterface { return dq }
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+
+			followUp := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "baz"}}
+			dq.Add(request)
+			fakeReconcile.AddResultWithFollowUps(reconcile.Result{}, fmt.Errorf("expected error: reconcile"), followUp)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 1, AddRateLimited: 1}))
+
+			By("Expecting only the original request to be reconciled again")
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Consistently(reconciled, 200*time.Millisecond).ShouldNot(Receive())
+		})
+
+		Context("with a DeletedObjectPolicy", func() {
+			var (
+				dq     *DelegatingQueue
+				exists atomic.Bool
+			)
+
+			BeforeEach(func() {
+				dq = &DelegatingQueue{RateLimitingInterface: ctrl.NewQueue("controller1", nil)}
+				ctrl.NewQueue = func(string, ratelimiter.RateLimiter) workqueue.RateLimitingInterface { return dq }
+				exists.Store(false)
+				ctrl.ObjectExists = func(context.Context, reconcile.Request) (bool, error) { return exists.Load(), nil }
+			})
+
+			startController := func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				DeferCleanup(cancel)
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+			}
+
+			reconcileWith := func(result reconcile.Result) {
+				dq.Add(request)
+				fakeReconcile.AddResult(result, nil)
+				Expect(<-reconciled).To(Equal(request))
+			}
+
+			It("should honor the requeue for a gone object with DeletedObjectHonorRequeue", func() {
+				ctrl.DeletedObjectPolicy = reconcile.DeletedObjectHonorRequeue
+				startController()
+
+				reconcileWith(reconcile.Result{RequeueAfter: time.Hour})
+				Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0, AddAfter: 1}))
+			})
+
+			It("should drop the requeue for a gone object with DeletedObjectDropRequeue", func() {
+				ctrl.DeletedObjectPolicy = reconcile.DeletedObjectDropRequeue
+				startController()
+
+				reconcileWith(reconcile.Result{RequeueAfter: time.Hour})
+				Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0}))
+				Consistently(dq.getCounts).Should(Equal(countInfo{Trying: 0}))
+			})
+
+			It("should drop the periodic requeue for a gone object with DeletedObjectDropRequeue", func() {
+				ctrl.DeletedObjectPolicy = reconcile.DeletedObjectDropRequeue
+				ctrl.DefaultRequeueAfter = time.Hour
+				startController()
+
+				reconcileWith(reconcile.Result{})
+				Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0}))
+				Consistently(dq.getCounts).Should(Equal(countInfo{Trying: 0}))
+			})
+
+			It("should honor the requeue for an existing object with DeletedObjectDropRequeue", func() {
+				// An object with a deletion timestamp, e.g. because of a finalizer, still exists.
+				ctrl.DeletedObjectPolicy = reconcile.DeletedObjectDropRequeue
+				exists.Store(true)
+				startController()
+
+				reconcileWith(reconcile.Result{RequeueAfter: time.Hour})
+				Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0, AddAfter: 1}))
+			})
+
+			It("should honor only the first requeue for a gone object with DeletedObjectReconcileOnce", func() {
+				ctrl.DeletedObjectPolicy = reconcile.DeletedObjectReconcileOnce
+				startController()
+
+				By("Honoring the first requeue so the Reconciler can clean up")
+				reconcileWith(reconcile.Result{RequeueAfter: time.Hour})
+				Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0, AddAfter: 1}))
+
+				By("Dropping the second requeue")
+				reconcileWith(reconcile.Result{RequeueAfter: time.Hour})
+				Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0, AddAfter: 1}))
+				Consistently(dq.getCounts).Should(Equal(countInfo{Trying: 0, AddAfter: 1}))
+
+				By("Honoring a requeue again once the object was re-created")
+				exists.Store(true)
+				reconcileWith(reconcile.Result{RequeueAfter: time.Hour})
+				Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0, AddAfter: 2}))
+
+				By("Honoring the first requeue after the re-created object is gone again")
+				exists.Store(false)
+				reconcileWith(reconcile.Result{RequeueAfter: time.Hour})
+				Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0, AddAfter: 3}))
+			})
+		})
+
+		Context("with RecordTriggeringEvents", func() {
+			var (
+				events chan any
+				queues chan workqueue.RateLimitingInterface
+			)
+
+			BeforeEach(func() {
+				events = make(chan any)
+				queues = make(chan workqueue.RateLimitingInterface, 1)
+				ctrl.NewQueue = func(string, ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
+					return workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+				}
+				ctrl.RecordTriggeringEvents = true
+				ctrl.Do = reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+					events <- reconcile.TriggeringEvent(ctx)
+					return reconcile.Result{}, nil
+				})
+				Expect(ctrl.Watch(source.Func(func(_ context.Context, q workqueue.RateLimitingInterface) error {
+					queues <- q
+					return nil
+				}))).To(Succeed())
+			})
+
+			startController := func() workqueue.RateLimitingInterface {
+				ctx, cancel := context.WithCancel(context.Background())
+				DeferCleanup(cancel)
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				return <-queues
+			}
+
+			It("should give the Reconciler access to the old and new object of an update", func() {
+				q := startController()
+
+				oldPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar", ResourceVersion: "1"}}
+				newPod := oldPod.DeepCopy()
+				newPod.ResourceVersion = "2"
+				(&handler.EnqueueRequestForObject{}).Update(context.Background(), event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod}, q)
+
+				var evt any
+				Eventually(events).Should(Receive(&evt))
+				Expect(evt).To(BeAssignableToTypeOf(event.UpdateEvent{}))
+				Expect(evt.(event.UpdateEvent).ObjectOld).To(Equal(oldPod))
+				Expect(evt.(event.UpdateEvent).ObjectNew).To(Equal(newPod))
+			})
+
+			It("should not give the Reconciler an event for a requeue", func() {
+				q := startController()
+
+				q.Add(request)
+				Eventually(events).Should(Receive(BeNil()))
+			})
+		})
+
+		Context("with InitialSyncRateLimiter", func() {
+			It("should rate limit the requests added while syncing separately from later ones", func() {
+				initialRequest := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "initial"}}
+				initialRateLimiter := &fakeRateLimiter{delay: time.Hour}
+				ctrl.InitialSyncRateLimiter = initialRateLimiter
+				ctrl.CacheSyncTimeout = 10 * time.Second
+				ctrl.NewQueue = func(string, ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
+					return workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+				}
+				ctrl.Do = reconcile.Func(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+					reconciled <- req
+					return reconcile.Result{}, nil
+				})
+
+				synced := make(chan struct{})
+				queues := make(chan workqueue.RateLimitingInterface, 1)
+				Expect(ctrl.Watch(&blockingSyncSource{
+					start: func(q workqueue.RateLimitingInterface) {
+						q.Add(initialRequest)
+						queues <- q
+					},
+					synced: synced,
+				})).To(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				q := <-queues
+
+				By("Rate limiting the initial request with the InitialSyncRateLimiter")
+				Eventually(initialRateLimiter.requested).Should(Equal([]interface{}{initialRequest}))
+
+				By("Not rate limiting requests once the sources synced")
+				close(synced)
+				Eventually(func() bool {
+					ctrl.mu.Lock()
+					defer ctrl.mu.Unlock()
+					return ctrl.Started
+				}).Should(BeTrue())
+				q.Add(request)
+				Eventually(reconciled).Should(Receive(Equal(request)))
+				Consistently(reconciled).ShouldNot(Receive())
+				Expect(initialRateLimiter.requested()).To(Equal([]interface{}{initialRequest}))
+			})
+		})
+
+		Context("with TrackInitialPass", func() {
+			It("should complete the initial pass once the requests enqueued until the sources synced were reconciled", func() {
+				ctrl.TrackInitialPass()
+				ctrl.CacheSyncTimeout = 10 * time.Second
+				synced := make(chan struct{})
+				Expect(ctrl.Watch(&blockingSyncSource{
+					start:  func(q workqueue.RateLimitingInterface) { q.Add(request) },
+					synced: synced,
+				})).To(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+
+				passed := make(chan bool)
+				go func() { passed <- ctrl.WaitForInitialPass(ctx) }()
+				close(synced)
+				Consistently(passed).ShouldNot(Receive())
+
+				fakeReconcile.AddResult(reconcile.Result{}, nil)
+				Expect(<-reconciled).To(Equal(request))
+				Eventually(passed).Should(Receive(BeTrue()))
+			})
+
+			It("should not wrap the queue unless it is called", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+
+				Expect(ctrl.WaitForInitialPass(ctx)).To(BeTrue())
+				ctrl.mu.Lock()
+				defer ctrl.mu.Unlock()
+				Expect(ctrl.Queue).To(BeIdenticalTo(queue))
+			})
+		})
+
+		Context("with Classify", func() {
+			It("should not let a chatty class of requests starve a quiet one", func() {
+				ctrl.Name = "classified"
+				ctrl.Classify = func(req reconcile.Request) string { return req.Namespace }
+				ctrl.CacheSyncTimeout = 10 * time.Second
+				var (
+					namesMu sync.Mutex
+					names   []string
+				)
+				ctrl.NewQueue = func(name string, _ ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
+					namesMu.Lock()
+					defer namesMu.Unlock()
+					names = append(names, name)
+					return workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+				}
+				ctrl.Do = reconcile.Func(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+					reconciled <- req
+					return reconcile.Result{}, nil
+				})
+
+				quiet := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "quiet", Name: "bar"}}
+				synced := make(chan struct{})
+				close(synced)
+				Expect(ctrl.Watch(&blockingSyncSource{
+					start: func(q workqueue.RateLimitingInterface) {
+						for i := 0; i < 50; i++ {
+							q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "chatty", Name: fmt.Sprintf("bar-%d", i)}})
+						}
+						q.Add(quiet)
+					},
+					synced: synced,
+				})).To(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+
+				By("Reconciling the quiet request right after the first chatty ones")
+				var first []reconcile.Request
+				for i := 0; i < 3; i++ {
+					var req reconcile.Request
+					Eventually(reconciled).Should(Receive(&req))
+					first = append(first, req)
+				}
+				Expect(first).To(ContainElement(quiet))
+
+				By("Reconciling the remaining chatty requests afterwards")
+				for i := 0; i < 48; i++ {
+					var req reconcile.Request
+					Eventually(reconciled).Should(Receive(&req))
+					Expect(req.Namespace).To(Equal("chatty"))
+				}
+
+				By("Naming the queue of every class after the controller and the class")
+				namesMu.Lock()
+				defer namesMu.Unlock()
+				Expect(names).To(ConsistOf("classified/chatty", "classified/quiet"))
+			})
+		})
+
+		Context("with Prioritize", func() {
+			var (
+				low    = reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "low", Name: "bar"}}
+				normal = reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "normal", Name: "bar"}}
+				high   = reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "high", Name: "bar"}}
+			)
+
+			// startWithQueued starts the controller once the queue handed out the requests to the
+			// priority bands, and returns the func that stops it.
+			startWithQueued := func(requests ...reconcile.Request) context.CancelFunc {
+				queues := make(chan workqueue.RateLimitingInterface, 1)
+				synced := make(chan struct{})
+				Expect(ctrl.Watch(&blockingSyncSource{
+					start: func(q workqueue.RateLimitingInterface) {
+						for _, req := range requests {
+							q.Add(req)
+						}
+						queues <- q
+					},
+					synced: synced,
+				})).To(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+
+				var q workqueue.RateLimitingInterface
+				Eventually(queues).Should(Receive(&q))
+				Eventually(q.Len).Should(BeZero())
+				close(synced)
+				return cancel
+			}
+
+			BeforeEach(func() {
+				ctrlmetrics.WorkQueueDepth.Reset()
+				ctrlmetrics.WorkQueueLatency.Reset()
+				ctrl.Name = "prioritized"
+				ctrl.CacheSyncTimeout = 10 * time.Second
+				ctrl.RateLimiter = workqueue.DefaultControllerRateLimiter()
+				ctrl.Prioritize = func(req reconcile.Request) int {
+					switch req.Namespace {
+					case "high":
+						return 10
+					case "low":
+						return -10
+					default:
+						return 0
+					}
+				}
+				ctrl.Do = reconcile.Func(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+					reconciled <- req
+					return reconcile.Result{}, nil
+				})
+			})
+
+			It("should reconcile the requests of a higher priority band first", func() {
+				cancel := startWithQueued(low, normal, high)
+				defer cancel()
+
+				for _, expected := range []reconcile.Request{high, normal, low} {
+					var req reconcile.Request
+					Eventually(reconciled).Should(Receive(&req))
+					Expect(req).To(Equal(expected))
+				}
+			})
+
+			It("should report the depth and latency of the queue of every band by controller and priority", func() {
+				cancel := startWithQueued(low, normal, high)
+				defer cancel()
+				for i := 0; i < 3; i++ {
+					Eventually(reconciled).Should(Receive())
+				}
+
+				labelsOf := func(collector prometheus.Collector) []map[string]string {
+					metrics := make(chan prometheus.Metric, 10)
+					collector.Collect(metrics)
+					close(metrics)
+					var labels []map[string]string
+					for metric := range metrics {
+						m := &dto.Metric{}
+						Expect(metric.Write(m)).To(Succeed())
+						l := map[string]string{}
+						for _, pair := range m.GetLabel() {
+							l[pair.GetName()] = pair.GetValue()
+						}
+						labels = append(labels, l)
+					}
+					return labels
+				}
+				bands := []map[string]string{
+					{"controller": "prioritized", "priority": ctrlmetrics.HighPriority},
+					{"controller": "prioritized", "priority": ctrlmetrics.NormalPriority},
+					{"controller": "prioritized", "priority": ctrlmetrics.LowPriority},
+				}
+				Expect(labelsOf(ctrlmetrics.WorkQueueDepth)).To(ConsistOf(bands))
+				Expect(labelsOf(ctrlmetrics.WorkQueueLatency)).To(ConsistOf(bands))
+
+				for _, band := range ctrlmetrics.PriorityBands {
+					var depth dto.Metric
+					Expect(ctrlmetrics.WorkQueueDepth.WithLabelValues("prioritized", band).Write(&depth)).To(Succeed())
+					Expect(depth.GetGauge().GetValue()).To(BeZero())
+
+					var latency dto.Metric
+					Expect(ctrlmetrics.WorkQueueLatency.WithLabelValues("prioritized", band).(prometheus.Histogram).Write(&latency)).To(Succeed())
+					Expect(latency.GetHistogram().GetSampleCount()).To(Equal(uint64(1)))
+				}
+			})
+		})
+
+		Context("with LogReconcileSpans", func() {
+			var (
+				logsMu sync.Mutex
+				logs   []string
+			)
+
+			BeforeEach(func() {
+				logsMu.Lock()
+				logs = nil
+				logsMu.Unlock()
+				sink := funcr.New(func(prefix, args string) {
+					logsMu.Lock()
+					defer logsMu.Unlock()
+					logs = append(logs, args)
+				}, funcr.Options{Verbosity: 1})
+				ctrl.LogReconcileSpans = true
+				ctrl.LogConstructor = func(req *reconcile.Request) logr.Logger {
+					if req != nil {
+						return sink.WithValues("name", req.Name)
+					}
+					return sink
+				}
+			})
+
+			spans := func() []string {
+				logsMu.Lock()
+				defer logsMu.Unlock()
+				var res []string
+				for _, l := range logs {
+					if strings.Contains(l, `"msg"="Reconcile started"`) || strings.Contains(l, `"msg"="Reconcile finished"`) {
+						res = append(res, l)
+					}
+				}
+				return res
+			}
+
+			It("should log the start and end of every reconcile with the request, duration and result", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+
+				queue.Add(request)
+				fakeReconcile.AddResult(reconcile.Result{RequeueAfter: time.Hour}, nil)
+				Expect(<-reconciled).To(Equal(request))
+
+				// The test queue adds requeues immediately, so the request may be reconciled again meanwhile.
+				Eventually(spans).Should(ContainElement(ContainSubstring(`"msg"="Reconcile finished"`)))
+				Expect(spans()[0]).To(And(ContainSubstring(`"msg"="Reconcile started"`), ContainSubstring(`"name"="bar"`)))
+				Expect(spans()[1]).To(And(
+					ContainSubstring(`"msg"="Reconcile finished"`),
+					ContainSubstring(`"name"="bar"`),
+					ContainSubstring(`"duration"=`),
+					ContainSubstring(`"requeue"=false`),
+					ContainSubstring(`"requeueAfter"="1h0m0s"`),
+					ContainSubstring(`"error"=null`),
+				))
+			})
+
+			It("should log the error a reconcile failed with", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+
+				queue.Add(request)
+				fakeReconcile.AddResult(reconcile.Result{}, errors.New("expected error"))
+				Expect(<-reconciled).To(Equal(request))
+
+				Eventually(spans).Should(ContainElement(And(
+					ContainSubstring(`"msg"="Reconcile finished"`),
+					ContainSubstring(`"error"="expected error"`),
+				)))
+			})
+
+			It("should not log spans at the default verbosity", func() {
+				ctrl.LogConstructor = func(*reconcile.Request) logr.Logger {
+					return funcr.New(func(prefix, args string) {
+						logsMu.Lock()
+						defer logsMu.Unlock()
+						logs = append(logs, args)
+					}, funcr.Options{})
+				}
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+
+				queue.Add(request)
+				fakeReconcile.AddResult(reconcile.Result{}, nil)
+				Expect(<-reconciled).To(Equal(request))
+
+				Consistently(spans).Should(BeEmpty())
+			})
+		})
+
+		Context("with TrackCausality", func() {
+			newController := func(name string, do reconcile.Reconciler) workqueue.RateLimitingInterface {
+				c := &Controller[reconcile.Request]{
+					Name:                    name,
+					MaxConcurrentReconciles: 1,
+					Do:                      do,
+					NewQueue: func(string, ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
+						return workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+					},
+					LogConstructor: func(_ *reconcile.Request) logr.Logger {
+						return log.RuntimeLog.WithName("controller").WithName(name)
+					},
+					TrackCausality: true,
+				}
+				queues := make(chan workqueue.RateLimitingInterface, 1)
+				Expect(c.Watch(source.Func(func(_ context.Context, q workqueue.RateLimitingInterface) error {
+					queues <- q
+					return nil
+				}))).To(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				DeferCleanup(cancel)
+				go func() {
+					defer GinkgoRecover()
+					Expect(c.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				return <-queues
+			}
+
+			It("should give the Reconciler the chain of reconciles that led to it across two hops", func() {
+				cl := causality.NewClient(fake.NewClientBuilder().Build())
+				chains := make(chan causality.Chain, 1)
+
+				By("Creating the downstream controller c, which records its chain")
+				qc := newController("c", reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+					chains <- causality.FromContext(ctx)
+					return reconcile.Result{}, nil
+				}))
+
+				By("Creating controller b, which creates z for every y it reconciles")
+				qb := newController("b", reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+					z := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: req.Namespace, Name: "z"}}
+					if err := cl.Create(ctx, z); err != nil {
+						return reconcile.Result{}, err
+					}
+					(&handler.EnqueueRequestForObject{}).Create(ctx, event.CreateEvent{Object: z}, qc)
+					return reconcile.Result{}, nil
+				}))
+
+				By("Creating y as if from a reconcile of x by controller a")
+				ctx := causality.IntoContext(context.Background(), causality.Chain{{Controller: "a", Key: "foo/x"}})
+				y := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "y"}}
+				Expect(cl.Create(ctx, y)).To(Succeed())
+				(&handler.EnqueueRequestForObject{}).Create(ctx, event.CreateEvent{Object: y}, qb)
+
+				var chain causality.Chain
+				Eventually(chains).Should(Receive(&chain))
+				Expect(chain).To(Equal(causality.Chain{
+					{Controller: "a", Key: "foo/x"},
+					{Controller: "b", Key: "foo/y"},
+					{Controller: "c", Key: "foo/z"},
+				}))
+			})
+
+			It("should start a new chain for a requeue", func() {
+				chains := make(chan causality.Chain, 1)
+				q := newController("a", reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+					chains <- causality.FromContext(ctx)
+					return reconcile.Result{}, nil
+				}))
+
+				q.Add(request)
+				Eventually(chains).Should(Receive(Equal(causality.Chain{{Controller: "a", Key: "foo/bar"}})))
+			})
+		})
+
+		Context("with RecordChangedChildren", func() {
+			It("should give the Reconciler the children of all events coalesced into one owner reconcile", func() {
+				mapper := meta.NewDefaultRESTMapper(nil)
+				mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+				ownerHandler := handler.EnqueueRequestForOwner(scheme.Scheme, mapper, &appsv1.Deployment{})
+
+				owner := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar", UID: "uid"}}
+				newChild := func(name string) *corev1.Pod {
+					pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: name}}
+					Expect(controllerutil.SetOwnerReference(owner, pod, scheme.Scheme)).To(Succeed())
+					return pod
+				}
+				podKey := func(name string) reconcile.ChildKey {
+					return reconcile.ChildKey{
+						GroupKind:      schema.GroupKind{Kind: "Pod"},
+						NamespacedName: types.NamespacedName{Namespace: "foo", Name: name},
+					}
+				}
+
+				changedChildren := make(chan []reconcile.ChildKey, 2)
+				c := &Controller[reconcile.Request]{
+					Name:                    "owner",
+					MaxConcurrentReconciles: 1,
+					Do: reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+						changedChildren <- reconcile.ChangedChildren(ctx)
+						return reconcile.Result{}, nil
+					}),
+					NewQueue: func(string, ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
+						return workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+					},
+					LogConstructor: func(_ *reconcile.Request) logr.Logger {
+						return log.RuntimeLog.WithName("controller").WithName("owner")
+					},
+					RecordChangedChildren: true,
+				}
+
+				By("Adding events for multiple children before the workers start")
+				queues := make(chan workqueue.RateLimitingInterface, 1)
+				Expect(c.Watch(source.Func(func(ctx context.Context, q workqueue.RateLimitingInterface) error {
+					ownerHandler.Create(ctx, event.CreateEvent{Object: newChild("b")}, q)
+					ownerHandler.Update(ctx, event.UpdateEvent{ObjectOld: newChild("a"), ObjectNew: newChild("a")}, q)
+					ownerHandler.Delete(ctx, event.DeleteEvent{Object: newChild("c")}, q)
+					ownerHandler.Update(ctx, event.UpdateEvent{ObjectOld: newChild("b"), ObjectNew: newChild("b")}, q)
+					queues <- q
+					return nil
+				}))).To(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(c.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				q := <-queues
+
+				Eventually(changedChildren).Should(Receive(Equal([]reconcile.ChildKey{podKey("a"), podKey("b"), podKey("c")})))
+				Consistently(changedChildren).ShouldNot(Receive())
+
+				By("Adding an event for another child after the owner was reconciled")
+				ownerHandler.Create(ctx, event.CreateEvent{Object: newChild("d")}, q)
+				Eventually(changedChildren).Should(Receive(Equal([]reconcile.ChildKey{podKey("d")})))
+			})
+		})
+
 		It("should perform error behavior if error is not nil, regardless of RequeueAfter", func() {
 			dq := &DelegatingQueue{RateLimitingInterface: ctrl.NewQueue("controller1", nil)}
 			ctrl.NewQueue = func(string, ratelimiter.RateLimiter) workqueue.RateLimitingInterface { return dq }
@@ -763,6 +1699,49 @@ var _ = Describe("ReconcileIDFromContext function", func() {
 	})
 })
 
+var _ = Describe("controller with TypedRequests", func() {
+	type externalID struct {
+		Provider string
+		ID       int
+	}
+
+	It("should reconcile the struct keys enqueued by a typed handler", func() {
+		reconciled := make(chan reconcile.TypedRequest[externalID])
+		ctrl := &Controller[reconcile.TypedRequest[externalID]]{
+			MaxConcurrentReconciles: 1,
+			Do: reconcile.TypedFunc[externalID](func(_ context.Context, req reconcile.TypedRequest[externalID]) (reconcile.Result, error) {
+				reconciled <- req
+				return reconcile.Result{}, nil
+			}),
+			NewQueue: func(string, ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
+				return workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			},
+			LogConstructor: func(_ *reconcile.TypedRequest[externalID]) logr.Logger {
+				return log.RuntimeLog.WithName("controller").WithName("test")
+			},
+		}
+
+		events := make(chan event.TypedGenericEvent[*corev1.Pod], 1)
+		Expect(ctrl.Watch(source.Channel(events, handler.EnqueueTypedRequestsFromMapFunc(
+			func(_ context.Context, pod *corev1.Pod) []reconcile.TypedRequest[externalID] {
+				return []reconcile.TypedRequest[externalID]{{Key: externalID{Provider: pod.Labels["provider"], ID: 42}}}
+			},
+		)))).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(ctrl.Start(ctx)).To(Succeed())
+		}()
+
+		events <- event.TypedGenericEvent[*corev1.Pod]{Object: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod", Labels: map[string]string{"provider": "acme"}},
+		}}
+		Eventually(reconciled).Should(Receive(Equal(reconcile.TypedRequest[externalID]{Key: externalID{Provider: "acme", ID: 42}})))
+	})
+})
+
 type DelegatingQueue struct {
 	workqueue.RateLimitingInterface
 	mu sync.Mutex
@@ -770,6 +1749,7 @@ type DelegatingQueue struct {
 	countAddRateLimited int
 	countAdd            int
 	countAddAfter       int
+	lastAddAfter        time.Duration
 }
 
 func (q *DelegatingQueue) AddRateLimited(item interface{}) {
@@ -785,9 +1765,17 @@ func (q *DelegatingQueue) AddAfter(item interface{}, d time.Duration) {
 	defer q.mu.Unlock()
 
 	q.countAddAfter++
+	q.lastAddAfter = d
 	q.RateLimitingInterface.AddAfter(item, d)
 }
 
+func (q *DelegatingQueue) getLastAddAfter() time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.lastAddAfter
+}
+
 func (q *DelegatingQueue) Add(item interface{}) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -820,8 +1808,9 @@ func (q *DelegatingQueue) getCounts() countInfo {
 }
 
 type fakeReconcileResultPair struct {
-	Result reconcile.Result
-	Err    error
+	Result    reconcile.Result
+	Err       error
+	FollowUps []reconcile.Request
 }
 
 type fakeReconciler struct {
@@ -833,14 +1822,68 @@ func (f *fakeReconciler) AddResult(res reconcile.Result, err error) {
 	f.results <- fakeReconcileResultPair{Result: res, Err: err}
 }
 
-func (f *fakeReconciler) Reconcile(_ context.Context, r reconcile.Request) (reconcile.Result, error) {
+// AddResultWithFollowUps is AddResult for a reconcile that also enqueues followUps.
+func (f *fakeReconciler) AddResultWithFollowUps(res reconcile.Result, err error, followUps ...reconcile.Request) {
+	f.results <- fakeReconcileResultPair{Result: res, Err: err, FollowUps: followUps}
+}
+
+func (f *fakeReconciler) Reconcile(ctx context.Context, r reconcile.Request) (reconcile.Result, error) {
 	res := <-f.results
+	if len(res.FollowUps) > 0 {
+		reconcile.EnqueueFollowUps(ctx, res.FollowUps...)
+	}
 	if f.Requests != nil {
 		f.Requests <- r
 	}
 	return res.Result, res.Err
 }
 
+// blockingSyncSource is a SyncingSource that calls start when started and
+// is synced once synced is closed.
+type blockingSyncSource struct {
+	start  func(workqueue.RateLimitingInterface)
+	synced chan struct{}
+}
+
+func (s *blockingSyncSource) Start(_ context.Context, q workqueue.RateLimitingInterface) error {
+	s.start(q)
+	return nil
+}
+
+func (s *blockingSyncSource) WaitForSync(ctx context.Context) error {
+	select {
+	case <-s.synced:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fakeRateLimiter delays every item by delay and records them.
+type fakeRateLimiter struct {
+	delay time.Duration
+
+	mu    sync.Mutex
+	items []interface{}
+}
+
+func (r *fakeRateLimiter) When(item interface{}) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items = append(r.items, item)
+	return r.delay
+}
+
+func (r *fakeRateLimiter) Forget(interface{}) {}
+
+func (r *fakeRateLimiter) NumRequeues(interface{}) int { return 0 }
+
+func (r *fakeRateLimiter) requested() []interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]interface{}(nil), r.items...)
+}
+
 type singnallingSourceWrapper struct {
 	cacheSyncDone chan struct{}
 	source.SyncingSource
@@ -68,8 +68,60 @@ var (
 		Name: "controller_runtime_active_workers",
 		Help: "Number of currently used workers per controller",
 	}, []string{"controller"})
+
+	// PredicateEvaluationsTotal is a prometheus counter metrics which holds the total
+	// number of predicate evaluations per controller. It has three labels. controller label
+	// refers to the controller name, event label refers to the event type i.e create, update,
+	// delete, generic and result label refers to the outcome i.e accepted, rejected.
+	PredicateEvaluationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_predicate_evaluations_total",
+		Help: "Total number of events accepted or rejected by predicates per controller",
+	}, []string{"controller", "event", "result"})
+
+	// WorkQueueDepth is a prometheus metric which holds the current depth of the workqueue
+	// of every priority band of the controllers that use a priority queue. It has two labels.
+	// controller label refers to the controller name and priority label refers to the priority
+	// band i.e high, normal, low.
+	WorkQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_runtime_workqueue_depth",
+		Help: "Current depth of the workqueue per controller and priority band",
+	}, []string{"controller", "priority"})
+
+	// WorkQueueLatency is a prometheus metric which keeps track of how long requests stay in
+	// the workqueue of every priority band of the controllers that use a priority queue, with
+	// the same labels as WorkQueueDepth.
+	WorkQueueLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "controller_runtime_workqueue_queue_duration_seconds",
+		Help:    "How long in seconds a request stays in the workqueue before being requested per controller and priority band",
+		Buckets: prometheus.ExponentialBuckets(10e-9, 10, 12),
+	}, []string{"controller", "priority"})
+)
+
+// The priority bands of the priority queue of a controller, which are the values of the
+// priority label of WorkQueueDepth and WorkQueueLatency.
+const (
+	HighPriority   = "high"
+	NormalPriority = "normal"
+	LowPriority    = "low"
 )
 
+// PriorityBands are the priority bands, from the highest to the lowest. Their number is fixed,
+// so that the cardinality of the priority label stays bounded whatever the priorities are.
+var PriorityBands = [...]string{HighPriority, NormalPriority, LowPriority}
+
+// PriorityBand returns the priority band of priority: positive priorities are high, negative
+// ones low and 0 is normal.
+func PriorityBand(priority int) string {
+	switch {
+	case priority > 0:
+		return HighPriority
+	case priority < 0:
+		return LowPriority
+	default:
+		return NormalPriority
+	}
+}
+
 func init() {
 	metrics.Registry.MustRegister(
 		ReconcileTotal,
@@ -78,6 +130,9 @@ func init() {
 		ReconcileTime,
 		WorkerCount,
 		ActiveWorkers,
+		PredicateEvaluationsTotal,
+		WorkQueueDepth,
+		WorkQueueLatency,
 		// expose process metrics like CPU, Memory, file descriptor usage etc.
 		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
 		// expose Go runtime metrics like GC stats, memory stats etc.
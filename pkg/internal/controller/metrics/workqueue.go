@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"math"
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// NewPriorityQueueMetrics returns a func that returns the workqueue.MetricsProvider of the queue
+// of a priority band of the priority queue of controller. The queues of all bands report their
+// metrics together under the name of the controller, like any other workqueue, and additionally
+// their depth and latency by priority band in WorkQueueDepth and WorkQueueLatency.
+func NewPriorityQueueMetrics(controller string) func(band string) workqueue.MetricsProvider {
+	provider := metrics.NewWorkQueueMetricsProvider(nil, nil)
+	m := &priorityQueueMetrics{
+		controller: controller,
+		provider:   provider,
+		unfinished: &combinedGauge{
+			gauge:   provider.NewUnfinishedWorkSecondsMetric(controller),
+			combine: func(a, b float64) float64 { return a + b },
+			values:  map[string]float64{},
+		},
+		longestRunningProcessor: &combinedGauge{
+			gauge:   provider.NewLongestRunningProcessorSecondsMetric(controller),
+			combine: math.Max,
+			values:  map[string]float64{},
+		},
+	}
+	return func(band string) workqueue.MetricsProvider {
+		return bandMetricsProvider{priorityQueueMetrics: m, band: band}
+	}
+}
+
+type priorityQueueMetrics struct {
+	controller string
+	provider   workqueue.MetricsProvider
+
+	// The gauges that the queues set periodically are shared by all bands, so that their
+	// values don't overwrite each other.
+	unfinished              *combinedGauge
+	longestRunningProcessor *combinedGauge
+}
+
+type bandMetricsProvider struct {
+	*priorityQueueMetrics
+	band string
+}
+
+func (p bandMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return gauges{p.provider.NewDepthMetric(name), WorkQueueDepth.WithLabelValues(p.controller, p.band)}
+}
+
+func (p bandMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return p.provider.NewAddsMetric(name)
+}
+
+func (p bandMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return histograms{p.provider.NewLatencyMetric(name), WorkQueueLatency.WithLabelValues(p.controller, p.band)}
+}
+
+func (p bandMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return p.provider.NewWorkDurationMetric(name)
+}
+
+func (p bandMetricsProvider) NewUnfinishedWorkSecondsMetric(string) workqueue.SettableGaugeMetric {
+	return bandGauge{combinedGauge: p.unfinished, band: p.band}
+}
+
+func (p bandMetricsProvider) NewLongestRunningProcessorSecondsMetric(string) workqueue.SettableGaugeMetric {
+	return bandGauge{combinedGauge: p.longestRunningProcessor, band: p.band}
+}
+
+func (p bandMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return p.provider.NewRetriesMetric(name)
+}
+
+// gauges updates all of its gauges together.
+type gauges []workqueue.GaugeMetric
+
+func (g gauges) Inc() {
+	for _, gauge := range g {
+		gauge.Inc()
+	}
+}
+
+func (g gauges) Dec() {
+	for _, gauge := range g {
+		gauge.Dec()
+	}
+}
+
+// histograms observes with all of its histograms together.
+type histograms []workqueue.HistogramMetric
+
+func (h histograms) Observe(value float64) {
+	for _, histogram := range h {
+		histogram.Observe(value)
+	}
+}
+
+// combinedGauge sets gauge to the combination of the values last set for every band.
+type combinedGauge struct {
+	gauge   workqueue.SettableGaugeMetric
+	combine func(a, b float64) float64
+
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// bandGauge sets the value of its band of a combinedGauge.
+type bandGauge struct {
+	*combinedGauge
+	band string
+}
+
+func (g bandGauge) Set(value float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.values[g.band] = value
+	combined := 0.0
+	for _, value := range g.values {
+		combined = g.combine(combined, value)
+	}
+	g.gauge.Set(combined)
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leaderelection
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/leaderelection"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
+)
+
+var log = logf.RuntimeLog.WithName("leader-election")
+
+// Backend is a lock held by the leader that is kept outside of Kubernetes, e.g. in etcd
+// or Consul. It allows running leader election without a Kubernetes resource lock, e.g.
+// for managers that run outside of a cluster.
+//
+// Implementations must be safe for concurrent use. Each candidate uses its own Backend,
+// which knows the identity of the candidate.
+type Backend interface {
+	// Acquire tries to acquire the lock for leaseDuration. It returns true if the lock
+	// is held by the candidate afterwards, and false if another candidate holds it.
+	// It must not block until the lock becomes available.
+	Acquire(ctx context.Context, leaseDuration time.Duration) (bool, error)
+
+	// Renew extends the lock held by the candidate by leaseDuration. It returns false
+	// if the candidate doesn't hold the lock anymore.
+	Renew(ctx context.Context, leaseDuration time.Duration) (bool, error)
+
+	// Release releases the lock if it is held by the candidate.
+	Release(ctx context.Context) error
+}
+
+// BackendConfig configures RunWithBackend.
+type BackendConfig struct {
+	// LeaseDuration is the duration the lock is acquired or renewed for.
+	LeaseDuration time.Duration
+
+	// RenewDeadline is the duration the leader retries renewing the lock for
+	// before giving up leadership.
+	RenewDeadline time.Duration
+
+	// RetryPeriod is the duration to wait between attempts to acquire or renew the lock.
+	RetryPeriod time.Duration
+
+	// ReleaseOnCancel releases the lock when ctx is done.
+	ReleaseOnCancel bool
+
+	// Callbacks are called when the candidate starts and stops leading.
+	// OnNewLeader is not supported, as a Backend doesn't expose the identity of the leader.
+	Callbacks leaderelection.LeaderCallbacks
+}
+
+// RunWithBackend runs leader election with backend until ctx is done or leadership is
+// lost, like a client-go LeaderElector does with a Kubernetes resource lock. The
+// OnStartedLeading callback is called in a new goroutine with a context that is
+// cancelled once leadership is lost, and OnStoppedLeading is always called on return.
+func RunWithBackend(ctx context.Context, backend Backend, config BackendConfig) {
+	defer func() {
+		if config.Callbacks.OnStoppedLeading != nil {
+			config.Callbacks.OnStoppedLeading()
+		}
+	}()
+
+	if !acquire(ctx, backend, config) {
+		return
+	}
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if config.Callbacks.OnStartedLeading != nil {
+		go config.Callbacks.OnStartedLeading(leaderCtx)
+	}
+
+	renew(ctx, backend, config)
+
+	if config.ReleaseOnCancel && ctx.Err() != nil {
+		releaseCtx, cancel := context.WithTimeout(context.Background(), config.RenewDeadline)
+		defer cancel()
+		if err := backend.Release(releaseCtx); err != nil {
+			log.Error(err, "Failed to release leader election lock")
+		}
+	}
+}
+
+// acquire tries to acquire the lock every RetryPeriod until it succeeds or ctx is done.
+func acquire(ctx context.Context, backend Backend, config BackendConfig) bool {
+	for {
+		acquired, err := backend.Acquire(ctx, config.LeaseDuration)
+		if err != nil {
+			log.Error(err, "Failed to acquire leader election lock")
+		} else if acquired {
+			log.Info("Successfully acquired leader election lock")
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(wait.Jitter(config.RetryPeriod, 1.2)):
+		}
+	}
+}
+
+// renew renews the lock every RetryPeriod until ctx is done, another candidate holds
+// the lock, or renewing it failed for longer than RenewDeadline.
+func renew(ctx context.Context, backend Backend, config BackendConfig) {
+	lastRenewal := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(config.RetryPeriod):
+		}
+
+		renewCtx, cancel := context.WithTimeout(ctx, config.RenewDeadline)
+		renewed, err := backend.Renew(renewCtx, config.LeaseDuration)
+		cancel()
+		switch {
+		case err != nil:
+			log.Error(err, "Failed to renew leader election lock")
+		case !renewed:
+			log.Info("Leader election lock is held by another candidate")
+			return
+		default:
+			lastRenewal = time.Now()
+			continue
+		}
+
+		if time.Since(lastRenewal) > config.RenewDeadline {
+			log.Info("Failed to renew leader election lock within the renew deadline")
+			return
+		}
+	}
+}
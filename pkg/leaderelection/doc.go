@@ -19,6 +19,7 @@ Package leaderelection contains a constructor for a leader election resource loc
 This is used to ensure that multiple copies of a controller manager can be run with
 only one active set of controllers, for active-passive HA.
 
-It uses built-in Kubernetes leader election APIs.
+It uses built-in Kubernetes leader election APIs by default. A Backend allows
+keeping the lock elsewhere, e.g. in etcd or Consul, see RunWithBackend.
 */
 package leaderelection
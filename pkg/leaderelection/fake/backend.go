@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package fake
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/leaderelection"
+)
+
+// Lock is an in-memory lock that multiple candidates can contend for
+// through the Backends it returns.
+type Lock struct {
+	mu      sync.Mutex
+	holder  string
+	expires time.Time
+}
+
+// Holder returns the identity of the candidate holding the lock, or an
+// empty string if it is not held.
+func (l *Lock) Holder() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if time.Now().After(l.expires) {
+		return ""
+	}
+	return l.holder
+}
+
+// Backend returns a leaderelection.Backend for the candidate with the given identity.
+func (l *Lock) Backend(identity string) leaderelection.Backend {
+	return &backend{lock: l, identity: identity}
+}
+
+type backend struct {
+	lock     *Lock
+	identity string
+}
+
+// Acquire implements leaderelection.Backend.
+func (b *backend) Acquire(_ context.Context, leaseDuration time.Duration) (bool, error) {
+	b.lock.mu.Lock()
+	defer b.lock.mu.Unlock()
+	if b.lock.holder != b.identity && time.Now().Before(b.lock.expires) {
+		return false, nil
+	}
+	b.lock.holder = b.identity
+	b.lock.expires = time.Now().Add(leaseDuration)
+	return true, nil
+}
+
+// Renew implements leaderelection.Backend.
+func (b *backend) Renew(_ context.Context, leaseDuration time.Duration) (bool, error) {
+	b.lock.mu.Lock()
+	defer b.lock.mu.Unlock()
+	if b.lock.holder != b.identity {
+		return false, nil
+	}
+	b.lock.expires = time.Now().Add(leaseDuration)
+	return true, nil
+}
+
+// Release implements leaderelection.Backend.
+func (b *backend) Release(context.Context) error {
+	b.lock.mu.Lock()
+	defer b.lock.mu.Unlock()
+	if b.lock.holder == b.identity {
+		b.lock.holder = ""
+		b.lock.expires = time.Time{}
+	}
+	return nil
+}
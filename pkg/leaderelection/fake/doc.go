@@ -17,5 +17,7 @@ limitations under the License.
 /*
 Package fake mocks a resource lock for testing purposes.
 Always returns leadership.
+
+It also provides an in-memory Lock whose Backends contend for leadership.
 */
 package fake
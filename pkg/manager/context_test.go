@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+type baseContextKey struct{}
+
+func TestRunnablesGetSchemeAndRESTMapperFromContext(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	mapper := meta.NewDefaultRESTMapper(nil)
+	m, err := New(&rest.Config{Host: "http://127.0.0.1:1"}, Options{
+		Scheme:  scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		MapperProvider: func(*rest.Config, *http.Client) (meta.RESTMapper, error) {
+			return mapper, nil
+		},
+		BaseContext: func() context.Context {
+			return context.WithValue(context.Background(), baseContextKey{}, "base")
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	runnableCtxs := make(chan context.Context, 1)
+	g.Expect(m.Add(RunnableFunc(func(ctx context.Context) error {
+		runnableCtxs <- ctx
+		<-ctx.Done()
+		return nil
+	}))).To(Succeed())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- m.Start(ctx)
+	}()
+	defer func() {
+		cancel()
+		g.Expect(<-done).To(Succeed())
+	}()
+
+	var runnableCtx context.Context
+	g.Eventually(runnableCtxs).Should(Receive(&runnableCtx))
+	g.Expect(runnableCtx.Value(baseContextKey{})).To(Equal("base"))
+
+	got, ok := client.SchemeFromContext(runnableCtx)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(got).To(BeIdenticalTo(scheme))
+
+	gotMapper, ok := client.RESTMapperFromContext(runnableCtx)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(gotMapper).To(BeIdenticalTo(mapper))
+}
@@ -23,14 +23,18 @@ import (
 	"net"
 	"net/http"
 	"net/http/pprof"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
@@ -43,7 +47,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/internal/httpserver"
 	intrec "sigs.k8s.io/controller-runtime/pkg/internal/recorder"
+	crleaderelection "sigs.k8s.io/controller-runtime/pkg/leaderelection"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/recorder"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
@@ -75,9 +81,15 @@ type controllerManager struct {
 	// (and EventHandlers, Sources and Predicates).
 	recorderProvider *intrec.Provider
 
+	// rateLimitedRecorders, if set, provides the event recorders returned by GetEventRecorderFor.
+	rateLimitedRecorders recorder.Provider
+
 	// resourceLock forms the basis for leader election
 	resourceLock resourcelock.Interface
 
+	// leaderElectionBackend is used for leader election instead of resourceLock if set.
+	leaderElectionBackend crleaderelection.Backend
+
 	// leaderElectionReleaseOnCancel defines if the manager should step back from the leader lease
 	// on shutdown
 	leaderElectionReleaseOnCancel bool
@@ -103,6 +115,12 @@ type controllerManager struct {
 	// pprofListener is used to serve pprof
 	pprofListener net.Listener
 
+	// serveOnWebhookServer serves the metrics, and the health probes and pprof if
+	// serveHealthProbes and servePprof are set, on the webhook server.
+	serveOnWebhookServer bool
+	serveHealthProbes    bool
+	servePprof           bool
+
 	// controllerConfig are the global controller options.
 	controllerConfig config.Controller
 
@@ -161,6 +179,13 @@ type controllerManager struct {
 	// internalProceduresStop channel is used internally to the manager when coordinating
 	// the proper shutdown of servers. This channel is also used for dependency injection.
 	internalProceduresStop chan struct{}
+
+	// controllers are the added controllers by name, and controllerDependencies the names of
+	// the controllers each of them depends on, see AddControllerDependency. They are guarded
+	// by controllersMu rather than the manager lock, as the latter is held while starting.
+	controllers            map[string]dependableController
+	controllerDependencies map[string][]string
+	controllersMu          sync.Mutex
 }
 
 type hasCache interface {
@@ -168,6 +193,16 @@ type hasCache interface {
 	GetCache() cache.Cache
 }
 
+// dependableController is a controller, e.g. one created with controller.New, that can
+// depend on other controllers, see AddControllerDependency.
+type dependableController interface {
+	Runnable
+	GetName() string
+	SetStartGate(gate func(context.Context) error)
+	TrackInitialPass()
+	WaitForInitialPass(ctx context.Context) bool
+}
+
 // Add sets dependencies on i, and adds it to the list of Runnables to start.
 func (cm *controllerManager) Add(r Runnable) error {
 	cm.Lock()
@@ -176,9 +211,121 @@ func (cm *controllerManager) Add(r Runnable) error {
 }
 
 func (cm *controllerManager) add(r Runnable) error {
+	if ctrl, ok := r.(dependableController); ok {
+		name := ctrl.GetName()
+		cm.controllersMu.Lock()
+		if cm.controllers == nil {
+			cm.controllers = map[string]dependableController{}
+		}
+		cm.controllers[name] = ctrl
+		cm.controllersMu.Unlock()
+		ctrl.SetStartGate(func(ctx context.Context) error {
+			return cm.waitForControllerDependencies(ctx, name)
+		})
+	}
 	return cm.runnables.Add(r)
 }
 
+// AddControllerDependency declares that the controller dependent starts after dependsOn completed its initial pass.
+func (cm *controllerManager) AddControllerDependency(dependent, dependsOn string) error {
+	cm.Lock()
+	defer cm.Unlock()
+	if cm.started {
+		return fmt.Errorf("unable to add controller dependency because the manager has already been started")
+	}
+
+	cm.controllersMu.Lock()
+	defer cm.controllersMu.Unlock()
+	if path := cm.controllerDependencyPathLocked(dependsOn, dependent); path != nil {
+		return fmt.Errorf("controller %q can't depend on controller %q, as that would create the dependency cycle %s",
+			dependent, dependsOn, strings.Join(append([]string{dependent}, path...), " -> "))
+	}
+	if slices.Contains(cm.controllerDependencies[dependent], dependsOn) {
+		return nil
+	}
+	if cm.controllerDependencies == nil {
+		cm.controllerDependencies = map[string][]string{}
+	}
+	cm.controllerDependencies[dependent] = append(cm.controllerDependencies[dependent], dependsOn)
+	return nil
+}
+
+// controllerDependencyPathLocked returns the names of the controllers from one to another
+// following their dependencies, or nil if another isn't reachable from one.
+func (cm *controllerManager) controllerDependencyPathLocked(one, another string) []string {
+	if one == another {
+		return []string{one}
+	}
+	visited := sets.New[string]()
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		if name == another {
+			return []string{name}
+		}
+		if visited.Has(name) {
+			return nil
+		}
+		visited.Insert(name)
+		for _, dep := range cm.controllerDependencies[name] {
+			if path := visit(dep); path != nil {
+				return append([]string{name}, path...)
+			}
+		}
+		return nil
+	}
+	return visit(one)
+}
+
+// checkControllerDependencies errors out if a controller dependency refers to a controller
+// that wasn't added to the manager, and makes the controllers others depend on track their
+// initial pass.
+func (cm *controllerManager) checkControllerDependencies() error {
+	cm.controllersMu.Lock()
+	defer cm.controllersMu.Unlock()
+	for _, dependent := range sets.List(sets.KeySet(cm.controllerDependencies)) {
+		if _, ok := cm.controllers[dependent]; !ok {
+			return fmt.Errorf("controller %q has dependencies, but wasn't added to the manager", dependent)
+		}
+		for _, dependsOn := range cm.controllerDependencies[dependent] {
+			ctrl, ok := cm.controllers[dependsOn]
+			if !ok {
+				return fmt.Errorf("controller %q depends on controller %q, which wasn't added to the manager", dependent, dependsOn)
+			}
+			ctrl.TrackInitialPass()
+		}
+	}
+	return nil
+}
+
+// waitForControllerDependencies blocks until the controllers the controller name depends on
+// completed their initial pass, or ctx is done.
+func (cm *controllerManager) waitForControllerDependencies(ctx context.Context, name string) error {
+	cm.controllersMu.Lock()
+	names := slices.Clone(cm.controllerDependencies[name])
+	deps := make([]dependableController, 0, len(names))
+	for _, dependsOn := range names {
+		ctrl, ok := cm.controllers[dependsOn]
+		if !ok {
+			cm.controllersMu.Unlock()
+			return fmt.Errorf("controller %q depends on controller %q, which wasn't added to the manager", name, dependsOn)
+		}
+		deps = append(deps, ctrl)
+	}
+	cm.controllersMu.Unlock()
+
+	if len(deps) == 0 {
+		return nil
+	}
+	cm.logger.Info("Waiting for controllers to complete their initial pass", "controller", name, "dependencies", names)
+	for _, dep := range deps {
+		// Stop waiting once ctx is done, the controller doesn't start then anyway.
+		if !dep.WaitForInitialPass(ctx) {
+			break
+		}
+	}
+	return nil
+}
+
 // AddMetricsServerExtraHandler adds extra handler served on path to the http server that serves metrics.
 func (cm *controllerManager) AddMetricsServerExtraHandler(path string, handler http.Handler) error {
 	cm.Lock()
@@ -256,6 +403,9 @@ func (cm *controllerManager) GetCache() cache.Cache {
 }
 
 func (cm *controllerManager) GetEventRecorderFor(name string) record.EventRecorder {
+	if cm.rateLimitedRecorders != nil {
+		return cm.rateLimitedRecorders.GetEventRecorderFor(name)
+	}
 	return cm.cluster.GetEventRecorderFor(name)
 }
 
@@ -290,7 +440,16 @@ func (cm *controllerManager) GetControllerOptions() config.Controller {
 func (cm *controllerManager) addHealthProbeServer() error {
 	mux := http.NewServeMux()
 	srv := httpserver.New(mux)
+	cm.registerHealthProbes(mux)
 
+	return cm.add(&Server{
+		Name:     "health probe",
+		Server:   srv,
+		Listener: cm.healthProbeListener,
+	})
+}
+
+func (cm *controllerManager) registerHealthProbes(mux *http.ServeMux) {
 	if cm.readyzHandler != nil {
 		mux.Handle(cm.readinessEndpointName, http.StripPrefix(cm.readinessEndpointName, cm.readyzHandler))
 		// Append '/' suffix to handle subpaths
@@ -301,29 +460,48 @@ func (cm *controllerManager) addHealthProbeServer() error {
 		// Append '/' suffix to handle subpaths
 		mux.Handle(cm.livenessEndpointName+"/", http.StripPrefix(cm.livenessEndpointName, cm.healthzHandler))
 	}
-
-	return cm.add(&Server{
-		Name:     "health probe",
-		Server:   srv,
-		Listener: cm.healthProbeListener,
-	})
 }
 
 func (cm *controllerManager) addPprofServer() error {
 	mux := http.NewServeMux()
 	srv := httpserver.New(mux)
+	registerPprof(mux)
 
+	return cm.add(&Server{
+		Name:     "pprof",
+		Server:   srv,
+		Listener: cm.pprofListener,
+	})
+}
+
+func registerPprof(mux *http.ServeMux) {
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
 	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
 	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
 	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
 
-	return cm.add(&Server{
-		Name:     "pprof",
-		Server:   srv,
-		Listener: cm.pprofListener,
-	})
+// addHandlersToWebhookServer serves the metrics, health probes and pprof on the webhook server
+// rather than on their own servers.
+func (cm *controllerManager) addHandlersToWebhookServer() error {
+	mux := cm.GetWebhookServer().WebhookMux()
+	if cm.metricsServer != nil {
+		handlers, err := metricsserver.Handlers(cm.metricsServer)
+		if err != nil {
+			return err
+		}
+		for path, handler := range handlers {
+			mux.Handle(path, handler)
+		}
+	}
+	if cm.serveHealthProbes {
+		cm.registerHealthProbes(mux)
+	}
+	if cm.servePprof {
+		registerPprof(mux)
+	}
+	return nil
 }
 
 // Start starts the manager and waits indefinitely.
@@ -348,6 +526,10 @@ func (cm *controllerManager) Start(ctx context.Context) (err error) {
 		}
 	}()
 
+	if err := cm.checkControllerDependencies(); err != nil {
+		return err
+	}
+
 	// Initialize the internal context.
 	cm.internalCtx, cm.internalCancel = context.WithCancel(ctx)
 
@@ -375,10 +557,18 @@ func (cm *controllerManager) Start(ctx context.Context) (err error) {
 		return fmt.Errorf("failed to add cluster to runnables: %w", err)
 	}
 
+	// Serve metrics, health probes and pprof on the webhook server if asked to. Like
+	// the HTTP servers, the webhook server is started before the caches.
+	if cm.serveOnWebhookServer {
+		if err := cm.addHandlersToWebhookServer(); err != nil {
+			return fmt.Errorf("failed to add handlers to webhook server: %w", err)
+		}
+	}
+
 	// Metrics should be served whether the controller is leader or not.
 	// (If we don't serve metrics for non-leaders, prometheus will still scrape
 	// the pod but will get a connection refused).
-	if cm.metricsServer != nil {
+	if cm.metricsServer != nil && !cm.serveOnWebhookServer {
 		// Note: We are adding the metrics server directly to HTTPServers here as matching on the
 		// metricsserver.Server interface in cm.runnables.Add would be very brittle.
 		if err := cm.runnables.HTTPServers.Add(cm.metricsServer, nil); err != nil {
@@ -434,7 +624,7 @@ func (cm *controllerManager) Start(ctx context.Context) (err error) {
 		ctx, cancel := context.WithCancel(context.Background())
 		cm.leaderElectionCancel = cancel
 		go func() {
-			if cm.resourceLock != nil {
+			if cm.resourceLock != nil || cm.leaderElectionBackend != nil {
 				if err := cm.startLeaderElection(ctx); err != nil {
 					cm.errChan <- err
 				}
@@ -510,7 +700,7 @@ func (cm *controllerManager) engageStopProcedure(stopComplete <-chan struct{}) e
 	defer cm.recorderProvider.Stop(cm.shutdownCtx)
 	defer func() {
 		// Cancel leader election only after we waited. It will os.Exit() the app for safety.
-		if cm.resourceLock != nil {
+		if cm.resourceLock != nil || cm.leaderElectionBackend != nil {
 			// After asking the context to be cancelled, make sure
 			// we wait for the leader stopped channel to be closed, otherwise
 			// we might encounter race conditions between this code
@@ -531,16 +721,18 @@ func (cm *controllerManager) engageStopProcedure(stopComplete <-chan struct{}) e
 		cm.runnables.LeaderElection.startOnce.Do(func() {})
 		cm.runnables.LeaderElection.StopAndWait(cm.shutdownCtx)
 
+		// Drain the webhooks before stopping the caches, as in-flight admission requests
+		// might still read from them.
+		cm.logger.Info("Stopping and waiting for webhooks")
+		cm.runnables.Webhooks.StopAndWait(cm.shutdownCtx)
+
 		// Stop the caches before the leader election runnables, this is an important
 		// step to make sure that we don't race with the reconcilers by receiving more events
 		// from the API servers and enqueueing them.
 		cm.logger.Info("Stopping and waiting for caches")
 		cm.runnables.Caches.StopAndWait(cm.shutdownCtx)
 
-		// Webhooks and internal HTTP servers should come last, as they might be still serving some requests.
-		cm.logger.Info("Stopping and waiting for webhooks")
-		cm.runnables.Webhooks.StopAndWait(cm.shutdownCtx)
-
+		// Internal HTTP servers should come last, as they might be still serving some requests.
 		cm.logger.Info("Stopping and waiting for HTTP servers")
 		cm.runnables.HTTPServers.StopAndWait(cm.shutdownCtx)
 
@@ -569,32 +761,49 @@ func (cm *controllerManager) startLeaderElectionRunnables() error {
 }
 
 func (cm *controllerManager) startLeaderElection(ctx context.Context) (err error) {
-	l, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
-		Lock:          cm.resourceLock,
-		LeaseDuration: cm.leaseDuration,
-		RenewDeadline: cm.renewDeadline,
-		RetryPeriod:   cm.retryPeriod,
-		Callbacks: leaderelection.LeaderCallbacks{
-			OnStartedLeading: func(_ context.Context) {
-				if err := cm.startLeaderElectionRunnables(); err != nil {
-					cm.errChan <- err
-					return
-				}
-				close(cm.elected)
-			},
-			OnStoppedLeading: func() {
-				if cm.onStoppedLeading != nil {
-					cm.onStoppedLeading()
-				}
-				// Make sure graceful shutdown is skipped if we lost the leader lock without
-				// intending to.
-				cm.gracefulShutdownTimeout = time.Duration(0)
-				// Most implementations of leader election log.Fatal() here.
-				// Since Start is wrapped in log.Fatal when called, we can just return
-				// an error here which will cause the program to exit.
-				cm.errChan <- errors.New("leader election lost")
-			},
+	callbacks := leaderelection.LeaderCallbacks{
+		OnStartedLeading: func(_ context.Context) {
+			if err := cm.startLeaderElectionRunnables(); err != nil {
+				cm.errChan <- err
+				return
+			}
+			close(cm.elected)
+		},
+		OnStoppedLeading: func() {
+			if cm.onStoppedLeading != nil {
+				cm.onStoppedLeading()
+			}
+			// Make sure graceful shutdown is skipped if we lost the leader lock without
+			// intending to.
+			cm.gracefulShutdownTimeout = time.Duration(0)
+			// Most implementations of leader election log.Fatal() here.
+			// Since Start is wrapped in log.Fatal when called, we can just return
+			// an error here which will cause the program to exit.
+			cm.errChan <- errors.New("leader election lost")
 		},
+	}
+
+	if cm.leaderElectionBackend != nil {
+		go func() {
+			crleaderelection.RunWithBackend(ctx, cm.leaderElectionBackend, crleaderelection.BackendConfig{
+				LeaseDuration:   cm.leaseDuration,
+				RenewDeadline:   cm.renewDeadline,
+				RetryPeriod:     cm.retryPeriod,
+				ReleaseOnCancel: cm.leaderElectionReleaseOnCancel,
+				Callbacks:       callbacks,
+			})
+			<-ctx.Done()
+			close(cm.leaderElectionStopped)
+		}()
+		return nil
+	}
+
+	l, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            cm.resourceLock,
+		LeaseDuration:   cm.leaseDuration,
+		RenewDeadline:   cm.renewDeadline,
+		RetryPeriod:     cm.retryPeriod,
+		Callbacks:       callbacks,
 		ReleaseOnCancel: cm.leaderElectionReleaseOnCancel,
 		Name:            cm.leaderElectionID,
 	})
@@ -614,3 +823,29 @@ func (cm *controllerManager) startLeaderElection(ctx context.Context) (err error
 func (cm *controllerManager) Elected() <-chan struct{} {
 	return cm.elected
 }
+
+func (cm *controllerManager) LeaderElectionInfo(ctx context.Context) (LeaderElectionInfo, error) {
+	info := LeaderElectionInfo{Enabled: cm.resourceLock != nil || cm.leaderElectionBackend != nil}
+	select {
+	case <-cm.elected:
+		info.IsLeader = true
+	default:
+	}
+	if cm.resourceLock == nil {
+		return info, nil
+	}
+
+	info.Identity = cm.resourceLock.Identity()
+	record, _, err := cm.resourceLock.Get(ctx)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Nobody acquired the lease yet.
+			return info, nil
+		}
+		return info, fmt.Errorf("failed to get leader election record: %w", err)
+	}
+	info.HolderIdentity = record.HolderIdentity
+	info.AcquireTime = record.AcquireTime.Time
+	info.RenewTime = record.RenewTime.Time
+	return info, nil
+}
@@ -57,6 +57,7 @@ type Manager interface {
 	// started when Start is called.
 	// Depending on if a Runnable implements LeaderElectionRunnable interface, a Runnable can be run in either
 	// non-leaderelection mode (always running) or leader election mode (managed by leader election if enabled).
+	// Runnables implementing OrderedRunnable are started in order of their priority.
 	Add(Runnable) error
 
 	// Elected is closed when this manager is elected leader of a group of
@@ -64,6 +65,11 @@ type Manager interface {
 	// election was configured.
 	Elected() <-chan struct{}
 
+	// LeaderElectionInfo returns the state of the leader election, e.g. to show which replica is
+	// the leader. The lease holder is read from the resource lock, it is unknown with a custom
+	// LeaderElectionBackend. It can be called before the manager is started or elected.
+	LeaderElectionInfo(ctx context.Context) (LeaderElectionInfo, error)
+
 	// AddMetricsServerExtraHandler adds an extra handler served on path to the http server that serves metrics.
 	// Might be useful to register some diagnostic endpoints e.g. pprof.
 	//
@@ -79,9 +85,20 @@ type Manager interface {
 	// AddReadyzCheck allows you to add Readyz checker
 	AddReadyzCheck(name string, check healthz.Checker) error
 
+	// AddControllerDependency declares that the controller named dependent must not start its
+	// sources until the controller named dependsOn completed its initial pass, i.e. its sources
+	// synced and it reconciled every object that existed by then once. Both controllers must be
+	// added to the manager, and dependencies must be declared before the manager is started.
+	// It errors out if the dependency would make controllers depend on each other, directly or
+	// transitively.
+	AddControllerDependency(dependent, dependsOn string) error
+
 	// Start starts all registered Controllers and blocks until the context is cancelled.
 	// Returns an error if there is an error starting any controller.
 	//
+	// The context passed to the runnables, and thus to reconcilers, carries the scheme and
+	// RESTMapper of the manager, see client.SchemeFromContext and client.RESTMapperFromContext.
+	//
 	// If LeaderElection is used, the binary must be exited immediately after this returns,
 	// otherwise components that need leader election might continue to run after the leader
 	// lock was lost.
@@ -207,6 +224,13 @@ type Options struct {
 	// want to use a locking mechanism that is currently not supported, like a MultiLock across two Kubernetes clusters.
 	LeaderElectionResourceLockInterface resourcelock.Interface
 
+	// LeaderElectionBackend allows to run leader election with a lock that is kept outside of Kubernetes,
+	// e.g. in etcd or Consul, for example for managers running outside of a cluster. If this value is set,
+	// the options LeaderElectionID, LeaderElectionNamespace, LeaderElectionResourceLock and
+	// LeaderElectionResourceLockInterface will be ignored, while LeaseDuration, RenewDeadline, RetryPeriod
+	// and LeaderElectionReleaseOnCancel still apply. Defaults to a Kubernetes resource lock.
+	LeaderElectionBackend leaderelection.Backend
+
 	// LeaseDuration is the duration that non-leader candidates will
 	// wait to force acquire leadership. This is measured against time of
 	// last observed ack. Default is 15 seconds.
@@ -241,6 +265,17 @@ type Options struct {
 	// before exposing it to public.
 	PprofBindAddress string
 
+	// ServeOnWebhookServer serves the metrics, health probes and pprof on the webhook server
+	// rather than on their own ports, so that a single port needs to be exposed. They keep
+	// their paths, i.e. /metrics and the Metrics.ExtraHandlers, the readiness and liveness
+	// endpoints and /debug/pprof/, next to the paths of the webhooks, and are served with TLS
+	// using the certificate of the webhook server. Each of them is served if it is enabled by
+	// Metrics.BindAddress, HealthProbeBindAddress and PprofBindAddress respectively, whose
+	// addresses are otherwise ignored. Note that the webhook server is started even if no
+	// webhooks are registered, and that Metrics.SecureServing, Metrics.TLSOpts and the
+	// certificate options of Metrics are ignored.
+	ServeOnWebhookServer bool
+
 	// WebhookServer is an externally configured webhook.Server. By default,
 	// a Manager will create a server via webhook.NewServer with default settings.
 	// If this is set, the Manager will use this server instead.
@@ -248,7 +283,8 @@ type Options struct {
 
 	// BaseContext is the function that provides Context values to Runnables
 	// managed by the Manager. If a BaseContext function isn't provided, Runnables
-	// will receive a new Background Context instead.
+	// will receive a new Background Context instead. Either way, the manager adds its
+	// scheme and RESTMapper to the Context, see client.SchemeFromContext.
 	BaseContext BaseContextFunc
 
 	// EventBroadcaster records Events emitted by the manager and sends them to the Kubernetes API
@@ -258,6 +294,11 @@ type Options struct {
 	// is shorter than the lifetime of your process.
 	EventBroadcaster record.EventBroadcaster
 
+	// EventRateLimit, if set, rate limits identical events recorded with the recorders returned by
+	// GetEventRecorderFor, e.g. to keep Reconcilers that fail repeatedly from flooding the events API.
+	// See recorder.NewRateLimitedRecorder.
+	EventRateLimit *recorder.RateLimitOptions
+
 	// GracefulShutdownTimeout is the duration given to runnable to stop before the manager actually returns on stop.
 	// To disable graceful shutdown, set to time.Duration(0)
 	// To use graceful shutdown without timeout, set to a negative duration, e.G. time.Duration(-1)
@@ -287,6 +328,28 @@ type Options struct {
 // managed by a Manager.
 type BaseContextFunc func() context.Context
 
+// LeaderElectionInfo describes the state of the leader election of a manager.
+type LeaderElectionInfo struct {
+	// Enabled is true if leader election is configured.
+	Enabled bool
+
+	// IsLeader is true once the manager is elected, see Manager.Elected.
+	IsLeader bool
+
+	// Identity is the identity of the manager in the resource lock.
+	Identity string
+
+	// HolderIdentity is the identity of the current holder of the lease, empty if it is not held
+	// or unknown.
+	HolderIdentity string
+
+	// AcquireTime is the time the holder acquired the lease.
+	AcquireTime time.Time
+
+	// RenewTime is the time the holder last renewed the lease.
+	RenewTime time.Time
+}
+
 // Runnable allows a component to be started.
 // It's very important that Start blocks until
 // it's done running.
@@ -307,6 +370,18 @@ func (r RunnableFunc) Start(ctx context.Context) error {
 	return r(ctx)
 }
 
+// BestEffortInformersSyncedCheck returns a healthz.Checker, e.g. for AddReadyzCheck, that fails
+// until all informers of the cache of mgr with the cache.InformerStartBestEffort policy synced.
+// As opposed to other informers, those don't block the start of the manager.
+func BestEffortInformersSyncedCheck(mgr Manager) healthz.Checker {
+	return func(_ *http.Request) error {
+		if gvks := cache.UnsyncedBestEffortInformers(mgr.GetCache()); len(gvks) > 0 {
+			return fmt.Errorf("informers for %v have not synced yet", gvks)
+		}
+		return nil
+	}
+}
+
 // LeaderElectionRunnable knows if a Runnable needs to be run in the leader election mode.
 type LeaderElectionRunnable interface {
 	// NeedLeaderElection returns true if the Runnable needs to be run in the leader election mode.
@@ -314,6 +389,33 @@ type LeaderElectionRunnable interface {
 	NeedLeaderElection() bool
 }
 
+// OrderedRunnable is a Runnable with a start priority.
+//
+// The manager starts the runnables of each of its phases, e.g. the webhook servers, the caches
+// and the remaining runnables, in order of decreasing priority, and stops them in the reverse
+// order. It only starts the runnables of a priority once those of the higher priorities are ready,
+// and only stops them once those of the lower priorities returned. Runnables that don't implement
+// OrderedRunnable have priority 0. Runnables added to the manager after it started are started
+// right away.
+type OrderedRunnable interface {
+	Runnable
+
+	// StartPriority returns the priority of the Runnable. Runnables with a higher priority are
+	// started before and stopped after runnables with a lower one.
+	StartPriority() int
+}
+
+// ReadinessRunnable is a Runnable that reports when it is ready, e.g. finished initializing.
+// The manager waits for it to be ready before starting the runnables of a lower priority,
+// see OrderedRunnable. Other runnables are ready as soon as they were started.
+type ReadinessRunnable interface {
+	Runnable
+
+	// WaitForReady blocks until the Runnable is ready and returns true, or returns false if
+	// ctx is done first.
+	WaitForReady(ctx context.Context) bool
+}
+
 // New returns a new Manager for creating Controllers.
 // Note that if ContentType in the given config is not set, "application/vnd.kubernetes.protobuf"
 // will be used for all built-in resources of Kubernetes, and "application/json" is for other types
@@ -381,9 +483,13 @@ func New(config *rest.Config, options Options) (Manager, error) {
 	}
 
 	var resourceLock resourcelock.Interface
-	if options.LeaderElectionResourceLockInterface != nil && options.LeaderElection {
+	var leaderElectionBackend leaderelection.Backend
+	switch {
+	case options.LeaderElectionBackend != nil && options.LeaderElection:
+		leaderElectionBackend = options.LeaderElectionBackend
+	case options.LeaderElectionResourceLockInterface != nil && options.LeaderElection:
 		resourceLock = options.LeaderElectionResourceLockInterface
-	} else {
+	default:
 		resourceLock, err = options.newResourceLock(leaderConfig, leaderRecorderProvider, leaderelection.Options{
 			LeaderElection:             options.LeaderElection,
 			LeaderElectionResourceLock: options.LeaderElectionResourceLock,
@@ -401,22 +507,30 @@ func New(config *rest.Config, options Options) (Manager, error) {
 		return nil, err
 	}
 
-	// Create health probes listener. This will throw an error if the bind
-	// address is invalid or already in use.
-	healthProbeListener, err := options.newHealthProbeListener(options.HealthProbeBindAddress)
-	if err != nil {
-		return nil, err
-	}
+	var healthProbeListener, pprofListener net.Listener
+	if !options.ServeOnWebhookServer {
+		// Create health probes listener. This will throw an error if the bind
+		// address is invalid or already in use.
+		healthProbeListener, err = options.newHealthProbeListener(options.HealthProbeBindAddress)
+		if err != nil {
+			return nil, err
+		}
 
-	// Create pprof listener. This will throw an error if the bind
-	// address is invalid or already in use.
-	pprofListener, err := options.newPprofListener(options.PprofBindAddress)
-	if err != nil {
-		return nil, fmt.Errorf("failed to new pprof listener: %w", err)
+		// Create pprof listener. This will throw an error if the bind
+		// address is invalid or already in use.
+		pprofListener, err = options.newPprofListener(options.PprofBindAddress)
+		if err != nil {
+			return nil, fmt.Errorf("failed to new pprof listener: %w", err)
+		}
 	}
 
 	errChan := make(chan error, 1)
-	runnables := newRunnables(options.BaseContext, errChan)
+	runnables := newRunnables(withSchemeAndRESTMapper(options.BaseContext, cluster), errChan)
+	var rateLimitedRecorders recorder.Provider
+	if options.EventRateLimit != nil {
+		rateLimitedRecorders = recorder.NewRateLimitedProvider(cluster, *options.EventRateLimit)
+	}
+
 	return &controllerManager{
 		stopProcedureEngaged:          ptr.To(int64(0)),
 		cluster:                       cluster,
@@ -424,6 +538,7 @@ func New(config *rest.Config, options Options) (Manager, error) {
 		errChan:                       errChan,
 		recorderProvider:              recorderProvider,
 		resourceLock:                  resourceLock,
+		leaderElectionBackend:         leaderElectionBackend,
 		metricsServer:                 metricsServer,
 		controllerConfig:              options.Controller,
 		logger:                        options.Logger,
@@ -437,16 +552,25 @@ func New(config *rest.Config, options Options) (Manager, error) {
 		readinessEndpointName:         options.ReadinessEndpointName,
 		livenessEndpointName:          options.LivenessEndpointName,
 		pprofListener:                 pprofListener,
+		serveOnWebhookServer:          options.ServeOnWebhookServer,
+		serveHealthProbes:             options.ServeOnWebhookServer && bindAddressEnabled(options.HealthProbeBindAddress),
+		servePprof:                    options.ServeOnWebhookServer && bindAddressEnabled(options.PprofBindAddress),
 		gracefulShutdownTimeout:       *options.GracefulShutdownTimeout,
 		internalProceduresStop:        make(chan struct{}),
 		leaderElectionStopped:         make(chan struct{}),
 		leaderElectionReleaseOnCancel: options.LeaderElectionReleaseOnCancel,
+		rateLimitedRecorders:          rateLimitedRecorders,
 	}, nil
 }
 
+// bindAddressEnabled returns false for the addresses that disable serving, i.e. "" and "0".
+func bindAddressEnabled(addr string) bool {
+	return addr != "" && addr != "0"
+}
+
 // defaultHealthProbeListener creates the default health probes listener bound to the given address.
 func defaultHealthProbeListener(addr string) (net.Listener, error) {
-	if addr == "" || addr == "0" {
+	if !bindAddressEnabled(addr) {
 		return nil, nil
 	}
 
@@ -459,7 +583,7 @@ func defaultHealthProbeListener(addr string) (net.Listener, error) {
 
 // defaultPprofListener creates the default pprof listener bound to the given address.
 func defaultPprofListener(addr string) (net.Listener, error) {
-	if addr == "" || addr == "0" {
+	if !bindAddressEnabled(addr) {
 		return nil, nil
 	}
 
@@ -470,6 +594,16 @@ func defaultPprofListener(addr string) (net.Listener, error) {
 	return ln, nil
 }
 
+// withSchemeAndRESTMapper returns a BaseContextFunc that puts the scheme and RESTMapper of
+// cluster into the contexts of baseContext, so that runnables, e.g. reconcilers, can get them
+// without a reference to the manager.
+func withSchemeAndRESTMapper(baseContext BaseContextFunc, cluster cluster.Cluster) BaseContextFunc {
+	return func() context.Context {
+		ctx := client.SchemeIntoContext(baseContext(), cluster.GetScheme())
+		return client.RESTMapperIntoContext(ctx, cluster.GetRESTMapper())
+	}
+}
+
 // defaultBaseContext is used as the BaseContext value in Options if one
 // has not already been set.
 func defaultBaseContext() context.Context {
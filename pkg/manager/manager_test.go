@@ -36,20 +36,30 @@ import (
 	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	intcontroller "sigs.k8s.io/controller-runtime/pkg/internal/controller"
 	intrec "sigs.k8s.io/controller-runtime/pkg/internal/recorder"
 	"sigs.k8s.io/controller-runtime/pkg/leaderelection"
 	fakeleaderelection "sigs.k8s.io/controller-runtime/pkg/leaderelection/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/recorder"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
@@ -494,6 +504,135 @@ var _ = Describe("manger.Manager", func() {
 					Expect(cm.resourceLock).To(Equal(rl))
 				})
 			})
+			When("reporting the leader election info", func() {
+				It("should report the holder of the lease before the manager is elected", func() {
+					rl, err := fakeleaderelection.NewResourceLock(nil, nil, leaderelection.Options{})
+					Expect(err).NotTo(HaveOccurred())
+					acquireTime := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+					Expect(rl.Update(context.Background(), resourcelock.LeaderElectionRecord{
+						HolderIdentity: "other-replica",
+						AcquireTime:    acquireTime,
+						RenewTime:      metav1.NewTime(acquireTime.Add(30 * time.Second)),
+					})).To(Succeed())
+
+					m, err := New(cfg, Options{
+						LeaderElection:                      true,
+						LeaderElectionResourceLockInterface: rl,
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					info, err := m.LeaderElectionInfo(context.Background())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(info.Enabled).To(BeTrue())
+					Expect(info.IsLeader).To(BeFalse())
+					Expect(info.Identity).To(Equal(rl.Identity()))
+					Expect(info.HolderIdentity).To(Equal("other-replica"))
+					Expect(info.AcquireTime).To(BeTemporally("==", acquireTime.Time))
+					Expect(info.RenewTime).To(BeTemporally("==", acquireTime.Add(30*time.Second)))
+				})
+
+				It("should report being the leader once elected", func() {
+					rl, err := fakeleaderelection.NewResourceLock(nil, nil, leaderelection.Options{})
+					Expect(err).NotTo(HaveOccurred())
+
+					m, err := New(cfg, Options{
+						LeaderElection:                      true,
+						LeaderElectionResourceLockInterface: rl,
+						HealthProbeBindAddress:              "0",
+						Metrics:                             metricsserver.Options{BindAddress: "0"},
+						PprofBindAddress:                    "0",
+					})
+					Expect(err).NotTo(HaveOccurred())
+					m.(*controllerManager).onStoppedLeading = func() {}
+
+					ctx, cancel := context.WithCancel(context.Background())
+					defer cancel()
+					go func() {
+						defer GinkgoRecover()
+						Expect(m.Start(ctx)).NotTo(HaveOccurred())
+					}()
+					Eventually(m.Elected()).Should(BeClosed())
+
+					info, err := m.LeaderElectionInfo(context.Background())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(info.IsLeader).To(BeTrue())
+					Expect(info.HolderIdentity).To(Equal(rl.Identity()))
+				})
+
+				It("should report leader election as disabled", func() {
+					m, err := New(cfg, Options{})
+					Expect(err).NotTo(HaveOccurred())
+
+					info, err := m.LeaderElectionInfo(context.Background())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(info.Enabled).To(BeFalse())
+					Expect(info.HolderIdentity).To(BeEmpty())
+				})
+			})
+			When("using a custom LeaderElectionBackend", func() {
+				It("should only elect one of two managers contending for the lock", func() {
+					lock := &fakeleaderelection.Lock{}
+					newManager := func(identity string) (Manager, chan struct{}) {
+						m, err := New(cfg, Options{
+							LeaderElection:                true,
+							LeaderElectionBackend:         lock.Backend(identity),
+							LeaderElectionReleaseOnCancel: true,
+							LeaseDuration:                 ptr.To(2 * time.Second),
+							RenewDeadline:                 ptr.To(time.Second),
+							RetryPeriod:                   ptr.To(100 * time.Millisecond),
+							newResourceLock: func(config *rest.Config, recorderProvider recorder.Provider, options leaderelection.Options) (resourcelock.Interface, error) {
+								return nil, fmt.Errorf("this should not be called")
+							},
+							HealthProbeBindAddress: "0",
+							Metrics:                metricsserver.Options{BindAddress: "0"},
+							PprofBindAddress:       "0",
+						})
+						Expect(err).ToNot(HaveOccurred())
+						m.(*controllerManager).onStoppedLeading = func() {}
+
+						ran := make(chan struct{})
+						Expect(m.Add(RunnableFunc(func(context.Context) error {
+							close(ran)
+							return nil
+						}))).To(Succeed())
+						return m, ran
+					}
+					startManager := func(m Manager) (context.CancelFunc, chan struct{}) {
+						ctx, cancel := context.WithCancel(context.Background())
+						done := make(chan struct{})
+						go func() {
+							defer GinkgoRecover()
+							defer close(done)
+							Expect(m.Start(ctx)).NotTo(HaveOccurred())
+						}()
+						return cancel, done
+					}
+
+					m1, ran1 := newManager("m1")
+					cancel1, done1 := startManager(m1)
+					defer cancel1()
+					Eventually(m1.Elected()).Should(BeClosed())
+					Eventually(ran1).Should(BeClosed())
+					Expect(lock.Holder()).To(Equal("m1"))
+
+					m2, ran2 := newManager("m2")
+					cancel2, done2 := startManager(m2)
+					defer cancel2()
+					Consistently(m2.Elected()).ShouldNot(BeClosed())
+					Expect(ran2).NotTo(BeClosed())
+					Expect(lock.Holder()).To(Equal("m1"))
+
+					By("Stopping the leader, which releases the lock")
+					cancel1()
+					Eventually(done1).Should(BeClosed())
+					Eventually(m2.Elected()).Should(BeClosed())
+					Eventually(ran2).Should(BeClosed())
+					Expect(lock.Holder()).To(Equal("m2"))
+
+					cancel2()
+					Eventually(done2).Should(BeClosed())
+				})
+			})
 		})
 
 		It("should create a metrics server if a valid address is provided", func() {
@@ -1501,6 +1640,105 @@ var _ = Describe("manger.Manager", func() {
 		})
 	})
 
+	Context("should serve on the webhook server", func() {
+		var servingOpts envtest.WebhookInstallOptions
+		var client *http.Client
+
+		BeforeEach(func() {
+			servingOpts = envtest.WebhookInstallOptions{}
+			Expect(servingOpts.PrepWithoutInstalling()).To(Succeed())
+
+			transport, err := rest.TransportFor(&rest.Config{
+				TLSClientConfig: rest.TLSClientConfig{CAData: servingOpts.LocalServingCAData},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			client = &http.Client{Transport: transport}
+		})
+
+		AfterEach(func() {
+			Expect(servingOpts.Cleanup()).To(Succeed())
+		})
+
+		It("should serve webhooks, metrics, health probes and pprof on a single port", func() {
+			m, err := New(cfg, Options{
+				ServeOnWebhookServer:   true,
+				Metrics:                metricsserver.Options{BindAddress: ":8080"},
+				HealthProbeBindAddress: ":8081",
+				PprofBindAddress:       ":8082",
+				WebhookServer: webhook.NewServer(webhook.Options{
+					Host:    servingOpts.LocalServingHost,
+					Port:    servingOpts.LocalServingPort,
+					CertDir: servingOpts.LocalServingCertDir,
+				}),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.AddHealthzCheck("check", func(_ *http.Request) error { return nil })).To(Succeed())
+			Expect(m.AddReadyzCheck("check", func(_ *http.Request) error { return nil })).To(Succeed())
+			m.GetWebhookServer().Register("/validate", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			<-m.Elected()
+
+			endpoint := fmt.Sprintf("https://%s", net.JoinHostPort(servingOpts.LocalServingHost, fmt.Sprint(servingOpts.LocalServingPort)))
+			statusCode := func(path string) func() (int, error) {
+				return func() (int, error) {
+					resp, err := client.Get(endpoint + path)
+					if err != nil {
+						return 0, err
+					}
+					defer resp.Body.Close()
+					return resp.StatusCode, nil
+				}
+			}
+			Eventually(statusCode("/validate")).Should(Equal(http.StatusTeapot))
+			Eventually(statusCode("/metrics")).Should(Equal(http.StatusOK))
+			Eventually(statusCode(defaultLivenessEndpoint)).Should(Equal(http.StatusOK))
+			Eventually(statusCode(defaultReadinessEndpoint + "/check")).Should(Equal(http.StatusOK))
+			Eventually(statusCode("/debug/pprof/")).Should(Equal(http.StatusOK))
+		})
+
+		It("should not serve the endpoints that are disabled", func() {
+			m, err := New(cfg, Options{
+				ServeOnWebhookServer: true,
+				Metrics:              metricsserver.Options{BindAddress: "0"},
+				WebhookServer: webhook.NewServer(webhook.Options{
+					Host:    servingOpts.LocalServingHost,
+					Port:    servingOpts.LocalServingPort,
+					CertDir: servingOpts.LocalServingCertDir,
+				}),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.AddHealthzCheck("check", func(_ *http.Request) error { return nil })).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			<-m.Elected()
+
+			endpoint := fmt.Sprintf("https://%s", net.JoinHostPort(servingOpts.LocalServingHost, fmt.Sprint(servingOpts.LocalServingPort)))
+			for _, path := range []string{"/metrics", defaultLivenessEndpoint, "/debug/pprof/"} {
+				Eventually(func() (int, error) {
+					resp, err := client.Get(endpoint + path)
+					if err != nil {
+						return 0, err
+					}
+					defer resp.Body.Close()
+					return resp.StatusCode, nil
+				}).Should(Equal(http.StatusNotFound))
+			}
+		})
+	})
+
 	Context("should start serving pprof", func() {
 		var listener net.Listener
 		var opts Options
@@ -1793,6 +2031,95 @@ var _ = Describe("manger.Manager", func() {
 	})
 })
 
+var _ = Describe("AddControllerDependency", func() {
+	newController := func(name string, do reconcile.Func, src source.Source) *intcontroller.Controller[reconcile.Request] {
+		c := &intcontroller.Controller[reconcile.Request]{
+			Name:                    name,
+			MaxConcurrentReconciles: 1,
+			CacheSyncTimeout:        10 * time.Second,
+			Do:                      do,
+			NewQueue: func(string, ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
+				return workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			},
+			LogConstructor: func(*reconcile.Request) logr.Logger {
+				return logf.Log.WithName(name)
+			},
+		}
+		Expect(c.Watch(src)).To(Succeed())
+		return c
+	}
+
+	It("should start the sources of a controller only after its dependency completed its initial pass", func() {
+		m, err := New(cfg, Options{Metrics: metricsserver.Options{BindAddress: "0"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		events := &eventLog{}
+		releaseA := make(chan struct{})
+		a := newController("a",
+			func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+				<-releaseA
+				events.add("a reconciled " + req.Name)
+				return reconcile.Result{}, nil
+			},
+			source.Func(func(_ context.Context, q workqueue.RateLimitingInterface) error {
+				q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: "one"}})
+				q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: "two"}})
+				return nil
+			}),
+		)
+		b := newController("b",
+			func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{}, nil
+			},
+			source.Func(func(context.Context, workqueue.RateLimitingInterface) error {
+				events.add("b source started")
+				return nil
+			}),
+		)
+		Expect(m.Add(b)).To(Succeed())
+		Expect(m.Add(a)).To(Succeed())
+		Expect(m.AddControllerDependency("b", "a")).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(m.Start(ctx)).To(Succeed())
+		}()
+
+		Consistently(events.list, 300*time.Millisecond).ShouldNot(ContainElement("b source started"))
+		close(releaseA)
+		Eventually(events.list).Should(ContainElement("b source started"))
+
+		Expect(events.index("b source started")).To(BeNumerically(">", events.index("a reconciled one")))
+		Expect(events.index("b source started")).To(BeNumerically(">", events.index("a reconciled two")))
+	})
+
+	It("should return an error for a dependency cycle", func() {
+		m, err := New(cfg, Options{Metrics: metricsserver.Options{BindAddress: "0"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(m.AddControllerDependency("a", "a")).To(MatchError(ContainSubstring("dependency cycle a -> a")))
+		Expect(m.AddControllerDependency("a", "b")).To(Succeed())
+		Expect(m.AddControllerDependency("b", "c")).To(Succeed())
+		Expect(m.AddControllerDependency("c", "a")).To(MatchError(ContainSubstring("dependency cycle c -> a -> b -> c")))
+		Expect(m.AddControllerDependency("a", "c")).To(Succeed())
+	})
+
+	It("should return an error on Start if a controller of a dependency wasn't added", func() {
+		m, err := New(cfg, Options{Metrics: metricsserver.Options{BindAddress: "0"}})
+		Expect(err).NotTo(HaveOccurred())
+		noop := func(context.Context, reconcile.Request) (reconcile.Result, error) { return reconcile.Result{}, nil }
+		Expect(m.Add(newController("a", noop, source.Func(func(context.Context, workqueue.RateLimitingInterface) error { return nil })))).To(Succeed())
+		Expect(m.AddControllerDependency("a", "missing")).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		Expect(m.Start(ctx)).To(MatchError(ContainSubstring(`depends on controller "missing"`)))
+		Expect(m.AddControllerDependency("a", "b")).To(MatchError(ContainSubstring("already been started")))
+	})
+})
+
 type runnableError struct {
 }
 
@@ -3,6 +3,7 @@ package manager
 import (
 	"context"
 	"errors"
+	"sort"
 	"sync"
 
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -18,6 +19,7 @@ type readyRunnable struct {
 	Runnable
 	Check       runnableCheck
 	signalReady bool
+	priority    int
 }
 
 // runnableCheck can be passed to Add() to let the runnable group determine that a
@@ -105,6 +107,18 @@ type runnableGroup struct {
 	// wg is an internal sync.WaitGroup that allows us to properly stop
 	// and wait for all the runnables to finish before returning.
 	wg *sync.WaitGroup
+
+	// tiers are the started runnables by priority, so that they can
+	// be stopped in reverse order of priority.
+	tiers   map[int]*runnableTier
+	tiersMu sync.Mutex
+}
+
+// runnableTier holds the started runnables of a priority.
+type runnableTier struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
 func newRunnableGroup(baseContext BaseContextFunc, errChan chan error) *runnableGroup {
@@ -113,6 +127,7 @@ func newRunnableGroup(baseContext BaseContextFunc, errChan chan error) *runnable
 		errChan:      errChan,
 		ch:           make(chan *readyRunnable),
 		wg:           new(sync.WaitGroup),
+		tiers:        map[int]*runnableTier{},
 	}
 
 	r.ctx, r.cancel = context.WithCancel(baseContext())
@@ -138,52 +153,79 @@ func (r *runnableGroup) Start(ctx context.Context) error {
 		// Start the internal reconciler.
 		go r.reconcile()
 
-		// Start the group and queue up all
+		// Start the group and take all
 		// the runnables that were added prior.
 		r.start.Lock()
 		r.started = true
-		for _, rn := range r.startQueue {
-			rn.signalReady = true
-			r.ch <- rn
-		}
+		queue := r.startQueue
+		r.startQueue = nil
 		r.start.Unlock()
 
-		// If we don't have any queue, return.
-		if len(r.startQueue) == 0 {
-			return
-		}
-
-		// Wait for all runnables to signal.
-		for {
-			select {
-			case <-ctx.Done():
-				if err := ctx.Err(); !errors.Is(err, context.Canceled) {
-					retErr = err
-				}
-			case rn := <-r.startReadyCh:
-				for i, existing := range r.startQueue {
-					if existing == rn {
-						// Remove the item from the start queue.
-						r.startQueue = append(r.startQueue[:i], r.startQueue[i+1:]...)
-						break
-					}
-				}
-				// We're done waiting if the queue is empty, return.
-				if len(r.startQueue) == 0 {
-					return
-				}
+		// Queue them up in order of decreasing priority, waiting for
+		// all runnables of a priority to signal before the next one.
+		sort.SliceStable(queue, func(i, j int) bool {
+			return queue[i].priority > queue[j].priority
+		})
+		for len(queue) > 0 {
+			n := 1
+			for n < len(queue) && queue[n].priority == queue[0].priority {
+				n++
+			}
+			if err := r.startAndWait(ctx, queue[:n]); err != nil && retErr == nil {
+				retErr = err
 			}
+			queue = queue[n:]
 		}
 	})
 
 	return retErr
 }
 
+// startAndWait queues up the given runnables and waits for all of them to signal.
+func (r *runnableGroup) startAndWait(ctx context.Context, runnables []*readyRunnable) error {
+	var retErr error
+
+	pending := make(map[*readyRunnable]struct{}, len(runnables))
+	for _, rn := range runnables {
+		rn.signalReady = true
+		pending[rn] = struct{}{}
+		r.ch <- rn
+	}
+
+	// Wait for all runnables to signal.
+	for len(pending) > 0 {
+		select {
+		case <-ctx.Done():
+			if err := ctx.Err(); !errors.Is(err, context.Canceled) {
+				retErr = err
+			}
+		case rn := <-r.startReadyCh:
+			delete(pending, rn)
+		}
+	}
+	return retErr
+}
+
+// tier returns the tier of the runnables with the given priority.
+func (r *runnableGroup) tier(priority int) *runnableTier {
+	r.tiersMu.Lock()
+	defer r.tiersMu.Unlock()
+	t, ok := r.tiers[priority]
+	if !ok {
+		t = &runnableTier{}
+		t.ctx, t.cancel = context.WithCancel(r.ctx)
+		r.tiers[priority] = t
+	}
+	return t
+}
+
 // reconcile is our main entrypoint for every runnable added
 // to this group. Its primary job is to read off the internal channel
 // and schedule runnables while tracking their state.
 func (r *runnableGroup) reconcile() {
 	for runnable := range r.ch {
+		tier := r.tier(runnable.priority)
+
 		// Handle stop.
 		// If the shutdown has been called we want to avoid
 		// adding new goroutines to the WaitGroup because Wait()
@@ -203,6 +245,7 @@ func (r *runnableGroup) reconcile() {
 			// the WaitGroup is incremented while StopAndWait has called Wait(),
 			// which would result in a panic.
 			r.wg.Add(1)
+			tier.wg.Add(1)
 			r.stop.RUnlock()
 		}
 
@@ -221,9 +264,10 @@ func (r *runnableGroup) reconcile() {
 			//
 			// We should always decrement the WaitGroup here.
 			defer r.wg.Done()
+			defer tier.wg.Done()
 
 			// Start the runnable.
-			if err := rn.Start(r.ctx); err != nil {
+			if err := rn.Start(tier.ctx); err != nil {
 				r.errChan <- err
 			}
 		}(runnable)
@@ -242,12 +286,18 @@ func (r *runnableGroup) Add(rn Runnable, ready runnableCheck) error {
 
 	if ready == nil {
 		ready = func(_ context.Context) bool { return true }
+		if readiness, ok := rn.(ReadinessRunnable); ok {
+			ready = readiness.WaitForReady
+		}
 	}
 
 	readyRunnable := &readyRunnable{
 		Runnable: rn,
 		Check:    ready,
 	}
+	if ordered, ok := rn.(OrderedRunnable); ok {
+		readyRunnable.priority = ordered.StartPriority()
+	}
 
 	// Handle start.
 	// If the overall runnable group isn't started yet
@@ -297,6 +347,30 @@ func (r *runnableGroup) StopAndWait(ctx context.Context) {
 		r.stopped = true
 		r.stop.Unlock()
 
+		// Cancel the runnables in order of increasing priority, waiting
+		// for all runnables of a priority to finish before the next one.
+		r.tiersMu.Lock()
+		priorities := make([]int, 0, len(r.tiers))
+		for priority := range r.tiers {
+			priorities = append(priorities, priority)
+		}
+		r.tiersMu.Unlock()
+		sort.Ints(priorities)
+		for _, priority := range priorities {
+			tier := r.tier(priority)
+			tier.cancel()
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				tier.wg.Wait()
+			}()
+			select {
+			case <-done:
+			case <-ctx.Done():
+			}
+		}
+
 		// Cancel the internal channel.
 		r.cancel()
 
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -198,6 +200,39 @@ var _ = Describe("runnableGroup", func() {
 		}
 	})
 
+	It("should start runnables in order of priority and stop them in reverse order", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := &eventLog{}
+		rg := newRunnableGroup(defaultBaseContext, errCh)
+		Expect(rg.Add(&orderedRunnable{name: "low", priority: -5, events: events}, nil)).To(Succeed())
+		Expect(rg.Add(RunnableFunc(func(c context.Context) error {
+			events.add("start default")
+			<-c.Done()
+			events.add("stop default")
+			return nil
+		}), nil)).To(Succeed())
+		Expect(rg.Add(&orderedRunnable{name: "mid", priority: 0, events: events}, nil)).To(Succeed())
+		Expect(rg.Add(&orderedRunnable{name: "high-a", priority: 10, events: events, readyAfter: 50 * time.Millisecond}, nil)).To(Succeed())
+		Expect(rg.Add(&orderedRunnable{name: "high-b", priority: 10, events: events, readyAfter: 100 * time.Millisecond}, nil)).To(Succeed())
+
+		Expect(rg.Start(ctx)).To(Succeed())
+		rg.StopAndWait(context.Background())
+
+		By("Starting a priority once all runnables of the higher priorities are ready")
+		Expect(events.index("ready high-a")).To(BeNumerically("<", events.index("start mid")))
+		Expect(events.index("ready high-b")).To(BeNumerically("<", events.index("start mid")))
+		Expect(events.index("ready high-b")).To(BeNumerically("<", events.index("start default")))
+		Expect(events.index("ready mid")).To(BeNumerically("<", events.index("start low")))
+
+		By("Stopping a priority once all runnables of the lower priorities returned")
+		Expect(events.index("stop low")).To(BeNumerically("<", events.index("stop mid")))
+		Expect(events.index("stop low")).To(BeNumerically("<", events.index("stop default")))
+		Expect(events.index("stop mid")).To(BeNumerically("<", events.index("stop high-a")))
+		Expect(events.index("stop default")).To(BeNumerically("<", events.index("stop high-b")))
+	})
+
 	It("should not turn ready if some readiness check fail", func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
@@ -224,3 +259,73 @@ var _ = Describe("runnableGroup", func() {
 		}
 	})
 })
+
+// eventLog records events in the order they occur.
+type eventLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *eventLog) add(event string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+// list returns a copy of the events that occurred so far.
+func (l *eventLog) list() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.events)
+}
+
+// index returns the position of event in the log, failing if it didn't occur.
+func (l *eventLog) index(event string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	Expect(l.events).To(ContainElement(event))
+	return slices.Index(l.events, event)
+}
+
+// orderedRunnable is an OrderedRunnable and ReadinessRunnable that logs
+// when it starts, is ready and stops.
+type orderedRunnable struct {
+	name       string
+	priority   int
+	readyAfter time.Duration
+	events     *eventLog
+
+	ready chan struct{}
+	once  sync.Once
+}
+
+func (r *orderedRunnable) readyCh() chan struct{} {
+	r.once.Do(func() { r.ready = make(chan struct{}) })
+	return r.ready
+}
+
+func (r *orderedRunnable) Start(ctx context.Context) error {
+	r.events.add("start " + r.name)
+	time.Sleep(r.readyAfter)
+	r.events.add("ready " + r.name)
+	close(r.readyCh())
+
+	<-ctx.Done()
+	// Give runnables of other priorities the chance to stop out of order.
+	time.Sleep(10 * time.Millisecond)
+	r.events.add("stop " + r.name)
+	return nil
+}
+
+func (r *orderedRunnable) StartPriority() int {
+	return r.priority
+}
+
+func (r *orderedRunnable) WaitForReady(ctx context.Context) bool {
+	select {
+	case <-r.readyCh():
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
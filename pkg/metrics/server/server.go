@@ -216,32 +216,13 @@ func (s *defaultServer) Start(ctx context.Context) error {
 	s.bindAddr = listener.Addr().String()
 	s.mu.Unlock()
 
-	mux := http.NewServeMux()
-
-	handler := promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{
-		ErrorHandling: promhttp.HTTPErrorOnError,
-	})
-	if s.metricsFilter != nil {
-		log := log.WithValues("path", defaultMetricsEndpoint)
-		var err error
-		handler, err = s.metricsFilter(log, handler)
-		if err != nil {
-			return fmt.Errorf("failed to start metrics server: failed to add metrics filter: %w", err)
-		}
+	handlers, err := s.handlers()
+	if err != nil {
+		return fmt.Errorf("failed to start metrics server: %w", err)
 	}
-	// TODO(JoelSpeed): Use existing Kubernetes machinery for serving metrics
-	mux.Handle(defaultMetricsEndpoint, handler)
-
-	for path, extraHandler := range s.options.ExtraHandlers {
-		if s.metricsFilter != nil {
-			log := log.WithValues("path", path)
-			var err error
-			extraHandler, err = s.metricsFilter(log, extraHandler)
-			if err != nil {
-				return fmt.Errorf("failed to start metrics server: failed to add metrics filter to extra handler for path %s: %w", path, err)
-			}
-		}
-		mux.Handle(path, extraHandler)
+	mux := http.NewServeMux()
+	for path, handler := range handlers {
+		mux.Handle(path, handler)
 	}
 
 	log.Info("Serving metrics server", "bindAddress", s.options.BindAddress, "secure", s.options.SecureServing)
@@ -270,6 +251,52 @@ func (s *defaultServer) Start(ctx context.Context) error {
 	return nil
 }
 
+// Handlers returns the handlers of s by path, i.e. the metrics handler and the ExtraHandlers wrapped
+// with the filter of s, e.g. to serve them on another server rather than starting s. It returns an
+// error for Servers that were not created by NewServer.
+func Handlers(s Server) (map[string]http.Handler, error) {
+	srv, ok := s.(*defaultServer)
+	if !ok {
+		return nil, fmt.Errorf("can't get the handlers of metrics server %T", s)
+	}
+	return srv.handlers()
+}
+
+// handlers returns the metrics handler and the extra handlers by path, wrapped with metricsFilter.
+func (s *defaultServer) handlers() (map[string]http.Handler, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	handlers := map[string]http.Handler{}
+
+	handler := promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.HTTPErrorOnError,
+	})
+	if s.metricsFilter != nil {
+		log := log.WithValues("path", defaultMetricsEndpoint)
+		var err error
+		handler, err = s.metricsFilter(log, handler)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add metrics filter: %w", err)
+		}
+	}
+	// TODO(JoelSpeed): Use existing Kubernetes machinery for serving metrics
+	handlers[defaultMetricsEndpoint] = handler
+
+	for path, extraHandler := range s.options.ExtraHandlers {
+		if s.metricsFilter != nil {
+			log := log.WithValues("path", path)
+			var err error
+			extraHandler, err = s.metricsFilter(log, extraHandler)
+			if err != nil {
+				return nil, fmt.Errorf("failed to add metrics filter to extra handler for path %s: %w", path, err)
+			}
+		}
+		handlers[path] = extraHandler
+	}
+	return handlers, nil
+}
+
 func (s *defaultServer) createListener(ctx context.Context, log logr.Logger) (net.Listener, error) {
 	if !s.options.SecureServing {
 		return s.options.ListenConfig.Listen(ctx, "tcp", s.options.BindAddress)
@@ -17,6 +17,8 @@ limitations under the License.
 package metrics
 
 import (
+	"errors"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/client-go/util/workqueue"
 )
@@ -50,19 +52,21 @@ var (
 		Help:      "Total number of adds handled by workqueue",
 	}, []string{"name"})
 
-	latency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	latencyOpts = prometheus.HistogramOpts{
 		Subsystem: WorkQueueSubsystem,
 		Name:      QueueLatencyKey,
 		Help:      "How long in seconds an item stays in workqueue before being requested",
 		Buckets:   prometheus.ExponentialBuckets(10e-9, 10, 12),
-	}, []string{"name"})
+	}
+	latency = prometheus.NewHistogramVec(latencyOpts, []string{"name"})
 
-	workDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	workDurationOpts = prometheus.HistogramOpts{
 		Subsystem: WorkQueueSubsystem,
 		Name:      WorkDurationKey,
 		Help:      "How long in seconds processing an item from workqueue takes.",
 		Buckets:   prometheus.ExponentialBuckets(10e-9, 10, 12),
-	}, []string{"name"})
+	}
+	workDuration = prometheus.NewHistogramVec(workDurationOpts, []string{"name"})
 
 	unfinished = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: WorkQueueSubsystem,
@@ -128,3 +132,55 @@ func (workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name str
 func (workqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
 	return retries.WithLabelValues(name)
 }
+
+// NewWorkQueueMetricsProvider returns a workqueue.MetricsProvider that registers the same metrics with
+// Registry as the default one, but observes the queue latency and the work duration of workqueues with
+// the given histogram buckets. Nil buckets keep the default ones.
+//
+// As the buckets of a histogram can't differ between label values, the histograms with custom buckets
+// are registered as separate collectors with the name of the workqueue as a constant label. They are
+// exposed under the same metric names and labels as those of all other workqueues.
+func NewWorkQueueMetricsProvider(latencyBuckets, workDurationBuckets []float64) workqueue.MetricsProvider {
+	return customBucketsMetricsProvider{
+		latencyBuckets:      latencyBuckets,
+		workDurationBuckets: workDurationBuckets,
+	}
+}
+
+type customBucketsMetricsProvider struct {
+	workqueueMetricsProvider
+
+	latencyBuckets      []float64
+	workDurationBuckets []float64
+}
+
+func (p customBucketsMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	if p.latencyBuckets == nil {
+		return p.workqueueMetricsProvider.NewLatencyMetric(name)
+	}
+	return registerWorkQueueHistogram(latencyOpts, name, p.latencyBuckets)
+}
+
+func (p customBucketsMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	if p.workDurationBuckets == nil {
+		return p.workqueueMetricsProvider.NewWorkDurationMetric(name)
+	}
+	return registerWorkQueueHistogram(workDurationOpts, name, p.workDurationBuckets)
+}
+
+// registerWorkQueueHistogram registers a histogram for the workqueue with the given name, or returns the
+// one that is already registered for it, e.g. by a previous queue of a controller that was recreated.
+func registerWorkQueueHistogram(opts prometheus.HistogramOpts, name string, buckets []float64) prometheus.Histogram {
+	opts.Buckets = buckets
+	opts.ConstLabels = prometheus.Labels{"name": name}
+	histogram := prometheus.NewHistogram(opts)
+	if err := Registry.Register(histogram); err != nil {
+		alreadyRegistered := prometheus.AlreadyRegisteredError{}
+		if errors.As(err, &alreadyRegistered) {
+			if existing, ok := alreadyRegistered.ExistingCollector.(prometheus.Histogram); ok {
+				return existing
+			}
+		}
+	}
+	return histogram
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicate
+
+import (
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// Debouncer is a TypedDebouncer for client.Objects.
+type Debouncer = TypedDebouncer[client.Object]
+
+// Debounce returns a Debouncer collapsing update events of an object arriving within the given window.
+func Debounce(window time.Duration) *Debouncer {
+	return TypedDebounce[client.Object](window)
+}
+
+// TypedDebouncer is a predicate collapsing rapid update events, e.g. of objects whose status is
+// updated many times per second. Per object, it accepts an update event only if the last accepted
+// one is at least the window old, and suppresses the others.
+//
+// The last suppressed update event of an object is not dropped: once the window elapsed, its new
+// object is delivered as a generic event on the channel returned by Settled, which must be watched,
+// e.g. with source.Channel, for the final state of the object to be reconciled. Call Stop once the
+// channel isn't watched anymore, e.g. when the manager stops, to discard the pending update events.
+//
+// Create, delete and generic events are always accepted. A delete event discards the pending update
+// event of the object.
+//
+// TypedDebouncer is experimental and subject to future change.
+type TypedDebouncer[T client.Object] struct {
+	window  time.Duration
+	settled chan event.TypedGenericEvent[T]
+
+	stopOnce sync.Once
+	stopped  chan struct{}
+
+	mu sync.Mutex
+	// objects holds the objects with an update event accepted within the window. An object is
+	// removed once its window elapsed, after delivering its pending update event, if any.
+	objects map[client.ObjectKey]*debouncedObject[T]
+}
+
+type debouncedObject[T client.Object] struct {
+	lastAccepted time.Time
+	pending      *T
+	// timer expires the object once its window elapsed.
+	timer *time.Timer
+}
+
+// TypedDebounce returns a TypedDebouncer collapsing update events of an object arriving within the given window.
+//
+// TypedDebounce is experimental and subject to future change.
+func TypedDebounce[T client.Object](window time.Duration) *TypedDebouncer[T] {
+	return &TypedDebouncer[T]{
+		window:  window,
+		settled: make(chan event.TypedGenericEvent[T]),
+		stopped: make(chan struct{}),
+		objects: map[client.ObjectKey]*debouncedObject[T]{},
+	}
+}
+
+// Settled returns the channel the last suppressed update event of an object is delivered on, as a
+// generic event, once the window elapsed.
+func (d *TypedDebouncer[T]) Settled() <-chan event.TypedGenericEvent[T] {
+	return d.settled
+}
+
+// Stop discards the pending update events, and those suppressed afterwards, instead of delivering
+// them on the channel returned by Settled.
+func (d *TypedDebouncer[T]) Stop() {
+	d.stopOnce.Do(func() { close(d.stopped) })
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for key, obj := range d.objects {
+		obj.timer.Stop()
+		delete(d.objects, key)
+	}
+}
+
+// Create implements TypedPredicate.
+func (d *TypedDebouncer[T]) Create(event.TypedCreateEvent[T]) bool {
+	return true
+}
+
+// Update implements TypedPredicate.
+func (d *TypedDebouncer[T]) Update(e event.TypedUpdateEvent[T]) bool {
+	if isNil(e.ObjectNew) {
+		return true
+	}
+	key := client.ObjectKeyFromObject(e.ObjectNew)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	obj, ok := d.objects[key]
+	if !ok || now.Sub(obj.lastAccepted) >= d.window {
+		if ok {
+			obj.timer.Stop()
+		}
+		d.acceptLocked(key, now)
+		return true
+	}
+
+	newObj := e.ObjectNew
+	obj.pending = &newObj
+	return false
+}
+
+// Delete implements TypedPredicate.
+func (d *TypedDebouncer[T]) Delete(e event.TypedDeleteEvent[T]) bool {
+	if isNil(e.Object) {
+		return true
+	}
+	key := client.ObjectKeyFromObject(e.Object)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if obj, ok := d.objects[key]; ok {
+		obj.timer.Stop()
+		delete(d.objects, key)
+	}
+	return true
+}
+
+// Generic implements TypedPredicate.
+func (d *TypedDebouncer[T]) Generic(event.TypedGenericEvent[T]) bool {
+	return true
+}
+
+// acceptLocked records that an update event of the object with key was accepted at now.
+func (d *TypedDebouncer[T]) acceptLocked(key client.ObjectKey, now time.Time) {
+	obj := &debouncedObject[T]{lastAccepted: now}
+	obj.timer = time.AfterFunc(d.window, func() { d.expire(key, obj) })
+	d.objects[key] = obj
+}
+
+// expire removes obj once its window elapsed, or delivers its pending update event, unless it
+// was replaced or discarded meanwhile.
+func (d *TypedDebouncer[T]) expire(key client.ObjectKey, obj *debouncedObject[T]) {
+	d.mu.Lock()
+	if d.objects[key] != obj {
+		d.mu.Unlock()
+		return
+	}
+	if obj.pending == nil {
+		delete(d.objects, key)
+		d.mu.Unlock()
+		return
+	}
+	pending := *obj.pending
+	// The delivered event counts as accepted, so updates right after it are debounced again.
+	d.acceptLocked(key, time.Now())
+	d.mu.Unlock()
+
+	select {
+	case d.settled <- event.TypedGenericEvent[T]{Object: pending}:
+	case <-d.stopped:
+	}
+}
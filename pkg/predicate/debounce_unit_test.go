@@ -0,0 +1,54 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package predicate
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// The predicate package declares And, Or and Not, so gomega can't be dot-imported here.
+var _ = Describe("TypedDebouncer objects", func() {
+	It("should forget an object once its window elapsed", func() {
+		d := Debounce(50 * time.Millisecond)
+		objects := func() int {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			return len(d.objects)
+		}
+		pod := func(version string) client.Object {
+			return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: "baz", ResourceVersion: version}}
+		}
+
+		gomega.Expect(d.Update(event.UpdateEvent{ObjectOld: pod("1"), ObjectNew: pod("2")})).To(gomega.BeTrue())
+		gomega.Expect(objects()).To(gomega.Equal(1))
+		gomega.Eventually(objects).Should(gomega.BeZero())
+
+		By("Forgetting it after delivering its pending update too")
+		gomega.Expect(d.Update(event.UpdateEvent{ObjectOld: pod("2"), ObjectNew: pod("3")})).To(gomega.BeTrue())
+		gomega.Expect(d.Update(event.UpdateEvent{ObjectOld: pod("3"), ObjectNew: pod("4")})).To(gomega.BeFalse())
+		gomega.Eventually(d.Settled()).Should(gomega.Receive())
+		gomega.Eventually(objects).Should(gomega.BeZero())
+	})
+})
@@ -16,5 +16,11 @@ limitations under the License.
 
 /*
 Package predicate defines Predicates used by Controllers to filter Events before they are provided to EventHandlers.
+
+Most predicates only look at the metadata of objects, e.g. GenerationChangedPredicate, LabelChangedPredicate,
+AnnotationChangedPredicate, ResourceVersionChangedPredicate, LabelSelectorPredicate and AnnotationValuePredicate, so
+they work the same with metadata-only watches, which deliver metav1.PartialObjectMetadata objects. Predicates
+that need the spec or status of objects, like ObservedGenerationPredicate, document how they treat
+metadata-only objects; they never panic on them.
 */
 package predicate
@@ -21,9 +21,13 @@ import (
 	"reflect"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
 	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
 )
 
@@ -51,6 +55,7 @@ var _ Predicate = Funcs{}
 var _ Predicate = ResourceVersionChangedPredicate{}
 var _ Predicate = GenerationChangedPredicate{}
 var _ Predicate = AnnotationChangedPredicate{}
+var _ Predicate = ObservedGenerationPredicate{}
 var _ Predicate = or[client.Object]{}
 var _ Predicate = and[client.Object]{}
 var _ Predicate = not[client.Object]{}
@@ -283,6 +288,63 @@ func (TypedLabelChangedPredicate[T]) Update(e event.TypedUpdateEvent[T]) bool {
 	return !maps.Equal(e.ObjectNew.GetLabels(), e.ObjectOld.GetLabels())
 }
 
+// ObservedGenerationPredicate implements a create predicate function that skips objects the controller
+// already reconciled.
+//
+// This predicate will skip create events for objects whose status.observedGeneration field matches their
+// metadata.generation field, e.g. because they were reconciled before the controller was restarted, and
+// objects without a status.observedGeneration field are never skipped. This allows a controller to avoid
+// reconciling every existing object when it starts, as the initial contents of the cache produce a create
+// event for each of them.
+//
+// Caveats:
+//
+// * Only use this predicate if the Reconciler sets status.observedGeneration to the metadata.generation it
+// reconciled, and only once it fully reconciled that generation.
+//
+// * With this predicate, drift that occurred while the controller was not running, e.g. in objects owned by
+// the skipped objects or in external systems, is not corrected until the next event for the skipped objects.
+// Combine it with a periodic resync, e.g. DefaultRequeueAfter or SyncPeriod, if that matters.
+//
+// * This predicate requires full objects, as a metav1.PartialObjectMetadata carries no status. It never
+// skips metadata-only objects, e.g. when used with WatchesMetadata or OnlyMetadata.
+type ObservedGenerationPredicate = TypedObservedGenerationPredicate[client.Object]
+
+// TypedObservedGenerationPredicate implements a create predicate function that skips objects the
+// controller already reconciled.
+type TypedObservedGenerationPredicate[T client.Object] struct {
+	TypedFuncs[T]
+}
+
+// Create implements default CreateEvent filter for checking the observed generation.
+func (TypedObservedGenerationPredicate[T]) Create(e event.TypedCreateEvent[T]) bool {
+	if isNil(e.Object) {
+		log.Error(nil, "Create event has no object to create", "event", e)
+		return false
+	}
+	if _, ok := any(e.Object).(*metav1.PartialObjectMetadata); ok {
+		log.V(1).Info("Not skipping metadata-only object, the observed generation requires the full object", "event", e)
+		return true
+	}
+
+	var content map[string]interface{}
+	if u, ok := any(e.Object).(runtime.Unstructured); ok {
+		content = u.UnstructuredContent()
+	} else {
+		var err error
+		content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(e.Object)
+		if err != nil {
+			log.Error(err, "Failed to read the observed generation of the object", "event", e)
+			return true
+		}
+	}
+	observedGeneration, found, err := unstructured.NestedInt64(content, "status", "observedGeneration")
+	if err != nil || !found {
+		return true
+	}
+	return observedGeneration != e.Object.GetGeneration()
+}
+
 // And returns a composite predicate that implements a logical AND of the predicates passed to it.
 func And[T any](predicates ...TypedPredicate[T]) TypedPredicate[T] {
 	return and[T]{predicates}
@@ -398,6 +460,43 @@ func (n not[T]) Generic(e event.TypedGenericEvent[T]) bool {
 	return !n.predicate.Generic(e)
 }
 
+// Instrumented returns a predicate that counts the events accepted and rejected by the
+// predicate passed to it in the controller_runtime_predicate_evaluations_total metric,
+// labeled with the given controller name and the event type.
+func Instrumented[T any](name string, predicate TypedPredicate[T]) TypedPredicate[T] {
+	return instrumented[T]{name: name, predicate: predicate}
+}
+
+type instrumented[T any] struct {
+	name      string
+	predicate TypedPredicate[T]
+}
+
+func (i instrumented[T]) Create(e event.TypedCreateEvent[T]) bool {
+	return i.observe("create", i.predicate.Create(e))
+}
+
+func (i instrumented[T]) Update(e event.TypedUpdateEvent[T]) bool {
+	return i.observe("update", i.predicate.Update(e))
+}
+
+func (i instrumented[T]) Delete(e event.TypedDeleteEvent[T]) bool {
+	return i.observe("delete", i.predicate.Delete(e))
+}
+
+func (i instrumented[T]) Generic(e event.TypedGenericEvent[T]) bool {
+	return i.observe("generic", i.predicate.Generic(e))
+}
+
+func (i instrumented[T]) observe(eventType string, accepted bool) bool {
+	result := "rejected"
+	if accepted {
+		result = "accepted"
+	}
+	ctrlmetrics.PredicateEvaluationsTotal.WithLabelValues(i.name, eventType, result).Inc()
+	return accepted
+}
+
 // LabelSelectorPredicate constructs a Predicate from a LabelSelector.
 // Only objects matching the LabelSelector will be admitted.
 func LabelSelectorPredicate(s metav1.LabelSelector) (Predicate, error) {
@@ -410,6 +509,43 @@ func LabelSelectorPredicate(s metav1.LabelSelector) (Predicate, error) {
 	}), nil
 }
 
+// AnnotationValuePredicate constructs a Predicate that only admits objects whose annotation key
+// is set to value. Objects without the annotation don't match. For update events, the new object
+// is checked, so that updates that set the annotation to value are admitted while those that
+// change or remove it are not.
+func AnnotationValuePredicate(key, value string) Predicate {
+	return NewPredicateFuncs(func(o client.Object) bool {
+		v, ok := o.GetAnnotations()[key]
+		return ok && v == value
+	})
+}
+
+// HasOwner constructs a Predicate that only admits objects with an owner reference to an object
+// of the group and kind of gvk. The version of gvk is ignored, as owner references may refer to
+// any version of their owner. For update events, the new object is checked.
+func HasOwner(gvk schema.GroupVersionKind) Predicate {
+	return NewPredicateFuncs(func(o client.Object) bool {
+		for _, ref := range o.GetOwnerReferences() {
+			refGV, err := schema.ParseGroupVersion(ref.APIVersion)
+			if err != nil {
+				continue
+			}
+			if refGV.Group == gvk.Group && ref.Kind == gvk.Kind {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// HasNoOwner constructs a Predicate that only admits objects without owner references. For update
+// events, the new object is checked.
+func HasNoOwner() Predicate {
+	return NewPredicateFuncs(func(o client.Object) bool {
+		return len(o.GetOwnerReferences()) == 0
+	})
+}
+
 func isNil(arg any) bool {
 	if v := reflect.ValueOf(arg); !v.IsValid() || ((v.Kind() == reflect.Ptr ||
 		v.Kind() == reflect.Interface ||
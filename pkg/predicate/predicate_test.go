@@ -17,13 +17,21 @@ limitations under the License.
 package predicate_test
 
 import (
+	"fmt"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/goleak"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
@@ -408,6 +416,21 @@ var _ = Describe("Predicate", func() {
 			})
 		})
 
+		Context("Where the objects are metadata-only", func() {
+			It("should compare their Generation", func() {
+				newMeta := func(generation int64) *metav1.PartialObjectMetadata {
+					obj := &metav1.PartialObjectMetadata{
+						ObjectMeta: metav1.ObjectMeta{Name: "baz", Namespace: "biz", Generation: generation},
+					}
+					obj.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+					return obj
+				}
+
+				Expect(instance.Update(event.UpdateEvent{ObjectOld: newMeta(1), ObjectNew: newMeta(1)})).To(BeFalse())
+				Expect(instance.Update(event.UpdateEvent{ObjectOld: newMeta(1), ObjectNew: newMeta(2)})).To(BeTrue())
+			})
+		})
+
 	})
 
 	// AnnotationChangedPredicate has almost identical test cases as LabelChangedPredicates,
@@ -806,6 +829,57 @@ var _ = Describe("Predicate", func() {
 		})
 	})
 
+	Describe("When checking an ObservedGenerationPredicate", func() {
+		instance := predicate.ObservedGenerationPredicate{}
+		newDeployment := func(generation, observedGeneration int64) *appsv1.Deployment {
+			return &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "baz", Namespace: "biz", Generation: generation},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: observedGeneration},
+			}
+		}
+
+		Context("Where the observed generation matches the generation", func() {
+			It("should return false", func() {
+				Expect(instance.Create(event.CreateEvent{Object: newDeployment(2, 2)})).To(BeFalse())
+
+				u := &unstructured.Unstructured{}
+				u.SetGeneration(2)
+				Expect(unstructured.SetNestedField(u.Object, int64(2), "status", "observedGeneration")).To(Succeed())
+				Expect(instance.Create(event.CreateEvent{Object: u})).To(BeFalse())
+			})
+		})
+
+		Context("Where the observed generation is behind the generation", func() {
+			It("should return true", func() {
+				Expect(instance.Create(event.CreateEvent{Object: newDeployment(2, 1)})).To(BeTrue())
+				Expect(instance.Create(event.CreateEvent{Object: newDeployment(1, 0)})).To(BeTrue())
+			})
+		})
+
+		Context("Where the object has no observed generation", func() {
+			It("should return true", func() {
+				Expect(instance.Create(event.CreateEvent{Object: pod})).To(BeTrue())
+			})
+		})
+
+		Context("Where the object is metadata-only", func() {
+			It("should return true", func() {
+				obj := &metav1.PartialObjectMetadata{
+					ObjectMeta: metav1.ObjectMeta{Name: "baz", Namespace: "biz", Generation: 2},
+				}
+				obj.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+				Expect(instance.Create(event.CreateEvent{Object: obj})).To(BeTrue())
+			})
+		})
+
+		It("should not filter other events", func() {
+			deployment := newDeployment(2, 2)
+			Expect(instance.Update(event.UpdateEvent{ObjectOld: deployment, ObjectNew: deployment})).To(BeTrue())
+			Expect(instance.Delete(event.DeleteEvent{Object: deployment})).To(BeTrue())
+			Expect(instance.Generic(event.GenericEvent{Object: deployment})).To(BeTrue())
+		})
+	})
+
 	Context("With a boolean predicate", func() {
 		funcs := func(pass bool) predicate.Funcs {
 			return predicate.Funcs{
@@ -874,6 +948,40 @@ var _ = Describe("Predicate", func() {
 				Expect(n.Generic(event.GenericEvent{})).To(BeTrue())
 			})
 		})
+		Describe("When checking an Instrumented predicate", func() {
+			count := func(name, eventType, result string) float64 {
+				return testutil.ToFloat64(ctrlmetrics.PredicateEvaluationsTotal.WithLabelValues(name, eventType, result))
+			}
+
+			It("should count the accepted events", func() {
+				i := predicate.Instrumented("instrumented-pass", passFuncs)
+				Expect(i.Create(event.CreateEvent{})).To(BeTrue())
+				Expect(i.Update(event.UpdateEvent{})).To(BeTrue())
+				Expect(i.Update(event.UpdateEvent{})).To(BeTrue())
+				Expect(i.Delete(event.DeleteEvent{})).To(BeTrue())
+				Expect(i.Generic(event.GenericEvent{})).To(BeTrue())
+
+				Expect(count("instrumented-pass", "create", "accepted")).To(Equal(1.0))
+				Expect(count("instrumented-pass", "update", "accepted")).To(Equal(2.0))
+				Expect(count("instrumented-pass", "delete", "accepted")).To(Equal(1.0))
+				Expect(count("instrumented-pass", "generic", "accepted")).To(Equal(1.0))
+				Expect(count("instrumented-pass", "create", "rejected")).To(BeZero())
+			})
+			It("should count the rejected events", func() {
+				i := predicate.Instrumented("instrumented-fail", failFuncs)
+				Expect(i.Create(event.CreateEvent{})).To(BeFalse())
+				Expect(i.Update(event.UpdateEvent{})).To(BeFalse())
+				Expect(i.Delete(event.DeleteEvent{})).To(BeFalse())
+				Expect(i.Delete(event.DeleteEvent{})).To(BeFalse())
+				Expect(i.Generic(event.GenericEvent{})).To(BeFalse())
+
+				Expect(count("instrumented-fail", "create", "rejected")).To(Equal(1.0))
+				Expect(count("instrumented-fail", "update", "rejected")).To(Equal(1.0))
+				Expect(count("instrumented-fail", "delete", "rejected")).To(Equal(2.0))
+				Expect(count("instrumented-fail", "generic", "rejected")).To(Equal(1.0))
+				Expect(count("instrumented-fail", "generic", "accepted")).To(BeZero())
+			})
+		})
 	})
 
 	Describe("NewPredicateFuncs with a namespace filter function", func() {
@@ -956,4 +1064,189 @@ var _ = Describe("Predicate", func() {
 			})
 		})
 	})
+
+	Describe("When checking an AnnotationValuePredicate", func() {
+		instance := predicate.AnnotationValuePredicate("example.com/enabled", "true")
+		podWithAnnotations := func(annotations map[string]string) *corev1.Pod {
+			return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: "baz", Annotations: annotations}}
+		}
+
+		Context("When the annotation is missing", func() {
+			It("should return false", func() {
+				for _, p := range []*corev1.Pod{podWithAnnotations(nil), podWithAnnotations(map[string]string{"other": "true"})} {
+					Expect(instance.Create(event.CreateEvent{Object: p})).To(BeFalse())
+					Expect(instance.Delete(event.DeleteEvent{Object: p})).To(BeFalse())
+					Expect(instance.Generic(event.GenericEvent{Object: p})).To(BeFalse())
+					Expect(instance.Update(event.UpdateEvent{ObjectOld: p, ObjectNew: p})).To(BeFalse())
+				}
+			})
+		})
+
+		Context("When the annotation has another value", func() {
+			It("should return false", func() {
+				p := podWithAnnotations(map[string]string{"example.com/enabled": "false"})
+				Expect(instance.Create(event.CreateEvent{Object: p})).To(BeFalse())
+				Expect(instance.Delete(event.DeleteEvent{Object: p})).To(BeFalse())
+				Expect(instance.Generic(event.GenericEvent{Object: p})).To(BeFalse())
+				Expect(instance.Update(event.UpdateEvent{ObjectOld: p, ObjectNew: p})).To(BeFalse())
+			})
+		})
+
+		Context("When the annotation matches", func() {
+			It("should return true", func() {
+				p := podWithAnnotations(map[string]string{"example.com/enabled": "true"})
+				Expect(instance.Create(event.CreateEvent{Object: p})).To(BeTrue())
+				Expect(instance.Delete(event.DeleteEvent{Object: p})).To(BeTrue())
+				Expect(instance.Generic(event.GenericEvent{Object: p})).To(BeTrue())
+				Expect(instance.Update(event.UpdateEvent{ObjectOld: p, ObjectNew: p})).To(BeTrue())
+			})
+		})
+
+		Context("When the annotation changes to the value", func() {
+			It("should return true", func() {
+				enabled := podWithAnnotations(map[string]string{"example.com/enabled": "true"})
+				Expect(instance.Update(event.UpdateEvent{ObjectOld: podWithAnnotations(nil), ObjectNew: enabled})).To(BeTrue())
+				Expect(instance.Update(event.UpdateEvent{
+					ObjectOld: podWithAnnotations(map[string]string{"example.com/enabled": "false"}),
+					ObjectNew: enabled,
+				})).To(BeTrue())
+			})
+		})
+
+		Context("When the annotation changes from the value", func() {
+			It("should return false", func() {
+				enabled := podWithAnnotations(map[string]string{"example.com/enabled": "true"})
+				Expect(instance.Update(event.UpdateEvent{ObjectOld: enabled, ObjectNew: podWithAnnotations(nil)})).To(BeFalse())
+				Expect(instance.Update(event.UpdateEvent{
+					ObjectOld: enabled,
+					ObjectNew: podWithAnnotations(map[string]string{"example.com/enabled": "false"}),
+				})).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("When checking owner reference predicates", func() {
+		deploymentGVK := appsv1.SchemeGroupVersion.WithKind("Deployment")
+		podOwnedBy := func(refs ...metav1.OwnerReference) *corev1.Pod {
+			return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: "baz", OwnerReferences: refs}}
+		}
+		replicaSetRef := metav1.OwnerReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "foo"}
+		deploymentRef := metav1.OwnerReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "foo"}
+
+		Context("When checking a HasOwner predicate", func() {
+			instance := predicate.HasOwner(deploymentGVK)
+
+			It("should return true for objects owned by the kind", func() {
+				for _, p := range []*corev1.Pod{
+					podOwnedBy(deploymentRef),
+					podOwnedBy(replicaSetRef, deploymentRef),
+					podOwnedBy(metav1.OwnerReference{APIVersion: "apps/v1beta2", Kind: "Deployment", Name: "foo"}),
+				} {
+					Expect(instance.Create(event.CreateEvent{Object: p})).To(BeTrue())
+					Expect(instance.Delete(event.DeleteEvent{Object: p})).To(BeTrue())
+					Expect(instance.Update(event.UpdateEvent{ObjectOld: podOwnedBy(), ObjectNew: p})).To(BeTrue())
+				}
+			})
+
+			It("should return false for objects not owned by the kind", func() {
+				for _, p := range []*corev1.Pod{
+					podOwnedBy(),
+					podOwnedBy(replicaSetRef),
+					podOwnedBy(metav1.OwnerReference{APIVersion: "example.com/v1", Kind: "Deployment", Name: "foo"}),
+				} {
+					Expect(instance.Create(event.CreateEvent{Object: p})).To(BeFalse())
+					Expect(instance.Delete(event.DeleteEvent{Object: p})).To(BeFalse())
+					Expect(instance.Update(event.UpdateEvent{ObjectOld: podOwnedBy(deploymentRef), ObjectNew: p})).To(BeFalse())
+				}
+			})
+		})
+
+		Context("When checking a HasNoOwner predicate", func() {
+			instance := predicate.HasNoOwner()
+
+			It("should return true for objects without owners", func() {
+				p := podOwnedBy()
+				Expect(instance.Create(event.CreateEvent{Object: p})).To(BeTrue())
+				Expect(instance.Delete(event.DeleteEvent{Object: p})).To(BeTrue())
+				Expect(instance.Update(event.UpdateEvent{ObjectOld: podOwnedBy(deploymentRef), ObjectNew: p})).To(BeTrue())
+			})
+
+			It("should return false for owned objects", func() {
+				p := podOwnedBy(replicaSetRef)
+				Expect(instance.Create(event.CreateEvent{Object: p})).To(BeFalse())
+				Expect(instance.Delete(event.DeleteEvent{Object: p})).To(BeFalse())
+				Expect(instance.Update(event.UpdateEvent{ObjectOld: podOwnedBy(), ObjectNew: p})).To(BeFalse())
+			})
+		})
+	})
+})
+
+var _ = Describe("Debounce", func() {
+	newPod := func(name, version string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "biz", Name: name, ResourceVersion: version}}
+	}
+	update := func(oldObj, newObj client.Object) event.UpdateEvent {
+		return event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj}
+	}
+
+	It("should collapse a burst of updates and deliver the settled one", func() {
+		d := predicate.Debounce(200 * time.Millisecond)
+
+		Expect(d.Update(update(newPod("baz", "1"), newPod("baz", "2")))).To(BeTrue())
+		for i := 3; i <= 6; i++ {
+			Expect(d.Update(update(newPod("baz", fmt.Sprint(i-1)), newPod("baz", fmt.Sprint(i))))).To(BeFalse())
+		}
+
+		var settled event.GenericEvent
+		Eventually(d.Settled()).Should(Receive(&settled))
+		Expect(settled.Object.GetResourceVersion()).To(Equal("6"))
+		Consistently(d.Settled(), 400*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("should debounce every object on its own", func() {
+		d := predicate.Debounce(time.Hour)
+
+		Expect(d.Update(update(newPod("foo", "1"), newPod("foo", "2")))).To(BeTrue())
+		Expect(d.Update(update(newPod("bar", "1"), newPod("bar", "2")))).To(BeTrue())
+		Expect(d.Update(update(newPod("foo", "2"), newPod("foo", "3")))).To(BeFalse())
+	})
+
+	It("should accept updates arriving after the window", func() {
+		d := predicate.Debounce(50 * time.Millisecond)
+
+		Expect(d.Update(update(newPod("baz", "1"), newPod("baz", "2")))).To(BeTrue())
+		time.Sleep(100 * time.Millisecond)
+		Expect(d.Update(update(newPod("baz", "2"), newPod("baz", "3")))).To(BeTrue())
+		Consistently(d.Settled(), 200*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("should discard the pending update of a deleted object", func() {
+		d := predicate.Debounce(100 * time.Millisecond)
+
+		Expect(d.Update(update(newPod("baz", "1"), newPod("baz", "2")))).To(BeTrue())
+		Expect(d.Update(update(newPod("baz", "2"), newPod("baz", "3")))).To(BeFalse())
+		Expect(d.Delete(event.DeleteEvent{Object: newPod("baz", "3")})).To(BeTrue())
+		Consistently(d.Settled(), 300*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("should not block delivering a settled update once stopped", func() {
+		currentGRs := goleak.IgnoreCurrent()
+		d := predicate.Debounce(50 * time.Millisecond)
+
+		Expect(d.Update(update(newPod("baz", "1"), newPod("baz", "2")))).To(BeTrue())
+		Expect(d.Update(update(newPod("baz", "2"), newPod("baz", "3")))).To(BeFalse())
+		// Let the window elapse without receiving the settled update.
+		time.Sleep(100 * time.Millisecond)
+		d.Stop()
+
+		Eventually(func() error { return goleak.Find(currentGRs) }).Should(Succeed())
+		Consistently(d.Settled(), 200*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("should accept create and generic events", func() {
+		d := predicate.Debounce(time.Hour)
+
+		Expect(d.Create(event.CreateEvent{Object: newPod("baz", "1")})).To(BeTrue())
+		Expect(d.Generic(event.GenericEvent{Object: newPod("baz", "1")})).To(BeTrue())
+	})
 })
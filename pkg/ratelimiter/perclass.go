@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimiter
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// ClassifyFunc returns the class of an item, e.g. the type of object a
+// reconcile.Request refers to.
+type ClassifyFunc func(item interface{}) string
+
+// NewPerClassRateLimiter returns a RateLimiter that rate limits every class of
+// items, as returned by classify, with its own RateLimiter constructed by
+// newRateLimiter. This keeps a chatty class of items from exhausting the overall
+// rate limit shared with quieter classes in the same controller, while all of
+// them still share the controller's workers.
+//
+// Controllers queue reconcile.Requests, so the class must be derivable from the
+// request, e.g. from a naming convention, or from a Request type that carries
+// the kind of object it refers to.
+//
+// It only delays the requests that are requeued with rate limiting. To also keep
+// a chatty class from starving the others in the queue, classify the requests of
+// the controller the same way with controller.Options.ClassifyRequest.
+//
+// newRateLimiter defaults to workqueue.DefaultControllerRateLimiter.
+func NewPerClassRateLimiter(classify ClassifyFunc, newRateLimiter func(class string) RateLimiter) RateLimiter {
+	if newRateLimiter == nil {
+		newRateLimiter = func(string) RateLimiter { return workqueue.DefaultControllerRateLimiter() }
+	}
+	return &perClassRateLimiter{
+		classify:       classify,
+		newRateLimiter: newRateLimiter,
+		limiters:       map[string]RateLimiter{},
+	}
+}
+
+type perClassRateLimiter struct {
+	classify       ClassifyFunc
+	newRateLimiter func(class string) RateLimiter
+
+	mu       sync.Mutex
+	limiters map[string]RateLimiter
+}
+
+func (r *perClassRateLimiter) limiterFor(item interface{}) RateLimiter {
+	class := r.classify(item)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	limiter, ok := r.limiters[class]
+	if !ok {
+		limiter = r.newRateLimiter(class)
+		r.limiters[class] = limiter
+	}
+	return limiter
+}
+
+// When returns how long to wait before requeuing item, as decided by the RateLimiter of its class.
+func (r *perClassRateLimiter) When(item interface{}) time.Duration {
+	return r.limiterFor(item).When(item)
+}
+
+// Forget makes the RateLimiter of the class of item stop tracking it.
+func (r *perClassRateLimiter) Forget(item interface{}) {
+	r.limiterFor(item).Forget(item)
+}
+
+// NumRequeues returns how many times item has been requeued, as tracked by the RateLimiter of its class.
+func (r *perClassRateLimiter) NumRequeues(item interface{}) int {
+	return r.limiterFor(item).NumRequeues(item)
+}
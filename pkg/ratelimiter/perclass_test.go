@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimiter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+
+	// This appears to be needed so that the prow test runner won't fail.
+	_ "github.com/onsi/ginkgo/v2"
+	_ "github.com/onsi/gomega"
+)
+
+func classifyByPrefix(item interface{}) string {
+	return strings.SplitN(item.(string), "/", 2)[0]
+}
+
+func TestPerClassRateLimiterDoesNotStarveQuietClass(t *testing.T) {
+	queue := workqueue.NewRateLimitingQueue(NewPerClassRateLimiter(classifyByPrefix, nil))
+	defer queue.ShutDown()
+
+	// Exhaust the overall token bucket of the chatty class.
+	for i := 0; i < 300; i++ {
+		queue.AddRateLimited(fmt.Sprintf("chatty/%d", i))
+	}
+	queue.AddRateLimited("quiet/0")
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		item, _ := queue.Get()
+		queue.Done(item)
+		if item == "quiet/0" {
+			return
+		}
+	}
+	t.Fatal("quiet item was not processed within a second of being queued")
+}
+
+func TestPerClassRateLimiterTracksClassesIndependently(t *testing.T) {
+	var classes []string
+	limiter := NewPerClassRateLimiter(classifyByPrefix, func(class string) RateLimiter {
+		classes = append(classes, class)
+		return workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, time.Second)
+	})
+
+	limiter.When("a/x")
+	limiter.When("a/x")
+	limiter.When("b/y")
+
+	if got := limiter.NumRequeues("a/x"); got != 2 {
+		t.Errorf("expected 2 requeues for a/x, got %d", got)
+	}
+	if got := limiter.NumRequeues("b/y"); got != 1 {
+		t.Errorf("expected 1 requeue for b/y, got %d", got)
+	}
+	if len(classes) != 2 {
+		t.Errorf("expected a rate limiter per class, got %v", classes)
+	}
+
+	limiter.Forget("a/x")
+	if got := limiter.NumRequeues("a/x"); got != 0 {
+		t.Errorf("expected no requeues for a/x after Forget, got %d", got)
+	}
+	if got := limiter.NumRequeues("b/y"); got != 1 {
+		t.Errorf("expected Forget to not affect b/y, got %d", got)
+	}
+}
@@ -19,14 +19,27 @@ package reconcile
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"sort"
+	"sync"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // Result contains the result of a Reconciler invocation.
+//
+// Result is comparable, e.g. with == Result{}, so it only has comparable fields. Requests to
+// enqueue after a reconcile are thus not part of it, see EnqueueFollowUps.
 type Result struct {
 	// Requeue tells the Controller to requeue the reconcile key.  Defaults to false.
 	Requeue bool
@@ -34,6 +47,24 @@ type Result struct {
 	// RequeueAfter if greater than 0, tells the Controller to requeue the reconcile key after the Duration.
 	// Implies that Requeue is true, there is no need to set Requeue to true at the same time as RequeueAfter.
 	RequeueAfter time.Duration
+
+	// RequeueAt, if not zero, tells the Controller to requeue the reconcile key at the given time. The delay
+	// is computed when the key is requeued, after the Reconciler returned, and a time in the past requeues
+	// it immediately. It has no effect if RequeueAfter is set. See RequeueAt.
+	RequeueAt time.Time
+
+	// NoRequeue tells the Controller to not requeue the reconcile key, even if the Controller is configured
+	// with a DefaultRequeueAfter. It distinguishes "explicitly no requeue" from a zero Result, which means
+	// the Reconciler has no opinion. It has no effect if Requeue, RequeueAfter or RequeueAt is set.
+	NoRequeue bool
+}
+
+// RequeueAt returns a Result requeueing the reconcile key at t, e.g. the next run of a cron-like
+// controller. Unlike computing a RequeueAfter from t, this doesn't let the time until the Reconciler
+// returned drift the requeue. Times with a monotonic clock reading, e.g. derived from time.Now, are
+// compared against the monotonic clock, so that they aren't affected by changes of the wall clock.
+func RequeueAt(t time.Time) Result {
+	return Result{RequeueAt: t}
 }
 
 // IsZero returns true if this result is empty.
@@ -44,6 +75,24 @@ func (r *Result) IsZero() bool {
 	return *r == Result{}
 }
 
+// DeletedObjectPolicy determines what a Controller does with a requeue requested by the Reconciler
+// when the object the Request refers to is gone. Objects that still exist with a deletion timestamp
+// set, e.g. because they have finalizers, are not gone and are always requeued as requested.
+type DeletedObjectPolicy string
+
+const (
+	// DeletedObjectHonorRequeue requeues the Request as requested by the Reconciler. This is the default.
+	DeletedObjectHonorRequeue DeletedObjectPolicy = "HonorRequeue"
+
+	// DeletedObjectDropRequeue drops the requeue. The Request is only reconciled again if another event
+	// for it occurs, e.g. because the object was re-created.
+	DeletedObjectDropRequeue DeletedObjectPolicy = "DropRequeue"
+
+	// DeletedObjectReconcileOnce honors the first requeue after the object is gone, so that the
+	// Reconciler gets one more chance to clean up, and drops any further requeue.
+	DeletedObjectReconcileOnce DeletedObjectPolicy = "ReconcileOnce"
+)
+
 // Request contains the information necessary to reconcile a Kubernetes object.  This includes the
 // information to uniquely identify the object - its Name and Namespace.  It does NOT contain information about
 // any specific Event or the object contents itself.
@@ -99,8 +148,11 @@ type Reconciler interface {
 	// If the error is nil and the returned Result has a non-zero result.RequeueAfter, the request
 	// will be requeued after the specified duration.
 	//
-	// If the error is nil and result.RequeueAfter is zero and result.Requeue is true, the request
-	// will be requeued using exponential backoff.
+	// If the error is nil and result.RequeueAfter is zero and result.RequeueAt is non-zero, the request
+	// will be requeued at the specified time.
+	//
+	// If the error is nil and result.RequeueAfter and result.RequeueAt are zero and result.Requeue is
+	// true, the request will be requeued using exponential backoff.
 	Reconcile(context.Context, Request) (Result, error)
 }
 
@@ -112,6 +164,54 @@ var _ Reconciler = Func(nil)
 // Reconcile implements Reconciler.
 func (r Func) Reconcile(ctx context.Context, o Request) (Result, error) { return r(ctx, o) }
 
+// TypedRequest contains the key of something to reconcile, for controllers that identify what they
+// reconcile by something else than the name and namespace of a Kubernetes object, e.g. a composite
+// external ID. Like Request, it does NOT contain information about any specific Event.
+//
+// TypedRequest is experimental and subject to future change.
+type TypedRequest[K comparable] struct {
+	// Key identifies what to reconcile.
+	Key K
+}
+
+// TypedReconciler is a Reconciler for TypedRequests keyed on K. See Reconciler for details.
+//
+// TypedReconciler is experimental and subject to future change.
+type TypedReconciler[K comparable] interface {
+	// Reconcile performs a full reconciliation for the key referred to by the TypedRequest.
+	// The Result and error are handled like the ones of Reconciler.Reconcile.
+	Reconcile(context.Context, TypedRequest[K]) (Result, error)
+}
+
+// TypedFunc is a function that implements the TypedReconciler interface.
+//
+// TypedFunc is experimental and subject to future change.
+type TypedFunc[K comparable] func(context.Context, TypedRequest[K]) (Result, error)
+
+var _ TypedReconciler[string] = TypedFunc[string](nil)
+
+// Reconcile implements TypedReconciler.
+func (r TypedFunc[K]) Reconcile(ctx context.Context, req TypedRequest[K]) (Result, error) {
+	return r(ctx, req)
+}
+
+// ClusterKey identifies an object in one of several clusters. It is the key of the TypedRequests
+// of controllers reconciling objects of a cache.MultiCluster, see source.MultiClusterKind.
+//
+// ClusterKey is experimental and subject to future change.
+type ClusterKey struct {
+	// ClusterName is the name of the cluster the object is in.
+	ClusterName string
+
+	// NamespacedName is the name and namespace of the object to reconcile.
+	types.NamespacedName
+}
+
+// String returns the cluster name, namespace and name of the object, separated by slashes.
+func (k ClusterKey) String() string {
+	return k.ClusterName + "/" + k.NamespacedName.String()
+}
+
 // ObjectReconciler is a specialized version of Reconciler that acts on instances of client.Object. Each reconciliation
 // event gets the associated object from Kubernetes before passing it to Reconcile. An ObjectReconciler can be used in
 // Builder.Complete by calling AsReconciler. See Reconciler for more details.
@@ -142,6 +242,256 @@ func (a *objectReconcilerAdapter[T]) Reconcile(ctx context.Context, req Request)
 	return a.objReconciler.Reconcile(ctx, o)
 }
 
+// PhasedReconciler is a specialized version of ObjectReconciler that splits the reconciliation of an object into
+// two phases: Reconcile acts on the spec of the object, then UpdateStatus sets its status. The status is written by
+// the framework, so that the phases don't need to handle updating it. A PhasedReconciler can be used in
+// Builder.Complete by calling AsPhasedReconciler.
+type PhasedReconciler[T client.Object] interface {
+	// Reconcile reconciles the spec of the object. It must not update the status of the object.
+	Reconcile(context.Context, T) (Result, error)
+
+	// UpdateStatus sets the status of the object from the Result and error of Reconcile. It is called after
+	// every Reconcile, even if it failed or requeues, and must only modify the status of the object. It is
+	// called again with the latest version of the object if the status update conflicts.
+	UpdateStatus(ctx context.Context, obj T, result Result, reconcileErr error) error
+}
+
+// AsPhasedReconciler creates a Reconciler based on the given PhasedReconciler. Each reconciliation gets the
+// object, calls Reconcile and then UpdateStatus, and updates the status of the object if UpdateStatus changed
+// it, retrying on conflicts. It returns the Result of Reconcile, and its error combined with the one of the
+// status update, if any.
+func AsPhasedReconciler[T client.Object](client client.Client, rec PhasedReconciler[T]) Reconciler {
+	return &phasedReconcilerAdapter[T]{
+		phasedReconciler: rec,
+		client:           client,
+	}
+}
+
+type phasedReconcilerAdapter[T client.Object] struct {
+	phasedReconciler PhasedReconciler[T]
+	client           client.Client
+}
+
+// Reconcile implements Reconciler.
+func (a *phasedReconcilerAdapter[T]) Reconcile(ctx context.Context, req Request) (Result, error) {
+	o := a.newObject()
+	if err := a.client.Get(ctx, req.NamespacedName, o); err != nil {
+		return Result{}, client.IgnoreNotFound(err)
+	}
+
+	result, reconcileErr := a.phasedReconciler.Reconcile(ctx, o)
+	if statusErr := a.updateStatus(ctx, req, o, result, reconcileErr); statusErr != nil {
+		if reconcileErr == nil {
+			return result, statusErr
+		}
+		return result, kerrors.NewAggregate([]error{reconcileErr, statusErr})
+	}
+	return result, reconcileErr
+}
+
+// updateStatus calls UpdateStatus and writes the status if it changed, starting from o and
+// getting the latest version of the object on conflicts.
+func (a *phasedReconcilerAdapter[T]) updateStatus(ctx context.Context, req Request, o T, result Result, reconcileErr error) error {
+	first := true
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !first {
+			o = a.newObject()
+			if err := a.client.Get(ctx, req.NamespacedName, o); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		before := o.DeepCopyObject()
+		if err := a.phasedReconciler.UpdateStatus(ctx, o, result, reconcileErr); err != nil {
+			return err
+		}
+		if equality.Semantic.DeepEqual(before, o) {
+			return nil
+		}
+		return a.client.Status().Update(ctx, o)
+	})
+	return client.IgnoreNotFound(err)
+}
+
+func (a *phasedReconcilerAdapter[T]) newObject() T {
+	return reflect.New(reflect.TypeOf(*new(T)).Elem()).Interface().(T)
+}
+
+// ByGVK creates a Reconciler that routes each request to the Reconciler registered for the kind of
+// the object it refers to, for controllers that watch several kinds and enqueue requests for all of
+// them. As a Request doesn't carry the kind of its object, the kind is resolved by getting an object
+// with the name of the request for each registered kind. If objects of several registered kinds have
+// that name, the request is routed to the Reconciler of each of them, and their Results and errors
+// are combined: the combined Result requeues as soon as any of them asks for it, and only sets
+// NoRequeue if all of them do. Requests for objects that don't exist, e.g. because they were deleted or are of a
+// kind that isn't registered, are ignored.
+//
+// Kinds that are not registered with the scheme of the client are got as unstructured objects.
+func ByGVK(c client.Client, reconcilers map[schema.GroupVersionKind]Reconciler) Reconciler {
+	gvks := make([]schema.GroupVersionKind, 0, len(reconcilers))
+	for gvk := range reconcilers {
+		gvks = append(gvks, gvk)
+	}
+	sort.Slice(gvks, func(i, j int) bool { return gvks[i].String() < gvks[j].String() })
+
+	return &byGVKReconciler{
+		client:      c,
+		gvks:        gvks,
+		reconcilers: reconcilers,
+	}
+}
+
+type byGVKReconciler struct {
+	client      client.Client
+	gvks        []schema.GroupVersionKind
+	reconcilers map[schema.GroupVersionKind]Reconciler
+}
+
+// Reconcile implements Reconciler.
+func (r *byGVKReconciler) Reconcile(ctx context.Context, req Request) (Result, error) {
+	var (
+		result    Result
+		errs      []error
+		found     bool
+		noRequeue = true
+	)
+	for _, gvk := range r.gvks {
+		if err := r.client.Get(ctx, req.NamespacedName, r.newObject(gvk)); err != nil {
+			if !apierrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("failed to get %s %s: %w", gvk.Kind, req.NamespacedName, err))
+			}
+			continue
+		}
+		found = true
+
+		res, err := r.reconcilers[gvk].Reconcile(ctx, req)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		result.Requeue = result.Requeue || res.Requeue
+		if res.RequeueAfter > 0 && (result.RequeueAfter == 0 || res.RequeueAfter < result.RequeueAfter) {
+			result.RequeueAfter = res.RequeueAfter
+		}
+		if !res.RequeueAt.IsZero() && (result.RequeueAt.IsZero() || res.RequeueAt.Before(result.RequeueAt)) {
+			result.RequeueAt = res.RequeueAt
+		}
+		noRequeue = noRequeue && res.NoRequeue
+	}
+	if !found && len(errs) == 0 {
+		log.FromContext(ctx).V(1).Info("No object of a registered kind found for the request, ignoring it")
+	}
+	result.NoRequeue = found && noRequeue
+	return result, kerrors.NewAggregate(errs)
+}
+
+func (r *byGVKReconciler) newObject(gvk schema.GroupVersionKind) client.Object {
+	if obj, err := r.client.Scheme().New(gvk); err == nil {
+		if o, ok := obj.(client.Object); ok {
+			return o
+		}
+	}
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	return u
+}
+
+type triggeringEventKey struct{}
+
+// TriggeringEvent returns the event that triggered the reconciliation of the current Request, e.g. an
+// event.UpdateEvent with both the old and the new object, or nil if it is unknown. It is only known
+// if the controller was configured to record triggering events and the Request was enqueued by one of
+// the EventHandlers of the handler package.
+//
+// If multiple events were collapsed into one Request by the queue, the most recent one is returned.
+// Requests that were requeued, e.g. because of an error or a Result asking for it, have no triggering
+// event, unless an event for the same Request occurred in the meantime.
+func TriggeringEvent(ctx context.Context) any {
+	return ctx.Value(triggeringEventKey{})
+}
+
+// WithTriggeringEvent returns a copy of ctx carrying the event that triggered the reconciliation,
+// to be retrieved with TriggeringEvent. It is meant to be used by controller implementations.
+func WithTriggeringEvent(ctx context.Context, evt any) context.Context {
+	return context.WithValue(ctx, triggeringEventKey{}, evt)
+}
+
+// ChildKey identifies an object owned by the object of a Request.
+type ChildKey struct {
+	// GroupKind is the Group and Kind of the object.
+	GroupKind schema.GroupKind
+
+	types.NamespacedName
+}
+
+type changedChildrenKey struct{}
+
+// ChangedChildren returns the owned objects whose events enqueued the current Request since the owner
+// was last reconciled, sorted by GroupKind, Namespace and Name. Multiple events of the same child are
+// reported once. The children are only known if the controller was configured to record changed children
+// and the Request was enqueued by the EventHandler returned by handler.EnqueueRequestForOwner.
+//
+// The set is cleared when the reconcile starts, so the children of a Request that is requeued, e.g.
+// because of an error, are not reported again, unless they changed again in the meantime.
+func ChangedChildren(ctx context.Context) []ChildKey {
+	children, _ := ctx.Value(changedChildrenKey{}).([]ChildKey)
+	return children
+}
+
+// WithChangedChildren returns a copy of ctx carrying the changed children of the reconciled object,
+// to be retrieved with ChangedChildren. It is meant to be used by controller implementations.
+func WithChangedChildren(ctx context.Context, children []ChildKey) context.Context {
+	return context.WithValue(ctx, changedChildrenKey{}, children)
+}
+
+// MaxFollowUps is the maximum number of follow-up Requests a Controller enqueues after a reconcile,
+// guarding against an unbounded fan-out.
+const MaxFollowUps = 100
+
+type followUpsKey struct{}
+
+// FollowUps collects the follow-up Requests of a reconcile, see EnqueueFollowUps.
+type FollowUps struct {
+	mu       sync.Mutex
+	requests []Request
+}
+
+// Requests returns the follow-up Requests collected so far.
+func (f *FollowUps) Requests() []Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Request(nil), f.requests...)
+}
+
+// EnqueueFollowUps asks the controller to enqueue reqs, e.g. for objects that have to be reconciled
+// as soon as the one of the current reconcile is, after the Reconciler returns, without coupling the
+// Reconciler to the workqueue. They are enqueued independently of whether the reconcile key is
+// requeued, and are dropped if the Reconciler returns an error. The controller enqueues at most
+// MaxFollowUps of them per reconcile and drops the rest.
+//
+// Follow-ups are requested through ctx rather than returned in the Result, as a slice field would
+// make Result non-comparable and break the Reconcilers comparing it, e.g. with == Result{}.
+//
+// It returns false if the controller doesn't support follow-ups, i.e. ctx carries no FollowUps, in
+// which case reqs are not enqueued. Controllers for TypedRequests, e.g. those created with
+// controller.NewTyped, don't support them.
+func EnqueueFollowUps(ctx context.Context, reqs ...Request) bool {
+	f, ok := ctx.Value(followUpsKey{}).(*FollowUps)
+	if !ok {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, reqs...)
+	return true
+}
+
+// WithFollowUps returns a copy of ctx collecting the Requests passed to EnqueueFollowUps in f.
+// It is meant to be used by controller implementations.
+func WithFollowUps(ctx context.Context, f *FollowUps) context.Context {
+	return context.WithValue(ctx, followUpsKey{}, f)
+}
+
 // TerminalError is an error that will not be retried but still be logged
 // and recorded in metrics.
 func TerminalError(wrapped error) error {
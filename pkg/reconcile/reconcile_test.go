@@ -18,16 +18,21 @@ package reconcile_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -62,6 +67,23 @@ var _ = Describe("reconcile", func() {
 		})
 	})
 
+	Describe("EnqueueFollowUps", func() {
+		It("should collect the follow-up requests in the FollowUps of the context", func() {
+			followUps := &reconcile.FollowUps{}
+			ctx := reconcile.WithFollowUps(context.Background(), followUps)
+			first := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "bar"}}
+			second := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "baz"}}
+
+			Expect(reconcile.EnqueueFollowUps(ctx, first)).To(BeTrue())
+			Expect(reconcile.EnqueueFollowUps(ctx, second)).To(BeTrue())
+			Expect(followUps.Requests()).To(Equal([]reconcile.Request{first, second}))
+		})
+
+		It("should return false if the context carries no FollowUps", func() {
+			Expect(reconcile.EnqueueFollowUps(context.Background(), reconcile.Request{})).To(BeFalse())
+		})
+	})
+
 	Describe("Func", func() {
 		It("should call the function with the request and return a nil error.", func() {
 			request := reconcile.Request{
@@ -186,3 +208,282 @@ var _ = Describe("reconcile", func() {
 		})
 	})
 })
+
+type mockPhasedReconciler struct {
+	reconcileFunc    func(context.Context, *appsv1.Deployment) (reconcile.Result, error)
+	updateStatusFunc func(context.Context, *appsv1.Deployment, reconcile.Result, error) error
+}
+
+func (r *mockPhasedReconciler) Reconcile(ctx context.Context, dep *appsv1.Deployment) (reconcile.Result, error) {
+	return r.reconcileFunc(ctx, dep)
+}
+
+func (r *mockPhasedReconciler) UpdateStatus(ctx context.Context, dep *appsv1.Deployment, result reconcile.Result, reconcileErr error) error {
+	return r.updateStatusFunc(ctx, dep, result, reconcileErr)
+}
+
+var _ = Describe("AsPhasedReconciler", func() {
+	var (
+		dep          *appsv1.Deployment
+		key          client.ObjectKey
+		statusWrites int
+		conflicts    int
+		testClient   client.Client
+	)
+
+	BeforeEach(func() {
+		dep = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test", Generation: 2}}
+		key = client.ObjectKeyFromObject(dep)
+		statusWrites = 0
+		conflicts = 0
+		testClient = fake.NewClientBuilder().
+			WithObjects(dep).
+			WithStatusSubresource(dep).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+					statusWrites++
+					if conflicts > 0 {
+						conflicts--
+						// Another writer updates the object in the meantime.
+						latest := &appsv1.Deployment{}
+						Expect(c.Get(ctx, key, latest)).To(Succeed())
+						latest.Labels = map[string]string{"updated": "true"}
+						Expect(c.Update(ctx, latest)).To(Succeed())
+						return apierrors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, obj.GetName(), errors.New("conflict"))
+					}
+					return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+				},
+			}).
+			Build()
+	})
+
+	setObservedGeneration := func(ctx context.Context, dep *appsv1.Deployment, _ reconcile.Result, _ error) error {
+		dep.Status.ObservedGeneration = dep.Generation
+		return nil
+	}
+
+	It("should write the status once after the spec phase, even when it requeues", func() {
+		var statusResult reconcile.Result
+		reconciler := reconcile.AsPhasedReconciler(testClient, &mockPhasedReconciler{
+			reconcileFunc: func(ctx context.Context, dep *appsv1.Deployment) (reconcile.Result, error) {
+				return reconcile.Result{RequeueAfter: time.Minute}, nil
+			},
+			updateStatusFunc: func(ctx context.Context, dep *appsv1.Deployment, result reconcile.Result, reconcileErr error) error {
+				statusResult = result
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				return setObservedGeneration(ctx, dep, result, reconcileErr)
+			},
+		})
+
+		res, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+		Expect(statusResult).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+		Expect(statusWrites).To(Equal(1))
+
+		actual := &appsv1.Deployment{}
+		Expect(testClient.Get(context.Background(), key, actual)).To(Succeed())
+		Expect(actual.Status.ObservedGeneration).To(Equal(int64(2)))
+	})
+
+	It("should pass the error of the spec phase to the status phase and return it", func() {
+		reconcileErr := errors.New("spec failed")
+		reconciler := reconcile.AsPhasedReconciler(testClient, &mockPhasedReconciler{
+			reconcileFunc: func(ctx context.Context, dep *appsv1.Deployment) (reconcile.Result, error) {
+				return reconcile.Result{}, reconcileErr
+			},
+			updateStatusFunc: func(ctx context.Context, dep *appsv1.Deployment, result reconcile.Result, err error) error {
+				Expect(err).To(MatchError(reconcileErr))
+				dep.Status.Conditions = []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Message: err.Error()}}
+				return nil
+			},
+		})
+
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).To(MatchError(reconcileErr))
+		Expect(statusWrites).To(Equal(1))
+
+		actual := &appsv1.Deployment{}
+		Expect(testClient.Get(context.Background(), key, actual)).To(Succeed())
+		Expect(actual.Status.Conditions).To(HaveLen(1))
+		Expect(actual.Status.Conditions[0].Message).To(Equal("spec failed"))
+	})
+
+	It("should not write the status if it did not change", func() {
+		reconciler := reconcile.AsPhasedReconciler(testClient, &mockPhasedReconciler{
+			reconcileFunc: func(ctx context.Context, dep *appsv1.Deployment) (reconcile.Result, error) {
+				return reconcile.Result{}, nil
+			},
+			updateStatusFunc: func(context.Context, *appsv1.Deployment, reconcile.Result, error) error {
+				return nil
+			},
+		})
+
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statusWrites).To(BeZero())
+	})
+
+	It("should retry the status phase with the latest object on conflicts", func() {
+		conflicts = 2
+		reconciles := 0
+		var labels []map[string]string
+		reconciler := reconcile.AsPhasedReconciler(testClient, &mockPhasedReconciler{
+			reconcileFunc: func(ctx context.Context, dep *appsv1.Deployment) (reconcile.Result, error) {
+				reconciles++
+				return reconcile.Result{}, nil
+			},
+			updateStatusFunc: func(ctx context.Context, dep *appsv1.Deployment, result reconcile.Result, reconcileErr error) error {
+				labels = append(labels, dep.Labels)
+				return setObservedGeneration(ctx, dep, result, reconcileErr)
+			},
+		})
+
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciles).To(Equal(1))
+		Expect(statusWrites).To(Equal(3))
+		Expect(labels).To(Equal([]map[string]string{nil, {"updated": "true"}, {"updated": "true"}}))
+
+		actual := &appsv1.Deployment{}
+		Expect(testClient.Get(context.Background(), key, actual)).To(Succeed())
+		Expect(actual.Status.ObservedGeneration).To(Equal(int64(2)))
+	})
+
+	It("should return the error of the status update", func() {
+		reconciler := reconcile.AsPhasedReconciler(testClient, &mockPhasedReconciler{
+			reconcileFunc: func(ctx context.Context, dep *appsv1.Deployment) (reconcile.Result, error) {
+				return reconcile.Result{}, nil
+			},
+			updateStatusFunc: func(context.Context, *appsv1.Deployment, reconcile.Result, error) error {
+				return errors.New("status failed")
+			},
+		})
+
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+		Expect(err).To(MatchError("status failed"))
+	})
+})
+
+var _ = Describe("ByGVK", func() {
+	var (
+		testClient       client.Client
+		configMaps       []reconcile.Request
+		deployments      []reconcile.Request
+		configMapResult  reconcile.Result
+		deploymentResult reconcile.Result
+		reconciler       reconcile.Reconciler
+	)
+
+	BeforeEach(func() {
+		testClient = fake.NewClientBuilder().WithObjects(
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "config"}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}},
+		).Build()
+		configMaps = nil
+		deployments = nil
+		configMapResult = reconcile.Result{RequeueAfter: time.Minute}
+		deploymentResult = reconcile.Result{RequeueAfter: time.Second}
+		reconciler = reconcile.ByGVK(testClient, map[schema.GroupVersionKind]reconcile.Reconciler{
+			corev1.SchemeGroupVersion.WithKind("ConfigMap"): reconcile.Func(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+				configMaps = append(configMaps, req)
+				return configMapResult, nil
+			}),
+			appsv1.SchemeGroupVersion.WithKind("Deployment"): reconcile.Func(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+				deployments = append(deployments, req)
+				return deploymentResult, nil
+			}),
+		})
+	})
+
+	It("should route requests to the Reconciler of the kind of their object", func() {
+		configReq := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "config"}}
+		res, err := reconciler.Reconcile(context.Background(), configReq)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+		Expect(configMaps).To(ConsistOf(configReq))
+		Expect(deployments).To(BeEmpty())
+
+		appReq := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "app"}}
+		res, err = reconciler.Reconcile(context.Background(), appReq)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(reconcile.Result{RequeueAfter: time.Second}))
+		Expect(configMaps).To(ConsistOf(configReq))
+		Expect(deployments).To(ConsistOf(appReq))
+	})
+
+	It("should route requests to each kind with an object of their name and combine the results", func() {
+		Expect(testClient.Create(context.Background(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}})).To(Succeed())
+
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "app"}}
+		res, err := reconciler.Reconcile(context.Background(), req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(reconcile.Result{RequeueAfter: time.Second}))
+		Expect(configMaps).To(ConsistOf(req))
+		Expect(deployments).To(ConsistOf(req))
+	})
+
+	It("should combine the RequeueAt of the results to the earliest one", func() {
+		Expect(testClient.Create(context.Background(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}})).To(Succeed())
+		now := time.Now()
+		configMapResult = reconcile.RequeueAt(now.Add(time.Hour))
+		deploymentResult = reconcile.Result{}
+
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "app"}}
+		res, err := reconciler.Reconcile(context.Background(), req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(reconcile.RequeueAt(now.Add(time.Hour))))
+
+		deploymentResult = reconcile.RequeueAt(now.Add(time.Minute))
+		res, err = reconciler.Reconcile(context.Background(), req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(reconcile.RequeueAt(now.Add(time.Minute))))
+	})
+
+	It("should only set NoRequeue if all results set it", func() {
+		Expect(testClient.Create(context.Background(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}})).To(Succeed())
+		configMapResult = reconcile.Result{NoRequeue: true}
+		deploymentResult = reconcile.Result{}
+
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "app"}}
+		res, err := reconciler.Reconcile(context.Background(), req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.NoRequeue).To(BeFalse())
+
+		deploymentResult = reconcile.Result{NoRequeue: true}
+		res, err = reconciler.Reconcile(context.Background(), req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(reconcile.Result{NoRequeue: true}))
+	})
+
+	It("should ignore requests for objects that don't exist or are of unregistered kinds", func() {
+		Expect(testClient.Create(context.Background(), &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "secret"}})).To(Succeed())
+
+		for _, name := range []string{"missing", "secret"} {
+			res, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.IsZero()).To(BeTrue())
+		}
+		Expect(configMaps).To(BeEmpty())
+		Expect(deployments).To(BeEmpty())
+	})
+
+	It("should return the errors getting the object", func() {
+		testClient = interceptor.NewClient(testClient.(client.WithWatch), interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*corev1.ConfigMap); ok {
+					return errors.New("get failed")
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		})
+		reconciler = reconcile.ByGVK(testClient, map[schema.GroupVersionKind]reconcile.Reconciler{
+			corev1.SchemeGroupVersion.WithKind("ConfigMap"): reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{}, nil
+			}),
+		})
+
+		_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "config"}})
+		Expect(err).To(MatchError(ContainSubstring("get failed")))
+	})
+})
@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reconciletest provides a Harness to drive a Reconciler through a
+// predefined sequence of object states in tests, without a running informer.
+package reconciletest
+
+import (
+	"context"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Verb is the kind of a client Operation.
+type Verb string
+
+// Verbs of the client operations recorded by a Harness.
+const (
+	Get         Verb = "get"
+	List        Verb = "list"
+	Create      Verb = "create"
+	Update      Verb = "update"
+	Patch       Verb = "patch"
+	Delete      Verb = "delete"
+	DeleteAllOf Verb = "deleteallof"
+)
+
+// Operation is a client operation made by the Reconciler of a Harness.
+type Operation struct {
+	// Verb is the kind of the operation.
+	Verb Verb
+
+	// GroupVersionKind is the GroupVersionKind of the object the operation was made with.
+	// For List it is the GroupVersionKind of the list.
+	GroupVersionKind schema.GroupVersionKind
+
+	// Key is the key of the object the operation was made with. It is empty for
+	// List and DeleteAllOf.
+	Key client.ObjectKey
+
+	// SubResource is the subresource the operation was made on, e.g. "status".
+	SubResource string
+
+	// Err is the error the operation returned.
+	Err error
+}
+
+// Harness calls a Reconciler for the requests it is triggered with, and records
+// the client operations the Reconciler makes.
+type Harness struct {
+	client     client.WithWatch
+	reconciler reconcile.Reconciler
+
+	mu         sync.Mutex
+	operations []Operation
+}
+
+// New returns a Harness for the Reconciler returned by newReconciler, which is
+// passed a client that reads from and writes to c, usually a fake client, and
+// records the operations made with it.
+func New(c client.WithWatch, newReconciler func(client.Client) reconcile.Reconciler) *Harness {
+	h := &Harness{client: c}
+	h.reconciler = newReconciler(interceptor.NewClient(c, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			return h.record(Get, key, obj, "", c.Get(ctx, key, obj, opts...))
+		},
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			return h.record(List, client.ObjectKey{}, list, "", c.List(ctx, list, opts...))
+		},
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			return h.record(Create, client.ObjectKeyFromObject(obj), obj, "", c.Create(ctx, obj, opts...))
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			return h.record(Update, client.ObjectKeyFromObject(obj), obj, "", c.Update(ctx, obj, opts...))
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			return h.record(Patch, client.ObjectKeyFromObject(obj), obj, "", c.Patch(ctx, obj, patch, opts...))
+		},
+		Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+			return h.record(Delete, client.ObjectKeyFromObject(obj), obj, "", c.Delete(ctx, obj, opts...))
+		},
+		DeleteAllOf: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteAllOfOption) error {
+			return h.record(DeleteAllOf, client.ObjectKey{}, obj, "", c.DeleteAllOf(ctx, obj, opts...))
+		},
+		SubResourceGet: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceGetOption) error {
+			return h.record(Get, client.ObjectKeyFromObject(obj), obj, subResourceName, c.SubResource(subResourceName).Get(ctx, obj, subResource, opts...))
+		},
+		SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+			return h.record(Create, client.ObjectKeyFromObject(obj), obj, subResourceName, c.SubResource(subResourceName).Create(ctx, obj, subResource, opts...))
+		},
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			return h.record(Update, client.ObjectKeyFromObject(obj), obj, subResourceName, c.SubResource(subResourceName).Update(ctx, obj, opts...))
+		},
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			return h.record(Patch, client.ObjectKeyFromObject(obj), obj, subResourceName, c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...))
+		},
+	}))
+	return h
+}
+
+func (h *Harness) record(verb Verb, key client.ObjectKey, obj runtime.Object, subResource string, err error) error {
+	gvk, _ := h.client.GroupVersionKindFor(obj)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.operations = append(h.operations, Operation{
+		Verb:             verb,
+		GroupVersionKind: gvk,
+		Key:              key,
+		SubResource:      subResource,
+		Err:              err,
+	})
+	return err
+}
+
+// Trigger calls the Reconciler with req and returns its result.
+func (h *Harness) Trigger(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return h.reconciler.Reconcile(ctx, req)
+}
+
+// TriggerFor calls the Reconciler with the request for obj and returns its result.
+func (h *Harness) TriggerFor(ctx context.Context, obj client.Object) (reconcile.Result, error) {
+	return h.Trigger(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+}
+
+// Operations returns the client operations the Reconciler made so far, in order.
+func (h *Harness) Operations() []Operation {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]Operation(nil), h.operations...)
+}
+
+// ResetOperations forgets the client operations recorded so far, e.g. to only
+// assert on the operations of the next Trigger.
+func (h *Harness) ResetOperations() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.operations = nil
+}
+
+// Apply advances the state the Reconciler observes by creating obj, or updating
+// it if it already exists. Operations made by Apply are not recorded.
+func (h *Harness) Apply(ctx context.Context, obj client.Object) error {
+	current := obj.DeepCopyObject().(client.Object)
+	if err := h.client.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		obj.SetResourceVersion("")
+		return h.client.Create(ctx, obj)
+	}
+
+	obj.SetResourceVersion(current.GetResourceVersion())
+	return h.client.Update(ctx, obj)
+}
+
+// Remove advances the state the Reconciler observes by deleting obj. Operations
+// made by Remove are not recorded.
+func (h *Harness) Remove(ctx context.Context, obj client.Object) error {
+	return h.client.Delete(ctx, obj)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciletest_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile/reconciletest"
+)
+
+// mirrorReconciler mirrors the data of a ConfigMap into a Secret of the same name.
+type mirrorReconciler struct {
+	client client.Client
+}
+
+func (r *mirrorReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	cm := &corev1.ConfigMap{}
+	if err := r.client.Get(ctx, req.NamespacedName, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: req.Namespace, Name: req.Name}}
+		return reconcile.Result{}, client.IgnoreNotFound(r.client.Delete(ctx, secret))
+	}
+
+	secret := &corev1.Secret{}
+	err := r.client.Get(ctx, req.NamespacedName, secret)
+	if apierrors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: req.Namespace, Name: req.Name},
+			StringData: cm.Data,
+		}
+		return reconcile.Result{}, r.client.Create(ctx, secret)
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	secret.Data = nil
+	secret.StringData = cm.Data
+	return reconcile.Result{}, r.client.Update(ctx, secret)
+}
+
+var _ = Describe("Harness", func() {
+	var (
+		ctx     context.Context
+		c       client.WithWatch
+		harness *reconciletest.Harness
+		cm      *corev1.ConfigMap
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		c = fake.NewClientBuilder().Build()
+		harness = reconciletest.New(c, func(c client.Client) reconcile.Reconciler {
+			return &mirrorReconciler{client: c}
+		})
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "config"},
+			Data:       map[string]string{"key": "value"},
+		}
+	})
+
+	operation := func(verb reconciletest.Verb, kind string) reconciletest.Operation {
+		return reconciletest.Operation{
+			Verb:             verb,
+			GroupVersionKind: corev1.SchemeGroupVersion.WithKind(kind),
+			Key:              client.ObjectKeyFromObject(cm),
+		}
+	}
+
+	It("should drive the reconciler through create, update and delete", func() {
+		By("creating the ConfigMap")
+		Expect(harness.Apply(ctx, cm)).To(Succeed())
+		Expect(harness.TriggerFor(ctx, cm)).To(Equal(reconcile.Result{}))
+
+		secret := &corev1.Secret{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(cm), secret)).To(Succeed())
+		Expect(secret.StringData).To(Equal(map[string]string{"key": "value"}))
+
+		By("updating the ConfigMap")
+		cm.Data = map[string]string{"key": "other"}
+		Expect(harness.Apply(ctx, cm)).To(Succeed())
+		Expect(harness.TriggerFor(ctx, cm)).To(Equal(reconcile.Result{}))
+
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(cm), secret)).To(Succeed())
+		Expect(secret.StringData).To(Equal(map[string]string{"key": "other"}))
+
+		By("deleting the ConfigMap")
+		Expect(harness.Remove(ctx, cm)).To(Succeed())
+		Expect(harness.TriggerFor(ctx, cm)).To(Equal(reconcile.Result{}))
+
+		err := c.Get(ctx, client.ObjectKeyFromObject(cm), secret)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should record the sequence of client operations", func() {
+		Expect(harness.Apply(ctx, cm)).To(Succeed())
+		Expect(harness.TriggerFor(ctx, cm)).To(Equal(reconcile.Result{}))
+
+		notFound := operation(reconciletest.Get, "Secret")
+		notFound.Err = apierrors.NewNotFound(corev1.Resource("secrets"), cm.Name)
+		Expect(harness.Operations()).To(Equal([]reconciletest.Operation{
+			operation(reconciletest.Get, "ConfigMap"),
+			notFound,
+			operation(reconciletest.Create, "Secret"),
+		}))
+
+		harness.ResetOperations()
+		Expect(harness.Remove(ctx, cm)).To(Succeed())
+		Expect(harness.TriggerFor(ctx, cm)).To(Equal(reconcile.Result{}))
+
+		notFound = operation(reconciletest.Get, "ConfigMap")
+		notFound.Err = apierrors.NewNotFound(corev1.Resource("configmaps"), cm.Name)
+		Expect(harness.Operations()).To(Equal([]reconciletest.Operation{
+			notFound,
+			operation(reconciletest.Delete, "Secret"),
+		}))
+	})
+})
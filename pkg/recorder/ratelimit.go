@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recorder
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/lru"
+)
+
+const (
+	defaultRateLimitInterval = 5 * time.Minute
+	defaultRateLimitBurst    = 1
+	defaultRateLimitMaxKeys  = 4096
+)
+
+// RateLimitOptions configures how identical events are rate limited, see NewRateLimitedRecorder.
+type RateLimitOptions struct {
+	// Interval is the period identical events are counted in. Defaults to 5 minutes.
+	Interval time.Duration
+
+	// Burst is the number of identical events recorded per Interval. Defaults to 1.
+	Burst int
+
+	// MaxKeys is the maximum number of distinct events that are tracked. The least recently
+	// recorded ones are forgotten beyond that. Defaults to 4096.
+	MaxKeys int
+}
+
+func (o *RateLimitOptions) defaults() {
+	if o.Interval <= 0 {
+		o.Interval = defaultRateLimitInterval
+	}
+	if o.Burst <= 0 {
+		o.Burst = defaultRateLimitBurst
+	}
+	if o.MaxKeys <= 0 {
+		o.MaxKeys = defaultRateLimitMaxKeys
+	}
+}
+
+// NewRateLimitedRecorder wraps an EventRecorder so that identical events, i.e. events with the same
+// object, type, reason and message, are recorded at most Burst times per Interval, e.g. to keep a
+// Reconciler that fails repeatedly from flooding the events API. Suppressed events are aggregated: the
+// next identical event recorded after the Interval passed reports how many were suppressed. Events
+// that differ in any of those fields, e.g. in their message, are rate limited independently.
+//
+// This complements the event correlation of the EventBroadcaster, which happens after the events
+// were queued for sending and can't be configured per recorder.
+func NewRateLimitedRecorder(rec record.EventRecorder, opts RateLimitOptions) record.EventRecorder {
+	opts.defaults()
+	return &rateLimitedRecorder{
+		rec:    rec,
+		opts:   opts,
+		events: lru.New(opts.MaxKeys),
+		now:    time.Now,
+	}
+}
+
+// NewRateLimitedProvider wraps a Provider so that the recorders it returns are rate limited like
+// those returned by NewRateLimitedRecorder. Recorders with the same name share their limits.
+func NewRateLimitedProvider(p Provider, opts RateLimitOptions) Provider {
+	return &rateLimitedProvider{
+		provider:  p,
+		opts:      opts,
+		recorders: map[string]record.EventRecorder{},
+	}
+}
+
+type rateLimitedProvider struct {
+	provider Provider
+	opts     RateLimitOptions
+
+	mu        sync.Mutex
+	recorders map[string]record.EventRecorder
+}
+
+func (p *rateLimitedProvider) GetEventRecorderFor(name string) record.EventRecorder {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	rec, ok := p.recorders[name]
+	if !ok {
+		rec = NewRateLimitedRecorder(p.provider.GetEventRecorderFor(name), p.opts)
+		p.recorders[name] = rec
+	}
+	return rec
+}
+
+// eventKey identifies identical events.
+type eventKey struct {
+	objectType string
+	namespace  string
+	name       string
+	uid        types.UID
+	eventtype  string
+	reason     string
+	message    string
+}
+
+// eventCount counts the occurrences of an event in the current interval.
+type eventCount struct {
+	intervalStart time.Time
+	recorded      int
+	suppressed    int
+}
+
+type rateLimitedRecorder struct {
+	rec  record.EventRecorder
+	opts RateLimitOptions
+
+	mu     sync.Mutex
+	events *lru.Cache
+	now    func() time.Time
+}
+
+func (r *rateLimitedRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if message, ok := r.admit(object, eventtype, reason, message); ok {
+		r.rec.Event(object, eventtype, reason, message)
+	}
+}
+
+func (r *rateLimitedRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *rateLimitedRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	if message, ok := r.admit(object, eventtype, reason, fmt.Sprintf(messageFmt, args...)); ok {
+		r.rec.AnnotatedEventf(object, annotations, eventtype, reason, "%s", message)
+	}
+}
+
+// admit returns whether the event is to be recorded, and the message to record it with.
+func (r *rateLimitedRecorder) admit(object runtime.Object, eventtype, reason, message string) (string, bool) {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		// Let the underlying recorder deal with objects it can't reference.
+		return message, true
+	}
+	key := eventKey{
+		objectType: fmt.Sprintf("%T", object),
+		namespace:  accessor.GetNamespace(),
+		name:       accessor.GetName(),
+		uid:        accessor.GetUID(),
+		eventtype:  eventtype,
+		reason:     reason,
+		message:    message,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	count := &eventCount{intervalStart: now}
+	if cached, ok := r.events.Get(key); ok {
+		count = cached.(*eventCount)
+	} else {
+		r.events.Add(key, count)
+	}
+
+	if now.Sub(count.intervalStart) >= r.opts.Interval {
+		if count.suppressed > 0 {
+			message = fmt.Sprintf("%s (%d identical events were suppressed in the last %s)", message, count.suppressed, now.Sub(count.intervalStart).Round(time.Second))
+		}
+		*count = eventCount{intervalStart: now}
+	}
+	if count.recorded >= r.opts.Burst {
+		count.suppressed++
+		return "", false
+	}
+	count.recorded++
+	return message, true
+}
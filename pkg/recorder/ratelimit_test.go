@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recorder
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+type fakeProvider struct {
+	rec *record.FakeRecorder
+}
+
+func (p fakeProvider) GetEventRecorderFor(string) record.EventRecorder {
+	return p.rec
+}
+
+var _ = Describe("NewRateLimitedRecorder", func() {
+	var (
+		fake *record.FakeRecorder
+		rec  *rateLimitedRecorder
+		now  time.Time
+		pod  *corev1.Pod
+	)
+
+	BeforeEach(func() {
+		fake = record.NewFakeRecorder(100)
+		rec = NewRateLimitedRecorder(fake, RateLimitOptions{Interval: time.Minute, Burst: 2}).(*rateLimitedRecorder)
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		rec.now = func() time.Time { return now }
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo", UID: "uid"}}
+	})
+
+	It("should record at most Burst identical events per Interval", func() {
+		for i := 0; i < 5; i++ {
+			rec.Eventf(pod, corev1.EventTypeWarning, "Failed", "failed to %s", "sync")
+		}
+		Expect(fake.Events).To(HaveLen(2))
+		Expect(<-fake.Events).To(Equal("Warning Failed failed to sync"))
+		Expect(<-fake.Events).To(Equal("Warning Failed failed to sync"))
+	})
+
+	It("should rate limit events that differ independently", func() {
+		other := pod.DeepCopy()
+		other.Name = "bar"
+		for i := 0; i < 3; i++ {
+			rec.Event(pod, corev1.EventTypeWarning, "Failed", "failed")
+			rec.Event(pod, corev1.EventTypeWarning, "Failed", "failed differently")
+			rec.Event(pod, corev1.EventTypeNormal, "Failed", "failed")
+			rec.Event(other, corev1.EventTypeWarning, "Failed", "failed")
+		}
+		Expect(fake.Events).To(HaveLen(8))
+	})
+
+	It("should report the suppressed events once the Interval passed", func() {
+		for i := 0; i < 5; i++ {
+			rec.Event(pod, corev1.EventTypeWarning, "Failed", "failed")
+		}
+		Expect(fake.Events).To(HaveLen(2))
+		<-fake.Events
+		<-fake.Events
+
+		now = now.Add(90 * time.Second)
+		rec.Event(pod, corev1.EventTypeWarning, "Failed", "failed")
+		Expect(fake.Events).To(HaveLen(1))
+		Expect(<-fake.Events).To(Equal("Warning Failed failed (3 identical events were suppressed in the last 1m30s)"))
+
+		rec.Event(pod, corev1.EventTypeWarning, "Failed", "failed")
+		rec.Event(pod, corev1.EventTypeWarning, "Failed", "failed")
+		Expect(fake.Events).To(HaveLen(1))
+		Expect(<-fake.Events).To(Equal("Warning Failed failed"))
+	})
+
+	It("should forget the least recently recorded events beyond MaxKeys", func() {
+		rec = NewRateLimitedRecorder(fake, RateLimitOptions{MaxKeys: 1}).(*rateLimitedRecorder)
+		rec.Event(pod, corev1.EventTypeWarning, "Failed", "first")
+		rec.Event(pod, corev1.EventTypeWarning, "Failed", "second")
+		rec.Event(pod, corev1.EventTypeWarning, "Failed", "first")
+		Expect(fake.Events).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("NewRateLimitedProvider", func() {
+	It("should share the limits of recorders with the same name", func() {
+		fake := record.NewFakeRecorder(100)
+		p := NewRateLimitedProvider(fakeProvider{rec: fake}, RateLimitOptions{})
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}}
+
+		p.GetEventRecorderFor("a").Event(pod, corev1.EventTypeNormal, "Synced", "synced")
+		p.GetEventRecorderFor("a").Event(pod, corev1.EventTypeNormal, "Synced", "synced")
+		Expect(fake.Events).To(HaveLen(1))
+
+		p.GetEventRecorderFor("b").Event(pod, corev1.EventTypeNormal, "Synced", "synced")
+		Expect(fake.Events).To(HaveLen(2))
+	})
+})
@@ -18,6 +18,7 @@ package source_test
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -37,6 +38,21 @@ func ExampleKind() {
 	}
 }
 
+// This example Watches the Events of the ConfigMap named "config" in the "system" namespace only, without
+// caching all ConfigMaps of the cluster, and enqueues a reconcile.Request with its Name and Namespace.
+func ExampleSingleObject() {
+	err := ctrl.Watch(source.SingleObject(
+		mgr.GetConfig(),
+		mgr.GetScheme(),
+		client.ObjectKey{Namespace: "system", Name: "config"},
+		&corev1.ConfigMap{},
+		&handler.TypedEnqueueRequestForObject[*corev1.ConfigMap]{},
+	))
+	if err != nil {
+		// handle it
+	}
+}
+
 // This example reads GenericEvents from a channel and enqueues a reconcile.Request containing the Name and Namespace
 // provided by the event.
 func ExampleChannel() {
@@ -21,13 +21,18 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	internal "sigs.k8s.io/controller-runtime/pkg/internal/source"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -64,6 +69,143 @@ func Kind[T client.Object](cache cache.Cache, object T, handler handler.TypedEve
 	}
 }
 
+// MultiClusterKind creates a SyncingSource for the objects of the type of object in all clusters of
+// the MultiCluster cache. It enqueues a reconcile.TypedRequest keyed on a reconcile.ClusterKey carrying
+// the name of the cluster of the object, to be used with a controller created with controller.NewTyped.
+//
+// MultiClusterKind is experimental and subject to future change.
+func MultiClusterKind[T client.Object](cache *cache.MultiCluster, object T, predicates ...predicate.TypedPredicate[T]) SyncingSource {
+	mcs := &multiClusterKind{objectType: fmt.Sprintf("%T", object), clusterNames: cache.ClusterNames()}
+	for _, name := range mcs.clusterNames {
+		clusterCache, _ := cache.Cluster(name)
+		mcs.sources = append(mcs.sources, Kind(clusterCache, object, handler.EnqueueTypedRequestsFromMapFunc(
+			func(_ context.Context, obj T) []reconcile.TypedRequest[reconcile.ClusterKey] {
+				return []reconcile.TypedRequest[reconcile.ClusterKey]{{
+					Key: reconcile.ClusterKey{ClusterName: name, NamespacedName: client.ObjectKeyFromObject(obj)},
+				}}
+			},
+		), predicates...))
+	}
+	return mcs
+}
+
+type multiClusterKind struct {
+	objectType   string
+	clusterNames []string
+	sources      []SyncingSource
+}
+
+// Start implements Source.
+func (mcs *multiClusterKind) Start(ctx context.Context, queue workqueue.RateLimitingInterface) error {
+	for _, src := range mcs.sources {
+		if err := src.Start(ctx, queue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WaitForSync implements SyncingSource.
+func (mcs *multiClusterKind) WaitForSync(ctx context.Context) error {
+	for _, src := range mcs.sources {
+		if err := src.WaitForSync(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mcs *multiClusterKind) String() string {
+	return fmt.Sprintf("multi-cluster kind source: %s in clusters %v", mcs.objectType, mcs.clusterNames)
+}
+
+// SingleObject creates a SyncingSource for the single object of the type of object identified by key,
+// e.g. a ConfigMap holding configuration. Rather than caching all objects of the type, it starts its
+// own informer that only lists and watches the named object, using a field selector on metadata.name
+// that is evaluated by the API server. Leave the namespace of key empty for cluster-scoped objects.
+//
+// The informer is created from config and scheme, e.g. the ones of the Manager, when the Source is
+// started, and stopped when the context passed to Start is done.
+func SingleObject[T client.Object](config *rest.Config, scheme *runtime.Scheme, key client.ObjectKey, object T, handler handler.TypedEventHandler[T], predicates ...predicate.TypedPredicate[T]) SyncingSource {
+	return &singleObject[T]{
+		config:     config,
+		scheme:     scheme,
+		key:        key,
+		object:     object,
+		handler:    handler,
+		predicates: predicates,
+	}
+}
+
+type singleObject[T client.Object] struct {
+	config     *rest.Config
+	scheme     *runtime.Scheme
+	key        client.ObjectKey
+	object     T
+	handler    handler.TypedEventHandler[T]
+	predicates []predicate.TypedPredicate[T]
+
+	// kind is the Source of the events of the cache of the object, set by Start.
+	kind SyncingSource
+	// cacheErr receives the error the cache of the object failed to start with.
+	cacheErr chan error
+}
+
+// Start implements Source.
+func (so *singleObject[T]) Start(ctx context.Context, queue workqueue.RateLimitingInterface) error {
+	if so.kind != nil {
+		return fmt.Errorf("%s was started more than once", so)
+	}
+
+	byObject := cache.ByObject{Field: fields.OneTermEqualSelector("metadata.name", so.key.Name)}
+	if so.key.Namespace != "" {
+		byObject.Namespaces = map[string]cache.Config{so.key.Namespace: {}}
+	}
+	c, err := cache.New(so.config, cache.Options{
+		Scheme:   so.scheme,
+		ByObject: map[client.Object]cache.ByObject{so.object: byObject},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create cache for %s: %w", so, err)
+	}
+
+	so.kind = Kind(c, so.object, so.handler, so.predicates...)
+	if err := so.kind.Start(ctx, queue); err != nil {
+		return err
+	}
+
+	so.cacheErr = make(chan error, 1)
+	go func() {
+		so.cacheErr <- c.Start(ctx)
+	}()
+	return nil
+}
+
+// WaitForSync implements SyncingSource.
+func (so *singleObject[T]) WaitForSync(ctx context.Context) error {
+	if so.kind == nil {
+		return fmt.Errorf("%s must be started before waiting for it to sync", so)
+	}
+
+	synced := make(chan error, 1)
+	go func() {
+		synced <- so.kind.WaitForSync(ctx)
+	}()
+	select {
+	case err := <-so.cacheErr:
+		if err != nil {
+			return fmt.Errorf("failed to start cache for %s: %w", so, err)
+		}
+		return <-synced
+	case err := <-synced:
+		return err
+	}
+}
+
+func (so *singleObject[T]) String() string {
+	return fmt.Sprintf("single object source: %T %s", so.object, so.key)
+}
+
 var _ Source = &channel[string]{}
 
 // ChannelOpt allows to configure a source.Channel.
@@ -84,6 +226,24 @@ func WithBufferSize[T any](bufferSize int) ChannelOpt[T] {
 	}
 }
 
+// WithDeduplication configures a source.Channel to coalesce events with the same key,
+// as returned by keyFunc, e.g. to collapse bursts of the same logical event of an external
+// system. The first event of a key starts a window of the given duration, during which further
+// events of that key replace it; only the latest of them is passed on once the window elapsed.
+// This delays all events by the window.
+//
+// The workqueue already coalesces identical reconcile.Requests that are waiting to be processed,
+// but only those; keys allow to also collapse events for different objects, and to avoid
+// reconciling the same object repeatedly for a burst of events that spans its processing.
+// Pending events are passed on right away when the source channel is closed, and dropped when
+// the context is done.
+func WithDeduplication[T any](keyFunc func(event.TypedGenericEvent[T]) string, window time.Duration) ChannelOpt[T] {
+	return func(c *channel[T]) {
+		c.dedupKeyFunc = keyFunc
+		c.dedupWindow = window
+	}
+}
+
 // Channel is used to provide a source of events originating outside the cluster
 // (e.g. GitHub Webhook callback).  Channel requires the user to wire the external
 // source (e.g. http handler) to write GenericEvents to the underlying channel.
@@ -112,6 +272,10 @@ type channel[T any] struct {
 
 	bufferSize *int
 
+	// dedupKeyFunc and dedupWindow configure the deduplication of events, see WithDeduplication.
+	dedupKeyFunc func(event.TypedGenericEvent[T]) string
+	dedupWindow  time.Duration
+
 	// dest is the destination channels of the added event handlers
 	dest []chan event.TypedGenericEvent[T]
 
@@ -198,6 +362,11 @@ func (cs *channel[T]) distribute(evt event.TypedGenericEvent[T]) {
 }
 
 func (cs *channel[T]) syncLoop(ctx context.Context) {
+	if cs.dedupKeyFunc != nil {
+		cs.dedupSyncLoop(ctx)
+		return
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -216,6 +385,110 @@ func (cs *channel[T]) syncLoop(ctx context.Context) {
 	}
 }
 
+// dedupSyncLoop is the syncLoop of a channel with deduplication, which only distributes
+// the latest event of each key once its window elapsed.
+func (cs *channel[T]) dedupSyncLoop(ctx context.Context) {
+	pending := map[string]event.TypedGenericEvent[T]{}
+	elapsed := make(chan string)
+	done := make(chan struct{})
+	defer close(done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Close destination channels
+			cs.doStop()
+			return
+		case evt, stillOpen := <-cs.source:
+			if !stillOpen {
+				// There won't be any more duplicates, so don't wait for the windows to elapse.
+				for _, evt := range pending {
+					cs.distribute(evt)
+				}
+				cs.doStop()
+				return
+			}
+			key := cs.dedupKeyFunc(evt)
+			if _, found := pending[key]; !found {
+				time.AfterFunc(cs.dedupWindow, func() {
+					select {
+					case elapsed <- key:
+					case <-done:
+					}
+				})
+			}
+			pending[key] = evt
+		case key := <-elapsed:
+			cs.distribute(pending[key])
+			delete(pending, key)
+		}
+	}
+}
+
+// TypedChannel is a Channel for a source of objects rather than GenericEvents, e.g. objects
+// built from the responses of an external system. Each object received from source is
+// delivered to the handler as the Object of a GenericEvent. It stops receiving from source
+// once source is closed or the context passed to Start is done.
+func TypedChannel[T client.Object](source <-chan T, handler handler.TypedEventHandler[T], opts ...ChannelOpt[T]) Source {
+	events := make(chan event.TypedGenericEvent[T])
+	return &typedChannel[T]{
+		source:  source,
+		events:  events,
+		channel: Channel(events, handler, opts...),
+	}
+}
+
+type typedChannel[T client.Object] struct {
+	// once ensures the forwarding goroutine will be started only once
+	once sync.Once
+
+	// source is the source channel to fetch objects
+	source <-chan T
+
+	// events is the source of channel, fed with the objects from source
+	events chan event.TypedGenericEvent[T]
+
+	channel Source
+}
+
+func (tc *typedChannel[T]) String() string {
+	return fmt.Sprintf("typed channel source: %p", tc)
+}
+
+// Start implements Source and should only be called by the Controller.
+func (tc *typedChannel[T]) Start(ctx context.Context, queue workqueue.RateLimitingInterface) error {
+	if tc.source == nil {
+		return errors.New("must specify TypedChannel.Source")
+	}
+	if err := tc.channel.Start(ctx, queue); err != nil {
+		return err
+	}
+
+	tc.once.Do(func() {
+		go tc.forward(ctx)
+	})
+	return nil
+}
+
+func (tc *typedChannel[T]) forward(ctx context.Context) {
+	defer close(tc.events)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case obj, stillOpen := <-tc.source:
+			if !stillOpen {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case tc.events <- event.TypedGenericEvent[T]{Object: obj}:
+			}
+		}
+	}
+}
+
 // Informer is used to provide a source of events originating inside the cluster from Watches (e.g. Pod Create).
 type Informer struct {
 	// Informer is the controller-runtime Informer
@@ -261,3 +534,93 @@ func (f Func) Start(ctx context.Context, queue workqueue.RateLimitingInterface)
 func (f Func) String() string {
 	return fmt.Sprintf("func source: %p", f)
 }
+
+// NewFakeKind creates a SyncingSource that stands in for a Kind source in unit tests of handlers
+// and predicates, without a cache or informer. Each object received as a GenericEvent from events
+// is delivered like an informer would deliver it: as a CreateEvent if no object with its namespace
+// and name was received before, as an UpdateEvent with the previously received object otherwise,
+// and as a DeleteEvent if its DeletionTimestamp is set and it has no finalizers, like an object that
+// is removed by the API server. The events are filtered by predicates and passed to the handler
+// exactly like the ones of a Kind source. It stops receiving from events once events is closed or
+// the context passed to Start is done.
+//
+// To test a Kind source itself with a fake informer, pass an informertest.FakeInformers as its
+// cache and send objects to the controllertest.FakeInformer returned by its FakeInformerFor.
+func NewFakeKind[T client.Object](events <-chan event.TypedGenericEvent[T], handler handler.TypedEventHandler[T], predicates ...predicate.TypedPredicate[T]) SyncingSource {
+	return &fakeKind[T]{
+		events:     events,
+		handler:    handler,
+		predicates: predicates,
+		started:    make(chan struct{}),
+	}
+}
+
+type fakeKind[T client.Object] struct {
+	events     <-chan event.TypedGenericEvent[T]
+	handler    handler.TypedEventHandler[T]
+	predicates []predicate.TypedPredicate[T]
+
+	// once ensures the delivering goroutine will be started only once
+	once sync.Once
+	// started is closed once the source is started
+	started chan struct{}
+}
+
+func (fk *fakeKind[T]) String() string {
+	return fmt.Sprintf("fake kind source: %p", fk)
+}
+
+// Start implements Source and should only be called by the Controller.
+func (fk *fakeKind[T]) Start(ctx context.Context, queue workqueue.RateLimitingInterface) error {
+	if fk.events == nil {
+		return errors.New("must specify FakeKind.Events")
+	}
+	if fk.handler == nil {
+		return errors.New("must specify FakeKind.Handler")
+	}
+
+	fk.once.Do(func() {
+		go fk.deliver(ctx, internal.NewEventHandler(ctx, queue, fk.handler, fk.predicates))
+		close(fk.started)
+	})
+	return nil
+}
+
+// WaitForSync implements SyncingSource and returns once the source is started, as it has no
+// informer to sync.
+func (fk *fakeKind[T]) WaitForSync(ctx context.Context) error {
+	select {
+	case <-fk.started:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (fk *fakeKind[T]) deliver(ctx context.Context, eventHandler *internal.EventHandler[T]) {
+	known := map[client.ObjectKey]T{}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, stillOpen := <-fk.events:
+			if !stillOpen {
+				return
+			}
+			obj := evt.Object
+			key := client.ObjectKeyFromObject(obj)
+			old, exists := known[key]
+			switch {
+			case obj.GetDeletionTimestamp() != nil && len(obj.GetFinalizers()) == 0:
+				delete(known, key)
+				eventHandler.OnDelete(obj)
+			case exists:
+				known[key] = obj
+				eventHandler.OnUpdate(old, obj)
+			default:
+				known[key] = obj
+				eventHandler.OnAdd(obj)
+			}
+		}
+	}
+}
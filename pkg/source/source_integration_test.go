@@ -32,6 +32,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/scheme"
 	toolscache "k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 )
@@ -193,6 +194,49 @@ var _ = Describe("Source", func() {
 		})
 	})
 
+	Describe("SingleObject", func() {
+		It("should only provide events for the named object", func() {
+			configMaps := clientset.CoreV1().ConfigMaps(ns)
+			other, err := configMaps.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other"}}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			watched, err := configMaps.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "watched"}}, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			events := make(chan interface{}, 10)
+			instance := source.SingleObject(config, scheme.Scheme, client.ObjectKey{Namespace: ns, Name: "watched"}, &corev1.ConfigMap{},
+				handler.TypedFuncs[*corev1.ConfigMap]{
+					CreateFunc: func(ctx context.Context, evt event.TypedCreateEvent[*corev1.ConfigMap], _ workqueue.RateLimitingInterface) {
+						events <- evt
+					},
+					UpdateFunc: func(ctx context.Context, evt event.TypedUpdateEvent[*corev1.ConfigMap], _ workqueue.RateLimitingInterface) {
+						events <- evt
+					},
+				})
+			srcCtx, srcCancel := context.WithCancel(ctx)
+			defer srcCancel()
+			Expect(instance.Start(srcCtx, q)).To(Succeed())
+			Expect(instance.WaitForSync(srcCtx)).To(Succeed())
+
+			By("Expecting a CreateEvent only for the named ConfigMap")
+			var evt interface{}
+			Eventually(events).Should(Receive(&evt))
+			Expect(evt.(event.TypedCreateEvent[*corev1.ConfigMap]).Object.Name).To(Equal("watched"))
+
+			By("Updating the other ConfigMap and expecting no event")
+			other.Data = map[string]string{"foo": "bar"}
+			_, err = configMaps.Update(ctx, other, metav1.UpdateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Consistently(events).ShouldNot(Receive())
+
+			By("Updating the named ConfigMap and expecting an UpdateEvent")
+			watched.Data = map[string]string{"foo": "bar"}
+			_, err = configMaps.Update(ctx, watched, metav1.UpdateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(events).Should(Receive(&evt))
+			Expect(evt.(event.TypedUpdateEvent[*corev1.ConfigMap]).ObjectNew.Data).To(Equal(watched.Data))
+		})
+	})
+
 	Describe("Informer", func() {
 		var c chan struct{}
 		var rs *appsv1.ReplicaSet
@@ -24,16 +24,20 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
 )
 
 var _ = Describe("Source", func() {
@@ -236,6 +240,41 @@ var _ = Describe("Source", func() {
 		})
 	})
 
+	Describe("MultiClusterKind", func() {
+		It("should enqueue requests carrying the cluster of the object", func() {
+			east := &informertest.FakeInformers{}
+			west := &informertest.FakeInformers{}
+			mc := cache.NewMultiClusterFromCaches(map[string]cache.Cache{"east": east, "west": west})
+
+			q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+			instance := source.MultiClusterKind(mc, &corev1.Pod{})
+			Expect(instance.Start(ctx, q)).To(Succeed())
+			Expect(instance.WaitForSync(context.Background())).To(Succeed())
+			Expect(fmt.Sprint(instance)).To(Equal("multi-cluster kind source: *v1.Pod in clusters [east west]"))
+
+			eastInformer, err := east.FakeInformerFor(ctx, &corev1.Pod{})
+			Expect(err).NotTo(HaveOccurred())
+			westInformer, err := west.FakeInformerFor(ctx, &corev1.Pod{})
+			Expect(err).NotTo(HaveOccurred())
+
+			eastInformer.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}})
+			westInformer.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}})
+			westInformer.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bar"}})
+
+			var got []reconcile.TypedRequest[reconcile.ClusterKey]
+			for i := 0; i < 3; i++ {
+				item, _ := q.Get()
+				got = append(got, item.(reconcile.TypedRequest[reconcile.ClusterKey]))
+				q.Done(item)
+			}
+			Expect(got).To(ConsistOf(
+				reconcile.TypedRequest[reconcile.ClusterKey]{Key: reconcile.ClusterKey{ClusterName: "east", NamespacedName: types.NamespacedName{Namespace: "default", Name: "foo"}}},
+				reconcile.TypedRequest[reconcile.ClusterKey]{Key: reconcile.ClusterKey{ClusterName: "west", NamespacedName: types.NamespacedName{Namespace: "default", Name: "foo"}}},
+				reconcile.TypedRequest[reconcile.ClusterKey]{Key: reconcile.ClusterKey{ClusterName: "west", NamespacedName: types.NamespacedName{Namespace: "default", Name: "bar"}}},
+			))
+		})
+	})
+
 	Describe("Func", func() {
 		It("should be called from Start", func() {
 			run := false
@@ -467,6 +506,68 @@ var _ = Describe("Source", func() {
 				Eventually(processed).Should(Receive())
 				Consistently(processed).ShouldNot(Receive())
 			})
+			It("should coalesce events with the same key if deduplication is configured", func() {
+				ch := make(chan event.GenericEvent)
+				q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+				instance := source.Channel(
+					ch,
+					&handler.EnqueueRequestForObject{},
+					source.WithDeduplication(func(evt event.GenericEvent) string {
+						return evt.Object.GetLabels()["key"]
+					}, 200*time.Millisecond),
+				)
+				err := instance.Start(ctx, q)
+				Expect(err).NotTo(HaveOccurred())
+
+				newEvent := func(name, key string) event.GenericEvent {
+					return event.GenericEvent{Object: &corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: map[string]string{"key": key}},
+					}}
+				}
+				for i := 0; i < 5; i++ {
+					ch <- newEvent(fmt.Sprintf("foo-%d", i), "foo")
+				}
+				ch <- newEvent("bar", "bar")
+
+				By("expecting a single request for the latest event of each key")
+				var names []string
+				for i := 0; i < 2; i++ {
+					item, shutdown := q.Get()
+					Expect(shutdown).To(BeFalse())
+					names = append(names, item.(reconcile.Request).Name)
+					q.Done(item)
+				}
+				Expect(names).To(ConsistOf("foo-4", "bar"))
+				Consistently(q.Len).Should(BeZero())
+
+				By("expecting events after the window to be passed on again")
+				ch <- newEvent("foo-5", "foo")
+				item, shutdown := q.Get()
+				Expect(shutdown).To(BeFalse())
+				Expect(item.(reconcile.Request).Name).To(Equal("foo-5"))
+				q.Done(item)
+			})
+			It("should pass on pending deduplicated events when the source channel is closed", func() {
+				ch := make(chan event.GenericEvent, 2)
+				ch <- event.GenericEvent{Object: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}}}
+				ch <- event.GenericEvent{Object: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bar"}}}
+				close(ch)
+
+				q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+				instance := source.Channel(
+					ch,
+					&handler.EnqueueRequestForObject{},
+					source.WithDeduplication(func(event.GenericEvent) string { return "same" }, time.Hour),
+				)
+				err := instance.Start(ctx, q)
+				Expect(err).NotTo(HaveOccurred())
+
+				item, shutdown := q.Get()
+				Expect(shutdown).To(BeFalse())
+				Expect(item.(reconcile.Request).Name).To(Equal("bar"))
+				q.Done(item)
+				Consistently(q.Len).Should(BeZero())
+			})
 			It("should get error if no source specified", func() {
 				q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
 				instance := source.Channel[string](nil, nil /*no source specified*/)
@@ -475,4 +576,162 @@ var _ = Describe("Source", func() {
 			})
 		})
 	})
+
+	Describe("NewFakeKind", func() {
+		var ctx context.Context
+		var cancel context.CancelFunc
+		var events chan event.TypedGenericEvent[*corev1.Pod]
+		var received chan string
+		var recorder handler.TypedFuncs[*corev1.Pod]
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithCancel(context.Background())
+			events = make(chan event.TypedGenericEvent[*corev1.Pod])
+			received = make(chan string, 10)
+			recorder = handler.TypedFuncs[*corev1.Pod]{
+				CreateFunc: func(_ context.Context, evt event.TypedCreateEvent[*corev1.Pod], _ workqueue.RateLimitingInterface) {
+					received <- "create " + evt.Object.Name
+				},
+				UpdateFunc: func(_ context.Context, evt event.TypedUpdateEvent[*corev1.Pod], _ workqueue.RateLimitingInterface) {
+					received <- fmt.Sprintf("update %s %d->%d", evt.ObjectNew.Name, evt.ObjectOld.Generation, evt.ObjectNew.Generation)
+				},
+				DeleteFunc: func(_ context.Context, evt event.TypedDeleteEvent[*corev1.Pod], _ workqueue.RateLimitingInterface) {
+					received <- "delete " + evt.Object.Name
+				},
+			}
+		})
+
+		AfterEach(func() {
+			cancel()
+		})
+
+		pod := func(name string, generation int64) *corev1.Pod {
+			return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Generation: generation}}
+		}
+
+		It("should deliver objects as create, update and delete events", func() {
+			q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+			instance := source.NewFakeKind(events, recorder)
+			Expect(instance.Start(ctx, q)).To(Succeed())
+			Expect(instance.WaitForSync(ctx)).To(Succeed())
+
+			deleted := pod("foo", 2)
+			deleted.DeletionTimestamp = ptr.To(metav1.Now())
+			for _, p := range []*corev1.Pod{pod("foo", 1), pod("bar", 1), pod("foo", 2), deleted, pod("foo", 1)} {
+				events <- event.TypedGenericEvent[*corev1.Pod]{Object: p}
+			}
+
+			for _, expected := range []string{"create foo", "create bar", "update foo 1->2", "delete foo", "create foo"} {
+				Eventually(received).Should(Receive(Equal(expected)))
+			}
+		})
+
+		It("should filter the events with the predicates", func() {
+			q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+			instance := source.NewFakeKind(events, recorder,
+				predicate.TypedGenerationChangedPredicate[*corev1.Pod]{},
+				predicate.NewTypedPredicateFuncs(func(p *corev1.Pod) bool {
+					return p.Name != "filtered"
+				}),
+			)
+			Expect(instance.Start(ctx, q)).To(Succeed())
+
+			updated := pod("foo", 1)
+			updated.Labels = map[string]string{"updated": "true"}
+			for _, p := range []*corev1.Pod{pod("foo", 1), pod("filtered", 1), updated, pod("foo", 2)} {
+				events <- event.TypedGenericEvent[*corev1.Pod]{Object: p}
+			}
+
+			Eventually(received).Should(Receive(Equal("create foo")))
+			Eventually(received).Should(Receive(Equal("update foo 1->2")))
+			Consistently(received).ShouldNot(Receive())
+		})
+
+		It("should enqueue requests with the handler", func() {
+			q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+			instance := source.NewFakeKind(events, &handler.TypedEnqueueRequestForObject[*corev1.Pod]{})
+			Expect(instance.Start(ctx, q)).To(Succeed())
+
+			events <- event.TypedGenericEvent[*corev1.Pod]{Object: pod("foo", 1)}
+
+			item, shutdown := q.Get()
+			Expect(shutdown).To(BeFalse())
+			Expect(item).To(Equal(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "foo"}}))
+			q.Done(item)
+		})
+
+		It("should not be synced before it is started", func() {
+			instance := source.NewFakeKind(events, recorder)
+			timeoutCtx, timeoutCancel := context.WithTimeout(ctx, 10*time.Millisecond)
+			defer timeoutCancel()
+			Expect(instance.WaitForSync(timeoutCtx)).To(MatchError(context.DeadlineExceeded))
+		})
+
+		It("should get error if no events specified", func() {
+			q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+			instance := source.NewFakeKind[*corev1.Pod](nil, recorder)
+			Expect(instance.Start(ctx, q)).To(MatchError("must specify FakeKind.Events"))
+		})
+	})
+
+	Describe("TypedChannel", func() {
+		var ctx context.Context
+		var cancel context.CancelFunc
+
+		BeforeEach(func() {
+			ctx, cancel = context.WithCancel(context.Background())
+		})
+
+		AfterEach(func() {
+			cancel()
+		})
+
+		It("should enqueue a request for every object sent", func() {
+			ch := make(chan *corev1.Pod)
+			q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+			instance := source.TypedChannel(ch, &handler.TypedEnqueueRequestForObject[*corev1.Pod]{},
+				source.WithPredicates(predicate.NewTypedPredicateFuncs(func(p *corev1.Pod) bool {
+					return p.Name != "filtered"
+				})),
+			)
+			Expect(instance.Start(ctx, q)).To(Succeed())
+
+			for _, name := range []string{"foo", "filtered", "bar", "baz"} {
+				ch <- &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+			}
+
+			var names []string
+			for i := 0; i < 3; i++ {
+				item, shutdown := q.Get()
+				Expect(shutdown).To(BeFalse())
+				names = append(names, item.(reconcile.Request).Name)
+				q.Done(item)
+			}
+			Expect(names).To(ConsistOf("foo", "bar", "baz"))
+			Consistently(q.Len).Should(BeZero())
+		})
+
+		It("should stop receiving objects once the context is done", func() {
+			ch := make(chan *corev1.Pod, 1)
+			q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+			instance := source.TypedChannel(ch, &handler.TypedEnqueueRequestForObject[*corev1.Pod]{})
+			Expect(instance.Start(ctx, q)).To(Succeed())
+
+			cancel()
+			Eventually(func() bool {
+				select {
+				case ch <- &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}}:
+				default:
+				}
+				return len(ch) == 1
+			}).Should(BeTrue())
+			Consistently(func() int { return len(ch) }).Should(Equal(1))
+		})
+
+		It("should get error if no source specified", func() {
+			q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+			instance := source.TypedChannel[*corev1.Pod](nil, &handler.TypedEnqueueRequestForObject[*corev1.Pod]{})
+			Expect(instance.Start(ctx, q)).To(MatchError("must specify TypedChannel.Source"))
+		})
+	})
 })
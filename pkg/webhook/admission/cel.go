@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// CELRule is a validation rule of a CELValidator.
+type CELRule struct {
+	// Expression is a CEL expression that must evaluate to true for the request to be admitted.
+	// It has access to the following variables:
+	//
+	// - object: the object from the request, null for DELETE requests.
+	//
+	// - oldObject: the existing object, null for CREATE requests.
+	//
+	// - request: the attributes of the request, i.e. operation, name, namespace, subResource and
+	// userInfo with username and groups.
+	//
+	// Objects are accessed as in their JSON representation, e.g. object.spec.replicas. Rules accessing
+	// the fields of object fail for DELETE requests unless they check request.operation first.
+	Expression string
+
+	// Message is the message returned when Expression evaluates to false.
+	// Defaults to "failed expression: " followed by Expression.
+	Message string
+
+	// FieldPath is the path of the field the rule validates, e.g. "spec.replicas".
+	// It is set as the field of the cause of the denial. Optional.
+	FieldPath string
+}
+
+type celProgram struct {
+	rule    CELRule
+	program cel.Program
+}
+
+type celValidator struct {
+	programs []celProgram
+}
+
+// CELValidator returns a validating Handler which evaluates the given CEL rules against the object
+// of each request, similar to a ValidatingAdmissionPolicy. The rules are compiled once, an error
+// is returned if any of them doesn't compile or doesn't evaluate to a bool.
+//
+// Requests are denied if any rule evaluates to false or fails to evaluate. The denial has the
+// messages of all failed rules, and a cause per failed rule in the details of its status.
+func CELValidator(rules []CELRule) (Handler, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		cel.Variable("oldObject", cel.DynType),
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	v := &celValidator{programs: make([]celProgram, 0, len(rules))}
+	for _, rule := range rules {
+		ast, issues := env.Compile(rule.Expression)
+		if issues.Err() != nil {
+			return nil, fmt.Errorf("failed to compile expression %q: %w", rule.Expression, issues.Err())
+		}
+		if outputType := ast.OutputType(); !outputType.IsExactType(cel.BoolType) && !outputType.IsExactType(cel.DynType) {
+			return nil, fmt.Errorf("expression %q must evaluate to bool, not %s", rule.Expression, outputType)
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create program for expression %q: %w", rule.Expression, err)
+		}
+		if rule.Message == "" {
+			rule.Message = "failed expression: " + rule.Expression
+		}
+		v.programs = append(v.programs, celProgram{rule: rule, program: program})
+	}
+	return v, nil
+}
+
+// Handle implements Handler.
+func (v *celValidator) Handle(ctx context.Context, req Request) Response {
+	object, err := celObject(req.Object)
+	if err != nil {
+		return Errored(http.StatusBadRequest, fmt.Errorf("failed to decode object: %w", err))
+	}
+	oldObject, err := celObject(req.OldObject)
+	if err != nil {
+		return Errored(http.StatusBadRequest, fmt.Errorf("failed to decode old object: %w", err))
+	}
+	vars := map[string]any{
+		"object":    object,
+		"oldObject": oldObject,
+		"request": map[string]any{
+			"operation":   string(req.Operation),
+			"name":        req.Name,
+			"namespace":   req.Namespace,
+			"subResource": req.SubResource,
+			"userInfo": map[string]any{
+				"username": req.UserInfo.Username,
+				"groups":   req.UserInfo.Groups,
+			},
+		},
+	}
+
+	var (
+		msgs   []string
+		causes []metav1.StatusCause
+	)
+	for _, p := range v.programs {
+		msg := p.rule.Message
+		out, _, err := p.program.ContextEval(ctx, vars)
+		switch {
+		case err != nil:
+			msg = fmt.Sprintf("expression %q failed to evaluate: %v", p.rule.Expression, err)
+		case out == types.True:
+			continue
+		case out != types.False:
+			msg = fmt.Sprintf("expression %q evaluated to %v instead of a bool", p.rule.Expression, out.Value())
+		}
+		msgs = append(msgs, msg)
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldValueInvalid,
+			Message: msg,
+			Field:   p.rule.FieldPath,
+		})
+	}
+	if len(msgs) == 0 {
+		return Allowed("")
+	}
+
+	resp := Denied(strings.Join(msgs, "; "))
+	resp.Result.Details = &metav1.StatusDetails{
+		Name:   req.Name,
+		Kind:   req.Kind.Kind,
+		Causes: causes,
+	}
+	return resp
+}
+
+// celObject decodes a raw object of a request into the JSON representation CEL expressions access.
+// Whole numbers are decoded as int64, so that they can be compared with integer literals.
+func celObject(raw runtime.RawExtension) (any, error) {
+	if len(raw.Raw) == 0 {
+		return nil, nil
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(raw.Raw, &obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
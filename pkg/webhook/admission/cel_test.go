@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("CELValidator", func() {
+	deploymentRequest := func(operation admissionv1.Operation, object, oldObject string) Request {
+		req := Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: operation,
+			Name:      "foo",
+			Namespace: "default",
+			Kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			UserInfo:  authenticationv1.UserInfo{Username: "alice", Groups: []string{"system:authenticated"}},
+		}}
+		if object != "" {
+			req.Object = runtime.RawExtension{Raw: []byte(object)}
+		}
+		if oldObject != "" {
+			req.OldObject = runtime.RawExtension{Raw: []byte(oldObject)}
+		}
+		return req
+	}
+	const deployment = `{
+		"apiVersion": "apps/v1",
+		"kind": "Deployment",
+		"metadata": {"name": "foo", "namespace": "default", "labels": {"app": "foo"}},
+		"spec": {"replicas": 3, "template": {"spec": {"containers": [{"name": "foo", "image": "foo:v1"}]}}}
+	}`
+
+	It("should allow a request passing all rules", func() {
+		handler, err := CELValidator([]CELRule{
+			{Expression: "object.spec.replicas <= 5"},
+			{Expression: "object.metadata.labels.app == object.metadata.name"},
+			{Expression: "object.spec.template.spec.containers.all(c, c.image.endsWith(':v1'))"},
+			{Expression: "request.operation == 'CREATE' && request.userInfo.username == 'alice'"},
+			{Expression: "oldObject == null"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		resp := handler.Handle(context.Background(), deploymentRequest(admissionv1.Create, deployment, ""))
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Result.Code).To(Equal(int32(http.StatusOK)))
+	})
+
+	It("should deny a request failing rules with a cause per failed rule", func() {
+		handler, err := CELValidator([]CELRule{
+			{Expression: "object.spec.replicas <= 2", Message: "at most 2 replicas are allowed", FieldPath: "spec.replicas"},
+			{Expression: "object.metadata.name.startsWith('foo')"},
+			{Expression: "has(object.metadata.annotations)"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		resp := handler.Handle(context.Background(), deploymentRequest(admissionv1.Create, deployment, ""))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Code).To(Equal(int32(http.StatusForbidden)))
+		Expect(resp.Result.Reason).To(Equal(metav1.StatusReasonForbidden))
+		Expect(resp.Result.Message).To(Equal("at most 2 replicas are allowed; failed expression: has(object.metadata.annotations)"))
+		Expect(resp.Result.Details.Name).To(Equal("foo"))
+		Expect(resp.Result.Details.Kind).To(Equal("Deployment"))
+		Expect(resp.Result.Details.Causes).To(Equal([]metav1.StatusCause{
+			{Type: metav1.CauseTypeFieldValueInvalid, Message: "at most 2 replicas are allowed", Field: "spec.replicas"},
+			{Type: metav1.CauseTypeFieldValueInvalid, Message: "failed expression: has(object.metadata.annotations)"},
+		}))
+	})
+
+	It("should compare the object with the old object", func() {
+		handler, err := CELValidator([]CELRule{
+			{Expression: "object.spec.replicas >= oldObject.spec.replicas", Message: "scaling down is not allowed"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		scaledUp := `{"spec": {"replicas": 4}}`
+		resp := handler.Handle(context.Background(), deploymentRequest(admissionv1.Update, scaledUp, deployment))
+		Expect(resp.Allowed).To(BeTrue())
+
+		scaledDown := `{"spec": {"replicas": 1}}`
+		resp = handler.Handle(context.Background(), deploymentRequest(admissionv1.Update, scaledDown, deployment))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Message).To(Equal("scaling down is not allowed"))
+	})
+
+	It("should deny a request for which a rule fails to evaluate", func() {
+		handler, err := CELValidator([]CELRule{
+			{Expression: "object.spec.paused == false"},
+			{Expression: "request.operation == 'DELETE' || object.spec.replicas > 0"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		resp := handler.Handle(context.Background(), deploymentRequest(admissionv1.Create, deployment, ""))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Message).To(ContainSubstring(`expression "object.spec.paused == false" failed to evaluate`))
+		Expect(resp.Result.Details.Causes).To(HaveLen(1))
+
+		resp = handler.Handle(context.Background(), deploymentRequest(admissionv1.Delete, "", deployment))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Details.Causes).To(HaveLen(1))
+	})
+
+	It("should fail for rules that don't compile", func() {
+		_, err := CELValidator([]CELRule{{Expression: "object.spec.replicas >"}})
+		Expect(err).To(MatchError(ContainSubstring(`failed to compile expression "object.spec.replicas >"`)))
+	})
+
+	It("should fail for rules that don't evaluate to a bool", func() {
+		_, err := CELValidator([]CELRule{{Expression: "'foo' + 'bar'"}})
+		Expect(err).To(MatchError(ContainSubstring("must evaluate to bool")))
+	})
+
+	It("should return an error for an undecodable object", func() {
+		handler, err := CELValidator([]CELRule{{Expression: "true"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		resp := handler.Handle(context.Background(), deploymentRequest(admissionv1.Create, "{", ""))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Code).To(Equal(int32(http.StatusBadRequest)))
+	})
+})
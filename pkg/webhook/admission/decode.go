@@ -19,6 +19,7 @@ package admission
 import (
 	"fmt"
 
+	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/util/json"
@@ -26,6 +27,11 @@ import (
 
 // Decoder knows how to decode the contents of an admission
 // request into a concrete object.
+//
+// Decoding into a typed object merges the decoded fields into it: fields that are
+// absent from the raw object keep the values the passed-in object already has, e.g.
+// defaults set on a prototype, while fields that are present replace them, including
+// whole lists. Decoding into an unstructured object replaces its content.
 type Decoder interface {
 	// Decode decodes the inlined object in the AdmissionRequest into the passed-in runtime.Object.
 	// If you want decode the OldObject in the AdmissionRequest, use DecodeRaw.
@@ -51,6 +57,21 @@ func NewDecoder(scheme *runtime.Scheme) Decoder {
 	return &decoder{codecs: serializer.NewCodecFactory(scheme)}
 }
 
+// NewStrictDecoder creates a decoder given the runtime.Scheme that errors out on
+// unknown and duplicate fields when decoding into a typed object. The error is
+// a strict decoding error, see runtime.IsStrictDecodingError, that lists every
+// offending field by its path, e.g. `unknown field "spec.foo"`. The decoded
+// object is still populated with the known fields.
+//
+// Decoding into an unstructured object is not strict, as there is no schema to
+// detect unknown fields with.
+func NewStrictDecoder(scheme *runtime.Scheme) Decoder {
+	if scheme == nil {
+		panic("scheme should never be nil")
+	}
+	return &decoder{codecs: serializer.NewCodecFactory(scheme, serializer.EnableStrict)}
+}
+
 // Decode decodes the inlined object in the AdmissionRequest into the passed-in runtime.Object.
 // If you want decode the OldObject in the AdmissionRequest, use DecodeRaw.
 // It errors out if req.Object.Raw is empty i.e. containing 0 raw bytes.
@@ -90,3 +111,27 @@ func (d *decoder) DecodeRaw(rawObj runtime.RawExtension, into runtime.Object) er
 	deserializer := d.codecs.UniversalDeserializer()
 	return runtime.DecodeInto(deserializer, rawObj.Raw, into)
 }
+
+// IsStatusUpdate returns true if req is an update of the status subresource of an object.
+func IsStatusUpdate(req Request) bool {
+	return req.Operation == admissionv1.Update && req.SubResource == "status"
+}
+
+// DecodeStatus decodes only the status of the object in rawObj into the passed-in status,
+// e.g. the Status field of a custom resource. It errors out if rawObj is empty i.e.
+// containing 0 raw bytes, and leaves status untouched if the object has no status.
+func DecodeStatus(rawObj runtime.RawExtension, status interface{}) error {
+	if len(rawObj.Raw) == 0 {
+		return fmt.Errorf("there is no content to decode")
+	}
+	var object struct {
+		Status runtime.RawExtension `json:"status"`
+	}
+	if err := json.Unmarshal(rawObj.Raw, &object); err != nil {
+		return err
+	}
+	if len(object.Status.Raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(object.Status.Raw, status)
+}
@@ -185,4 +185,94 @@ var _ = Describe("Admission Webhook Decoder", func() {
 		var target3 unstructured.Unstructured
 		Expect(decoder.DecodeRaw(req2.Object, &target3)).To(Succeed())
 	})
+
+	It("should keep the values of fields absent from the raw object", func() {
+		target := &corev1.Pod{Spec: corev1.PodSpec{RestartPolicy: corev1.RestartPolicyNever}}
+		Expect(decoder.Decode(req, target)).To(Succeed())
+		Expect(target.Name).To(Equal("foo"))
+		Expect(target.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+	})
+
+	It("should ignore unknown fields", func() {
+		target := &corev1.Pod{}
+		Expect(decoder.DecodeRaw(runtime.RawExtension{
+			Raw: []byte(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "foo"}, "spec": {"foo": "bar"}}`),
+		}, target)).To(Succeed())
+		Expect(target.Name).To(Equal("foo"))
+	})
+
+	Context("with a strict decoder", func() {
+		var strictDecoder Decoder
+		BeforeEach(func() {
+			strictDecoder = NewStrictDecoder(scheme.Scheme)
+		})
+
+		It("should decode a valid admission request", func() {
+			target := &corev1.Pod{}
+			Expect(strictDecoder.Decode(req, target)).To(Succeed())
+			Expect(target.Name).To(Equal("foo"))
+		})
+
+		It("should error out on unknown fields, listing each of them", func() {
+			target := &corev1.Pod{Spec: corev1.PodSpec{RestartPolicy: corev1.RestartPolicyNever}}
+			err := strictDecoder.DecodeRaw(runtime.RawExtension{
+				Raw: []byte(`{
+    "apiVersion": "v1",
+    "kind": "Pod",
+    "metadata": {"name": "foo"},
+    "spec": {"foo": "bar", "containers": [{"name": "bar", "image": "bar:v2", "baz": 1}]}
+}`),
+			}, target)
+			Expect(runtime.IsStrictDecodingError(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(`unknown field "spec.foo"`))
+			Expect(err.Error()).To(ContainSubstring(`unknown field "spec.containers[0].baz"`))
+
+			By("still decoding the known fields")
+			Expect(target.Name).To(Equal("foo"))
+			Expect(target.Spec.Containers).To(HaveLen(1))
+			Expect(target.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+		})
+
+		It("should error out on duplicate fields", func() {
+			err := strictDecoder.DecodeRaw(runtime.RawExtension{
+				Raw: []byte(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "foo", "name": "bar"}}`),
+			}, &corev1.Pod{})
+			Expect(runtime.IsStrictDecodingError(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(`duplicate field "metadata.name"`))
+		})
+
+		It("should not error out on unknown fields when decoding into an unstructured object", func() {
+			var target unstructured.Unstructured
+			Expect(strictDecoder.DecodeRaw(runtime.RawExtension{
+				Raw: []byte(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "foo"}, "spec": {"foo": "bar"}}`),
+			}, &target)).To(Succeed())
+		})
+	})
+
+	It("should decode only the status of an object", func() {
+		var status corev1.PodStatus
+		Expect(DecodeStatus(runtime.RawExtension{
+			Raw: []byte(`{"kind": "Pod", "spec": {"containers": [{"name": "bar"}]}, "status": {"phase": "Running"}}`),
+		}, &status)).To(Succeed())
+		Expect(status).To(Equal(corev1.PodStatus{Phase: corev1.PodRunning}))
+
+		By("leaving the status untouched for an object without status")
+		Expect(DecodeStatus(req2.Object, &status)).To(Succeed())
+		Expect(status).To(Equal(corev1.PodStatus{Phase: corev1.PodRunning}))
+
+		By("erroring out on an empty object")
+		Expect(DecodeStatus(runtime.RawExtension{}, &status)).NotTo(Succeed())
+	})
+
+	It("should detect updates of the status subresource", func() {
+		Expect(IsStatusUpdate(Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Update, SubResource: "status",
+		}})).To(BeTrue())
+		Expect(IsStatusUpdate(Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Update,
+		}})).To(BeFalse())
+		Expect(IsStatusUpdate(Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create, SubResource: "status",
+		}})).To(BeFalse())
+	})
 })
@@ -80,5 +80,5 @@ func (h *mutatingHandler) Handle(ctx context.Context, req Request) Response {
 	}
 
 	// Create the patch
-	return PatchResponseFromRaw(req.Object.Raw, marshalled)
+	return PatchResponseFromRaw(req.Object.Raw, marshalled, ForSubResource(req.SubResource))
 }
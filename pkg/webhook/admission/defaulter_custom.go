@@ -29,6 +29,11 @@ import (
 )
 
 // CustomDefaulter defines functions for setting defaults on resources.
+//
+// For resources with a status subresource, the defaults a CustomDefaulter sets on the status
+// are only persisted if the webhook is registered for the status subresource, and only
+// for its UPDATE requests, see PatchResponseFromRaw. For requests to the status subresource,
+// only the changes to the status are returned.
 type CustomDefaulter interface {
 	Default(ctx context.Context, obj runtime.Object) error
 }
@@ -90,5 +95,5 @@ func (h *defaulterForType) Handle(ctx context.Context, req Request) Response {
 	if err != nil {
 		return Errored(http.StatusInternalServerError, err)
 	}
-	return PatchResponseFromRaw(req.Object.Raw, marshalled)
+	return PatchResponseFromRaw(req.Object.Raw, marshalled, ForSubResource(req.SubResource))
 }
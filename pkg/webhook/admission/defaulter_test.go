@@ -7,7 +7,9 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	jsonpatch "gomodules.xyz/jsonpatch/v2"
 	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -30,8 +32,52 @@ var _ = Describe("Defaulter Handler", func() {
 		Expect(resp.Result.Code).Should(Equal(int32(http.StatusOK)))
 	})
 
+	It("should only patch the status of requests to the status subresource", func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		handler := WithCustomDefaulter(scheme, &corev1.Pod{}, &podDefaulter{})
+
+		raw := []byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"foo"},"spec":{"containers":[]},"status":{}}`)
+		resp := handler.Handle(context.TODO(), Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		})
+		Expect(resp.Allowed).Should(BeTrue())
+		Expect(resp.Patches).Should(ContainElements(
+			jsonpatch.NewOperation("add", "/spec/restartPolicy", "Always"),
+			jsonpatch.NewOperation("add", "/status/phase", "Pending"),
+		))
+
+		resp = handler.Handle(context.TODO(), Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation:   admissionv1.Update,
+				SubResource: "status",
+				Object:      runtime.RawExtension{Raw: raw},
+			},
+		})
+		Expect(resp.Allowed).Should(BeTrue())
+		Expect(resp.Patches).Should(ConsistOf(
+			jsonpatch.NewOperation("add", "/status/phase", "Pending"),
+		))
+	})
 })
 
+// podDefaulter defaults the restart policy and the phase of Pods.
+type podDefaulter struct{}
+
+func (*podDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	pod := obj.(*corev1.Pod)
+	if pod.Spec.RestartPolicy == "" {
+		pod.Spec.RestartPolicy = corev1.RestartPolicyAlways
+	}
+	if pod.Status.Phase == "" {
+		pod.Status.Phase = corev1.PodPending
+	}
+	return nil
+}
+
 // TestDefaulter.
 var _ runtime.Object = &TestDefaulter{}
 
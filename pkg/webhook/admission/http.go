@@ -17,6 +17,7 @@ limitations under the License.
 package admission
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -29,6 +30,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/internal/metrics"
 )
 
 var admissionScheme = runtime.NewScheme()
@@ -66,6 +69,12 @@ func (wh *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if wh.WithContextFunc != nil {
 		ctx = wh.WithContextFunc(ctx, r)
 	}
+	w.Header().Set("Content-Type", "application/json")
+
+	outcome := metrics.AdmissionOutcomeErrored
+	defer func() {
+		metrics.SetAdmissionOutcome(r.Context(), outcome)
+	}()
 
 	if r.Body == nil || r.Body == http.NoBody {
 		err := errors.New("request body is empty")
@@ -116,7 +125,46 @@ func (wh *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	wh.getLogger(&req).V(5).Info("received request")
 
-	wh.writeResponseTyped(w, wh.Handle(ctx, req), actualAdmRevGVK)
+	resp := wh.Handle(ctx, req)
+	if wh.MaxResponseBytes > 0 {
+		resp = wh.limitResponseSize(req, resp, actualAdmRevGVK)
+	}
+	outcome = admissionOutcome(resp)
+	wh.writeResponseTyped(w, resp, actualAdmRevGVK)
+}
+
+// limitResponseSize returns an error instead of resp if the AdmissionReview it is written in
+// exceeds MaxResponseBytes.
+func (wh *Webhook) limitResponseSize(req Request, resp Response, admRevGVK *schema.GroupVersionKind) Response {
+	var buf bytes.Buffer
+	if err := wh.encode(&buf, typedAdmissionReview(resp, admRevGVK)); err != nil {
+		// Writing the response fails and reports the error as well.
+		return resp
+	}
+	if int64(buf.Len()) <= wh.MaxResponseBytes {
+		return resp
+	}
+
+	err := fmt.Errorf("response of %d bytes exceeds the limit of %d bytes", buf.Len(), wh.MaxResponseBytes)
+	wh.getLogger(&req).Error(err, "refusing to write an oversized response", "allowed", resp.Allowed, "patchBytes", len(resp.Patch))
+	errored := Errored(http.StatusInternalServerError, err)
+	if err := errored.Complete(req); err != nil {
+		return resp
+	}
+	return errored
+}
+
+// admissionOutcome returns the outcome of resp for the metrics. Responses created with Errored
+// have no reason, unlike the ones created with Denied or from the error of a validator.
+func admissionOutcome(resp Response) string {
+	switch {
+	case resp.Allowed:
+		return metrics.AdmissionOutcomeAllowed
+	case resp.Result != nil && resp.Result.Reason != "":
+		return metrics.AdmissionOutcomeDenied
+	default:
+		return metrics.AdmissionOutcomeErrored
+	}
 }
 
 // writeResponse writes response to w generically, i.e. without encoding GVK information.
@@ -127,7 +175,12 @@ func (wh *Webhook) writeResponse(w io.Writer, response Response) {
 // writeResponseTyped writes response to w with GVK set to admRevGVK, which is necessary
 // if multiple AdmissionReview versions are permitted by the webhook.
 func (wh *Webhook) writeResponseTyped(w io.Writer, response Response, admRevGVK *schema.GroupVersionKind) {
-	ar := v1.AdmissionReview{
+	wh.writeAdmissionResponse(w, *typedAdmissionReview(response, admRevGVK))
+}
+
+// typedAdmissionReview returns an AdmissionReview of response with GVK set to admRevGVK.
+func typedAdmissionReview(response Response, admRevGVK *schema.GroupVersionKind) *v1.AdmissionReview {
+	ar := &v1.AdmissionReview{
 		Response: &response.AdmissionResponse,
 	}
 	// Default to a v1 AdmissionReview, otherwise the API server may not recognize the request
@@ -138,12 +191,12 @@ func (wh *Webhook) writeResponseTyped(w io.Writer, response Response, admRevGVK
 	} else {
 		ar.SetGroupVersionKind(*admRevGVK)
 	}
-	wh.writeAdmissionResponse(w, ar)
+	return ar
 }
 
 // writeAdmissionResponse writes ar to w.
 func (wh *Webhook) writeAdmissionResponse(w io.Writer, ar v1.AdmissionReview) {
-	if err := json.NewEncoder(w).Encode(ar); err != nil {
+	if err := wh.encode(w, &ar); err != nil {
 		wh.getLogger(nil).Error(err, "unable to encode and write the response")
 		// Since the `ar v1.AdmissionReview` is a clear and legal object,
 		// it should not have problem to be marshalled into bytes.
@@ -165,6 +218,14 @@ func (wh *Webhook) writeAdmissionResponse(w io.Writer, ar v1.AdmissionReview) {
 	}
 }
 
+// encode writes ar to w with the Encoder of the webhook, defaulting to encoding/json.
+func (wh *Webhook) encode(w io.Writer, ar *v1.AdmissionReview) error {
+	if wh.Encoder != nil {
+		return wh.Encoder.Encode(ar, w)
+	}
+	return json.NewEncoder(w).Encode(ar)
+}
+
 // unversionedAdmissionReview is used to decode both v1 and v1beta1 AdmissionReview types.
 type unversionedAdmissionReview struct {
 	v1.AdmissionReview
@@ -20,16 +20,29 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"testing"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"gomodules.xyz/jsonpatch/v2"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/internal/metrics"
 )
 
 var _ = Describe("Admission Webhooks", func() {
@@ -156,6 +169,84 @@ var _ = Describe("Admission Webhooks", func() {
 			Expect(respRecorder.Body.String()).To(Equal(expected))
 		})
 
+		It("should encode the response with the Encoder, if any", func() {
+			req := &http.Request{
+				Header: http.Header{"Content-Type": []string{"application/json"}},
+				Body:   nopCloser{Reader: bytes.NewBufferString(fmt.Sprintf(`{%s,"request":{}}`, gvkJSONv1))},
+			}
+			encoder := &countingEncoder{Encoder: kjson.NewSerializerWithOptions(kjson.DefaultMetaFactory, nil, nil, kjson.SerializerOptions{})}
+			webhook := (&Webhook{
+				Handler: &fakeHandler{},
+			}).WithEncoder(encoder)
+
+			expected := fmt.Sprintf(`{%s,"response":{"uid":"","allowed":true,"status":{"metadata":{},"code":200}}}
+`, gvkJSONv1)
+			webhook.ServeHTTP(respRecorder, req)
+			Expect(respRecorder.Body.String()).To(Equal(expected))
+			Expect(respRecorder.Header().Get("Content-Type")).To(Equal("application/json"))
+			Expect(encoder.count).To(Equal(1))
+		})
+
+		It("should reject requests with an unknown content type when using an Encoder", func() {
+			req := &http.Request{
+				Header: http.Header{"Content-Type": []string{"application/foo"}},
+				Body:   nopCloser{Reader: bytes.NewBuffer(nil)},
+			}
+			encoder := &countingEncoder{Encoder: kjson.NewSerializerWithOptions(kjson.DefaultMetaFactory, nil, nil, kjson.SerializerOptions{})}
+			webhook := (&Webhook{
+				Handler: &fakeHandler{},
+			}).WithEncoder(encoder)
+
+			expected := `{"response":{"uid":"","allowed":false,"status":{"metadata":{},"message":"contentType=application/foo, expected application/json","code":400}}}
+`
+			webhook.ServeHTTP(respRecorder, req)
+			Expect(respRecorder.Body.String()).To(Equal(expected))
+			Expect(respRecorder.Header().Get("Content-Type")).To(Equal("application/json"))
+			Expect(encoder.count).To(Equal(1))
+		})
+
+		It("should replace responses exceeding MaxResponseBytes with an error", func() {
+			req := &http.Request{
+				Header: http.Header{"Content-Type": []string{"application/json"}},
+				Body:   nopCloser{Reader: bytes.NewBufferString(fmt.Sprintf(`{%s,"request":{"uid":"uid"}}`, gvkJSONv1))},
+			}
+			webhook := &Webhook{
+				Handler: HandlerFunc(func(context.Context, Request) Response {
+					patches := make([]jsonpatch.JsonPatchOperation, 0, 100)
+					for i := 0; i < cap(patches); i++ {
+						patches = append(patches, jsonpatch.NewOperation("add", fmt.Sprintf("/metadata/annotations/key-%d", i), strings.Repeat("v", 100)))
+					}
+					return Patched("", patches...)
+				}),
+				MaxResponseBytes: 1024,
+			}
+
+			webhook.ServeHTTP(respRecorder, req)
+			review := admissionv1.AdmissionReview{}
+			Expect(json.Unmarshal(respRecorder.Body.Bytes(), &review)).To(Succeed())
+			Expect(review.Response.UID).To(BeEquivalentTo("uid"))
+			Expect(review.Response.Allowed).To(BeFalse())
+			Expect(review.Response.Patch).To(BeEmpty())
+			Expect(review.Response.Result.Code).To(BeEquivalentTo(http.StatusInternalServerError))
+			Expect(review.Response.Result.Message).To(MatchRegexp(`response of \d+ bytes exceeds the limit of 1024 bytes`))
+		})
+
+		It("should write responses within MaxResponseBytes", func() {
+			req := &http.Request{
+				Header: http.Header{"Content-Type": []string{"application/json"}},
+				Body:   nopCloser{Reader: bytes.NewBufferString(fmt.Sprintf(`{%s,"request":{}}`, gvkJSONv1))},
+			}
+			webhook := &Webhook{
+				Handler:          &fakeHandler{},
+				MaxResponseBytes: 1024,
+			}
+
+			expected := fmt.Sprintf(`{%s,"response":{"uid":"","allowed":true,"status":{"metadata":{},"code":200}}}
+`, gvkJSONv1)
+			webhook.ServeHTTP(respRecorder, req)
+			Expect(respRecorder.Body.String()).To(Equal(expected))
+		})
+
 		It("should present the Context from the HTTP request, if any", func() {
 			req := &http.Request{
 				Header: http.Header{"Content-Type": []string{"application/json"}},
@@ -228,6 +319,68 @@ var _ = Describe("Admission Webhooks", func() {
 	})
 })
 
+var _ = Describe("Admission Webhook metrics", func() {
+	serve := func(hook http.Handler, body string) {
+		req := &http.Request{
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body:   nopCloser{Reader: bytes.NewBufferString(body)},
+		}
+		hook.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	requests := func(path, outcome string) float64 {
+		return testutil.ToFloat64(metrics.AdmissionRequestTotal.WithLabelValues(path, outcome))
+	}
+	observations := func(path, outcome string) uint64 {
+		m := &dto.Metric{}
+		Expect(metrics.AdmissionRequestLatency.WithLabelValues(path, outcome).(prometheus.Histogram).Write(m)).To(Succeed())
+		return m.GetHistogram().GetSampleCount()
+	}
+
+	It("should count the requests and observe their latency by outcome", func() {
+		const path = "/metrics-by-outcome"
+		allow := true
+		hook := metrics.InstrumentedHook(path, &Webhook{
+			Handler: HandlerFunc(func(context.Context, Request) Response {
+				if allow {
+					return Allowed("")
+				}
+				return Denied("not allowed")
+			}),
+		})
+
+		serve(hook, `{"request":{}}`)
+		serve(hook, `{"request":{}}`)
+		allow = false
+		serve(hook, `{"request":{}}`)
+
+		Expect(requests(path, "allowed")).To(Equal(2.0))
+		Expect(requests(path, "denied")).To(Equal(1.0))
+		Expect(requests(path, "errored")).To(BeZero())
+		Expect(observations(path, "allowed")).To(Equal(uint64(2)))
+		Expect(observations(path, "denied")).To(Equal(uint64(1)))
+	})
+
+	It("should count validation errors as denied and handler errors as errored", func() {
+		const path = "/metrics-errors"
+		var resp Response
+		hook := metrics.InstrumentedHook(path, &Webhook{
+			Handler: HandlerFunc(func(context.Context, Request) Response {
+				return resp
+			}),
+		})
+
+		resp = validationResponseFromStatus(false, metav1.Status{Code: http.StatusUnprocessableEntity, Reason: metav1.StatusReasonInvalid})
+		serve(hook, `{"request":{}}`)
+		resp = Errored(http.StatusInternalServerError, errors.New("boom"))
+		serve(hook, `{"request":{}}`)
+		serve(hook, `{`)
+
+		Expect(requests(path, "allowed")).To(BeZero())
+		Expect(requests(path, "denied")).To(Equal(1.0))
+		Expect(requests(path, "errored")).To(Equal(2.0))
+	})
+})
+
 type nopCloser struct {
 	io.Reader
 }
@@ -256,3 +409,42 @@ type brokenWriter struct {
 func (bw *brokenWriter) Write(buf []byte) (int, error) {
 	return 0, fmt.Errorf("mock: write: broken pipe")
 }
+
+// countingEncoder counts the objects it encodes.
+type countingEncoder struct {
+	runtime.Encoder
+	count int
+}
+
+func (e *countingEncoder) Encode(obj runtime.Object, w io.Writer) error {
+	e.count++
+	return e.Encoder.Encode(obj, w)
+}
+
+func BenchmarkWriteResponse(b *testing.B) {
+	// A response with many patches, as returned for large objects.
+	patches := make([]jsonpatch.JsonPatchOperation, 0, 10000)
+	for i := 0; i < cap(patches); i++ {
+		patches = append(patches, jsonpatch.NewOperation("add", fmt.Sprintf("/metadata/annotations/key-%d", i), strings.Repeat("v", 100)))
+	}
+	response := Patched("", patches...)
+	if err := response.Complete(Request{}); err != nil {
+		b.Fatalf("expected no error, got %v", err)
+	}
+	gvk := admissionv1.SchemeGroupVersion.WithKind("AdmissionReview")
+
+	for _, tc := range []struct {
+		name    string
+		webhook *Webhook
+	}{
+		{name: "Default", webhook: &Webhook{}},
+		{name: "Encoder", webhook: (&Webhook{}).WithEncoder(kjson.NewSerializerWithOptions(kjson.DefaultMetaFactory, nil, nil, kjson.SerializerOptions{}))},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				tc.webhook.writeResponseTyped(io.Discard, response, &gvk)
+			}
+		})
+	}
+}
@@ -19,12 +19,16 @@ package admission
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	jsonpatch "gomodules.xyz/jsonpatch/v2"
 	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 type multiMutating []Handler
@@ -93,3 +97,110 @@ func (hs multiValidating) Handle(ctx context.Context, req Request) Response {
 func MultiValidatingHandler(handlers ...Handler) Handler {
 	return multiValidating(handlers)
 }
+
+// MultiValidatorOptions are the options of a validator created by MultiValidator.
+type MultiValidatorOptions struct {
+	// AggregateErrors makes the validator call all validators even if some of them
+	// fail, and combine their errors into a single *apierrors.StatusError.
+	// Defaults to false, i.e. the first error fails the validation.
+	AggregateErrors bool
+}
+
+type multiValidator struct {
+	validators []CustomValidator
+	opts       MultiValidatorOptions
+}
+
+var _ CustomStatusValidator = &multiValidator{}
+
+// MultiValidator combines multiple validators for the same type into a single
+// CustomValidator, e.g. to be passed to WithCustomValidator.  Validators are called
+// in sequential order and their warnings are collected.  The first error short-circuits
+// the rest, unless opts.AggregateErrors is set.
+//
+// Updates of the status subresource are validated by ValidateStatusUpdate for validators
+// implementing CustomStatusValidator, and by ValidateUpdate for the others.
+func MultiValidator(opts MultiValidatorOptions, validators ...CustomValidator) CustomValidator {
+	return &multiValidator{validators: validators, opts: opts}
+}
+
+// ValidateCreate implements CustomValidator.
+func (v *multiValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (Warnings, error) {
+	return v.validate(func(validator CustomValidator) (Warnings, error) {
+		return validator.ValidateCreate(ctx, obj)
+	})
+}
+
+// ValidateUpdate implements CustomValidator.
+func (v *multiValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (Warnings, error) {
+	return v.validate(func(validator CustomValidator) (Warnings, error) {
+		return validator.ValidateUpdate(ctx, oldObj, newObj)
+	})
+}
+
+// ValidateDelete implements CustomValidator.
+func (v *multiValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (Warnings, error) {
+	return v.validate(func(validator CustomValidator) (Warnings, error) {
+		return validator.ValidateDelete(ctx, obj)
+	})
+}
+
+// ValidateStatusUpdate implements CustomStatusValidator.
+func (v *multiValidator) ValidateStatusUpdate(ctx context.Context, oldObj, newObj runtime.Object) (Warnings, error) {
+	return v.validate(func(validator CustomValidator) (Warnings, error) {
+		if statusValidator, ok := validator.(CustomStatusValidator); ok {
+			return statusValidator.ValidateStatusUpdate(ctx, oldObj, newObj)
+		}
+		return validator.ValidateUpdate(ctx, oldObj, newObj)
+	})
+}
+
+func (v *multiValidator) validate(validate func(CustomValidator) (Warnings, error)) (Warnings, error) {
+	var (
+		warnings Warnings
+		errs     []error
+	)
+	for _, validator := range v.validators {
+		w, err := validate(validator)
+		warnings = append(warnings, w...)
+		if err != nil {
+			if !v.opts.AggregateErrors {
+				return warnings, err
+			}
+			errs = append(errs, err)
+		}
+	}
+	return warnings, aggregateValidationErrors(errs)
+}
+
+// aggregateValidationErrors combines errs into a single *apierrors.StatusError denying
+// the request, which carries the causes of all errors that are API statuses.
+func aggregateValidationErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+
+	msgs := make([]string, 0, len(errs))
+	var causes []metav1.StatusCause
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+		var apiStatus apierrors.APIStatus
+		if errors.As(err, &apiStatus) && apiStatus.Status().Details != nil {
+			causes = append(causes, apiStatus.Status().Details.Causes...)
+		}
+	}
+
+	status := metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusForbidden,
+		Reason:  metav1.StatusReasonForbidden,
+		Message: strings.Join(msgs, "; "),
+	}
+	if len(causes) > 0 {
+		status.Details = &metav1.StatusDetails{Causes: causes}
+	}
+	return &apierrors.StatusError{ErrStatus: status}
+}
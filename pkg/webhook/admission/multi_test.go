@@ -18,12 +18,20 @@ package admission
 
 import (
 	"context"
+	"errors"
+	"net/http"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	jsonpatch "gomodules.xyz/jsonpatch/v2"
 	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes/scheme"
 )
 
 var _ = Describe("Multi-Handler Admission Webhooks", func() {
@@ -129,4 +137,97 @@ var _ = Describe("Multi-Handler Admission Webhooks", func() {
 					`{"op":"replace","path":"/spec/replicas","value":"2"},{"op":"add","path":"/metadata/annotation/hello","value":"world"}]`)))
 		})
 	})
+
+	Context("with validators", func() {
+		var allowing, warning, failing, invalid *fakeMultiValidator
+		BeforeEach(func() {
+			allowing = &fakeMultiValidator{}
+			warning = &fakeMultiValidator{warnings: Warnings{"deprecated field"}}
+			failing = &fakeMultiValidator{warnings: Warnings{"failing"}, err: errors.New("not allowed")}
+			invalid = &fakeMultiValidator{err: apierrors.NewInvalid(
+				schema.GroupKind{Group: "apps", Kind: "Deployment"}, "foo",
+				field.ErrorList{field.Invalid(field.NewPath("spec", "replicas"), 0, "must be positive")},
+			)}
+		})
+
+		It("should allow the request and collect all warnings if all validators succeed", func() {
+			validator := MultiValidator(MultiValidatorOptions{}, allowing, warning, warning)
+
+			warnings, err := validator.ValidateCreate(context.Background(), &appsv1.Deployment{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(Equal(Warnings{"deprecated field", "deprecated field"}))
+			Expect(allowing.calls).To(Equal(1))
+			Expect(warning.calls).To(Equal(2))
+		})
+
+		It("should fail on the first error by default", func() {
+			validator := MultiValidator(MultiValidatorOptions{}, warning, failing, invalid, allowing)
+
+			warnings, err := validator.ValidateUpdate(context.Background(), &appsv1.Deployment{}, &appsv1.Deployment{})
+			Expect(err).To(MatchError("not allowed"))
+			Expect(warnings).To(Equal(Warnings{"deprecated field", "failing"}))
+			Expect(invalid.calls).To(Equal(0))
+			Expect(allowing.calls).To(Equal(0))
+		})
+
+		It("should aggregate all errors if AggregateErrors is set", func() {
+			validator := MultiValidator(MultiValidatorOptions{AggregateErrors: true}, failing, warning, invalid, allowing)
+
+			warnings, err := validator.ValidateDelete(context.Background(), &appsv1.Deployment{})
+			Expect(warnings).To(Equal(Warnings{"failing", "deprecated field"}))
+			Expect(allowing.calls).To(Equal(1))
+
+			var statusErr *apierrors.StatusError
+			Expect(errors.As(err, &statusErr)).To(BeTrue())
+			Expect(statusErr.ErrStatus.Code).To(Equal(int32(http.StatusForbidden)))
+			Expect(statusErr.ErrStatus.Message).To(HavePrefix("not allowed; "))
+			Expect(statusErr.ErrStatus.Message).To(ContainSubstring("must be positive"))
+			Expect(statusErr.ErrStatus.Details.Causes).To(HaveLen(1))
+			Expect(statusErr.ErrStatus.Details.Causes[0].Field).To(Equal("spec.replicas"))
+		})
+
+		It("should return a single error unchanged if AggregateErrors is set", func() {
+			validator := MultiValidator(MultiValidatorOptions{AggregateErrors: true}, allowing, invalid)
+
+			_, err := validator.ValidateCreate(context.Background(), &appsv1.Deployment{})
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		})
+
+		It("should deny the request with the aggregated errors when used by a webhook", func() {
+			validator := MultiValidator(MultiValidatorOptions{AggregateErrors: true}, failing, warning, invalid)
+			webhook := WithCustomValidator(scheme.Scheme, &appsv1.Deployment{}, validator)
+
+			resp := webhook.Handle(context.Background(), Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment"}`)},
+			}})
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Code).To(Equal(int32(http.StatusForbidden)))
+			Expect(resp.Result.Message).To(HavePrefix("not allowed; "))
+			Expect(resp.Warnings).To(ConsistOf("failing", "deprecated field"))
+		})
+	})
 })
+
+type fakeMultiValidator struct {
+	warnings Warnings
+	err      error
+	calls    int
+}
+
+func (v *fakeMultiValidator) validate() (Warnings, error) {
+	v.calls++
+	return v.warnings, v.err
+}
+
+func (v *fakeMultiValidator) ValidateCreate(context.Context, runtime.Object) (Warnings, error) {
+	return v.validate()
+}
+
+func (v *fakeMultiValidator) ValidateUpdate(context.Context, runtime.Object, runtime.Object) (Warnings, error) {
+	return v.validate()
+}
+
+func (v *fakeMultiValidator) ValidateDelete(context.Context, runtime.Object) (Warnings, error) {
+	return v.validate()
+}
@@ -17,8 +17,14 @@ limitations under the License.
 package admission
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
+	evanjsonpatch "github.com/evanphx/json-patch/v5"
 	jsonpatch "gomodules.xyz/jsonpatch/v2"
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -85,11 +91,34 @@ func ValidationResponse(allowed bool, message string) Response {
 // PatchResponseFromRaw takes 2 byte arrays and returns a new response with json patch.
 // The original object should be passed in as raw bytes to avoid the roundtripping problem
 // described in https://github.com/kubernetes-sigs/kubebuilder/issues/510.
-func PatchResponseFromRaw(original, current []byte) Response {
+//
+// The patch contains operations for all fields that differ, including the ones of the
+// status, but the API server only persists the changes a request is allowed to make:
+// for resources with a status subresource, changes to the status are only persisted for
+// UPDATE requests to the status subresource, and changes to all other fields only for
+// CREATE and UPDATE requests to the main resource. In particular, the status of such a
+// resource can't be defaulted on CREATE, as the API server resets it after admission.
+// Resources without a status subresource persist changes to the status for all requests.
+// See ForSubResource to only return the operations that are persisted for a subresource.
+func PatchResponseFromRaw(original, current []byte, opts ...PatchResponseOption) Response {
+	options := &patchResponseOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	patches, err := jsonpatch.CreatePatch(original, current)
 	if err != nil {
 		return Errored(http.StatusInternalServerError, err)
 	}
+	if options.subResource == "status" {
+		patches = statusPatches(patches)
+	}
+	if options.testOperations {
+		patches, err = withTestOperations(original, patches)
+		if err != nil {
+			return Errored(http.StatusInternalServerError, err)
+		}
+	}
 	return Response{
 		Patches: patches,
 		AdmissionResponse: admissionv1.AdmissionResponse{
@@ -105,6 +134,110 @@ func PatchResponseFromRaw(original, current []byte) Response {
 	}
 }
 
+// PatchResponseOption configures the patch of a response created by PatchResponseFromRaw.
+type PatchResponseOption func(*patchResponseOptions)
+
+type patchResponseOptions struct {
+	testOperations bool
+	subResource    string
+}
+
+// ForSubResource makes PatchResponseFromRaw only return the operations the API server persists
+// for a request to the given subresource, typically the SubResource of the Request. For the
+// status subresource, these are the operations on the status, as the API server resets all other
+// fields of the object. For all other subresources, and the main resource, all operations are
+// returned.
+func ForSubResource(subResource string) PatchResponseOption {
+	return func(o *patchResponseOptions) {
+		o.subResource = subResource
+	}
+}
+
+// WithTestOperations makes PatchResponseFromRaw guard every operation that replaces or removes
+// a value with a preceding "test" operation, asserting the value the original object has at
+// that path. The patch is then rejected if the object was changed at those paths in the
+// meantime, instead of silently overwriting the change.
+//
+// Operations that add a value are not guarded, as a test operation can't assert that a path
+// doesn't exist.
+func WithTestOperations() PatchResponseOption {
+	return func(o *patchResponseOptions) {
+		o.testOperations = true
+	}
+}
+
+// withTestOperations inserts a test operation before every replace and remove operation of
+// patches, asserting the value at its path in original after applying the preceding operations.
+func withTestOperations(original []byte, patches []jsonpatch.JsonPatchOperation) ([]jsonpatch.JsonPatchOperation, error) {
+	doc := original
+	guarded := make([]jsonpatch.JsonPatchOperation, 0, 2*len(patches))
+	for _, patch := range patches {
+		if patch.Operation == "replace" || patch.Operation == "remove" {
+			value, err := valueAtPath(doc, patch.Path)
+			if err != nil {
+				return nil, err
+			}
+			guarded = append(guarded, jsonpatch.NewOperation("test", patch.Path, value))
+		}
+		guarded = append(guarded, patch)
+
+		raw, err := json.Marshal([]jsonpatch.JsonPatchOperation{patch})
+		if err != nil {
+			return nil, err
+		}
+		decoded, err := evanjsonpatch.DecodePatch(raw)
+		if err != nil {
+			return nil, err
+		}
+		if doc, err = decoded.Apply(doc); err != nil {
+			return nil, err
+		}
+	}
+	return guarded, nil
+}
+
+// statusPatches returns the operations of patches on the status of an object.
+func statusPatches(patches []jsonpatch.JsonPatchOperation) []jsonpatch.JsonPatchOperation {
+	filtered := make([]jsonpatch.JsonPatchOperation, 0, len(patches))
+	for _, patch := range patches {
+		if patch.Path == "/status" || strings.HasPrefix(patch.Path, "/status/") {
+			filtered = append(filtered, patch)
+		}
+	}
+	return filtered
+}
+
+// valueAtPath returns the value of the JSON document doc at the JSON pointer path.
+func valueAtPath(doc []byte, path string) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	for _, token := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch v := value.(type) {
+		case map[string]interface{}:
+			child, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("path %q does not exist", path)
+			}
+			value = child
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("path %q does not exist", path)
+			}
+			value = v[i]
+		default:
+			return nil, fmt.Errorf("path %q does not exist", path)
+		}
+	}
+	return value, nil
+}
+
 // validationResponseFromStatus returns a response for admitting a request with provided Status object.
 func validationResponseFromStatus(allowed bool, status metav1.Status) Response {
 	resp := Response{
@@ -17,12 +17,14 @@ limitations under the License.
 package admission
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	evanjsonpatch "github.com/evanphx/json-patch/v5"
 	jsonpatch "gomodules.xyz/jsonpatch/v2"
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -219,6 +221,103 @@ var _ = Describe("Admission Webhook Response Helpers", func() {
 			resp := PatchResponseFromRaw([]byte(`{"a": "foo"}`), []byte(`{"a": "bar"}`))
 			Expect(resp).To(Equal(expected))
 		})
+
+		Context("WithTestOperations", func() {
+			original := []byte(`{"a": "foo", "b": {"c/d": 1}, "e": ["x", "y", "z"]}`)
+			current := []byte(`{"a": "bar", "e": ["x", "z"], "f": true}`)
+
+			apply := func(doc []byte, resp Response) ([]byte, error) {
+				raw, err := json.Marshal(resp.Patches)
+				Expect(err).NotTo(HaveOccurred())
+				patch, err := evanjsonpatch.DecodePatch(raw)
+				Expect(err).NotTo(HaveOccurred())
+				return patch.Apply(doc)
+			}
+
+			It("should guard replaced and removed values with test operations", func() {
+				resp := PatchResponseFromRaw(original, current, WithTestOperations())
+				Expect(resp.Allowed).To(BeTrue())
+				Expect(resp.Patches).To(ContainElements(
+					jsonpatch.NewOperation("test", "/a", "foo"),
+					jsonpatch.NewOperation("replace", "/a", "bar"),
+					jsonpatch.NewOperation("test", "/b", map[string]interface{}{"c/d": json.Number("1")}),
+					jsonpatch.NewOperation("remove", "/b", nil),
+				))
+				Expect(resp.Patches).To(ContainElement(jsonpatch.NewOperation("add", "/f", true)))
+				Expect(resp.Patches).NotTo(ContainElement(And(HaveField("Operation", "test"), HaveField("Path", "/f"))))
+
+				for i, patch := range resp.Patches {
+					if patch.Operation == "replace" || patch.Operation == "remove" {
+						Expect(i).To(BeNumerically(">", 0))
+						Expect(resp.Patches[i-1].Operation).To(Equal("test"))
+						Expect(resp.Patches[i-1].Path).To(Equal(patch.Path))
+					}
+				}
+			})
+
+			It("should produce a patch that applies to the original object", func() {
+				resp := PatchResponseFromRaw(original, current, WithTestOperations())
+				patched, err := apply(original, resp)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(patched).To(MatchJSON(current))
+			})
+
+			It("should produce a patch that is rejected if the object changed at a mutated path", func() {
+				resp := PatchResponseFromRaw(original, current, WithTestOperations())
+				_, err := apply([]byte(`{"a": "changed", "b": {"c/d": 1}, "e": ["x", "y", "z"]}`), resp)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should not add test operations without the option", func() {
+				resp := PatchResponseFromRaw(original, current)
+				Expect(resp.Patches).NotTo(ContainElement(HaveField("Operation", "test")))
+			})
+		})
+
+		Context("ForSubResource", func() {
+			original := []byte(`{"spec": {"replicas": 1}, "status": {}}`)
+			current := []byte(`{"spec": {"replicas": 2}, "status": {"phase": "Pending", "conditions": []}}`)
+
+			It("should include the operations on the status without the option", func() {
+				resp := PatchResponseFromRaw(original, current)
+				Expect(resp.Patches).To(ConsistOf(
+					jsonpatch.NewOperation("replace", "/spec/replicas", float64(2)),
+					jsonpatch.NewOperation("add", "/status/phase", "Pending"),
+					jsonpatch.NewOperation("add", "/status/conditions", []interface{}{}),
+				))
+			})
+
+			It("should only return the operations on the status for the status subresource", func() {
+				resp := PatchResponseFromRaw(original, current, ForSubResource("status"))
+				Expect(resp.Allowed).To(BeTrue())
+				Expect(resp.PatchType).NotTo(BeNil())
+				Expect(resp.Patches).To(ConsistOf(
+					jsonpatch.NewOperation("add", "/status/phase", "Pending"),
+					jsonpatch.NewOperation("add", "/status/conditions", []interface{}{}),
+				))
+			})
+
+			It("should replace a missing status as a whole for the status subresource", func() {
+				resp := PatchResponseFromRaw([]byte(`{"spec": {}}`), []byte(`{"spec": {}, "status": {"phase": "Pending"}}`), ForSubResource("status"))
+				Expect(resp.Patches).To(ConsistOf(
+					jsonpatch.NewOperation("add", "/status", map[string]interface{}{"phase": "Pending"}),
+				))
+			})
+
+			It("should not return a patch type if there are no operations on the status", func() {
+				resp := PatchResponseFromRaw([]byte(`{"spec": {"replicas": 1}}`), []byte(`{"spec": {"replicas": 2}}`), ForSubResource("status"))
+				Expect(resp.Allowed).To(BeTrue())
+				Expect(resp.Patches).To(BeEmpty())
+				Expect(resp.PatchType).To(BeNil())
+			})
+
+			It("should return all operations for the main resource and other subresources", func() {
+				for _, subResource := range []string{"", "scale"} {
+					resp := PatchResponseFromRaw(original, current, ForSubResource(subResource))
+					Expect(resp.Patches).To(HaveLen(3))
+				}
+			})
+		})
 	})
 
 	Describe("WithWarnings", func() {
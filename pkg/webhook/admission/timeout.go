@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type timeoutHandler struct {
+	timeout time.Duration
+	handler Handler
+}
+
+type timeoutResult struct {
+	resp     Response
+	panicked bool
+	panicVal interface{}
+}
+
+// WithTimeout wraps handler so that its context is cancelled after timeout, and the
+// request is rejected with http.StatusRequestTimeout if handler did not return by then.
+// Unlike relying on the timeoutSeconds of the webhook configuration, whose failurePolicy
+// decides whether the API server admits the request, this always denies it.
+//
+// handler keeps running in the background after the timeout until it returns, so it
+// should stop once its context is done.
+func WithTimeout(timeout time.Duration, handler Handler) Handler {
+	return &timeoutHandler{timeout: timeout, handler: handler}
+}
+
+// Handle implements Handler.
+func (h *timeoutHandler) Handle(ctx context.Context, req Request) Response {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	// Buffered, so that the handler does not block forever once we stopped waiting for it.
+	done := make(chan timeoutResult, 1)
+	go func() {
+		var res timeoutResult
+		defer func() {
+			if r := recover(); r != nil {
+				res.panicked, res.panicVal = true, r
+			}
+			done <- res
+		}()
+		res.resp = h.handler.Handle(ctx, req)
+	}()
+
+	select {
+	case res := <-done:
+		if res.panicked {
+			// Re-panic in the goroutine of the caller, so that Webhook.RecoverPanic applies.
+			panic(res.panicVal)
+		}
+		return res.resp
+	case <-ctx.Done():
+		logf.FromContext(ctx).Info("Admission handler did not complete in time", "timeout", h.timeout)
+		return Errored(http.StatusRequestTimeout, fmt.Errorf("admission handler did not complete within %s: %w", h.timeout, ctx.Err()))
+	}
+}
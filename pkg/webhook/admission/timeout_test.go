@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WithTimeout", func() {
+	It("should return the response of a handler completing in time", func() {
+		handler := WithTimeout(time.Second, HandlerFunc(func(ctx context.Context, req Request) Response {
+			return Allowed("fast enough")
+		}))
+
+		resp := handler.Handle(context.Background(), Request{})
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Result.Message).To(Equal("fast enough"))
+	})
+
+	It("should deny the request and cancel the context of a handler exceeding the timeout", func() {
+		handlerCtxDone := make(chan struct{})
+		handler := WithTimeout(50*time.Millisecond, HandlerFunc(func(ctx context.Context, req Request) Response {
+			select {
+			case <-time.After(5 * time.Second):
+			case <-ctx.Done():
+				close(handlerCtxDone)
+			}
+			return Allowed("too slow")
+		}))
+
+		start := time.Now()
+		resp := handler.Handle(context.Background(), Request{})
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Code).To(Equal(int32(http.StatusRequestTimeout)))
+		Expect(resp.Result.Message).To(ContainSubstring("did not complete within 50ms"))
+		Eventually(handlerCtxDone).Should(BeClosed())
+	})
+
+	It("should deny the request of a handler ignoring its context", func() {
+		handler := WithTimeout(50*time.Millisecond, HandlerFunc(func(ctx context.Context, req Request) Response {
+			time.Sleep(500 * time.Millisecond)
+			return Allowed("too slow")
+		}))
+
+		resp := handler.Handle(context.Background(), Request{})
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Code).To(Equal(int32(http.StatusRequestTimeout)))
+	})
+
+	It("should propagate panics of the handler to the webhook", func() {
+		wh := &Webhook{
+			Handler: WithTimeout(time.Second, HandlerFunc(func(ctx context.Context, req Request) Response {
+				panic("fake panic")
+			})),
+			RecoverPanic: true,
+		}
+
+		resp := wh.Handle(context.Background(), Request{})
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Code).To(Equal(int32(http.StatusInternalServerError)))
+		Expect(resp.Result.Message).To(Equal("panic: fake panic [recovered]"))
+	})
+})
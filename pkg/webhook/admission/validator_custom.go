@@ -46,6 +46,18 @@ type CustomValidator interface {
 	ValidateDelete(ctx context.Context, obj runtime.Object) (warnings Warnings, err error)
 }
 
+// CustomStatusValidator can be implemented by a CustomValidator to validate updates of the
+// status subresource separately. Those only change the status of the object, so validating
+// its spec, as ValidateUpdate usually does, is unnecessary for them.
+type CustomStatusValidator interface {
+	// ValidateStatusUpdate validates the object on an update of its status subresource.
+	// It is called instead of ValidateUpdate for those. Use DecodeStatus to only look at
+	// the status of the objects.
+	// The optional warnings will be added to the response as warning messages.
+	// Return an error if the object is invalid.
+	ValidateStatusUpdate(ctx context.Context, oldObj, newObj runtime.Object) (warnings Warnings, err error)
+}
+
 // WithCustomValidator creates a new Webhook for validating the provided type.
 func WithCustomValidator(scheme *runtime.Scheme, obj runtime.Object, validator CustomValidator) *Webhook {
 	return &Webhook{
@@ -98,6 +110,10 @@ func (h *validatorForType) Handle(ctx context.Context, req Request) Response {
 			return Errored(http.StatusBadRequest, err)
 		}
 
+		if statusValidator, ok := h.validator.(CustomStatusValidator); ok && IsStatusUpdate(req) {
+			warnings, err = statusValidator.ValidateStatusUpdate(ctx, oldObj, obj)
+			break
+		}
 		warnings, err = h.validator.ValidateUpdate(ctx, oldObj, obj)
 	case v1.Delete:
 		// In reference to PR: https://github.com/kubernetes/kubernetes/pull/76346
@@ -19,11 +19,13 @@ package admission
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -447,6 +449,64 @@ var _ = Describe("validatingHandler", func() {
 
 })
 
+var _ = Describe("customValidator", func() {
+	It("should only validate the status of status subresource updates", func() {
+		v := &fakeStatusValidator{}
+		handler := &validatorForType{validator: v, object: &corev1.Pod{}, decoder: NewDecoder(scheme.Scheme)}
+		req := Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation:   admissionv1.Update,
+				SubResource: "status",
+				Object: runtime.RawExtension{
+					Raw: []byte(`{"kind": "Pod", "apiVersion": "v1", "spec": {}, "status": {"phase": "Unknown"}}`),
+				},
+				OldObject: runtime.RawExtension{
+					Raw: []byte(`{"kind": "Pod", "apiVersion": "v1", "spec": {}, "status": {"phase": "Running"}}`),
+				},
+			},
+		}
+
+		response := handler.Handle(context.TODO(), req)
+		Expect(response.Allowed).To(BeFalse())
+		Expect(response.Result.Message).To(Equal("phase can't change from Running to Unknown"))
+		Expect(v.specValidated).To(BeFalse())
+
+		By("validating the spec of other updates")
+		req.SubResource = ""
+		response = handler.Handle(context.TODO(), req)
+		Expect(response.Allowed).To(BeFalse())
+		Expect(response.Result.Message).To(Equal("spec is invalid"))
+		Expect(v.specValidated).To(BeTrue())
+	})
+})
+
+// fakeStatusValidator is a CustomStatusValidator that rejects every spec, and
+// status updates that change the phase of a Pod to Unknown.
+type fakeStatusValidator struct {
+	specValidated bool
+}
+
+func (v *fakeStatusValidator) ValidateCreate(context.Context, runtime.Object) (Warnings, error) {
+	return nil, nil
+}
+
+func (v *fakeStatusValidator) ValidateUpdate(context.Context, runtime.Object, runtime.Object) (Warnings, error) {
+	v.specValidated = true
+	return nil, errors.New("spec is invalid")
+}
+
+func (v *fakeStatusValidator) ValidateDelete(context.Context, runtime.Object) (Warnings, error) {
+	return nil, nil
+}
+
+func (v *fakeStatusValidator) ValidateStatusUpdate(ctx context.Context, oldObj, newObj runtime.Object) (Warnings, error) {
+	oldPhase, newPhase := oldObj.(*corev1.Pod).Status.Phase, newObj.(*corev1.Pod).Status.Phase
+	if newPhase == corev1.PodUnknown && oldPhase != newPhase {
+		return nil, fmt.Errorf("phase can't change from %s to %s", oldPhase, newPhase)
+	}
+	return nil, nil
+}
+
 // fakeValidator provides fake validating webhook functionality for testing
 // It implements the admission.Validator interface and
 // rejects all requests with the same configured error
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type warningOnlyValidator struct {
+	validator CustomValidator
+}
+
+var _ CustomStatusValidator = &warningOnlyValidator{}
+
+// WarningOnly wraps validator so that it never denies a request, e.g. to roll out a new
+// policy in an audit mode before enforcing it. The message of every error validator returns
+// is added to its warnings instead, which the API server returns to the client, and the
+// request is allowed.
+//
+// Updates of the status subresource are validated by ValidateStatusUpdate if validator
+// implements CustomStatusValidator, and by ValidateUpdate otherwise.
+func WarningOnly(validator CustomValidator) CustomValidator {
+	return &warningOnlyValidator{validator: validator}
+}
+
+// ValidateCreate implements CustomValidator.
+func (v *warningOnlyValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (Warnings, error) {
+	warnings, err := v.validator.ValidateCreate(ctx, obj)
+	return warnOnly(ctx, warnings, err)
+}
+
+// ValidateUpdate implements CustomValidator.
+func (v *warningOnlyValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (Warnings, error) {
+	warnings, err := v.validator.ValidateUpdate(ctx, oldObj, newObj)
+	return warnOnly(ctx, warnings, err)
+}
+
+// ValidateDelete implements CustomValidator.
+func (v *warningOnlyValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (Warnings, error) {
+	warnings, err := v.validator.ValidateDelete(ctx, obj)
+	return warnOnly(ctx, warnings, err)
+}
+
+// ValidateStatusUpdate implements CustomStatusValidator.
+func (v *warningOnlyValidator) ValidateStatusUpdate(ctx context.Context, oldObj, newObj runtime.Object) (Warnings, error) {
+	if statusValidator, ok := v.validator.(CustomStatusValidator); ok {
+		warnings, err := statusValidator.ValidateStatusUpdate(ctx, oldObj, newObj)
+		return warnOnly(ctx, warnings, err)
+	}
+	return v.ValidateUpdate(ctx, oldObj, newObj)
+}
+
+// warnOnly adds the message of err to warnings instead of returning it.
+func warnOnly(ctx context.Context, warnings Warnings, err error) (Warnings, error) {
+	if err == nil {
+		return warnings, nil
+	}
+	logf.FromContext(ctx).Info("Allowing request that failed validation in warning-only mode", "reason", err.Error())
+	return append(warnings, err.Error()), nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+var _ = Describe("WarningOnly", func() {
+	It("should turn errors into warnings", func() {
+		validator := WarningOnly(&fakeMultiValidator{warnings: Warnings{"deprecated field"}, err: errors.New("not allowed")})
+
+		warnings, err := validator.ValidateCreate(context.Background(), &appsv1.Deployment{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(Equal(Warnings{"deprecated field", "not allowed"}))
+
+		warnings, err = validator.ValidateUpdate(context.Background(), &appsv1.Deployment{}, &appsv1.Deployment{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(Equal(Warnings{"deprecated field", "not allowed"}))
+
+		warnings, err = validator.ValidateDelete(context.Background(), &appsv1.Deployment{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(Equal(Warnings{"deprecated field", "not allowed"}))
+	})
+
+	It("should pass on the warnings of successful validations", func() {
+		validator := WarningOnly(&fakeMultiValidator{warnings: Warnings{"deprecated field"}})
+
+		warnings, err := validator.ValidateCreate(context.Background(), &appsv1.Deployment{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(Equal(Warnings{"deprecated field"}))
+	})
+
+	It("should validate status updates with ValidateUpdate if the validator doesn't validate them separately", func() {
+		inner := &fakeMultiValidator{err: errors.New("not allowed")}
+		validator := WarningOnly(inner).(CustomStatusValidator)
+
+		warnings, err := validator.ValidateStatusUpdate(context.Background(), &appsv1.Deployment{}, &appsv1.Deployment{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(Equal(Warnings{"not allowed"}))
+		Expect(inner.calls).To(Equal(1))
+	})
+
+	It("should allow the request with the denial as a warning when used by a webhook", func() {
+		invalid := &fakeMultiValidator{err: apierrors.NewInvalid(
+			schema.GroupKind{Group: "apps", Kind: "Deployment"}, "foo",
+			field.ErrorList{field.Invalid(field.NewPath("spec", "replicas"), 0, "must be positive")},
+		)}
+		webhook := WithCustomValidator(scheme.Scheme, &appsv1.Deployment{}, WarningOnly(invalid))
+
+		resp := webhook.Handle(context.Background(), Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment"}`)},
+		}})
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Warnings).To(ConsistOf(ContainSubstring("spec.replicas: Invalid value: 0: must be positive")))
+	})
+})
@@ -27,6 +27,7 @@ import (
 	"gomodules.xyz/jsonpatch/v2"
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/json"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/klog/v2"
@@ -62,8 +63,28 @@ type Response struct {
 	// AdmissionResponse is the raw admission response.
 	// The Patch field in it will be overwritten by the listed patches.
 	admissionv1.AdmissionResponse
+
+	// overridesDenial marks a response of a ResponseInterceptor as intentionally
+	// allowing a request the webhook denied, see OverridingDenial.
+	overridesDenial bool
+}
+
+// OverridingDenial marks resp, returned by a ResponseInterceptor, as intentionally allowing
+// a request the webhook denied. Without it, such a request stays denied.
+func OverridingDenial(resp Response) Response {
+	resp.overridesDenial = true
+	return resp
 }
 
+// ResponseInterceptor inspects and possibly modifies the response of a webhook to req before
+// it is sent, e.g. to attach audit annotations to every response or to enforce that denials
+// link to documentation.
+//
+// A ResponseInterceptor can deny an allowed request, but it can not accidentally allow a denied
+// one: if it returns an allowed response for a request the webhook denied, the request stays
+// denied with the original result, unless the response is wrapped in OverridingDenial.
+type ResponseInterceptor func(req Request, resp Response) Response
+
 // Complete populates any fields that are yet to be set in
 // the underlying AdmissionResponse, It mutates the response.
 func (r *Response) Complete(req Request) error {
@@ -136,6 +157,23 @@ type Webhook struct {
 	// outside the context of requests.
 	LogConstructor func(base logr.Logger, req *Request) logr.Logger
 
+	// Encoder, if set, encodes the AdmissionReviews written in response to requests instead of
+	// encoding/json, e.g. to use a faster serializer for large responses. It must encode them as
+	// JSON, as that is the content type the API server expects.
+	Encoder runtime.Encoder
+
+	// ResponseInterceptor, if set, is called with every response of the Handler before it is
+	// sent. webhook.Server sets it to the ResponseInterceptor of its Options when registering
+	// the webhook, chained after the one set here.
+	ResponseInterceptor ResponseInterceptor
+
+	// MaxResponseBytes, if positive, is the maximum size of the encoded AdmissionReviews written
+	// in response to requests. Larger responses, e.g. with an enormous patch produced by a buggy
+	// defaulter, are logged and replaced with an error with code 500, protecting the API server
+	// from oversized admission responses. webhook.Server sets it to the MaxResponseBytes of its
+	// Options when registering the webhook, unless it is set here.
+	MaxResponseBytes int64
+
 	setupLogOnce sync.Once
 	log          logr.Logger
 }
@@ -146,6 +184,12 @@ func (wh *Webhook) WithRecoverPanic(recoverPanic bool) *Webhook {
 	return wh
 }
 
+// WithEncoder sets the Encoder the responses of the webhook are encoded with.
+func (wh *Webhook) WithEncoder(encoder runtime.Encoder) *Webhook {
+	wh.Encoder = encoder
+	return wh
+}
+
 // Handle processes AdmissionRequest.
 // If the webhook is mutating type, it delegates the AdmissionRequest to each handler and merge the patches.
 // If the webhook is validating type, it delegates the AdmissionRequest to each handler and
@@ -167,6 +211,9 @@ func (wh *Webhook) Handle(ctx context.Context, req Request) (response Response)
 	ctx = logf.IntoContext(ctx, reqLog)
 
 	resp := wh.Handler.Handle(ctx, req)
+	if wh.ResponseInterceptor != nil {
+		resp = wh.interceptResponse(reqLog, req, resp)
+	}
 	if err := resp.Complete(req); err != nil {
 		reqLog.Error(err, "unable to encode response")
 		return Errored(http.StatusInternalServerError, errUnableToEncodeResponse)
@@ -175,6 +222,32 @@ func (wh *Webhook) Handle(ctx context.Context, req Request) (response Response)
 	return resp
 }
 
+// interceptResponse applies the ResponseInterceptor to resp, keeping a denied request
+// denied unless the ResponseInterceptor explicitly allows it.
+func (wh *Webhook) interceptResponse(log logr.Logger, req Request, resp Response) Response {
+	intercepted := wh.ResponseInterceptor(req, resp)
+	if !resp.Allowed && intercepted.Allowed && !intercepted.overridesDenial {
+		log.Error(nil, "ResponseInterceptor allowed a denied request without OverridingDenial, keeping it denied")
+		intercepted.Allowed = false
+		intercepted.Result = resp.Result
+	}
+	intercepted.overridesDenial = false
+	return intercepted
+}
+
+// ChainResponseInterceptors returns a ResponseInterceptor that applies the given ones in order,
+// skipping nil ones.
+func ChainResponseInterceptors(interceptors ...ResponseInterceptor) ResponseInterceptor {
+	return func(req Request, resp Response) Response {
+		for _, intercept := range interceptors {
+			if intercept != nil {
+				resp = intercept(req, resp)
+			}
+		}
+		return resp
+	}
+}
+
 // getLogger constructs a logger from the injected log and LogConstructor.
 func (wh *Webhook) getLogger(req *Request) logr.Logger {
 	wh.setupLogOnce.Do(func() {
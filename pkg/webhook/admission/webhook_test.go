@@ -198,6 +198,72 @@ var _ = Describe("Admission Webhooks", func() {
 		Eventually(logBuffer).Should(gbytes.Say(`"msg":"Received request","operation":"CREATE","requestID":"test123"}`))
 	})
 
+	Describe("ResponseInterceptor", func() {
+		denyHandler := func() *Webhook {
+			return &Webhook{
+				Handler: HandlerFunc(func(ctx context.Context, req Request) Response {
+					return Denied("not allowed")
+				}),
+			}
+		}
+		annotate := func(req Request, resp Response) Response {
+			resp.AuditAnnotations = map[string]string{"policy-version": "v1"}
+			return resp
+		}
+
+		It("should intercept every response", func() {
+			for _, webhook := range []*Webhook{allowHandler(), denyHandler()} {
+				webhook.ResponseInterceptor = annotate
+				resp := webhook.Handle(context.Background(), Request{})
+				Expect(resp.AuditAnnotations).To(HaveKeyWithValue("policy-version", "v1"))
+			}
+		})
+
+		It("should allow denying an allowed request", func() {
+			webhook := allowHandler()
+			webhook.ResponseInterceptor = func(req Request, resp Response) Response {
+				return Denied("denied by the interceptor")
+			}
+			resp := webhook.Handle(context.Background(), Request{})
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Message).To(Equal("denied by the interceptor"))
+		})
+
+		It("should keep a denied request denied when allowed without OverridingDenial", func() {
+			webhook := denyHandler()
+			webhook.ResponseInterceptor = func(req Request, resp Response) Response {
+				resp = annotate(req, resp)
+				resp.Allowed = true
+				resp.Result = nil
+				return resp
+			}
+			resp := webhook.Handle(context.Background(), Request{})
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Message).To(Equal("not allowed"))
+			Expect(resp.AuditAnnotations).To(HaveKeyWithValue("policy-version", "v1"))
+		})
+
+		It("should allow a denied request with OverridingDenial", func() {
+			webhook := denyHandler()
+			webhook.ResponseInterceptor = func(req Request, resp Response) Response {
+				return OverridingDenial(Allowed("allowed by the interceptor"))
+			}
+			resp := webhook.Handle(context.Background(), Request{})
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Result.Message).To(Equal("allowed by the interceptor"))
+		})
+
+		It("should apply chained interceptors in order", func() {
+			webhook := allowHandler()
+			webhook.ResponseInterceptor = ChainResponseInterceptors(annotate, nil, func(req Request, resp Response) Response {
+				resp.AuditAnnotations["policy-version"] += "-chained"
+				return resp
+			})
+			resp := webhook.Handle(context.Background(), Request{})
+			Expect(resp.AuditAnnotations).To(HaveKeyWithValue("policy-version", "v1-chained"))
+		})
+	})
+
 	Describe("panic recovery", func() {
 		It("should recover panic if RecoverPanic is true", func() {
 			panicHandler := func() *Webhook {
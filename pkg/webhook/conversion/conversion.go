@@ -25,6 +25,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync"
 
 	apix "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -47,6 +49,11 @@ func NewWebhookHandler(scheme *runtime.Scheme) http.Handler {
 type webhook struct {
 	scheme  *runtime.Scheme
 	decoder *Decoder
+
+	// hubGraphs caches the hub graph of each group-kind, keyed by
+	// schema.GroupKind. The graph only depends on the scheme, so it is
+	// built once on first use.
+	hubGraphs sync.Map
 }
 
 // ensure Webhook implements http.Handler
@@ -92,6 +99,7 @@ func (wh *webhook) handleConvertRequest(req *apix.ConversionRequest) (*apix.Conv
 		return nil, fmt.Errorf("conversion request is nil")
 	}
 	var objects []runtime.RawExtension
+	var warnings []string
 
 	for _, obj := range req.Objects {
 		src, gvk, err := wh.decoder.Decode(obj.Raw)
@@ -102,100 +110,125 @@ func (wh *webhook) handleConvertRequest(req *apix.ConversionRequest) (*apix.Conv
 		if err != nil {
 			return nil, err
 		}
-		err = wh.convertObject(src, dst)
+		objWarnings, err := wh.convertObject(src, dst)
 		if err != nil {
 			return nil, err
 		}
+		if len(objWarnings) > 0 {
+			log.Info("conversion produced warnings", "request", req.UID, "kind", gvk.Kind,
+				"desiredAPIVersion", req.DesiredAPIVersion, "warnings", objWarnings)
+			warnings = append(warnings, objWarnings...)
+		}
 		objects = append(objects, runtime.RawExtension{Object: dst})
 	}
 	return &apix.ConversionResponse{
 		UID:              req.UID,
 		ConvertedObjects: objects,
 		Result: metav1.Status{
-			Status: metav1.StatusSuccess,
+			Status:  metav1.StatusSuccess,
+			Message: strings.Join(warnings, "; "),
 		},
 	}, nil
 }
 
-// convertObject will convert given a src object to dst object.
-// Note(droot): couldn't find a way to reduce the cyclomatic complexity under 10
-// without compromising readability, so disabling gocyclo linter
-func (wh *webhook) convertObject(src, dst runtime.Object) error {
+// convertObject will convert given a src object to dst object, returning the
+// warnings of the conversion.
+func (wh *webhook) convertObject(src, dst runtime.Object) ([]string, error) {
 	srcGVK := src.GetObjectKind().GroupVersionKind()
 	dstGVK := dst.GetObjectKind().GroupVersionKind()
 
 	if srcGVK.GroupKind() != dstGVK.GroupKind() {
-		return fmt.Errorf("src %T and dst %T does not belong to same API Group", src, dst)
+		return nil, fmt.Errorf("src %T and dst %T does not belong to same API Group", src, dst)
 	}
 
 	if srcGVK == dstGVK {
-		return fmt.Errorf("conversion is not allowed between same type %T", src)
+		return nil, fmt.Errorf("conversion is not allowed between same type %T", src)
 	}
 
-	srcIsHub, dstIsHub := isHub(src), isHub(dst)
-	srcIsConvertible, dstIsConvertible := isConvertible(src), isConvertible(dst)
-
-	switch {
-	case srcIsHub && dstIsConvertible:
-		return dst.(conversion.Convertible).ConvertFrom(src.(conversion.Hub))
-	case dstIsHub && srcIsConvertible:
-		return src.(conversion.Convertible).ConvertTo(dst.(conversion.Hub))
-	case srcIsConvertible && dstIsConvertible:
-		return wh.convertViaHub(src.(conversion.Convertible), dst.(conversion.Convertible))
-	default:
-		return fmt.Errorf("%T is not convertible to %T", src, dst)
+	if (!isHub(src) && !isConvertible(src)) || (!isHub(dst) && !isConvertible(dst)) {
+		return nil, fmt.Errorf("%T is not convertible to %T", src, dst)
 	}
+
+	return wh.convertViaHubs(src, dst)
 }
 
-func (wh *webhook) convertViaHub(src, dst conversion.Convertible) error {
-	hub, err := wh.getHub(src)
+// convertViaHubs converts src to dst by walking the path connecting both
+// versions in the hub graph of their group-kind. With a single hub this is
+// either a direct conversion to/from the hub or src -> hub -> dst.
+func (wh *webhook) convertViaHubs(src, dst runtime.Object) ([]string, error) {
+	graph, err := wh.getHubGraph(src)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if hub == nil {
-		return fmt.Errorf("%s does not have any Hub defined", src)
+	if len(graph.hubs) == 0 {
+		return nil, fmt.Errorf("%s does not have any Hub defined", src)
 	}
 
-	err = src.ConvertTo(hub)
-	if err != nil {
-		return fmt.Errorf("%T failed to convert to hub version %T : %w", src, hub, err)
+	path := graph.path(src.GetObjectKind().GroupVersionKind(), dst.GetObjectKind().GroupVersionKind())
+	if path == nil {
+		return nil, fmt.Errorf("no conversion path found from %T to %T", src, dst)
 	}
 
-	err = dst.ConvertFrom(hub)
-	if err != nil {
-		return fmt.Errorf("%T failed to convert from hub version %T : %w", dst, hub, err)
+	var warnings []string
+	cur := src
+	for i := 1; i < len(path); i++ {
+		next := dst
+		if i < len(path)-1 {
+			next, err = wh.scheme.New(path[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to allocate an instance for gvk %v: %w", path[i], err)
+			}
+		}
+
+		var stepWarnings []string
+		if hub, ok := next.(conversion.Hub); ok {
+			if stepWarnings, err = convertTo(cur.(conversion.Convertible), hub); err != nil {
+				return nil, fmt.Errorf("%T failed to convert to hub version %T : %w", cur, hub, err)
+			}
+		} else {
+			hub := cur.(conversion.Hub)
+			if stepWarnings, err = convertFrom(next.(conversion.Convertible), hub); err != nil {
+				return nil, fmt.Errorf("%T failed to convert from hub version %T : %w", next, hub, err)
+			}
+		}
+		warnings = append(warnings, stepWarnings...)
+		cur = next
 	}
 
-	return nil
+	return warnings, nil
 }
 
-// getHub returns an instance of the Hub for passed-in object's group/kind.
-func (wh *webhook) getHub(obj runtime.Object) (conversion.Hub, error) {
-	gvks, err := objectGVKs(wh.scheme, obj)
-	if err != nil {
-		return nil, err
+// convertTo converts spoke to hub, returning the warnings of a conversion.WarningConvertible.
+func convertTo(spoke conversion.Convertible, hub conversion.Hub) ([]string, error) {
+	if spoke, ok := spoke.(conversion.WarningConvertible); ok {
+		return spoke.ConvertToWithWarnings(hub)
 	}
-	if len(gvks) == 0 {
-		return nil, fmt.Errorf("error retrieving gvks for object : %v", obj)
+	return nil, spoke.ConvertTo(hub)
+}
+
+// convertFrom converts hub to spoke, returning the warnings of a conversion.WarningConvertible.
+func convertFrom(spoke conversion.Convertible, hub conversion.Hub) ([]string, error) {
+	if spoke, ok := spoke.(conversion.WarningConvertible); ok {
+		return spoke.ConvertFromWithWarnings(hub)
+	}
+	return nil, spoke.ConvertFrom(hub)
+}
+
+// getHubGraph returns the hub graph for the group-kind of the passed-in object,
+// building and caching it on first use.
+func (wh *webhook) getHubGraph(obj runtime.Object) (*hubGraph, error) {
+	gk := obj.GetObjectKind().GroupVersionKind().GroupKind()
+	if graph, ok := wh.hubGraphs.Load(gk); ok {
+		return graph.(*hubGraph), nil
 	}
 
-	var hub conversion.Hub
-	var hubFoundAlready bool
-	for _, gvk := range gvks {
-		instance, err := wh.scheme.New(gvk)
-		if err != nil {
-			return nil, fmt.Errorf("failed to allocate an instance for gvk %v: %w", gvk, err)
-		}
-		if val, isHub := instance.(conversion.Hub); isHub {
-			if hubFoundAlready {
-				return nil, fmt.Errorf("multiple hub version defined for %T", obj)
-			}
-			hubFoundAlready = true
-			hub = val
-		}
+	graph, err := newHubGraph(wh.scheme, obj)
+	if err != nil {
+		return nil, err
 	}
-	return hub, nil
+	wh.hubGraphs.Store(gk, graph)
+	return graph, nil
 }
 
 // allocateDstObject returns an instance for a given GVK.
@@ -261,6 +294,19 @@ func IsConvertible(scheme *runtime.Scheme, obj runtime.Object) (bool, error) {
 		return false, nil
 	}
 
+	if len(hubs) > 0 && len(nonSpokes) == 0 && (len(hubs) > 1 || declaresHubs(spokes)) {
+		// spokes declaring their hubs (required with multiple hubs) must
+		// form an acyclic graph that connects every version.
+		graph, err := newHubGraph(scheme, obj)
+		if err != nil {
+			return false, err
+		}
+		if err := graph.connected(); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
 	if len(hubs) == 1 && len(nonSpokes) == 0 { // convertible
 		return true, nil
 	}
@@ -332,6 +378,16 @@ func isConvertible(obj runtime.Object) bool {
 	return yes
 }
 
+// declaresHubs determines if any of the passed-in spokes declares its hubs.
+func declaresHubs(spokes []runtime.Object) bool {
+	for _, spoke := range spokes {
+		if _, ok := spoke.(conversion.MultiHubConvertible); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // helper to construct error response.
 func errored(err error) *apix.ConversionResponse {
 	return &apix.ConversionResponse{
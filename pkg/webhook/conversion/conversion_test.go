@@ -34,9 +34,15 @@ import (
 	kscheme "k8s.io/client-go/kubernetes/scheme"
 
 	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+	multihubv1 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/multihub/v1"
+	multihubv2 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/multihub/v2"
+	multihubv3 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/multihub/v3"
+	multihubv4 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/multihub/v4"
+	multihubv5 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/multihub/v5"
 	jobsv1 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/v1"
 	jobsv2 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/v2"
 	jobsv3 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/v3"
+	jobsv4 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/v4"
 )
 
 var _ = Describe("Conversion Webhook", func() {
@@ -56,6 +62,7 @@ var _ = Describe("Conversion Webhook", func() {
 		Expect(jobsv1.AddToScheme(scheme)).To(Succeed())
 		Expect(jobsv2.AddToScheme(scheme)).To(Succeed())
 		Expect(jobsv3.AddToScheme(scheme)).To(Succeed())
+		Expect(jobsv4.AddToScheme(scheme)).To(Succeed())
 
 		decoder = conversion.NewDecoder(scheme)
 		wh = conversion.NewWebhookHandler(scheme)
@@ -224,6 +231,65 @@ var _ = Describe("Conversion Webhook", func() {
 		Expect(got).To(Equal(expected))
 	})
 
+	It("should report the warnings of a converter in the response", func() {
+		makeV4Obj := func(name string, priority int) *jobsv4.ExternalJob {
+			return &jobsv4.ExternalJob{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "ExternalJob",
+					APIVersion: "jobs.testprojects.kb.io/v4",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      name,
+				},
+				Spec: jobsv4.ExternalJobSpec{
+					DeferredAt: "every 2 seconds",
+					Priority:   priority,
+				},
+			}
+		}
+
+		convReq := &apix.ConversionReview{
+			TypeMeta: metav1.TypeMeta{},
+			Request: &apix.ConversionRequest{
+				DesiredAPIVersion: "jobs.testprojects.kb.io/v1",
+				Objects: []runtime.RawExtension{
+					{Object: makeV4Obj("obj-1", 3)},
+					{Object: makeV4Obj("obj-2", 0)},
+					{Object: makeV4Obj("obj-3", 5)},
+				},
+			},
+		}
+
+		convReview := doRequest(convReq)
+
+		Expect(convReview.Response.ConvertedObjects).To(HaveLen(3))
+		Expect(convReview.Response.Result.Status).To(Equal(metav1.StatusSuccess))
+		Expect(convReview.Response.Result.Message).To(Equal(
+			"spec.priority 3 was dropped in conversion; spec.priority 5 was dropped in conversion"))
+		got, _, err := decoder.Decode(convReview.Response.ConvertedObjects[0].Raw)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got.(*jobsv1.ExternalJob).Spec.RunAt).To(Equal("every 2 seconds"))
+	})
+
+	It("should not report warnings for converters without any", func() {
+		convReq := &apix.ConversionReview{
+			TypeMeta: metav1.TypeMeta{},
+			Request: &apix.ConversionRequest{
+				DesiredAPIVersion: "jobs.testprojects.kb.io/v4",
+				Objects: []runtime.RawExtension{
+					{Object: makeV1Obj()},
+				},
+			},
+		}
+
+		convReview := doRequest(convReq)
+
+		Expect(convReview.Response.ConvertedObjects).To(HaveLen(1))
+		Expect(convReview.Response.Result.Status).To(Equal(metav1.StatusSuccess))
+		Expect(convReview.Response.Result.Message).To(BeEmpty())
+	})
+
 	It("should return error when dest/src objects belong to different API groups", func() {
 		v1Obj := makeV1Obj()
 
@@ -296,6 +362,114 @@ var _ = Describe("Conversion Webhook", func() {
 		Expect(convReview.Response.ConvertedObjects).To(BeEmpty())
 	})
 
+	Context("with multiple hubs", func() {
+		BeforeEach(func() {
+			Expect(multihubv1.AddToScheme(scheme)).To(Succeed())
+			Expect(multihubv2.AddToScheme(scheme)).To(Succeed())
+			Expect(multihubv3.AddToScheme(scheme)).To(Succeed())
+		})
+
+		convert := func(obj runtime.Object, desiredAPIVersion string) runtime.Object {
+			convReq := &apix.ConversionReview{
+				TypeMeta: metav1.TypeMeta{},
+				Request: &apix.ConversionRequest{
+					DesiredAPIVersion: desiredAPIVersion,
+					Objects: []runtime.RawExtension{
+						{
+							Object: obj,
+						},
+					},
+				},
+			}
+
+			convReview := doRequest(convReq)
+
+			Expect(convReview.Response.Result.Status).To(Equal(metav1.StatusSuccess), convReview.Response.Result.Message)
+			Expect(convReview.Response.ConvertedObjects).To(HaveLen(1))
+			got, _, err := decoder.Decode(convReview.Response.ConvertedObjects[0].Raw)
+			Expect(err).NotTo(HaveOccurred())
+			return got
+		}
+
+		objectMeta := metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "obj-1",
+		}
+
+		It("should convert a spoke to either hub", func() {
+			v2Obj := &multihubv2.ExternalJob{
+				TypeMeta:   metav1.TypeMeta{Kind: "ExternalJob", APIVersion: "multihub.testprojects.kb.io/v2"},
+				ObjectMeta: objectMeta,
+				Spec:       multihubv2.ExternalJobSpec{ScheduleAt: "every 2 seconds"},
+			}
+
+			Expect(convert(v2Obj, "multihub.testprojects.kb.io/v1")).To(Equal(&multihubv1.ExternalJob{
+				TypeMeta:   metav1.TypeMeta{Kind: "ExternalJob", APIVersion: "multihub.testprojects.kb.io/v1"},
+				ObjectMeta: objectMeta,
+				Spec:       multihubv1.ExternalJobSpec{RunAt: "every 2 seconds"},
+			}))
+			Expect(convert(v2Obj, "multihub.testprojects.kb.io/v3")).To(Equal(&multihubv3.ExternalJob{
+				TypeMeta:   metav1.TypeMeta{Kind: "ExternalJob", APIVersion: "multihub.testprojects.kb.io/v3"},
+				ObjectMeta: objectMeta,
+				Spec:       multihubv3.ExternalJobSpec{DeferredAt: "every 2 seconds"},
+			}))
+		})
+
+		It("should convert a hub to a spoke", func() {
+			v3Obj := &multihubv3.ExternalJob{
+				TypeMeta:   metav1.TypeMeta{Kind: "ExternalJob", APIVersion: "multihub.testprojects.kb.io/v3"},
+				ObjectMeta: objectMeta,
+				Spec:       multihubv3.ExternalJobSpec{DeferredAt: "every 2 seconds"},
+			}
+
+			Expect(convert(v3Obj, "multihub.testprojects.kb.io/v2")).To(Equal(&multihubv2.ExternalJob{
+				TypeMeta:   metav1.TypeMeta{Kind: "ExternalJob", APIVersion: "multihub.testprojects.kb.io/v2"},
+				ObjectMeta: objectMeta,
+				Spec:       multihubv2.ExternalJobSpec{ScheduleAt: "every 2 seconds"},
+			}))
+		})
+
+		It("should convert a hub to the other hub through the spoke connecting them", func() {
+			v1Obj := &multihubv1.ExternalJob{
+				TypeMeta:   metav1.TypeMeta{Kind: "ExternalJob", APIVersion: "multihub.testprojects.kb.io/v1"},
+				ObjectMeta: objectMeta,
+				Spec:       multihubv1.ExternalJobSpec{RunAt: "every 2 seconds"},
+			}
+
+			Expect(convert(v1Obj, "multihub.testprojects.kb.io/v3")).To(Equal(&multihubv3.ExternalJob{
+				TypeMeta:   metav1.TypeMeta{Kind: "ExternalJob", APIVersion: "multihub.testprojects.kb.io/v3"},
+				ObjectMeta: objectMeta,
+				Spec:       multihubv3.ExternalJobSpec{DeferredAt: "every 2 seconds"},
+			}))
+		})
+
+		It("should return error when the hubs form a cycle", func() {
+			Expect(multihubv4.AddToScheme(scheme)).To(Succeed())
+
+			v1Obj := &multihubv1.ExternalJob{
+				TypeMeta:   metav1.TypeMeta{Kind: "ExternalJob", APIVersion: "multihub.testprojects.kb.io/v1"},
+				ObjectMeta: objectMeta,
+				Spec:       multihubv1.ExternalJobSpec{RunAt: "every 2 seconds"},
+			}
+
+			convReq := &apix.ConversionReview{
+				TypeMeta: metav1.TypeMeta{},
+				Request: &apix.ConversionRequest{
+					DesiredAPIVersion: "multihub.testprojects.kb.io/v3",
+					Objects: []runtime.RawExtension{
+						{
+							Object: v1Obj,
+						},
+					},
+				},
+			}
+
+			convReview := doRequest(convReq)
+			Expect(convReview.Response.Result.Status).To(Equal("Failure"))
+			Expect(convReview.Response.Result.Message).To(ContainSubstring("form a cycle"))
+			Expect(convReview.Response.ConvertedObjects).To(BeEmpty())
+		})
+	})
 })
 
 var _ = Describe("IsConvertible", func() {
@@ -357,4 +531,36 @@ var _ = Describe("IsConvertible", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ok).ToNot(BeTrue())
 	})
+
+	It("should return true for types with multiple hubs connected by spokes", func() {
+		Expect(multihubv1.AddToScheme(scheme)).To(Succeed())
+		Expect(multihubv2.AddToScheme(scheme)).To(Succeed())
+		Expect(multihubv3.AddToScheme(scheme)).To(Succeed())
+
+		ok, err := conversion.IsConvertible(scheme, &multihubv1.ExternalJob{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should return an error for types whose hubs form a cycle", func() {
+		Expect(multihubv1.AddToScheme(scheme)).To(Succeed())
+		Expect(multihubv2.AddToScheme(scheme)).To(Succeed())
+		Expect(multihubv3.AddToScheme(scheme)).To(Succeed())
+		Expect(multihubv4.AddToScheme(scheme)).To(Succeed())
+
+		ok, err := conversion.IsConvertible(scheme, &multihubv1.ExternalJob{})
+		Expect(err).To(MatchError(ContainSubstring("form a cycle")))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should return an error for types with multiple hubs and a spoke that doesn't declare its hubs", func() {
+		Expect(multihubv1.AddToScheme(scheme)).To(Succeed())
+		Expect(multihubv2.AddToScheme(scheme)).To(Succeed())
+		Expect(multihubv3.AddToScheme(scheme)).To(Succeed())
+		Expect(multihubv5.AddToScheme(scheme)).To(Succeed())
+
+		ok, err := conversion.IsConvertible(scheme, &multihubv1.ExternalJob{})
+		Expect(err).To(MatchError(ContainSubstring("must implement MultiHubConvertible")))
+		Expect(ok).To(BeFalse())
+	})
 })
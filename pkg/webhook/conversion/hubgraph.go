@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// hubGraph describes how the versions of a single group-kind are connected for
+// conversion: every spoke has an edge to each hub it converts to/from.
+// newHubGraph rejects cycles, so the graph is a forest.
+type hubGraph struct {
+	groupKind schema.GroupKind
+	versions  []schema.GroupVersionKind
+	hubs      []schema.GroupVersionKind
+	edges     map[schema.GroupVersionKind][]schema.GroupVersionKind
+}
+
+// newHubGraph builds the hub graph for the group-kind of the given object. It
+// returns an error if the spokes declare hubs that form a cycle.
+func newHubGraph(scheme *runtime.Scheme, obj runtime.Object) (*hubGraph, error) {
+	gvks, err := objectGVKs(scheme, obj)
+	if err != nil {
+		return nil, err
+	}
+	if len(gvks) == 0 {
+		return nil, fmt.Errorf("error retrieving gvks for object : %v", obj)
+	}
+	sort.Slice(gvks, func(i, j int) bool { return gvks[i].Version < gvks[j].Version })
+
+	g := &hubGraph{
+		groupKind: gvks[0].GroupKind(),
+		versions:  gvks,
+		edges:     map[schema.GroupVersionKind][]schema.GroupVersionKind{},
+	}
+
+	spokes := map[schema.GroupVersionKind]conversion.Convertible{}
+	for _, gvk := range gvks {
+		instance, err := scheme.New(gvk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to allocate an instance for gvk %v: %w", gvk, err)
+		}
+		if isHub(instance) {
+			g.hubs = append(g.hubs, gvk)
+			continue
+		}
+		if spoke, ok := instance.(conversion.Convertible); ok {
+			spokes[gvk] = spoke
+		}
+	}
+
+	// roots is a union-find over the versions, used to detect cycles while
+	// adding edges.
+	roots := map[schema.GroupVersionKind]schema.GroupVersionKind{}
+	var root func(gvk schema.GroupVersionKind) schema.GroupVersionKind
+	root = func(gvk schema.GroupVersionKind) schema.GroupVersionKind {
+		parent, ok := roots[gvk]
+		if !ok || parent == gvk {
+			return gvk
+		}
+		r := root(parent)
+		roots[gvk] = r
+		return r
+	}
+
+	for _, gvk := range gvks {
+		spoke, ok := spokes[gvk]
+		if !ok {
+			continue
+		}
+		hubs, err := g.declaredHubs(scheme, spoke)
+		if err != nil {
+			return nil, err
+		}
+		for _, hub := range hubs {
+			if root(gvk) == root(hub) {
+				return nil, fmt.Errorf("conversion hubs for group-kind %s form a cycle: %s is already connected to hub %s through another hub",
+					g.groupKind, gvk.Version, hub.Version)
+			}
+			roots[root(gvk)] = root(hub)
+			g.edges[gvk] = append(g.edges[gvk], hub)
+			g.edges[hub] = append(g.edges[hub], gvk)
+		}
+	}
+
+	return g, nil
+}
+
+// declaredHubs returns the hubs the given spoke converts to/from. Spokes that
+// don't implement conversion.MultiHubConvertible convert to/from the single hub
+// of their group-kind.
+func (g *hubGraph) declaredHubs(scheme *runtime.Scheme, spoke conversion.Convertible) ([]schema.GroupVersionKind, error) {
+	multi, ok := spoke.(conversion.MultiHubConvertible)
+	if !ok {
+		if len(g.hubs) > 1 {
+			return nil, fmt.Errorf("multiple hubs defined for group-kind %s, %T must implement MultiHubConvertible", g.groupKind, spoke)
+		}
+		return g.hubs, nil
+	}
+
+	var hubs []schema.GroupVersionKind
+	for _, hub := range multi.Hubs() {
+		gvks, _, err := scheme.ObjectKinds(hub)
+		if err != nil {
+			return nil, err
+		}
+		if len(gvks) != 1 || gvks[0].GroupKind() != g.groupKind {
+			return nil, fmt.Errorf("%T declares hub %T which is not a version of group-kind %s", spoke, hub, g.groupKind)
+		}
+		hubs = append(hubs, gvks[0])
+	}
+	return hubs, nil
+}
+
+// path returns the versions to convert through to get from src to dst,
+// including both ends, or nil if they are not connected. As the graph is
+// acyclic, a breadth-first search finds the only path between them.
+func (g *hubGraph) path(src, dst schema.GroupVersionKind) []schema.GroupVersionKind {
+	prev := map[schema.GroupVersionKind]schema.GroupVersionKind{src: src}
+	queue := []schema.GroupVersionKind{src}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur == dst {
+			var path []schema.GroupVersionKind
+			for ; cur != src; cur = prev[cur] {
+				path = append([]schema.GroupVersionKind{cur}, path...)
+			}
+			return append([]schema.GroupVersionKind{src}, path...)
+		}
+		for _, next := range g.edges[cur] {
+			if _, seen := prev[next]; !seen {
+				prev[next] = cur
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+// connected returns an error if some version of the group-kind can't be
+// converted to every other version.
+func (g *hubGraph) connected() error {
+	for _, gvk := range g.versions[1:] {
+		if g.path(g.versions[0], gvk) == nil {
+			return fmt.Errorf("versions %s and %s of group-kind %s are not connected through a hub",
+				g.versions[0].Version, gvk.Version, g.groupKind)
+		}
+	}
+	return nil
+}
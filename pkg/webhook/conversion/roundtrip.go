@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	fuzz "github.com/google/gofuzz"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// roundTripIterations is the number of fuzzed objects RoundTripTest converts per spoke version.
+const roundTripIterations = 100
+
+// RoundTripTest fuzzes objects of each of the given spoke versions, and fails t if converting any of
+// them to the hub version and back doesn't yield the same object, e.g. because the hub has no field
+// for a field of the spoke. The failure names the first field that differs after the round trip,
+// and the seed of the fuzzer to reproduce it.
+//
+// Objects are fuzzed with the fuzzer of apimachinery, so that their metadata is valid. The spokes must
+// implement conversion.Convertible and the hub conversion.Hub, all of them registered with scheme.
+func RoundTripTest(t testing.TB, scheme *runtime.Scheme, hubGVK schema.GroupVersionKind, spokeGVKs ...schema.GroupVersionKind) {
+	t.Helper()
+
+	seed := time.Now().UnixNano()
+	f := fuzzer.FuzzerFor(metafuzzer.Funcs, rand.NewSource(seed), serializer.NewCodecFactory(scheme))
+	for _, spokeGVK := range spokeGVKs {
+		for i := 0; i < roundTripIterations; i++ {
+			if err := roundTrip(scheme, f, hubGVK, spokeGVK); err != nil {
+				t.Errorf("round trip of %s through %s failed (fuzzer seed %d): %v", spokeGVK, hubGVK, seed, err)
+				break
+			}
+		}
+	}
+}
+
+// roundTrip converts a fuzzed object of the spoke version to the hub version and back, and returns
+// an error naming the first field that differs from the fuzzed object.
+func roundTrip(scheme *runtime.Scheme, f *fuzz.Fuzzer, hubGVK, spokeGVK schema.GroupVersionKind) error {
+	original, err := newConvertible(scheme, spokeGVK)
+	if err != nil {
+		return err
+	}
+	f.Fuzz(original)
+	original.GetObjectKind().SetGroupVersionKind(spokeGVK)
+
+	obj, err := scheme.New(hubGVK)
+	if err != nil {
+		return err
+	}
+	hub, ok := obj.(conversion.Hub)
+	if !ok {
+		return fmt.Errorf("%s is not a conversion.Hub", hubGVK)
+	}
+	if _, err := convertTo(original.DeepCopyObject().(conversion.Convertible), hub); err != nil {
+		return fmt.Errorf("failed to convert to the hub: %w", err)
+	}
+
+	converted, err := newConvertible(scheme, spokeGVK)
+	if err != nil {
+		return err
+	}
+	if _, err := convertFrom(converted, hub); err != nil {
+		return fmt.Errorf("failed to convert from the hub: %w", err)
+	}
+	converted.GetObjectKind().SetGroupVersionKind(spokeGVK)
+
+	if field := firstDiff("", reflect.ValueOf(original), reflect.ValueOf(converted)); field != "" {
+		return fmt.Errorf("field %s differs after the round trip", field)
+	}
+	return nil
+}
+
+func newConvertible(scheme *runtime.Scheme, gvk schema.GroupVersionKind) (conversion.Convertible, error) {
+	obj, err := scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	convertible, ok := obj.(conversion.Convertible)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a conversion.Convertible", gvk)
+	}
+	return convertible, nil
+}
+
+// firstDiff returns the JSON path of the first field that differs between a and b, e.g. ".spec.priority",
+// or an empty string if they are semantically equal. Structs with unexported fields, like
+// resource.Quantity, are compared as a whole.
+func firstDiff(path string, a, b reflect.Value) string {
+	if equality.Semantic.DeepEqual(a.Interface(), b.Interface()) {
+		return ""
+	}
+	if diff := firstNestedDiff(path, a, b); diff != "" {
+		return diff
+	}
+	if path == "" {
+		return "."
+	}
+	return path
+}
+
+// firstNestedDiff returns the path of the first field nested in a and b that differs between them,
+// or an empty string if they only differ as a whole.
+func firstNestedDiff(path string, a, b reflect.Value) string {
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() || a.Elem().Type() != b.Elem().Type() {
+			return ""
+		}
+		return firstDiff(path, a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !a.Type().Field(i).IsExported() {
+				return ""
+			}
+		}
+		for i := 0; i < a.NumField(); i++ {
+			fieldPath := path
+			if name := jsonFieldName(a.Type().Field(i)); name != "" {
+				fieldPath += "." + name
+			}
+			if diff := firstDiff(fieldPath, a.Field(i), b.Field(i)); diff != "" {
+				return diff
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return ""
+		}
+		for i := 0; i < a.Len(); i++ {
+			if diff := firstDiff(fmt.Sprintf("%s[%d]", path, i), a.Index(i), b.Index(i)); diff != "" {
+				return diff
+			}
+		}
+	case reflect.Map:
+		keys := a.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, key := range keys {
+			keyPath := fmt.Sprintf("%s[%v]", path, key)
+			if !b.MapIndex(key).IsValid() {
+				return keyPath
+			}
+			if diff := firstDiff(keyPath, a.MapIndex(key), b.MapIndex(key)); diff != "" {
+				return diff
+			}
+		}
+	}
+	return ""
+}
+
+// jsonFieldName returns the name of the given field in the JSON representation of its struct, or an
+// empty string if its fields are inlined into the struct.
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch {
+	case name == "" && field.Anonymous:
+		return ""
+	case name == "" || name == "-":
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+	jobsv1 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/v1"
+	jobsv2 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/v2"
+	jobsv3 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/v3"
+	jobsv4 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/v4"
+)
+
+// recordingT records the errors of a test instead of failing it.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+var _ = Describe("RoundTripTest", func() {
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(jobsv1.AddToScheme(scheme)).To(Succeed())
+		Expect(jobsv2.AddToScheme(scheme)).To(Succeed())
+		Expect(jobsv3.AddToScheme(scheme)).To(Succeed())
+		Expect(jobsv4.AddToScheme(scheme)).To(Succeed())
+	})
+
+	It("should pass for lossless conversions", func() {
+		t := &recordingT{}
+		conversion.RoundTripTest(t, scheme, jobsv2.GroupVersion.WithKind("ExternalJob"),
+			jobsv1.GroupVersion.WithKind("ExternalJob"),
+			jobsv3.GroupVersion.WithKind("ExternalJob"),
+		)
+		Expect(t.errors).To(BeEmpty())
+	})
+
+	It("should report the first field a lossy conversion drops", func() {
+		t := &recordingT{}
+		conversion.RoundTripTest(t, scheme, jobsv2.GroupVersion.WithKind("ExternalJob"),
+			jobsv1.GroupVersion.WithKind("ExternalJob"),
+			jobsv4.GroupVersion.WithKind("ExternalJob"),
+		)
+		Expect(t.errors).To(HaveLen(1))
+		Expect(t.errors[0]).To(ContainSubstring("round trip of jobs.testprojects.kb.io/v4, Kind=ExternalJob"))
+		Expect(t.errors[0]).To(ContainSubstring("field .spec.priority differs after the round trip"))
+	})
+
+	It("should report types that are not convertible", func() {
+		t := &recordingT{}
+		conversion.RoundTripTest(t, scheme, jobsv1.GroupVersion.WithKind("ExternalJob"),
+			jobsv3.GroupVersion.WithKind("ExternalJob"),
+		)
+		Expect(t.errors).To(ConsistOf(ContainSubstring("is not a conversion.Hub")))
+	})
+})
@@ -0,0 +1,56 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ExternalJobSpec defines the desired state of ExternalJob
+type ExternalJobSpec struct {
+	RunAt string `json:"runAt"`
+}
+
+// ExternalJobStatus defines the observed state of ExternalJob
+type ExternalJobStatus struct {
+}
+
+// +kubebuilder:object:root=true
+
+// ExternalJob is the Schema for the externaljobs API
+type ExternalJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ExternalJobSpec   `json:"spec,omitempty"`
+	Status ExternalJobStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ExternalJobList contains a list of ExternalJob
+type ExternalJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ExternalJob `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ExternalJob{}, &ExternalJobList{})
+}
+
+// Hub marks v1.ExternalJob as one of the hubs of this group-kind.
+func (ej *ExternalJob) Hub() {}
@@ -0,0 +1,97 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	v1 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/multihub/v1"
+	v3 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/multihub/v3"
+)
+
+// ExternalJobSpec defines the desired state of ExternalJob
+type ExternalJobSpec struct {
+	PostponedAt string `json:"postponedAt"`
+}
+
+// ExternalJobStatus defines the observed state of ExternalJob
+type ExternalJobStatus struct {
+}
+
+// +kubebuilder:object:root=true
+
+// ExternalJob is the Schema for the externaljobs API
+type ExternalJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ExternalJobSpec   `json:"spec,omitempty"`
+	Status ExternalJobStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ExternalJobList contains a list of ExternalJob
+type ExternalJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ExternalJob `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ExternalJob{}, &ExternalJobList{})
+}
+
+// Hubs implements conversion.MultiHubConvertible: v4.ExternalJob converts
+// to/from both v1.ExternalJob and v3.ExternalJob.
+func (ej *ExternalJob) Hubs() []conversion.Hub {
+	return []conversion.Hub{&v1.ExternalJob{}, &v3.ExternalJob{}}
+}
+
+// ConvertTo implements conversion logic to convert to one of the hub types.
+func (ej *ExternalJob) ConvertTo(dst conversion.Hub) error {
+	switch t := dst.(type) {
+	case *v1.ExternalJob:
+		t.ObjectMeta = ej.ObjectMeta
+		t.Spec.RunAt = ej.Spec.PostponedAt
+		return nil
+	case *v3.ExternalJob:
+		t.ObjectMeta = ej.ObjectMeta
+		t.Spec.DeferredAt = ej.Spec.PostponedAt
+		return nil
+	default:
+		return fmt.Errorf("unsupported type %v", t)
+	}
+}
+
+// ConvertFrom implements conversion logic to convert from one of the hub types.
+func (ej *ExternalJob) ConvertFrom(src conversion.Hub) error {
+	switch t := src.(type) {
+	case *v1.ExternalJob:
+		ej.ObjectMeta = t.ObjectMeta
+		ej.Spec.PostponedAt = t.Spec.RunAt
+		return nil
+	case *v3.ExternalJob:
+		ej.ObjectMeta = t.ObjectMeta
+		ej.Spec.PostponedAt = t.Spec.DeferredAt
+		return nil
+	default:
+		return fmt.Errorf("unsupported type %v", t)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v4
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	v2 "sigs.k8s.io/controller-runtime/pkg/webhook/conversion/testdata/api/v2"
+)
+
+// ExternalJobSpec defines the desired state of ExternalJob
+type ExternalJobSpec struct {
+	DeferredAt string `json:"deferredAt"`
+	// Priority has no equivalent in the hub, it is dropped when converting to it.
+	Priority int `json:"priority,omitempty"`
+}
+
+// ExternalJobStatus defines the observed state of ExternalJob
+type ExternalJobStatus struct {
+}
+
+// +kubebuilder:object:root=true
+
+// ExternalJob is the Schema for the externaljobs API
+type ExternalJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ExternalJobSpec   `json:"spec,omitempty"`
+	Status ExternalJobStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ExternalJobList contains a list of ExternalJob
+type ExternalJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ExternalJob `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ExternalJob{}, &ExternalJobList{})
+}
+
+// ConvertTo implements conversion logic to convert to Hub type (v2.ExternalJob
+// in this case)
+func (ej *ExternalJob) ConvertTo(dst conversion.Hub) error {
+	_, err := ej.ConvertToWithWarnings(dst)
+	return err
+}
+
+// ConvertFrom implements conversion logic to convert from Hub type (v2.ExternalJob
+// in this case)
+func (ej *ExternalJob) ConvertFrom(src conversion.Hub) error {
+	_, err := ej.ConvertFromWithWarnings(src)
+	return err
+}
+
+// ConvertToWithWarnings implements conversion logic to convert to Hub type (v2.ExternalJob
+// in this case), warning about the dropped priority.
+func (ej *ExternalJob) ConvertToWithWarnings(dst conversion.Hub) ([]string, error) {
+	switch t := dst.(type) {
+	case *v2.ExternalJob:
+		t.ObjectMeta = ej.ObjectMeta
+		t.Spec.ScheduleAt = ej.Spec.DeferredAt
+		if ej.Spec.Priority != 0 {
+			return []string{fmt.Sprintf("spec.priority %d was dropped in conversion", ej.Spec.Priority)}, nil
+		}
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %v", t)
+	}
+}
+
+// ConvertFromWithWarnings implements conversion logic to convert from Hub type (v2.ExternalJob
+// in this case).
+func (ej *ExternalJob) ConvertFromWithWarnings(src conversion.Hub) ([]string, error) {
+	switch t := src.(type) {
+	case *v2.ExternalJob:
+		ej.ObjectMeta = t.ObjectMeta
+		ej.Spec.DeferredAt = t.Spec.ScheduleAt
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %v", t)
+	}
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package metrics
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -47,6 +49,26 @@ var (
 		)
 	}()
 
+	// AdmissionRequestLatency is a prometheus metric which is a histogram of the latency
+	// of processing admission requests by outcome.
+	AdmissionRequestLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "controller_runtime_webhook_admission_latency_seconds",
+			Help: "Histogram of the latency of processing admission requests by outcome",
+		},
+		[]string{"webhook", "outcome"},
+	)
+
+	// AdmissionRequestTotal is a prometheus metric which is a counter of the total processed admission
+	// requests by outcome.
+	AdmissionRequestTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "controller_runtime_webhook_admission_requests_total",
+			Help: "Total number of admission requests by outcome (allowed, denied or errored).",
+		},
+		[]string{"webhook", "outcome"},
+	)
+
 	// RequestInFlight is a prometheus metric which is a gauge of the in-flight admission requests.
 	RequestInFlight = func() *prometheus.GaugeVec {
 		return prometheus.NewGaugeVec(
@@ -60,7 +82,24 @@ var (
 )
 
 func init() {
-	metrics.Registry.MustRegister(RequestLatency, RequestTotal, RequestInFlight)
+	metrics.Registry.MustRegister(RequestLatency, RequestTotal, RequestInFlight, AdmissionRequestLatency, AdmissionRequestTotal)
+}
+
+// The outcomes of admission requests.
+const (
+	AdmissionOutcomeAllowed = "allowed"
+	AdmissionOutcomeDenied  = "denied"
+	AdmissionOutcomeErrored = "errored"
+)
+
+type admissionOutcomeKey struct{}
+
+// SetAdmissionOutcome sets the outcome of the admission request served with ctx, which
+// is recorded by the InstrumentedHook serving it, if any.
+func SetAdmissionOutcome(ctx context.Context, outcome string) {
+	if recorded, ok := ctx.Value(admissionOutcomeKey{}).(*string); ok {
+		*recorded = outcome
+	}
 }
 
 // InstrumentedHook adds some instrumentation on top of the given webhook.
@@ -75,11 +114,32 @@ func InstrumentedHook(path string, hookRaw http.Handler) http.Handler {
 	cnt.WithLabelValues("200")
 	cnt.WithLabelValues("500")
 
+	admissionLat := AdmissionRequestLatency.MustCurryWith(lbl)
+	admissionCnt := AdmissionRequestTotal.MustCurryWith(lbl)
+	for _, outcome := range []string{AdmissionOutcomeAllowed, AdmissionOutcomeDenied, AdmissionOutcomeErrored} {
+		admissionCnt.WithLabelValues(outcome)
+	}
+
 	return promhttp.InstrumentHandlerDuration(
 		lat,
 		promhttp.InstrumentHandlerCounter(
 			cnt,
-			promhttp.InstrumentHandlerInFlight(gge, hookRaw),
+			promhttp.InstrumentHandlerInFlight(gge, instrumentAdmissionOutcome(admissionLat, admissionCnt, hookRaw)),
 		),
 	)
 }
+
+// instrumentAdmissionOutcome records the latency and outcome of the admission requests served
+// by next, for the ones whose outcome is set with SetAdmissionOutcome.
+func instrumentAdmissionOutcome(lat prometheus.ObserverVec, cnt *prometheus.CounterVec, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		var outcome string
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), admissionOutcomeKey{}, &outcome)))
+		if outcome == "" {
+			return
+		}
+		lat.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+		cnt.WithLabelValues(outcome).Inc()
+	})
+}
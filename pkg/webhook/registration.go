@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// RegistrationType is the type of admission webhook a Registration is for.
+type RegistrationType string
+
+const (
+	// MutatingRegistration is for a webhook that belongs in a MutatingWebhookConfiguration.
+	MutatingRegistration RegistrationType = "Mutating"
+	// ValidatingRegistration is for a webhook that belongs in a ValidatingWebhookConfiguration.
+	ValidatingRegistration RegistrationType = "Validating"
+)
+
+// Registration describes an admission webhook for an API type and the path it is served at.
+type Registration struct {
+	// Type is the type of the webhook.
+	Type RegistrationType
+	// GVK is the API type the webhook admits.
+	GVK schema.GroupVersionKind
+	// Path is the path the webhook is served at.
+	Path string
+	// Handler is the handler serving the webhook.
+	Handler http.Handler
+}
+
+// MutatePath returns the path the mutating webhook for gvk is served at when registered with
+// builder.WebhookManagedBy, which is also the path controller-gen generates for the webhook
+// from the +kubebuilder:webhook markers, e.g. /mutate-apps-v1-deployment.
+func MutatePath(gvk schema.GroupVersionKind) string {
+	return "/mutate-" + pathSuffix(gvk)
+}
+
+// ValidatePath returns the path the validating webhook for gvk is served at when registered with
+// builder.WebhookManagedBy, which is also the path controller-gen generates for the webhook
+// from the +kubebuilder:webhook markers, e.g. /validate-apps-v1-deployment.
+func ValidatePath(gvk schema.GroupVersionKind) string {
+	return "/validate-" + pathSuffix(gvk)
+}
+
+func pathSuffix(gvk schema.GroupVersionKind) string {
+	return strings.ReplaceAll(gvk.Group, ".", "-") + "-" + gvk.Version + "-" + strings.ToLower(gvk.Kind)
+}
+
+// AddRegistration records an admission webhook in the registrations of the server returned by
+// RegistrationInfo, replacing any earlier one with the same path. builder.WebhookManagedBy records
+// the webhooks it registers with a DefaultServer, other webhooks can be recorded with AddRegistration.
+func (s *DefaultServer) AddRegistration(r Registration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.defaultingOnce.Do(s.setDefaults)
+	s.registrations[r.Path] = r
+}
+
+// RegistrationInfo returns the admission webhooks recorded on the server sorted by path, e.g. to
+// generate the MutatingWebhookConfiguration and ValidatingWebhookConfiguration pointing the API
+// server at them.
+func (s *DefaultServer) RegistrationInfo() []Registration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]Registration, 0, len(s.registrations))
+	for _, r := range s.registrations {
+		res = append(res, r)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Path < res[j].Path
+	})
+	return res
+}
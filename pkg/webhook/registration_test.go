@@ -0,0 +1,54 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+var _ = Describe("Webhook registration", func() {
+	DescribeTable("should derive the paths controller-gen generates for the webhook configurations",
+		func(gvk schema.GroupVersionKind, mutatePath, validatePath string) {
+			Expect(webhook.MutatePath(gvk)).To(Equal(mutatePath))
+			Expect(webhook.ValidatePath(gvk)).To(Equal(validatePath))
+		},
+		Entry("for a core type", schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+			"/mutate--v1-pod", "/validate--v1-pod"),
+		Entry("for a built-in type", schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			"/mutate-apps-v1-deployment", "/validate-apps-v1-deployment"),
+		Entry("for a custom resource", schema.GroupVersionKind{Group: "batch.tutorial.kubebuilder.io", Version: "v1", Kind: "CronJob"},
+			"/mutate-batch-tutorial-kubebuilder-io-v1-cronjob", "/validate-batch-tutorial-kubebuilder-io-v1-cronjob"),
+	)
+
+	It("should return the registrations recorded on the server sorted by path", func() {
+		gvk := schema.GroupVersionKind{Group: "registration.test.org", Version: "v1", Kind: "Widget"}
+		server := &webhook.DefaultServer{}
+		server.AddRegistration(webhook.Registration{Type: webhook.ValidatingRegistration, GVK: gvk, Path: webhook.ValidatePath(gvk)})
+		server.AddRegistration(webhook.Registration{Type: webhook.MutatingRegistration, GVK: gvk, Path: webhook.MutatePath(gvk)})
+		server.AddRegistration(webhook.Registration{Type: webhook.ValidatingRegistration, GVK: gvk, Path: webhook.ValidatePath(gvk)})
+
+		Expect(server.RegistrationInfo()).To(Equal([]webhook.Registration{
+			{Type: webhook.MutatingRegistration, GVK: gvk, Path: "/mutate-registration-test-org-v1-widget"},
+			{Type: webhook.ValidatingRegistration, GVK: gvk, Path: "/validate-registration-test-org-v1-widget"},
+		}))
+		Expect((&webhook.DefaultServer{}).RegistrationInfo()).To(BeEmpty())
+	})
+})
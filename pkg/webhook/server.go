@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -32,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/internal/httpserver"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/internal/metrics"
 )
 
@@ -91,6 +93,14 @@ type Options struct {
 	// Note: This option is only used when TLSOpts does not set GetCertificate.
 	KeyName string
 
+	// ServerName is the name the serving certificate is expected to be valid
+	// for, e.g. the DNS name of the service fronting the webhook server. If set,
+	// the server fails to start when the initial certificate loaded from CertDir
+	// is not valid for it. Rotated certificates are not validated.
+	//
+	// Note: This option is only used when TLSOpts does not set GetCertificate.
+	ServerName string
+
 	// ClientCAName is the CA certificate name which server used to verify remote(client)'s certificate.
 	// Defaults to "", which means server does not verify client's certificate.
 	ClientCAName string
@@ -101,6 +111,22 @@ type Options struct {
 
 	// WebhookMux is the multiplexer that handles different webhooks.
 	WebhookMux *http.ServeMux
+
+	// DrainTimeout is the maximum time the server waits on shutdown for in-flight
+	// admission requests to complete after it stopped accepting new connections.
+	// Requests still in flight afterwards are aborted. Defaults to 1 minute.
+	DrainTimeout time.Duration
+
+	// ResponseInterceptor, if set, is applied to every response of the admission webhooks registered
+	// with the server, i.e. the *admission.Webhooks, after their own ResponseInterceptor.
+	// See admission.ResponseInterceptor.
+	ResponseInterceptor admission.ResponseInterceptor
+
+	// MaxResponseBytes, if positive, limits the size of the responses of the admission webhooks
+	// registered with the server that don't set their own limit. Larger responses are logged and
+	// replaced with an error with code 500 before they are written to the API server.
+	// See admission.Webhook.MaxResponseBytes.
+	MaxResponseBytes int64
 }
 
 // NewServer constructs a new webhook.Server from the provided options.
@@ -117,6 +143,9 @@ type DefaultServer struct {
 	// webhooks keep track of all registered webhooks
 	webhooks map[string]http.Handler
 
+	// registrations keep track of the admission webhooks recorded with AddRegistration
+	registrations map[string]Registration
+
 	// defaultingOnce ensures that the default fields are only ever set once.
 	defaultingOnce sync.Once
 
@@ -124,7 +153,7 @@ type DefaultServer struct {
 	// and thus can be used to check if the server has been started
 	started bool
 
-	// mu protects access to the webhook map & setFields for Start, Register, etc
+	// mu protects access to the webhook & registration maps & setFields for Start, Register, etc
 	mu sync.Mutex
 
 	webhookMux *http.ServeMux
@@ -151,10 +180,15 @@ func (o *Options) setDefaults() {
 	if len(o.KeyName) == 0 {
 		o.KeyName = "tls.key"
 	}
+
+	if o.DrainTimeout <= 0 {
+		o.DrainTimeout = time.Minute
+	}
 }
 
 func (s *DefaultServer) setDefaults() {
 	s.webhooks = map[string]http.Handler{}
+	s.registrations = map[string]Registration{}
 	s.Options.setDefaults()
 
 	s.webhookMux = s.Options.WebhookMux
@@ -176,6 +210,14 @@ func (s *DefaultServer) Register(path string, hook http.Handler) {
 	if _, found := s.webhooks[path]; found {
 		panic(fmt.Errorf("can't register duplicate path: %v", path))
 	}
+	if wh, ok := hook.(*admission.Webhook); ok {
+		if s.Options.ResponseInterceptor != nil {
+			wh.ResponseInterceptor = admission.ChainResponseInterceptors(wh.ResponseInterceptor, s.Options.ResponseInterceptor)
+		}
+		if wh.MaxResponseBytes == 0 {
+			wh.MaxResponseBytes = s.Options.MaxResponseBytes
+		}
+	}
 	s.webhooks[path] = hook
 	s.webhookMux.Handle(path, metrics.InstrumentedHook(path, hook))
 
@@ -206,7 +248,10 @@ func (s *DefaultServer) Start(ctx context.Context) error {
 		// set the config's GetCertificate on the TLSConfig
 		certWatcher, err := certwatcher.New(certPath, keyPath)
 		if err != nil {
-			return err
+			return fmt.Errorf("invalid webhook serving certificate: %w", err)
+		}
+		if err := certWatcher.ValidateCertificate(s.Options.ServerName); err != nil {
+			return fmt.Errorf("invalid webhook serving certificate: %w", err)
 		}
 		cfg.GetCertificate = certWatcher.GetCertificate
 
@@ -246,13 +291,17 @@ func (s *DefaultServer) Start(ctx context.Context) error {
 	idleConnsClosed := make(chan struct{})
 	go func() {
 		<-ctx.Done()
-		log.Info("Shutting down webhook server with timeout of 1 minute")
+		log.Info("Shutting down webhook server, draining in-flight requests", "drainTimeout", s.Options.DrainTimeout)
 
-		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+		ctx, cancel := context.WithTimeout(context.Background(), s.Options.DrainTimeout)
 		defer cancel()
 		if err := srv.Shutdown(ctx); err != nil {
 			// Error from closing listeners, or context timeout
 			log.Error(err, "error shutting down the HTTP server")
+			if errors.Is(err, context.DeadlineExceeded) {
+				// Abort the requests that did not complete within the drain timeout.
+				_ = srv.Close()
+			}
 		}
 		close(idleConnsClosed)
 	}()
@@ -298,5 +347,6 @@ func (s *DefaultServer) StartedChecker() healthz.Checker {
 
 // WebhookMux returns the servers WebhookMux
 func (s *DefaultServer) WebhookMux() *http.ServeMux {
+	s.defaultingOnce.Do(s.setDefaults)
 	return s.webhookMux
 }
@@ -17,21 +17,29 @@ limitations under the License.
 package webhook_test
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"os"
 	"path"
 	"reflect"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
 
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/internal/testing/certs"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 var _ = Describe("Webhook Server", func() {
@@ -163,6 +171,65 @@ var _ = Describe("Webhook Server", func() {
 		})
 	})
 
+	Context("with a ResponseInterceptor", func() {
+		It("should intercept the responses of every admission webhook", func() {
+			server = webhook.NewServer(webhook.Options{
+				Host:    servingOpts.LocalServingHost,
+				Port:    servingOpts.LocalServingPort,
+				CertDir: servingOpts.LocalServingCertDir,
+				ResponseInterceptor: func(req admission.Request, resp admission.Response) admission.Response {
+					resp.AuditAnnotations = map[string]string{"policy-version": "v1"}
+					return resp
+				},
+			})
+			server.Register("/allow", &admission.Webhook{Handler: admission.HandlerFunc(func(context.Context, admission.Request) admission.Response {
+				return admission.Allowed("")
+			})})
+			server.Register("/deny", &admission.Webhook{Handler: admission.HandlerFunc(func(context.Context, admission.Request) admission.Response {
+				return admission.Denied("not allowed")
+			})})
+
+			doneCh := startServer()
+			defer func() {
+				ctxCancel()
+				Eventually(doneCh, "4s").Should(BeClosed())
+			}()
+
+			for path, allowed := range map[string]bool{"/allow": true, "/deny": false} {
+				body, err := json.Marshal(admissionv1.AdmissionReview{
+					TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+					Request:  &admissionv1.AdmissionRequest{UID: "uid"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				resp, err := client.Post(fmt.Sprintf("https://%s%s", testHostPort, path), "application/json", bytes.NewReader(body))
+				Expect(err).NotTo(HaveOccurred())
+				defer resp.Body.Close()
+
+				review := admissionv1.AdmissionReview{}
+				Expect(json.NewDecoder(resp.Body).Decode(&review)).To(Succeed())
+				Expect(review.Response.Allowed).To(Equal(allowed))
+				Expect(review.Response.AuditAnnotations).To(HaveKeyWithValue("policy-version", "v1"))
+			}
+		})
+	})
+
+	Context("with MaxResponseBytes", func() {
+		It("should limit the responses of admission webhooks without their own limit", func() {
+			server = webhook.NewServer(webhook.Options{MaxResponseBytes: 1024})
+			unlimited := &admission.Webhook{Handler: admission.HandlerFunc(func(context.Context, admission.Request) admission.Response {
+				return admission.Allowed("")
+			})}
+			limited := &admission.Webhook{MaxResponseBytes: 4096, Handler: admission.HandlerFunc(func(context.Context, admission.Request) admission.Response {
+				return admission.Allowed("")
+			})}
+			server.Register("/unlimited", unlimited)
+			server.Register("/limited", limited)
+
+			Expect(unlimited.MaxResponseBytes).To(BeEquivalentTo(1024))
+			Expect(limited.MaxResponseBytes).To(BeEquivalentTo(4096))
+		})
+	})
+
 	It("should respect passed in TLS configurations", func() {
 		var finalCfg *tls.Config
 		tlsCfgFunc := func(cfg *tls.Config) {
@@ -250,6 +317,107 @@ var _ = Describe("Webhook Server", func() {
 		ctxCancel()
 		Eventually(doneCh, "4s").Should(BeClosed())
 	})
+
+	Context("when shutting down", func() {
+		var (
+			handlerStarted chan struct{}
+			releaseHandler chan struct{}
+		)
+
+		BeforeEach(func() {
+			handlerStarted = make(chan struct{})
+			releaseHandler = make(chan struct{})
+		})
+
+		startServerWithSlowHandler := func(drainTimeout time.Duration) (done <-chan struct{}) {
+			server = webhook.NewServer(webhook.Options{
+				Host:         servingOpts.LocalServingHost,
+				Port:         servingOpts.LocalServingPort,
+				CertDir:      servingOpts.LocalServingCertDir,
+				DrainTimeout: drainTimeout,
+			})
+			server.Register("/slow", &slowHandler{started: handlerStarted, release: releaseHandler})
+			return startServer()
+		}
+
+		requestSlowHandler := func() <-chan error {
+			errs := make(chan error, 1)
+			go func() {
+				resp, err := client.Get(fmt.Sprintf("https://%s/slow", testHostPort))
+				if err == nil {
+					defer resp.Body.Close()
+					_, err = io.ReadAll(resp.Body)
+				}
+				errs <- err
+			}()
+			Eventually(handlerStarted).Should(BeClosed())
+			return errs
+		}
+
+		It("should complete in-flight requests during the drain", func() {
+			doneCh := startServerWithSlowHandler(10 * time.Second)
+			errs := requestSlowHandler()
+
+			ctxCancel()
+			Consistently(doneCh).ShouldNot(BeClosed(), "the server stopped before the in-flight request completed")
+
+			close(releaseHandler)
+			Eventually(errs).Should(Receive(BeNil()))
+			Eventually(doneCh, "4s").Should(BeClosed())
+		})
+
+		It("should abort in-flight requests after the DrainTimeout", func() {
+			defer close(releaseHandler)
+			doneCh := startServerWithSlowHandler(500 * time.Millisecond)
+			errs := requestSlowHandler()
+
+			ctxCancel()
+			Eventually(doneCh, "4s").Should(BeClosed())
+			Eventually(errs).Should(Receive(HaveOccurred()))
+		})
+	})
+
+	Context("when validating the serving certificate at startup", func() {
+		It("should fail to start with a mismatched cert/key", func() {
+			ca, err := certs.NewTinyCA()
+			Expect(err).NotTo(HaveOccurred())
+			otherCert, err := ca.NewServingCert(servingOpts.LocalServingHost)
+			Expect(err).NotTo(HaveOccurred())
+			_, otherKey, err := otherCert.AsBytes()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.WriteFile(path.Join(servingOpts.LocalServingCertDir, "tls.key"), otherKey, 0600)).To(Succeed())
+
+			err = server.Start(ctx)
+			Expect(err).To(MatchError(ContainSubstring("invalid webhook serving certificate")))
+			Expect(err).To(MatchError(ContainSubstring("private key does not match public key")))
+		})
+
+		It("should fail to start if the certificate is not valid for the server name", func() {
+			server = webhook.NewServer(webhook.Options{
+				Host:       servingOpts.LocalServingHost,
+				Port:       servingOpts.LocalServingPort,
+				CertDir:    servingOpts.LocalServingCertDir,
+				ServerName: "webhook-service.default.svc",
+			})
+
+			err := server.Start(ctx)
+			Expect(err).To(MatchError(ContainSubstring("invalid webhook serving certificate")))
+			Expect(err).To(MatchError(ContainSubstring("is not valid for server name")))
+		})
+
+		It("should start if the certificate is valid for the server name", func() {
+			server = webhook.NewServer(webhook.Options{
+				Host:       servingOpts.LocalServingHost,
+				Port:       servingOpts.LocalServingPort,
+				CertDir:    servingOpts.LocalServingCertDir,
+				ServerName: servingOpts.LocalServingHost,
+			})
+			doneCh := startServer()
+
+			ctxCancel()
+			Eventually(doneCh, "4s").Should(BeClosed())
+		})
+	})
 })
 
 type testHandler struct {
@@ -260,3 +428,16 @@ func (t *testHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 		panic("unable to write http response!")
 	}
 }
+
+type slowHandler struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (h *slowHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	close(h.started)
+	<-h.release
+	if _, err := resp.Write([]byte("done")); err != nil {
+		panic("unable to write http response!")
+	}
+}
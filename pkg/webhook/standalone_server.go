@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/internal/httpserver"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	defaultReadinessEndpoint = "/readyz"
+	defaultLivenessEndpoint  = "/healthz"
+)
+
+// StandaloneServerOptions are the options for a StandaloneServer.
+type StandaloneServerOptions struct {
+	// Options configures the underlying webhook server, including its
+	// certificates.
+	Options
+
+	// Scheme is used to decode the objects contained in admission requests.
+	// Defaults to the kubernetes/client-go scheme.Scheme.
+	Scheme *runtime.Scheme
+
+	// HealthProbeBindAddress is the TCP address that the server should bind to
+	// for serving health probes.
+	// It can be set to "0" or "" to disable serving the health probe.
+	HealthProbeBindAddress string
+
+	// ReadinessEndpointName is the readiness probe endpoint name, defaults to "readyz".
+	ReadinessEndpointName string
+
+	// LivenessEndpointName is the liveness probe endpoint name, defaults to "healthz".
+	LivenessEndpointName string
+}
+
+// StandaloneServer runs a webhook Server together with its health probes,
+// without requiring a manager. This is meant for webhook-only deployments
+// that don't run any controllers and thus need neither a cache, a client nor
+// leader election.
+//
+// Handlers that only need to decode objects can be constructed using the
+// scheme and decoder returned by GetScheme and GetAdmissionDecoder.
+type StandaloneServer struct {
+	Server
+
+	scheme  *runtime.Scheme
+	decoder admission.Decoder
+
+	healthProbeBindAddress string
+	readinessEndpointName  string
+	livenessEndpointName   string
+
+	// mu protects access to the health checks.
+	mu            sync.Mutex
+	readyzChecks  map[string]healthz.Checker
+	healthzChecks map[string]healthz.Checker
+	started       bool
+}
+
+// NewStandaloneServer constructs a new StandaloneServer from the provided options.
+// The server is always ready once the webhook server has been started, and
+// always healthy; more checks can be added using AddReadyzCheck and AddHealthzCheck.
+func NewStandaloneServer(o StandaloneServerOptions) *StandaloneServer {
+	if o.Scheme == nil {
+		o.Scheme = clientgoscheme.Scheme
+	}
+	if o.ReadinessEndpointName == "" {
+		o.ReadinessEndpointName = defaultReadinessEndpoint
+	}
+	if o.LivenessEndpointName == "" {
+		o.LivenessEndpointName = defaultLivenessEndpoint
+	}
+
+	server := NewServer(o.Options)
+	return &StandaloneServer{
+		Server:                 server,
+		scheme:                 o.Scheme,
+		decoder:                admission.NewDecoder(o.Scheme),
+		healthProbeBindAddress: o.HealthProbeBindAddress,
+		readinessEndpointName:  o.ReadinessEndpointName,
+		livenessEndpointName:   o.LivenessEndpointName,
+		readyzChecks:           map[string]healthz.Checker{"webhook": server.StartedChecker()},
+		healthzChecks:          map[string]healthz.Checker{"ping": healthz.Ping},
+	}
+}
+
+// GetScheme returns the scheme used to decode admission requests.
+func (s *StandaloneServer) GetScheme() *runtime.Scheme {
+	return s.scheme
+}
+
+// GetAdmissionDecoder returns a decoder for the objects contained in admission requests.
+func (s *StandaloneServer) GetAdmissionDecoder() admission.Decoder {
+	return s.decoder
+}
+
+// AddHealthzCheck allows you to add a Healthz checker.
+func (s *StandaloneServer) AddHealthzCheck(name string, check healthz.Checker) error {
+	return s.addCheck(s.healthzChecks, name, check)
+}
+
+// AddReadyzCheck allows you to add a Readyz checker.
+func (s *StandaloneServer) AddReadyzCheck(name string, check healthz.Checker) error {
+	return s.addCheck(s.readyzChecks, name, check)
+}
+
+func (s *StandaloneServer) addCheck(checks map[string]healthz.Checker, name string, check healthz.Checker) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return fmt.Errorf("unable to add new checker because server is already started")
+	}
+	if _, found := checks[name]; found {
+		return fmt.Errorf("checker %s already exists", name)
+	}
+	checks[name] = check
+	return nil
+}
+
+// Start runs the webhook server and, if configured, the health probe server.
+// It blocks until the context is closed or the webhook server fails.
+func (s *StandaloneServer) Start(ctx context.Context) error {
+	s.mu.Lock()
+	s.started = true
+	s.mu.Unlock()
+
+	if s.healthProbeBindAddress != "" && s.healthProbeBindAddress != "0" {
+		ln, err := net.Listen("tcp", s.healthProbeBindAddress)
+		if err != nil {
+			return fmt.Errorf("error listening on %s: %w", s.healthProbeBindAddress, err)
+		}
+		srv := httpserver.New(s.healthProbeMux())
+
+		go func() {
+			log.Info("Serving health probes", "addr", ln.Addr())
+			if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+				log.Error(err, "error serving health probes")
+			}
+		}()
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := srv.Shutdown(ctx); err != nil {
+				log.Error(err, "error shutting down the health probe server")
+			}
+		}()
+	}
+
+	return s.Server.Start(ctx)
+}
+
+func (s *StandaloneServer) healthProbeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+
+	readyzHandler := &healthz.Handler{Checks: s.readyzChecks}
+	mux.Handle(s.readinessEndpointName, http.StripPrefix(s.readinessEndpointName, readyzHandler))
+	// Append '/' suffix to handle subpaths
+	mux.Handle(s.readinessEndpointName+"/", http.StripPrefix(s.readinessEndpointName, readyzHandler))
+
+	healthzHandler := &healthz.Handler{Checks: s.healthzChecks}
+	mux.Handle(s.livenessEndpointName, http.StripPrefix(s.livenessEndpointName, healthzHandler))
+	// Append '/' suffix to handle subpaths
+	mux.Handle(s.livenessEndpointName+"/", http.StripPrefix(s.livenessEndpointName, healthzHandler))
+
+	return mux
+}
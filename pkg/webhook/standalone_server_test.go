@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/internal/testing/addr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("Standalone Webhook Server", func() {
+	var (
+		ctx          context.Context
+		ctxCancel    context.CancelFunc
+		testHostPort string
+		probeAddr    string
+		client       *http.Client
+		server       *webhook.StandaloneServer
+		servingOpts  envtest.WebhookInstallOptions
+	)
+
+	BeforeEach(func() {
+		ctx, ctxCancel = context.WithCancel(context.Background())
+
+		servingOpts = envtest.WebhookInstallOptions{}
+		Expect(servingOpts.PrepWithoutInstalling()).To(Succeed())
+
+		testHostPort = net.JoinHostPort(servingOpts.LocalServingHost, fmt.Sprintf("%d", servingOpts.LocalServingPort))
+
+		probePort, probeHost, err := addr.Suggest("")
+		Expect(err).NotTo(HaveOccurred())
+		probeAddr = net.JoinHostPort(probeHost, fmt.Sprintf("%d", probePort))
+
+		clientTransport, err := rest.TransportFor(&rest.Config{
+			TLSClientConfig: rest.TLSClientConfig{CAData: servingOpts.LocalServingCAData},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		client = &http.Client{
+			Transport: clientTransport,
+		}
+
+		server = webhook.NewStandaloneServer(webhook.StandaloneServerOptions{
+			Options: webhook.Options{
+				Host:    servingOpts.LocalServingHost,
+				Port:    servingOpts.LocalServingPort,
+				CertDir: servingOpts.LocalServingCertDir,
+			},
+			HealthProbeBindAddress: probeAddr,
+		})
+	})
+	AfterEach(func() {
+		ctxCancel()
+		Expect(servingOpts.Cleanup()).To(Succeed())
+	})
+
+	startServer := func() (done <-chan struct{}) {
+		doneCh := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(doneCh)
+			Expect(server.Start(ctx)).To(Succeed())
+		}()
+		return doneCh
+	}
+
+	probe := func(endpoint string) (int, error) {
+		resp, err := http.Get(fmt.Sprintf("http://%s/%s", probeAddr, endpoint))
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+
+	validate := func(pod *corev1.Pod) *admissionv1.AdmissionResponse {
+		raw, err := json.Marshal(pod)
+		Expect(err).NotTo(HaveOccurred())
+
+		review := &admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+			Request: &admissionv1.AdmissionRequest{
+				UID:       "test-uid",
+				Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		}
+		body, err := json.Marshal(review)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := client.Post(fmt.Sprintf("https://%s/validate-pod", testHostPort), "application/json", bytes.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		Expect(json.NewDecoder(resp.Body).Decode(review)).To(Succeed())
+		return review.Response
+	}
+
+	It("should serve a validating webhook that only needs decoding", func() {
+		server.Register("/validate-pod", admission.WithCustomValidator(server.GetScheme(), &corev1.Pod{}, &podValidator{}))
+		doneCh := startServer()
+
+		Eventually(func() error {
+			_, err := client.Get(fmt.Sprintf("https://%s/unservedpath", testHostPort))
+			return err
+		}).Should(Succeed())
+
+		allowed := validate(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "allowed", Namespace: "default"}})
+		Expect(allowed.Allowed).To(BeTrue())
+
+		denied := validate(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "denied", Namespace: "default"}})
+		Expect(denied.Allowed).To(BeFalse())
+		Expect(denied.Result.Message).To(ContainSubstring("pod name is denied"))
+
+		ctxCancel()
+		Eventually(doneCh, "4s").Should(BeClosed())
+	})
+
+	It("should serve health probes", func() {
+		Expect(server.AddReadyzCheck("custom", func(_ *http.Request) error { return nil })).To(Succeed())
+		doneCh := startServer()
+
+		Eventually(func() (int, error) { return probe("readyz") }).Should(Equal(http.StatusOK))
+		Expect(probe("healthz")).To(Equal(http.StatusOK))
+
+		Expect(server.AddReadyzCheck("late", func(_ *http.Request) error { return nil })).NotTo(Succeed())
+
+		ctxCancel()
+		Eventually(doneCh, "4s").Should(BeClosed())
+	})
+
+	It("should not be ready before the webhook server is serving", func() {
+		// Hold the webhook server before it starts listening, while the
+		// health probes are already being served.
+		release := make(chan struct{})
+		var releaseOnce sync.Once
+		unblock := func() { releaseOnce.Do(func() { close(release) }) }
+		DeferCleanup(unblock)
+		server = webhook.NewStandaloneServer(webhook.StandaloneServerOptions{
+			Options: webhook.Options{
+				Host:    servingOpts.LocalServingHost,
+				Port:    servingOpts.LocalServingPort,
+				CertDir: servingOpts.LocalServingCertDir,
+				TLSOpts: []func(*tls.Config){
+					func(*tls.Config) { <-release },
+				},
+			},
+			HealthProbeBindAddress: probeAddr,
+		})
+		doneCh := startServer()
+
+		Eventually(func() (int, error) { return probe("healthz") }).Should(Equal(http.StatusOK))
+		Expect(probe("readyz")).To(Equal(http.StatusInternalServerError))
+
+		unblock()
+		Eventually(func() (int, error) { return probe("readyz") }).Should(Equal(http.StatusOK))
+
+		ctxCancel()
+		Eventually(doneCh, "4s").Should(BeClosed())
+	})
+})
+
+type podValidator struct{}
+
+func (*podValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	if obj.(*corev1.Pod).Name == "denied" {
+		return nil, errors.New("pod name is denied")
+	}
+	return nil, nil
+}
+
+func (*podValidator) ValidateUpdate(_ context.Context, _, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (*podValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}